@@ -0,0 +1,243 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Alerter notifies by email and/or webhook when the error rate, the LLM failure rate or the estimated
+// OpenAI spend crosses its threshold. Each alert is sent at most once per cooldown across all replicas.
+type Alerter struct {
+	service    *PortfolioService
+	metrics    *RequestMetrics
+	mailer     *Mailer
+	emailTo    []string
+	webhookURL string
+	httpClient *http.Client
+
+	window         time.Duration // Period the error and LLM failure rates are computed over
+	cooldown       time.Duration
+	minSamples     int64 // Fewer requests or LLM calls than this in the window never alert
+	errorRate      float64
+	llmFailureRate float64
+	dailySpendUSD  float64
+}
+
+// alertFloat reads a threshold from the environment; 0 disables the alert
+func alertFloat(key string, fallback float64) float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil || parsed < 0 {
+		log.Printf("Invalid %s %q, using %g", key, value, fallback)
+		return fallback
+	}
+	return parsed
+}
+
+// alertDuration reads a duration from the environment
+func alertDuration(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil || parsed <= 0 {
+		log.Printf("Invalid %s %q, using %s", key, value, fallback)
+		return fallback
+	}
+	return parsed
+}
+
+// NewAlerter creates the alerter from environment configuration, or nil if neither ALERT_EMAIL (with SMTP
+// configured) nor ALERT_WEBHOOK_URL is set. Thresholds: ALERT_ERROR_RATE (default 0.05),
+// ALERT_LLM_FAILURE_RATE (0.2) and ALERT_DAILY_SPEND_USD (5), over ALERT_WINDOW (15m) with at least
+// ALERT_MIN_SAMPLES (20) samples, repeated at most every ALERT_COOLDOWN (1h).
+func NewAlerter(service *PortfolioService, metrics *RequestMetrics, mailer *Mailer) *Alerter {
+	alerter := &Alerter{
+		service:    service,
+		metrics:    metrics,
+		webhookURL: os.Getenv("ALERT_WEBHOOK_URL"),
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+
+		window:         alertDuration("ALERT_WINDOW", 15*time.Minute),
+		cooldown:       alertDuration("ALERT_COOLDOWN", time.Hour),
+		minSamples:     20,
+		errorRate:      alertFloat("ALERT_ERROR_RATE", 0.05),
+		llmFailureRate: alertFloat("ALERT_LLM_FAILURE_RATE", 0.2),
+		dailySpendUSD:  alertFloat("ALERT_DAILY_SPEND_USD", 5),
+	}
+	if value := os.Getenv("ALERT_MIN_SAMPLES"); value != "" {
+		if parsed, err := strconv.ParseInt(value, 10, 64); err == nil && parsed > 0 {
+			alerter.minSamples = parsed
+		} else {
+			log.Printf("Invalid ALERT_MIN_SAMPLES %q, using %d", value, alerter.minSamples)
+		}
+	}
+	if to := emailList(os.Getenv("ALERT_EMAIL")); len(to) > 0 {
+		if mailer == nil {
+			log.Println("ALERT_EMAIL is set but SMTP_HOST is not, alerts are not emailed")
+		} else {
+			alerter.mailer, alerter.emailTo = mailer, to
+		}
+	}
+	if alerter.mailer == nil && alerter.webhookURL == "" {
+		log.Println("ALERT_EMAIL and ALERT_WEBHOOK_URL not set, alerting disabled")
+		return nil
+	}
+	return alerter
+}
+
+// Alert is a threshold that was crossed
+type Alert struct {
+	Name      string    `json:"name"`
+	Message   string    `json:"message"`
+	Value     float64   `json:"value"`
+	Threshold float64   `json:"threshold"`
+	FiredAt   time.Time `json:"fired_at"`
+}
+
+// Check evaluates every threshold and notifies about the ones crossed; it runs on the "alerts" schedule.
+// The error rate comes from this replica's request metrics, so every replica runs the check.
+func (a *Alerter) Check(ctx context.Context) error {
+	now := time.Now()
+	var alerts []Alert
+
+	if a.errorRate > 0 {
+		var requests, serverErrors int64
+		for _, route := range a.metrics.Summary(a.window) {
+			requests += int64(route.Requests)
+			serverErrors += int64(route.ServerErrors)
+		}
+		if requests >= a.minSamples {
+			if rate := float64(serverErrors) / float64(requests); rate >= a.errorRate {
+				alerts = append(alerts, Alert{
+					Name:      "error_rate",
+					Message:   fmt.Sprintf("%.1f%% of requests failed with a 5xx status in the last %s (%d of %d) on %s", rate*100, a.window, serverErrors, requests, instanceID),
+					Value:     rate,
+					Threshold: a.errorRate,
+				})
+			}
+		}
+	}
+
+	if a.llmFailureRate > 0 {
+		usage, err := a.service.GetLLMUsage(ctx, now.Add(-a.window))
+		if err != nil {
+			return fmt.Errorf("loading LLM usage: %w", err)
+		}
+		if usage.Calls >= a.minSamples {
+			if rate := float64(usage.Failures) / float64(usage.Calls); rate >= a.llmFailureRate {
+				alerts = append(alerts, Alert{
+					Name:      "llm_failure_rate",
+					Message:   fmt.Sprintf("%.1f%% of OpenAI calls failed in the last %s (%d of %d)", rate*100, a.window, usage.Failures, usage.Calls),
+					Value:     rate,
+					Threshold: a.llmFailureRate,
+				})
+			}
+		}
+	}
+
+	if a.dailySpendUSD > 0 {
+		usage, err := a.service.GetLLMUsage(ctx, now.Add(-24*time.Hour))
+		if err != nil {
+			return fmt.Errorf("loading LLM usage: %w", err)
+		}
+		if usage.CostUSD >= a.dailySpendUSD {
+			alerts = append(alerts, Alert{
+				Name:      "daily_spend",
+				Message:   fmt.Sprintf("Estimated OpenAI spend over the last 24h is $%.2f (%d calls, %d prompt and %d completion tokens)", usage.CostUSD, usage.Calls, usage.PromptTokens, usage.CompletionTokens),
+				Value:     usage.CostUSD,
+				Threshold: a.dailySpendUSD,
+			})
+		}
+	}
+
+	var failed []string
+	for _, alert := range alerts {
+		alert.FiredAt = now
+		claimed, err := a.service.ClaimAlert(ctx, alert.Name, a.cooldown)
+		if err != nil {
+			failed = append(failed, alert.Name+": "+err.Error())
+			continue
+		}
+		if !claimed {
+			continue // Already sent within the cooldown
+		}
+		log.Printf("Alert %s: %s", alert.Name, alert.Message)
+		if err := a.notify(ctx, alert); err != nil {
+			failed = append(failed, alert.Name+": "+err.Error())
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("sending alerts failed for %s", strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+// notify sends an alert to every configured channel
+func (a *Alerter) notify(ctx context.Context, alert Alert) error {
+	var errs []error
+	if a.mailer != nil {
+		subject := fmt.Sprintf("[portfolio] Alert: %s", alert.Name)
+		body := fmt.Sprintf("%s\n\nValue: %g\nThreshold: %g\nTime: %s\n", alert.Message, alert.Value, alert.Threshold, alert.FiredAt.Format(time.RFC1123))
+		if err := a.mailer.Send(a.emailTo, subject, body); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if a.webhookURL != "" {
+		// "text" makes the payload readable by Slack and Discord-compatible incoming webhooks
+		payload, _ := json.Marshal(map[string]interface{}{
+			"text":  "Portfolio alert: " + alert.Message,
+			"alert": alert,
+		})
+		req, err := http.NewRequestWithContext(ctx, "POST", a.webhookURL, bytes.NewReader(payload))
+		if err == nil {
+			req.Header.Set("Content-Type", "application/json")
+			var resp *http.Response
+			if resp, err = a.httpClient.Do(req); err == nil {
+				resp.Body.Close()
+				if resp.StatusCode >= 300 {
+					err = fmt.Errorf("alert webhook returned status %d", resp.StatusCode)
+				}
+			}
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ClaimAlert records that an alert is being sent, unless it was already sent within the cooldown (by any
+// replica), in which case it returns false
+func (ps *PortfolioService) ClaimAlert(ctx context.Context, name string, cooldown time.Duration) (bool, error) {
+	now := time.Now()
+	_, err := ps.alerts.UpdateOne(ctx,
+		bson.M{"_id": name, "sent_at": bson.M{"$lt": now.Add(-cooldown)}},
+		bson.M{"$set": bson.M{"sent_at": now, "instance": instanceID}},
+		options.Update().SetUpsert(true),
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,316 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// analyticsEventTypes are the accepted values of AnalyticsEvent.Type
+var analyticsEventTypes = []string{"page_view", "project_click", "chat_open"}
+
+// analyticsSalt keys the visitor hash. Set ANALYTICS_SALT to keep unique visitor counts stable across
+// restarts and instances; otherwise a random salt is generated at startup.
+var analyticsSalt = func() string {
+	if salt := os.Getenv("ANALYTICS_SALT"); salt != "" {
+		return salt
+	}
+	buf := make([]byte, 16)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}()
+
+// anonymizeIP zeroes the host part of an address: the last octet of IPv4 and the last 80 bits of IPv6
+func anonymizeIP(ip string) string {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return ""
+	}
+	if v4 := parsed.To4(); v4 != nil {
+		return v4.Mask(net.CIDRMask(24, 32)).String()
+	}
+	return parsed.Mask(net.CIDRMask(48, 128)).String()
+}
+
+// visitorHash identifies a visitor for one day without storing anything that can be reversed to the IP
+func visitorHash(anonymizedIP, userAgent string, day time.Time) string {
+	sum := sha256.Sum256([]byte(analyticsSalt + "|" + day.UTC().Format("2006-01-02") + "|" + anonymizedIP + "|" + userAgent))
+	return hex.EncodeToString(sum[:12])
+}
+
+// Analytics query methods
+
+// RecordAnalyticsEvent stores a visitor event
+func (ps *PortfolioService) RecordAnalyticsEvent(ctx context.Context, event *AnalyticsEvent) error {
+	event.ID = primitive.NewObjectID()
+	_, err := ps.analytics.InsertOne(ctx, event)
+	return err
+}
+
+// DailyAnalytics is the number of events and unique visitors on a day
+type DailyAnalytics struct {
+	Date     string `bson:"_id" json:"date"`
+	Events   int    `bson:"events" json:"events"`
+	Visitors int    `bson:"visitors" json:"visitors"`
+}
+
+// GetDailyAnalytics counts events per UTC day since the given time, optionally of a single type
+func (ps *PortfolioService) GetDailyAnalytics(ctx context.Context, since time.Time, eventType string) ([]DailyAnalytics, error) {
+	match := bson.M{"created_at": bson.M{"$gte": since}}
+	if eventType != "" {
+		match["type"] = eventType
+	}
+	pipeline := []bson.M{
+		{"$match": match},
+		{"$group": bson.M{
+			"_id":      bson.M{"$dateToString": bson.M{"format": "%Y-%m-%d", "date": "$created_at"}},
+			"events":   bson.M{"$sum": 1},
+			"visitors": bson.M{"$addToSet": "$visitor_hash"},
+		}},
+		{"$project": bson.M{"events": 1, "visitors": bson.M{"$size": "$visitors"}}},
+		{"$sort": bson.M{"_id": 1}},
+	}
+	cursor, err := ps.analytics.Aggregate(ctx, pipeline, timedAggregate())
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	days := []DailyAnalytics{}
+	if err = cursor.All(ctx, &days); err != nil {
+		return nil, err
+	}
+	return days, nil
+}
+
+// AnalyticsCount is the number of events for a page or project
+type AnalyticsCount struct {
+	Key      string `bson:"_id" json:"key"`
+	Count    int    `bson:"count" json:"count"`
+	Visitors int    `bson:"visitors" json:"visitors"`
+}
+
+// GetTopAnalytics returns the most visited pages, or the most clicked projects when eventType is
+// project_click
+func (ps *PortfolioService) GetTopAnalytics(ctx context.Context, since time.Time, eventType string, limit int) ([]AnalyticsCount, error) {
+	field := "$path"
+	if eventType == "project_click" {
+		field = "$project_slug"
+	}
+	pipeline := []bson.M{
+		{"$match": bson.M{"created_at": bson.M{"$gte": since}, "type": eventType}},
+		{"$group": bson.M{
+			"_id":      field,
+			"count":    bson.M{"$sum": 1},
+			"visitors": bson.M{"$addToSet": "$visitor_hash"},
+		}},
+		{"$project": bson.M{"count": 1, "visitors": bson.M{"$size": "$visitors"}}},
+		{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}},
+		{"$limit": limit},
+	}
+	cursor, err := ps.analytics.Aggregate(ctx, pipeline, timedAggregate())
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	counts := []AnalyticsCount{}
+	if err = cursor.All(ctx, &counts); err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// validateAnalyticsEvent checks an event reported by the frontend
+func validateAnalyticsEvent(event *AnalyticsEvent) error {
+	var v Validator
+	v.OneOf("type", event.Type, analyticsEventTypes...)
+	v.Check(strings.HasPrefix(event.Path, "/") && len(event.Path) <= 300, "path", "format", "path must be an absolute path of at most 300 characters")
+	if event.Type == "project_click" {
+		v.Required("project_slug", event.ProjectSlug)
+	}
+	v.MaxLength("project_slug", event.ProjectSlug, 100)
+	v.MaxLength("referrer", event.Referrer, 500)
+	return v.Err()
+}
+
+// parseAnalyticsRange reads ?days= (default 30, max 365) into the start of the range
+func parseAnalyticsRange(v *Validator, r *http.Request) time.Time {
+	days := v.QueryInt(r, "days", 30, 1, 365)
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	return today.AddDate(0, 0, 1-days)
+}
+
+// Analytics ingestion endpoint: POST /api/analytics/event records a page view, project click or chat open
+func (h *APIHandler) handleAnalyticsEvent(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if r.Method != "POST" {
+		log.Printf("Date: %s | Route: /api/analytics/event | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	clientIP := trustedClientIP(r)
+	if !h.analyticsLimiter.IsAllowed(clientIP) {
+		http.Error(w, "Rate limit exceeded. Please wait before making another request.", http.StatusTooManyRequests)
+		return
+	}
+
+	var event AnalyticsEvent
+	// sendBeacon posts as text/plain, so the body is decoded regardless of content type
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4<<10)).Decode(&event); err != nil {
+		log.Printf("Date: %s | Route: /api/analytics/event | Status: BAD_REQUEST | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Invalid JSON request", http.StatusBadRequest)
+		return
+	}
+	event.Type = strings.TrimSpace(event.Type)
+	event.Path = strings.TrimSpace(event.Path)
+	event.ProjectSlug = strings.TrimSpace(event.ProjectSlug)
+	if event.Referrer == "" {
+		event.Referrer = r.Referer()
+	}
+	// Only the origin of the referrer is kept; paths and query strings can carry personal data
+	if referrer, err := url.Parse(event.Referrer); err == nil && referrer.Host != "" {
+		event.Referrer = referrer.Scheme + "://" + referrer.Host
+	} else {
+		event.Referrer = ""
+	}
+	if err := validateAnalyticsEvent(&event); err != nil {
+		log.Printf("Date: %s | Route: /api/analytics/event | Status: INVALID_INPUT | GPT Model: %s", currentTime, gptModel)
+		writeInvalidInput(w, err)
+		return
+	}
+
+	event.CreatedAt = time.Now()
+	event.IP = anonymizeIP(clientIP)
+	event.ReferrerDomain = referrerDomain(event.Referrer, r.Host)
+	event.Country = h.analyticsCountry(r, event.IP)
+	event.VisitorHash = visitorHash(event.IP, r.UserAgent(), event.CreatedAt)
+
+	if err := h.service.RecordAnalyticsEvent(context.Background(), &event); err != nil {
+		log.Printf("Date: %s | Route: /api/analytics/event | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Daily analytics endpoint: GET /api/analytics/daily?days=&type= returns events and unique visitors per day (admin only)
+func (h *APIHandler) handleAnalyticsDaily(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if r.Method != "GET" {
+		log.Printf("Date: %s | Route: /api/analytics/daily | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !isAdminRequest(r) {
+		log.Printf("Date: %s | Route: /api/analytics/daily | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var v Validator
+	since := parseAnalyticsRange(&v, r)
+	eventType := r.URL.Query().Get("type")
+	if eventType != "" {
+		v.OneOf("type", eventType, analyticsEventTypes...)
+	}
+	if err := v.Err(); err != nil {
+		writeInvalidInput(w, err)
+		return
+	}
+
+	days, err := h.service.GetDailyAnalytics(context.Background(), since, eventType)
+	if err != nil {
+		log.Printf("Date: %s | Route: /api/analytics/daily | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Date: %s | Route: /api/analytics/daily | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(days)
+}
+
+// Top analytics endpoint: GET /api/analytics/top?days=&type=&limit= returns the most visited pages, or the
+// most clicked projects with type=project_click (admin only)
+func (h *APIHandler) handleAnalyticsTop(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if r.Method != "GET" {
+		log.Printf("Date: %s | Route: /api/analytics/top | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !isAdminRequest(r) {
+		log.Printf("Date: %s | Route: /api/analytics/top | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var v Validator
+	since := parseAnalyticsRange(&v, r)
+	eventType := r.URL.Query().Get("type")
+	if eventType == "" {
+		eventType = "page_view"
+	}
+	v.OneOf("type", eventType, analyticsEventTypes...)
+	limit := v.QueryInt(r, "limit", 10, 1, 100)
+	if err := v.Err(); err != nil {
+		writeInvalidInput(w, err)
+		return
+	}
+
+	counts, err := h.service.GetTopAnalytics(context.Background(), since, eventType, limit)
+	if err != nil {
+		log.Printf("Date: %s | Route: /api/analytics/top | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Date: %s | Route: /api/analytics/top | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(counts)
+}
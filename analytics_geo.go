@@ -0,0 +1,256 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// countryHeaders are set by CDNs and hosting platforms that resolve the visitor's country themselves
+var countryHeaders = []string{"CF-IPCountry", "CloudFront-Viewer-Country", "X-Vercel-IP-Country", "Fly-Client-Country", "X-Country-Code"}
+
+// geoIPRange maps an inclusive range of addresses, in 16-byte form, to a country
+type geoIPRange struct {
+	start   net.IP
+	end     net.IP
+	country string
+}
+
+// GeoIPDatabase looks up countries in a self-hosted IP range database
+type GeoIPDatabase struct {
+	ranges []geoIPRange
+}
+
+// NewGeoIPDatabase loads the CSV at GEOIP_CSV, with rows of start address, end address and country code
+// (the layout of the free DB-IP and IP2Location country databases). Returns nil if not configured.
+func NewGeoIPDatabase() *GeoIPDatabase {
+	path := os.Getenv("GEOIP_CSV")
+	if path == "" {
+		log.Println("GEOIP_CSV not set, analytics countries will only come from CDN headers")
+		return nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		log.Printf("Failed to open GeoIP database: %v", err)
+		return nil
+	}
+	defer file.Close()
+
+	db, err := parseGeoIPCSV(file)
+	if err != nil {
+		log.Printf("Failed to load GeoIP database: %v", err)
+		return nil
+	}
+	log.Printf("Loaded GeoIP database with %d ranges", len(db.ranges))
+	return db
+}
+
+func parseGeoIPCSV(r io.Reader) (*GeoIPDatabase, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	db := &GeoIPDatabase{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(record) < 3 {
+			continue
+		}
+		start, end := parseGeoIPAddress(record[0]), parseGeoIPAddress(record[1])
+		country := strings.ToUpper(strings.TrimSpace(record[2]))
+		// Header rows and unassigned ranges ("-" or "ZZ") are skipped
+		if start == nil || end == nil || len(country) != 2 || country == "ZZ" {
+			continue
+		}
+		db.ranges = append(db.ranges, geoIPRange{start: start, end: end, country: country})
+	}
+	if len(db.ranges) == 0 {
+		return nil, fmt.Errorf("no ranges found")
+	}
+
+	sort.Slice(db.ranges, func(i, j int) bool { return bytes.Compare(db.ranges[i].start, db.ranges[j].start) < 0 })
+	return db, nil
+}
+
+// parseGeoIPAddress accepts dotted or colon notation as well as the integer form IP2Location uses for IPv4
+func parseGeoIPAddress(value string) net.IP {
+	value = strings.TrimSpace(value)
+	if ip := net.ParseIP(value); ip != nil {
+		return ip.To16()
+	}
+	if number, err := strconv.ParseUint(value, 10, 32); err == nil {
+		return net.IPv4(byte(number>>24), byte(number>>16), byte(number>>8), byte(number)).To16()
+	}
+	return nil
+}
+
+// Country returns the country of an address, or "" if it is not in the database
+func (db *GeoIPDatabase) Country(ip string) string {
+	parsed := net.ParseIP(ip)
+	if db == nil || parsed == nil {
+		return ""
+	}
+	parsed = parsed.To16()
+
+	// Find the last range starting at or before the address
+	index := sort.Search(len(db.ranges), func(i int) bool { return bytes.Compare(db.ranges[i].start, parsed) > 0 }) - 1
+	if index >= 0 && bytes.Compare(parsed, db.ranges[index].end) <= 0 {
+		return db.ranges[index].country
+	}
+	return ""
+}
+
+// analyticsCountry resolves the visitor's country from CDN headers, falling back to the GeoIP database
+func (h *APIHandler) analyticsCountry(r *http.Request, anonymizedIP string) string {
+	for _, header := range countryHeaders {
+		// "XX" and "T1" are what Cloudflare reports for unknown and Tor visitors
+		if country := strings.ToUpper(strings.TrimSpace(r.Header.Get(header))); len(country) == 2 && country != "XX" && country != "T1" {
+			return country
+		}
+	}
+	return h.geoIP.Country(anonymizedIP)
+}
+
+// referrerDomain returns the referring host without "www.", or "" for direct visits and links from this site
+func referrerDomain(referrer, ownHost string) string {
+	parsed, err := url.Parse(referrer)
+	if err != nil || parsed.Hostname() == "" {
+		return ""
+	}
+	host := strings.TrimPrefix(strings.ToLower(parsed.Hostname()), "www.")
+	own, _, err := net.SplitHostPort(ownHost)
+	if err != nil {
+		own = ownHost
+	}
+	if host == strings.TrimPrefix(strings.ToLower(own), "www.") {
+		return ""
+	}
+	return host
+}
+
+// analyticsRollupFields are the dimensions visits can be rolled up by, and the event field holding each
+var analyticsRollupFields = map[string]string{
+	"country":  "$country",
+	"referrer": "$referrer_domain",
+}
+
+// AnalyticsRollup is the number of visits in an ISO week for a country or referrer
+type AnalyticsRollup struct {
+	Week     string `bson:"week" json:"week"` // ISO week, e.g. 2024-W07
+	Key      string `bson:"key" json:"key"`   // Country code or referrer domain; "unknown" or "direct" when missing
+	Visits   int    `bson:"visits" json:"visits"`
+	Visitors int    `bson:"visitors" json:"visitors"`
+}
+
+// GetAnalyticsRollup counts page views per ISO week by country or referrer domain
+func (ps *PortfolioService) GetAnalyticsRollup(ctx context.Context, since time.Time, dimension string) ([]AnalyticsRollup, error) {
+	missing := "unknown"
+	if dimension == "referrer" {
+		missing = "direct"
+	}
+	pipeline := []bson.M{
+		{"$match": bson.M{"created_at": bson.M{"$gte": since}, "type": "page_view"}},
+		{"$group": bson.M{
+			"_id": bson.M{
+				"week": bson.M{"$dateToString": bson.M{"format": "%G-W%V", "date": "$created_at"}},
+				"key":  bson.M{"$ifNull": bson.A{analyticsRollupFields[dimension], missing}},
+			},
+			"visits":   bson.M{"$sum": 1},
+			"visitors": bson.M{"$addToSet": "$visitor_hash"},
+		}},
+		{"$project": bson.M{
+			"_id":      0,
+			"week":     "$_id.week",
+			"key":      "$_id.key",
+			"visits":   1,
+			"visitors": bson.M{"$size": "$visitors"},
+		}},
+		{"$sort": bson.D{{Key: "week", Value: 1}, {Key: "visits", Value: -1}, {Key: "key", Value: 1}}},
+	}
+	cursor, err := ps.analytics.Aggregate(ctx, pipeline, timedAggregate())
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	rollups := []AnalyticsRollup{}
+	if err = cursor.All(ctx, &rollups); err != nil {
+		return nil, err
+	}
+	return rollups, nil
+}
+
+// Analytics rollup endpoint: GET /api/analytics/rollups/{country|referrer}?weeks= returns weekly visits
+// by country or referrer domain (admin only)
+func (h *APIHandler) handleAnalyticsRollup(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if r.Method != "GET" {
+		log.Printf("Date: %s | Route: /api/analytics/rollups | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !isAdminRequest(r) {
+		log.Printf("Date: %s | Route: /api/analytics/rollups | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	dimension := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/analytics/rollups/"), "/")
+	if _, ok := analyticsRollupFields[dimension]; !ok {
+		http.Error(w, "Rollup not found (expected country or referrer)", http.StatusNotFound)
+		return
+	}
+
+	var v Validator
+	weeks := v.QueryInt(r, "weeks", 12, 1, 104)
+	if err := v.Err(); err != nil {
+		writeInvalidInput(w, err)
+		return
+	}
+	// Start from the Monday of the earliest week so the first bucket is complete
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	monday := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+	since := monday.AddDate(0, 0, -7*(weeks-1))
+
+	rollups, err := h.service.GetAnalyticsRollup(context.Background(), since, dimension)
+	if err != nil {
+		log.Printf("Date: %s | Route: /api/analytics/rollups | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Date: %s | Route: /api/analytics/rollups | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rollups)
+}
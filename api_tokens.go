@@ -0,0 +1,322 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// API token scopes. A token only ever acts on its own author's portfolio.
+const (
+	TokenScopeRead          = "read"           // Read the author's unlisted and private content
+	TokenScopeProjectsWrite = "projects:write" // Also change the author's projects and their media
+)
+
+const (
+	// apiTokenPrefix marks portfolio tokens, so leaked ones are easy to recognize
+	apiTokenPrefix = "pat_"
+	// apiTokenUsageInterval is how often last_used_at is updated, to avoid a write on every request
+	apiTokenUsageInterval = time.Hour
+)
+
+// Audit log actions for API tokens
+const (
+	AuditTokenMinted  = "token_minted"
+	AuditTokenRevoked = "token_revoked"
+)
+
+// APIToken gives a co-hosted author delegated access to their own portfolio without the admin token.
+// Only a hash of the secret is stored; the secret is shown once, when the token is minted.
+type APIToken struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	AuthorID   primitive.ObjectID `bson:"author_id" json:"author_id"`
+	Name       string             `bson:"name" json:"name"` // What the token is for, e.g. "CI deploys"
+	Scope      string             `bson:"scope" json:"scope"`
+	Hash       string             `bson:"hash" json:"-"`                // SHA-256 of the secret
+	Hint       string             `bson:"hint" json:"hint"`             // Last characters of the secret, to tell tokens apart
+	CreatedBy  string             `bson:"created_by" json:"created_by"` // "admin" or "token <id>" of the token that minted it
+	ExpiresAt  *time.Time         `bson:"expires_at,omitempty" json:"expires_at,omitempty"`
+	LastUsedAt *time.Time         `bson:"last_used_at,omitempty" json:"last_used_at,omitempty"`
+	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// allows reports whether the token grants a scope; projects:write includes read
+func (t *APIToken) allows(scope string) bool {
+	return t.Scope == scope || t.Scope == TokenScopeProjectsWrite
+}
+
+func hashAPIToken(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// MintAPIToken stores a new token and returns its secret
+func (ps *PortfolioService) MintAPIToken(ctx context.Context, token *APIToken) (string, error) {
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	secret := apiTokenPrefix + hex.EncodeToString(random)
+	token.ID = primitive.NewObjectID()
+	token.Hash = hashAPIToken(secret)
+	token.Hint = secret[len(secret)-4:]
+	token.CreatedAt = time.Now()
+	if _, err := ps.apiTokens.InsertOne(ctx, token); err != nil {
+		return "", err
+	}
+	return secret, nil
+}
+
+// GetAPITokens lists an author's tokens, newest first
+func (ps *PortfolioService) GetAPITokens(ctx context.Context, authorID primitive.ObjectID) ([]APIToken, error) {
+	cursor, err := ps.apiTokens.Find(ctx, bson.M{"author_id": authorID}, boundedFind(options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})))
+	if err != nil {
+		return nil, err
+	}
+	tokens := []APIToken{}
+	if err := cursor.All(ctx, &tokens); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// RevokeAPIToken deletes one of an author's tokens
+func (ps *PortfolioService) RevokeAPIToken(ctx context.Context, authorID, tokenID primitive.ObjectID) error {
+	result, err := ps.apiTokens.DeleteOne(ctx, bson.M{"_id": tokenID, "author_id": authorID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// GetAPIToken returns a token by ID
+func (ps *PortfolioService) GetAPIToken(ctx context.Context, id primitive.ObjectID) (*APIToken, error) {
+	var token APIToken
+	if err := ps.apiTokens.FindOne(ctx, bson.M{"_id": id}).Decode(&token); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// FindAPIToken returns the unexpired token with a secret and records its use
+func (ps *PortfolioService) FindAPIToken(ctx context.Context, secret string) (*APIToken, error) {
+	var token APIToken
+	err := ps.apiTokens.FindOne(ctx, bson.M{"hash": hashAPIToken(secret)}).Decode(&token)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if token.ExpiresAt != nil && !token.ExpiresAt.After(now) {
+		return nil, mongo.ErrNoDocuments
+	}
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) > apiTokenUsageInterval {
+		if _, err := ps.apiTokens.UpdateOne(ctx, bson.M{"_id": token.ID}, bson.M{"$set": bson.M{"last_used_at": now}}); err != nil {
+			log.Printf("Error recording use of API token %s: %v", token.ID.Hex(), err)
+		}
+		token.LastUsedAt = &now
+	}
+	return &token, nil
+}
+
+// mayHoldAuthorAccess is a cheap check, made before the request body is read, that the request is an
+// admin's or carries an API token; authorAccess then checks the token against the author
+func mayHoldAuthorAccess(r *http.Request) bool {
+	return isAdminRequest(r) || strings.HasPrefix(r.Header.Get("Authorization"), "Bearer "+apiTokenPrefix)
+}
+
+// requestToken returns the author API token the request authenticates with, or nil
+func (h *APIHandler) requestToken(r *http.Request) *APIToken {
+	secret, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !found || !strings.HasPrefix(secret, apiTokenPrefix) {
+		return nil
+	}
+	token, err := h.service.FindAPIToken(r.Context(), secret)
+	if err != nil {
+		if err != mongo.ErrNoDocuments {
+			log.Printf("Error checking API token: %v", err)
+		}
+		return nil
+	}
+	return token
+}
+
+// authorAccess reports whether the request may act on an author's portfolio with a scope: admins always
+// may, author tokens only on their own author
+func (h *APIHandler) authorAccess(r *http.Request, authorID primitive.ObjectID, scope string) bool {
+	if isAdminRequest(r) {
+		return true
+	}
+	token := h.requestToken(r)
+	return token != nil && token.AuthorID == authorID && token.allows(scope)
+}
+
+// API token endpoints, for admins and for authors holding a projects:write token of their own:
+//   - GET /api/tokens lists the author's tokens (admins pass ?author_id=)
+//   - POST /api/tokens {"author_id", "name", "scope": "read"|"projects:write", "duration": "720h"} mints a
+//     token and returns its secret once; authors can only mint for themselves
+//   - DELETE /api/tokens/{id} revokes a token
+func (h *APIHandler) handleAPITokens(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	// Tokens manage their own author's tokens only; a read token cannot mint or revoke
+	admin := isAdminRequest(r)
+	var caller *APIToken
+	if !admin {
+		caller = h.requestToken(r)
+		if caller == nil || (r.Method != "GET" && !caller.allows(TokenScopeProjectsWrite)) {
+			log.Printf("Date: %s | Route: /api/tokens | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	ctx := r.Context()
+	id := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/tokens"), "/")
+	switch {
+	case id == "" && r.Method == "GET":
+		authorID := primitive.NilObjectID
+		if caller != nil {
+			authorID = caller.AuthorID
+		} else {
+			parsed, err := primitive.ObjectIDFromHex(r.URL.Query().Get("author_id"))
+			if err != nil {
+				writeInvalidInput(w, invalidField("author_id", "format", "author_id must be an ID"))
+				return
+			}
+			authorID = parsed
+		}
+		tokens, err := h.service.GetAPITokens(ctx, authorID)
+		if err != nil {
+			log.Printf("Date: %s | Route: /api/tokens | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		log.Printf("Date: %s | Route: /api/tokens | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(tokens)
+
+	case id == "" && r.Method == "POST":
+		var request struct {
+			AuthorID string `json:"author_id"`
+			Name     string `json:"name"`
+			Scope    string `json:"scope"`
+			Duration string `json:"duration"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, "Invalid JSON request", http.StatusBadRequest)
+			return
+		}
+		var v Validator
+		token := APIToken{Name: strings.TrimSpace(request.Name), Scope: request.Scope, CreatedBy: "admin"}
+		if caller != nil {
+			token.AuthorID = caller.AuthorID
+			token.CreatedBy = "token " + caller.ID.Hex()
+		} else {
+			parsed, err := primitive.ObjectIDFromHex(request.AuthorID)
+			v.Check(err == nil, "author_id", "format", "author_id must be an ID")
+			token.AuthorID = parsed
+		}
+		v.Required("name", token.Name)
+		v.MaxLength("name", token.Name, 100)
+		v.OneOf("scope", token.Scope, TokenScopeRead, TokenScopeProjectsWrite)
+		if request.Duration != "" {
+			duration, err := time.ParseDuration(request.Duration)
+			if v.Check(err == nil && duration > 0, "duration", "duration", "must be a positive duration such as 720h") {
+				expiresAt := time.Now().Add(duration)
+				token.ExpiresAt = &expiresAt
+			}
+		}
+		// A token never outlives the one that minted it
+		if caller != nil && caller.ExpiresAt != nil && (token.ExpiresAt == nil || token.ExpiresAt.After(*caller.ExpiresAt)) {
+			token.ExpiresAt = caller.ExpiresAt
+		}
+		if err := v.Err(); err != nil {
+			writeInvalidInput(w, err)
+			return
+		}
+		if caller == nil {
+			if _, err := h.service.GetAuthorByID(ctx, token.AuthorID); err == mongo.ErrNoDocuments {
+				writeInvalidInput(w, invalidField("author_id", "exists", "author_id does not match an author"))
+				return
+			} else if err != nil {
+				log.Printf("Date: %s | Route: /api/tokens | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+
+		secret, err := h.service.MintAPIToken(ctx, &token)
+		if err != nil {
+			log.Printf("Date: %s | Route: /api/tokens | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if err := h.service.RecordAudit(ctx, &AuditEntry{Action: AuditTokenMinted, Actor: token.CreatedBy, Reason: token.Scope + " token " + token.ID.Hex() + " for author " + token.AuthorID.Hex(), ExpiresAt: token.ExpiresAt}); err != nil {
+			log.Printf("Error recording audit entry: %v", err)
+		}
+
+		log.Printf("Date: %s | Route: /api/tokens | Status: CREATED | GPT Model: %s", currentTime, gptModel)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]interface{}{"token": token, "secret": secret})
+
+	case id != "" && r.Method == "DELETE":
+		tokenID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			writeInvalidInput(w, invalidField("id", "format", "id must be an ID"))
+			return
+		}
+		token, err := h.service.GetAPIToken(ctx, tokenID)
+		if err == nil && caller != nil && token.AuthorID != caller.AuthorID {
+			err = mongo.ErrNoDocuments
+		}
+		if err == nil {
+			err = h.service.RevokeAPIToken(ctx, token.AuthorID, token.ID)
+		}
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Token not found", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			log.Printf("Date: %s | Route: /api/tokens/{id} | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		actor := "admin"
+		if caller != nil {
+			actor = "token " + caller.ID.Hex()
+		}
+		if err := h.service.RecordAudit(ctx, &AuditEntry{Action: AuditTokenRevoked, Actor: actor, Reason: token.Scope + " token " + token.ID.Hex() + " for author " + token.AuthorID.Hex()}); err != nil {
+			log.Printf("Error recording audit entry: %v", err)
+		}
+		log.Printf("Date: %s | Route: /api/tokens/{id} | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		log.Printf("Date: %s | Route: /api/tokens | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Audit log actions
+const (
+	AuditIPRuleSet     = "ip_rule_set"     // An admin banned or whitelisted an IP
+	AuditIPRuleDeleted = "ip_rule_deleted" // An admin lifted an IP rule
+	AuditIPAutoBan     = "ip_auto_ban"     // An IP was banned for repeated violations
+)
+
+// AuditEntry records a security-relevant action, by an admin or by the server itself
+type AuditEntry struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Action    string             `bson:"action" json:"action"`
+	Actor     string             `bson:"actor" json:"actor"` // "admin", "system" or "token <id>"
+	IP        string             `bson:"ip,omitempty" json:"ip,omitempty"`
+	Reason    string             `bson:"reason,omitempty" json:"reason,omitempty"`
+	ExpiresAt *time.Time         `bson:"expires_at,omitempty" json:"expires_at,omitempty"` // End of a temporary ban
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// RecordAudit stores an audit entry and logs it
+func (ps *PortfolioService) RecordAudit(ctx context.Context, entry *AuditEntry) error {
+	entry.ID = primitive.NewObjectID()
+	entry.CreatedAt = time.Now()
+	log.Printf("Audit: %s by %s (ip %s): %s", entry.Action, entry.Actor, entry.IP, entry.Reason)
+	_, err := ps.auditLog.InsertOne(ctx, entry)
+	return err
+}
+
+// GetAuditLog lists recent entries, newest first, optionally only those of an action or an IP
+func (ps *PortfolioService) GetAuditLog(ctx context.Context, action, ip string, limit int64) ([]AuditEntry, error) {
+	filter := bson.M{}
+	if action != "" {
+		filter["action"] = action
+	}
+	if ip != "" {
+		filter["ip"] = ip
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(limit)
+	cursor, err := ps.auditLog.Find(ctx, filter, boundedFind(opts))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	entries := []AuditEntry{}
+	if err = cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// CountAuditEntries counts the entries of an action for an IP since a time
+func (ps *PortfolioService) CountAuditEntries(ctx context.Context, action, ip string, since time.Time) (int64, error) {
+	return ps.auditLog.CountDocuments(ctx, bson.M{"action": action, "ip": ip, "created_at": bson.M{"$gte": since}})
+}
+
+// Audit log endpoint: GET /api/admin/audit?action=&ip=&limit= lists recent audit entries (admin only)
+func (h *APIHandler) handleAuditLog(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if r.Method != "GET" {
+		log.Printf("Date: %s | Route: /api/admin/audit | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !isAdminRequest(r) {
+		log.Printf("Date: %s | Route: /api/admin/audit | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var v Validator
+	limit := int64(v.QueryInt(r, "limit", 50, 1, 500))
+	action := r.URL.Query().Get("action")
+	if action != "" {
+		v.OneOf("action", action, AuditIPRuleSet, AuditIPRuleDeleted, AuditIPAutoBan, AuditTokenMinted, AuditTokenRevoked)
+	}
+	if err := v.Err(); err != nil {
+		writeInvalidInput(w, err)
+		return
+	}
+
+	entries, err := h.service.GetAuditLog(r.Context(), action, r.URL.Query().Get("ip"), limit)
+	if err != nil {
+		log.Printf("Date: %s | Route: /api/admin/audit | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Date: %s | Route: /api/admin/audit | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entries)
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// autoBanHistory is how far back earlier automatic bans of an IP count towards escalation
+	autoBanHistory = 30 * 24 * time.Hour
+	// autoBanTimeout bounds the store and database calls made after a violating response
+	autoBanTimeout = 10 * time.Second
+)
+
+// autoBanDurations are the ban lengths for an IP's first, second, ... automatic ban within autoBanHistory;
+// later bans use the last
+var autoBanDurations = []time.Duration{15 * time.Minute, time.Hour, 24 * time.Hour, 7 * 24 * time.Hour}
+
+// AutoBanner counts each client's rate limit (429) and validation (400) responses and bans an IP for a
+// while once it has too many within a window, so a persistent abuser is stopped without an admin. Counts
+// live in the state store next to the rate limits; bans are IP rules, so every replica enforces them.
+type AutoBanner struct {
+	service    *PortfolioService
+	rules      *IPRules
+	violations int           // Violations within window that trigger a ban; 0 disables banning
+	window     time.Duration // At most an hour, the longest window the state store keeps
+	mutex      sync.Mutex    // Keeps concurrent violations of one IP from banning it twice
+}
+
+// NewAutoBanner creates the banner from AUTO_BAN_VIOLATIONS (default 20, 0 disables) and AUTO_BAN_WINDOW
+// (default 10m, at most 1h)
+func NewAutoBanner(service *PortfolioService, rules *IPRules) *AutoBanner {
+	banner := &AutoBanner{service: service, rules: rules, violations: 20, window: 10 * time.Minute}
+	if value := os.Getenv("AUTO_BAN_VIOLATIONS"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			log.Printf("Invalid AUTO_BAN_VIOLATIONS %q, using %d", value, banner.violations)
+		} else {
+			banner.violations = parsed
+		}
+	}
+	if value := os.Getenv("AUTO_BAN_WINDOW"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 || parsed > time.Hour {
+			log.Printf("Invalid AUTO_BAN_WINDOW %q, using %s", value, banner.window)
+		} else {
+			banner.window = parsed
+		}
+	}
+	return banner
+}
+
+// isViolation reports whether a response status counts against the client
+func isViolation(status int) bool {
+	return status == http.StatusTooManyRequests || status == http.StatusBadRequest
+}
+
+// Middleware counts the violating responses of every client; whitelisted IPs and admins are never banned
+func (b *AutoBanner) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if b.violations == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+		recorder := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(recorder, r)
+
+		if !isViolation(recorder.status) || isAdminRequest(r) {
+			return
+		}
+		ip := trustedClientIP(r)
+		if b.rules.Action(ip) != "" {
+			return
+		}
+		// Recorded after the response, so the client does not wait for the store
+		go b.recordViolation(ip, fmt.Sprintf("%d on %s %s", recorder.status, r.Method, r.URL.Path))
+	})
+}
+
+// recordViolation counts a violation and bans the IP when it reaches the threshold
+func (b *AutoBanner) recordViolation(ip, last string) {
+	ctx, cancel := context.WithTimeout(context.Background(), autoBanTimeout)
+	defer cancel()
+
+	// The counter admits violations-1 entries per window, so refusing one means this is the violations-th
+	underLimit, err := b.service.state.Allow(ctx, "violations:"+ip, []RateLimit{{Window: b.window, Max: b.violations - 1}})
+	if err != nil {
+		log.Printf("Error counting violations of %s: %v", ip, err)
+		return
+	}
+	if underLimit {
+		return
+	}
+	reason := fmt.Sprintf("%d violations within %s, last %s", b.violations, b.window, last)
+	if err := b.ban(ctx, ip, reason); err != nil {
+		log.Printf("Error banning %s: %v", ip, err)
+	}
+}
+
+// ban bans an IP for longer each time it was automatically banned recently, and records the ban in the
+// audit log. Honeypot routes call it directly.
+func (b *AutoBanner) ban(ctx context.Context, ip, reason string) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.rules.Action(ip) != "" {
+		return nil // Banned by a concurrent violation, or whitelisted meanwhile
+	}
+
+	previous, err := b.service.CountAuditEntries(ctx, AuditIPAutoBan, ip, time.Now().Add(-autoBanHistory))
+	if err != nil {
+		return err
+	}
+	duration := autoBanDurations[len(autoBanDurations)-1]
+	if previous < int64(len(autoBanDurations)) {
+		duration = autoBanDurations[previous]
+	}
+
+	rule := IPRule{
+		IP:        ip,
+		Action:    IPRuleBan,
+		Reason:    fmt.Sprintf("Automatic ban %d: %s", previous+1, reason),
+		CreatedAt: time.Now(),
+	}
+	expiresAt := rule.CreatedAt.Add(duration)
+	rule.ExpiresAt = &expiresAt
+	if err := b.service.SetIPRule(ctx, &rule); err != nil {
+		return err
+	}
+	b.rules.set(rule)
+
+	return b.service.RecordAudit(ctx, &AuditEntry{
+		Action:    AuditIPAutoBan,
+		Actor:     "system",
+		IP:        ip,
+		Reason:    rule.Reason,
+		ExpiresAt: rule.ExpiresAt,
+	})
+}
@@ -0,0 +1,421 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// TimeSlot is a bookable period of free time
+type TimeSlot struct {
+	Start      time.Time `json:"start"`
+	End        time.Time `json:"end"`
+	BookingURL string    `json:"booking_url,omitempty"` // Direct link for this slot, when the source provides one
+}
+
+// Availability combines the status from the now entry with free calendar slots
+type Availability struct {
+	Status     string     `json:"status"` // available, limited, unavailable, or unknown without a now entry
+	Note       string     `json:"note,omitempty"`
+	UpdatedAt  *time.Time `json:"updated_at,omitempty"`
+	Slots      []TimeSlot `json:"slots"`
+	Source     string     `json:"source,omitempty"` // "calendly" or "ics"; empty when no calendar is configured
+	Timezone   string     `json:"timezone"`
+	BookingURL string     `json:"booking_url,omitempty"`
+}
+
+// slotSource lists free time from a scheduling calendar
+type slotSource interface {
+	Name() string
+	FreeSlots(ctx context.Context, from, to time.Time) ([]TimeSlot, error)
+}
+
+// AvailabilityService fetches free slots with caching so the calendar isn't queried on every request
+type AvailabilityService struct {
+	source     slotSource
+	bookingURL string
+	days       int
+	location   *time.Location
+	cache      *TTLCache[[]TimeSlot]
+}
+
+// NewAvailabilityService configures the slot source from CALENDLY_TOKEN/CALENDLY_EVENT_TYPE or
+// AVAILABILITY_ICS_URL. The service is always created so the status can be served without a calendar.
+func NewAvailabilityService(store StateStore) *AvailabilityService {
+	service := &AvailabilityService{
+		bookingURL: os.Getenv("BOOKING_URL"),
+		days:       14,
+		location:   time.UTC,
+		cache:      NewTTLCache[[]TimeSlot](store, "availability"),
+	}
+	if value := os.Getenv("AVAILABILITY_DAYS"); value != "" {
+		if days, err := strconv.Atoi(value); err == nil && days > 0 && days <= 60 {
+			service.days = days
+		}
+	}
+	if value := os.Getenv("AVAILABILITY_TIMEZONE"); value != "" {
+		if location, err := time.LoadLocation(value); err == nil {
+			service.location = location
+		} else {
+			log.Printf("Invalid AVAILABILITY_TIMEZONE %q, using UTC", value)
+		}
+	}
+
+	httpClient := &http.Client{Timeout: 30 * time.Second}
+	if token, eventType := os.Getenv("CALENDLY_TOKEN"), os.Getenv("CALENDLY_EVENT_TYPE"); token != "" && eventType != "" {
+		service.source = &calendlySource{httpClient: httpClient, token: token, eventType: eventType}
+	} else if icsURL := os.Getenv("AVAILABILITY_ICS_URL"); icsURL != "" {
+		startHour, endHour := 9, 17
+		if value := os.Getenv("AVAILABILITY_HOURS"); value != "" {
+			if _, err := fmt.Sscanf(value, "%d-%d", &startHour, &endHour); err != nil || startHour < 0 || endHour > 24 || startHour >= endHour {
+				log.Printf("Invalid AVAILABILITY_HOURS %q, using 9-17", value)
+				startHour, endHour = 9, 17
+			}
+		}
+		service.source = &icsSource{
+			httpClient: httpClient,
+			url:        icsURL,
+			location:   service.location,
+			startHour:  startHour,
+			endHour:    endHour,
+			slotLength: 30 * time.Minute,
+		}
+	} else {
+		log.Println("No calendar configured (CALENDLY_TOKEN/CALENDLY_EVENT_TYPE or AVAILABILITY_ICS_URL), availability will not list free slots")
+	}
+	return service
+}
+
+// GetSlots returns cached free slots, refreshing them from the source when expired
+func (s *AvailabilityService) GetSlots(ctx context.Context) ([]TimeSlot, error) {
+	if s.source == nil {
+		return []TimeSlot{}, nil
+	}
+	if cached, ok := s.cache.Get("slots"); ok {
+		return cached, nil
+	}
+
+	from := time.Now()
+	slots, err := s.source.FreeSlots(ctx, from, from.AddDate(0, 0, s.days))
+	if err != nil {
+		if stale, ok := s.cache.GetStale("slots"); ok {
+			log.Printf("Availability refresh failed, serving stale data: %v", err)
+			return stale, nil
+		}
+		return nil, err
+	}
+
+	s.cache.Set("slots", slots, 15*time.Minute)
+	return slots, nil
+}
+
+// Calendly source, using the available times of a single event type
+type calendlySource struct {
+	httpClient *http.Client
+	token      string
+	eventType  string // Event type URI, e.g. https://api.calendly.com/event_types/XXXX
+}
+
+func (c *calendlySource) Name() string { return "calendly" }
+
+func (c *calendlySource) FreeSlots(ctx context.Context, from, to time.Time) ([]TimeSlot, error) {
+	slots := []TimeSlot{}
+	// Calendly accepts at most 7 days per request, and the start must be in the future
+	start := from.Add(time.Minute)
+	for start.Before(to) {
+		end := start.AddDate(0, 0, 7)
+		if end.After(to) {
+			end = to
+		}
+
+		query := url.Values{}
+		query.Set("event_type", c.eventType)
+		query.Set("start_time", start.UTC().Format(time.RFC3339))
+		query.Set("end_time", end.UTC().Format(time.RFC3339))
+		req, err := http.NewRequest("GET", "https://api.calendly.com/event_type_available_times?"+query.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+c.token)
+
+		var payload struct {
+			Collection []struct {
+				Status        string    `json:"status"`
+				StartTime     time.Time `json:"start_time"`
+				SchedulingURL string    `json:"scheduling_url"`
+			} `json:"collection"`
+		}
+		if err := getJSON(ctx, c.httpClient, req, &payload); err != nil {
+			return nil, err
+		}
+		for _, available := range payload.Collection {
+			if available.Status != "available" {
+				continue
+			}
+			// Calendly reports start times only; slots are shown at the default half hour
+			slots = append(slots, TimeSlot{
+				Start:      available.StartTime,
+				End:        available.StartTime.Add(30 * time.Minute),
+				BookingURL: available.SchedulingURL,
+			})
+		}
+		start = end
+	}
+	return slots, nil
+}
+
+// ICS source: a calendar of busy events (such as a free/busy export) subtracted from working hours.
+// Recurring events are not expanded, so only their first occurrence counts as busy.
+type icsSource struct {
+	httpClient *http.Client
+	url        string
+	location   *time.Location
+	startHour  int
+	endHour    int
+	slotLength time.Duration
+}
+
+func (c *icsSource) Name() string { return "ics" }
+
+type busyPeriod struct {
+	start time.Time
+	end   time.Time
+}
+
+func (c *icsSource) FreeSlots(ctx context.Context, from, to time.Time) ([]TimeSlot, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("calendar returned status %d", resp.StatusCode)
+	}
+
+	busy, err := parseICSBusy(io.LimitReader(resp.Body, 5<<20), c.location)
+	if err != nil {
+		return nil, err
+	}
+	return freeSlotsBetween(busy, from, to, c.location, c.startHour, c.endHour, c.slotLength), nil
+}
+
+// parseICSBusy reads the busy periods of the VEVENTs in an iCalendar feed, skipping cancelled and
+// transparent (free) events
+func parseICSBusy(r io.Reader, location *time.Location) ([]busyPeriod, error) {
+	// Unfold continuation lines, which start with a space or tab
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(line) > 0 && (line[0] == ' ' || line[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	var busy []busyPeriod
+	var start, end *time.Time
+	inEvent, skip := false, false
+	for _, line := range lines {
+		switch {
+		case line == "BEGIN:VEVENT":
+			inEvent, skip, start, end = true, false, nil, nil
+		case line == "END:VEVENT":
+			if inEvent && !skip && start != nil {
+				period := busyPeriod{start: *start, end: start.Add(time.Hour)}
+				if end != nil {
+					period.end = *end
+				}
+				busy = append(busy, period)
+			}
+			inEvent = false
+		case inEvent:
+			name, value, ok := strings.Cut(line, ":")
+			if !ok {
+				continue
+			}
+			property, params, _ := strings.Cut(name, ";")
+			switch property {
+			case "DTSTART":
+				start = parseICSTime(value, params, location)
+			case "DTEND":
+				end = parseICSTime(value, params, location)
+			case "TRANSP":
+				skip = skip || value == "TRANSPARENT"
+			case "STATUS":
+				skip = skip || value == "CANCELLED"
+			}
+		}
+	}
+	return busy, nil
+}
+
+// parseICSTime handles UTC, TZID-qualified, floating and all-day (VALUE=DATE) times
+func parseICSTime(value, params string, location *time.Location) *time.Time {
+	for _, param := range strings.Split(params, ";") {
+		if tzid, ok := strings.CutPrefix(param, "TZID="); ok {
+			if loaded, err := time.LoadLocation(strings.Trim(tzid, `"`)); err == nil {
+				location = loaded
+			}
+		}
+	}
+
+	layouts := []string{"20060102T150405Z", "20060102T150405", "20060102"}
+	for _, layout := range layouts {
+		var parsed time.Time
+		var err error
+		if strings.HasSuffix(layout, "Z") {
+			parsed, err = time.Parse(layout, value)
+		} else {
+			parsed, err = time.ParseInLocation(layout, value, location)
+		}
+		if err == nil {
+			return &parsed
+		}
+	}
+	return nil
+}
+
+// freeSlotsBetween splits weekday working hours into slots and keeps those that overlap no busy period
+func freeSlotsBetween(busy []busyPeriod, from, to time.Time, location *time.Location, startHour, endHour int, slotLength time.Duration) []TimeSlot {
+	sort.Slice(busy, func(i, j int) bool { return busy[i].start.Before(busy[j].start) })
+
+	slots := []TimeSlot{}
+	day := time.Date(from.In(location).Year(), from.In(location).Month(), from.In(location).Day(), 0, 0, 0, 0, location)
+	for ; day.Before(to); day = day.AddDate(0, 0, 1) {
+		if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+			continue
+		}
+		dayEnd := day.Add(time.Duration(endHour) * time.Hour)
+		for slotStart := day.Add(time.Duration(startHour) * time.Hour); !slotStart.Add(slotLength).After(dayEnd); slotStart = slotStart.Add(slotLength) {
+			slotEnd := slotStart.Add(slotLength)
+			if slotStart.Before(from) || slotEnd.After(to) {
+				continue
+			}
+			free := true
+			for _, period := range busy {
+				if period.start.After(slotEnd) {
+					break
+				}
+				if period.start.Before(slotEnd) && period.end.After(slotStart) {
+					free = false
+					break
+				}
+			}
+			if free {
+				slots = append(slots, TimeSlot{Start: slotStart, End: slotEnd})
+			}
+		}
+	}
+	return slots
+}
+
+// Availability endpoint: GET /api/availability returns the hiring/freelance status and upcoming free slots
+func (h *APIHandler) handleAvailability(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if r.Method != "GET" {
+		log.Printf("Date: %s | Route: /api/availability | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	clientIP := trustedClientIP(r)
+	if !h.statsLimiter.IsAllowed(clientIP) {
+		log.Printf("Date: %s | Route: /api/availability | Status: RATE_LIMITED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Rate limit exceeded. Please wait before making another request.", http.StatusTooManyRequests)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	availability := Availability{
+		Status:   "unknown",
+		Slots:    []TimeSlot{},
+		Timezone: h.availability.location.String(),
+	}
+	now, err := h.service.GetNow(ctx, primitive.NilObjectID)
+	if err == nil {
+		availability.Status = now.Availability
+		availability.Note = now.AvailabilityNote
+		availability.UpdatedAt = &now.UpdatedAt
+	} else if err != mongo.ErrNoDocuments {
+		log.Printf("Date: %s | Route: /api/availability | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Slots are only offered while open to new work
+	if availability.Status != "unavailable" {
+		slots, err := h.availability.GetSlots(ctx)
+		if err != nil {
+			log.Printf("Error fetching availability slots: %v", err)
+		} else {
+			availability.Slots = slots
+		}
+		if h.availability.source != nil {
+			availability.Source = h.availability.source.Name()
+		}
+		if h.availability.bookingURL != "" {
+			availability.BookingURL = "/api/availability/book"
+		}
+	}
+
+	log.Printf("Date: %s | Route: /api/availability | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(availability)
+}
+
+// Booking redirect: GET /api/availability/book sends the visitor to the configured BOOKING_URL
+func (h *APIHandler) handleAvailabilityBook(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.availability.bookingURL == "" {
+		log.Printf("Date: %s | Route: /api/availability/book | Status: DISABLED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Booking is not configured", http.StatusNotFound)
+		return
+	}
+
+	log.Printf("Date: %s | Route: /api/availability/book | Status: REDIRECT | GPT Model: %s", currentTime, gptModel)
+	http.Redirect(w, r, h.availability.bookingURL, http.StatusFound)
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Award query methods
+func (ps *PortfolioService) GetAwards(ctx context.Context, category string, author *Author) ([]Award, error) {
+	filter := hostScope(bson.M{}, "author_id", author)
+	if category != "" {
+		filter["category"] = strings.ToLower(category)
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "awarded_at", Value: -1}})
+	cursor, err := ps.awards.Find(ctx, filter, boundedFind(opts))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var awards []Award
+	if err = cursor.All(ctx, &awards); err != nil {
+		return nil, err
+	}
+	return awards, nil
+}
+
+func (ps *PortfolioService) InsertAward(ctx context.Context, award *Award) error {
+	if award.ID.IsZero() {
+		award.ID = primitive.NewObjectID()
+	}
+	award.stampCreated()
+	_, err := ps.awards.InsertOne(ctx, award)
+	return err
+}
+
+func (ps *PortfolioService) CountAwards(ctx context.Context) (int64, error) {
+	return ps.awards.CountDocuments(ctx, bson.M{})
+}
+
+// validateAward checks a manually entered award
+func validateAward(award *Award) error {
+	var v Validator
+	v.Required("title", award.Title)
+	v.Required("issuer", award.Issuer)
+	v.RequiredTime("awarded_at", award.AwardedAt)
+	v.HTTPURL("url", award.URL)
+	return v.Err()
+}
+
+// Awards endpoints: GET lists awards (?category= filters), POST creates one (admin only)
+func (h *APIHandler) handleAwards(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	ctx := context.Background()
+
+	switch r.Method {
+	case "GET":
+		awards, err := h.service.GetAwards(ctx, r.URL.Query().Get("category"), h.hostAuthor(r))
+		if err != nil {
+			log.Printf("Date: %s | Route: /api/awards | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Date: %s | Route: /api/awards | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+		writeTimestampedList(w, r, awards)
+
+	case "POST":
+		if !isAdminRequest(r) {
+			log.Printf("Date: %s | Route: /api/awards | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		var award Award
+		if err := json.NewDecoder(r.Body).Decode(&award); err != nil {
+			log.Printf("Date: %s | Route: /api/awards | Status: BAD_REQUEST | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, "Invalid JSON request", http.StatusBadRequest)
+			return
+		}
+		if err := validateAward(&award); err != nil {
+			log.Printf("Date: %s | Route: /api/awards | Status: INVALID_INPUT | GPT Model: %s", currentTime, gptModel)
+			writeInvalidInput(w, err)
+			return
+		}
+
+		award.ID = primitive.NilObjectID
+		award.Category = strings.ToLower(strings.TrimSpace(award.Category))
+		if err := h.service.InsertAward(ctx, &award); err != nil {
+			log.Printf("Date: %s | Route: /api/awards | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Date: %s | Route: /api/awards | Status: CREATED | GPT Model: %s", currentTime, gptModel)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(award)
+
+	default:
+		log.Printf("Date: %s | Route: /api/awards | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
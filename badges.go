@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// TechnologyCount is the number of projects using a technology
+type TechnologyCount struct {
+	Name  string `bson:"_id" json:"name"`
+	Count int    `bson:"count" json:"count"`
+}
+
+// GetTopTechnologies returns the most used technologies across all projects
+func (ps *PortfolioService) GetTopTechnologies(ctx context.Context, limit int) ([]TechnologyCount, error) {
+	pipeline := []bson.M{
+		{"$match": listedProjectsFilter()},
+		{"$unwind": "$technologies_used"},
+		{"$group": bson.M{"_id": "$technologies_used", "count": bson.M{"$sum": 1}}},
+		{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}},
+		{"$limit": limit},
+	}
+	cursor, err := ps.projects.Aggregate(ctx, pipeline, timedAggregate())
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var technologies []TechnologyCount
+	if err = cursor.All(ctx, &technologies); err != nil {
+		return nil, err
+	}
+	return technologies, nil
+}
+
+var badgeColorPattern = regexp.MustCompile(`^([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+var badgeNamedColors = map[string]string{
+	"brightgreen": "#4c1",
+	"green":       "#97ca00",
+	"yellow":      "#dfb317",
+	"orange":      "#fe7d37",
+	"red":         "#e05d44",
+	"blue":        "#007ec6",
+	"lightgrey":   "#9f9f9f",
+	"pink":        "#F5A9B8",
+	"cyan":        "#5BCEFA",
+}
+
+// badgeTextWidth approximates the rendered width of Verdana 11px text, which shields.io badges use
+func badgeTextWidth(text string) int {
+	width := 0
+	for _, r := range text {
+		switch {
+		case strings.ContainsRune("ijlI.,:;'!|", r):
+			width += 4
+		case strings.ContainsRune("ft()[] ", r):
+			width += 5
+		case strings.ContainsRune("mwMW@%", r):
+			width += 11
+		case r >= 'A' && r <= 'Z':
+			width += 8
+		default:
+			width += 7
+		}
+	}
+	return width
+}
+
+// resolveBadgeColor accepts a shields.io color name or a hex value without '#'
+func resolveBadgeColor(color, fallback string) string {
+	if named, ok := badgeNamedColors[strings.ToLower(color)]; ok {
+		return named
+	}
+	if badgeColorPattern.MatchString(color) {
+		return "#" + color
+	}
+	return fallback
+}
+
+// renderBadgeSVG renders a flat shields.io-style badge
+func renderBadgeSVG(label, message, color string) string {
+	labelWidth := badgeTextWidth(label) + 10
+	messageWidth := badgeTextWidth(message) + 10
+	totalWidth := labelWidth + messageWidth
+	label = html.EscapeString(label)
+	message = html.EscapeString(message)
+
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%[1]d" height="20" role="img" aria-label="%[4]s: %[5]s">`+
+		`<title>%[4]s: %[5]s</title>`+
+		`<linearGradient id="s" x2="0" y2="100%%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>`+
+		`<clipPath id="r"><rect width="%[1]d" height="20" rx="3" fill="#fff"/></clipPath>`+
+		`<g clip-path="url(#r)"><rect width="%[2]d" height="20" fill="#555"/><rect x="%[2]d" width="%[3]d" height="20" fill="%[6]s"/><rect width="%[1]d" height="20" fill="url(#s)"/></g>`+
+		`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">`+
+		`<text x="%[7]d" y="15" fill="#010101" fill-opacity=".3">%[4]s</text><text x="%[7]d" y="14">%[4]s</text>`+
+		`<text x="%[8]d" y="15" fill="#010101" fill-opacity=".3">%[5]s</text><text x="%[8]d" y="14">%[5]s</text>`+
+		`</g></svg>`,
+		totalWidth, labelWidth, messageWidth, label, message, color, labelWidth/2, labelWidth+messageWidth/2)
+}
+
+// Badge endpoints: /api/badges/{name}?label=...&color=...
+func (h *APIHandler) handleBadges(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if r.Method != "GET" {
+		log.Printf("Date: %s | Route: /api/badges | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	name := strings.TrimSuffix(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/badges/"), "/"), ".svg")
+	ctx := context.Background()
+
+	var label, message, color string
+	switch name {
+	case "projects":
+		count, err := h.service.CountProjects(ctx, false)
+		if err != nil {
+			log.Printf("Date: %s | Route: /api/badges/projects | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		label, message, color = "projects", fmt.Sprintf("%d", count), badgeNamedColors["blue"]
+	case "top-tech":
+		technologies, err := h.service.GetTopTechnologies(ctx, 3)
+		if err != nil {
+			log.Printf("Date: %s | Route: /api/badges/top-tech | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		names := []string{}
+		for _, technology := range technologies {
+			names = append(names, technology.Name)
+		}
+		message = strings.Join(names, " | ")
+		if message == "" {
+			message = "none"
+		}
+		label, color = "top tech", badgeNamedColors["pink"]
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	if customLabel := r.URL.Query().Get("label"); customLabel != "" {
+		label = customLabel
+	}
+	color = resolveBadgeColor(r.URL.Query().Get("color"), color)
+
+	log.Printf("Date: %s | Route: /api/badges/%s | Status: SUCCESS | GPT Model: %s", currentTime, name, gptModel)
+	w.Header().Set("Content-Type", "image/svg+xml")
+	// Short cache so README proxies (e.g. GitHub camo) pick up changes reasonably quickly
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	w.Write([]byte(renderBadgeSVG(label, message, color)))
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Maximum number of IDs across all collections in one batch request
+const maxBatchIDs = 200
+
+// batchCollection describes a collection that can be fetched through /api/batch
+type batchCollection struct {
+	collection func(ps *PortfolioService) *mongo.Collection
+	newSlice   func() interface{}      // Pointer to an empty slice of the document type
+	filter     func(admin bool) bson.M // Restricts what the client may see; nil when every document is public
+}
+
+var batchCollections = map[string]batchCollection{
+	"authors": {
+		collection: func(ps *PortfolioService) *mongo.Collection { return ps.authors },
+		newSlice:   func() interface{} { return &[]Author{} },
+	},
+	"projects": {
+		collection: func(ps *PortfolioService) *mongo.Collection { return ps.projects },
+		newSlice:   func() interface{} { return &[]Project{} },
+		// Unlisted projects are reachable by ID like they are by slug; private ones only by admins
+		filter: func(admin bool) bson.M {
+			if admin {
+				return nil
+			}
+			return bson.M{"visibility": bson.M{"$ne": "private"}}
+		},
+	},
+	"education": {
+		collection: func(ps *PortfolioService) *mongo.Collection { return ps.education },
+		newSlice:   func() interface{} { return &[]Education{} },
+	},
+	"resumes": {
+		collection: func(ps *PortfolioService) *mongo.Collection { return ps.resumes },
+		newSlice:   func() interface{} { return &[]Resume{} },
+	},
+	"experience": {
+		collection: func(ps *PortfolioService) *mongo.Collection { return ps.experience },
+		newSlice:   func() interface{} { return &[]Experience{} },
+	},
+	"certifications": {
+		collection: func(ps *PortfolioService) *mongo.Collection { return ps.certifications },
+		newSlice:   func() interface{} { return &[]Certification{} },
+	},
+	"posts": {
+		collection: func(ps *PortfolioService) *mongo.Collection { return ps.posts },
+		newSlice:   func() interface{} { return &[]Post{} },
+		filter: func(admin bool) bson.M {
+			if admin {
+				return nil
+			}
+			return publishedPostsFilter()
+		},
+	},
+	"testimonials": {
+		collection: func(ps *PortfolioService) *mongo.Collection { return ps.testimonials },
+		newSlice:   func() interface{} { return &[]Testimonial{} },
+		filter: func(admin bool) bson.M {
+			if admin {
+				return nil
+			}
+			return bson.M{"approved": true}
+		},
+	},
+	"awards": {
+		collection: func(ps *PortfolioService) *mongo.Collection { return ps.awards },
+		newSlice:   func() interface{} { return &[]Award{} },
+	},
+	"publications": {
+		collection: func(ps *PortfolioService) *mongo.Collection { return ps.publications },
+		newSlice:   func() interface{} { return &[]Publication{} },
+	},
+	"talks": {
+		collection: func(ps *PortfolioService) *mongo.Collection { return ps.talks },
+		newSlice:   func() interface{} { return &[]Talk{} },
+	},
+	"skills": {
+		collection: func(ps *PortfolioService) *mongo.Collection { return ps.skills },
+		newSlice:   func() interface{} { return &[]Skill{} },
+	},
+}
+
+// parseBatchRequest validates a batch request and converts its IDs, dropping duplicates
+func parseBatchRequest(request map[string][]string) (map[string][]primitive.ObjectID, error) {
+	if len(request) == 0 {
+		return nil, invalidField("", "required", "request must list IDs for at least one collection")
+	}
+
+	ids := make(map[string][]primitive.ObjectID)
+	total := 0
+	for name, hexIDs := range request {
+		if _, ok := batchCollections[name]; !ok {
+			names := make([]string, 0, len(batchCollections))
+			for known := range batchCollections {
+				names = append(names, known)
+			}
+			sort.Strings(names)
+			return nil, invalidField(name, "one_of", fmt.Sprintf("unknown collection %q, expected one of %s", name, strings.Join(names, ", ")))
+		}
+
+		ids[name] = []primitive.ObjectID{}
+		seen := make(map[primitive.ObjectID]bool)
+		for _, hexID := range hexIDs {
+			id, err := primitive.ObjectIDFromHex(hexID)
+			if err != nil {
+				return nil, invalidField(name, "format", fmt.Sprintf("invalid ID %q in %s", hexID, name))
+			}
+			if !seen[id] {
+				seen[id] = true
+				ids[name] = append(ids[name], id)
+			}
+		}
+		total += len(ids[name])
+	}
+	if total > maxBatchIDs {
+		return nil, invalidField("", "max_items", fmt.Sprintf("at most %d IDs can be fetched at once, got %d", maxBatchIDs, total))
+	}
+	return ids, nil
+}
+
+// GetBatch fetches documents by ID from several collections. Missing IDs are left out of the results.
+func (ps *PortfolioService) GetBatch(ctx context.Context, ids map[string][]primitive.ObjectID, admin bool) (map[string]interface{}, error) {
+	results := make(map[string]interface{})
+	for name, collectionIDs := range ids {
+		spec := batchCollections[name]
+		if len(collectionIDs) == 0 {
+			results[name] = spec.newSlice()
+			continue
+		}
+		filter := bson.M{"_id": bson.M{"$in": collectionIDs}}
+		if spec.filter != nil {
+			if restriction := spec.filter(admin); restriction != nil {
+				filter = bson.M{"$and": []bson.M{filter, restriction}}
+			}
+		}
+
+		cursor, err := spec.collection(ps).Find(ctx, filter, boundedFind())
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch %s: %w", name, err)
+		}
+		documents := spec.newSlice()
+		err = cursor.All(ctx, documents)
+		cursor.Close(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", name, err)
+		}
+
+		if !admin {
+			switch documents := documents.(type) {
+			case *[]Author:
+				for i := range *documents {
+					(*documents)[i].redactContact()
+				}
+			case *[]Resume:
+				redactResumes(*documents)
+			}
+		}
+		results[name] = documents
+	}
+	return results, nil
+}
+
+// Batch endpoint: POST /api/batch with {"projects": ["<id>", ...], "authors": [...]} returns the matching
+// documents of each collection in one response, e.g. to resolve the author_ids of a list of projects
+func (h *APIHandler) handleBatch(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if r.Method != "POST" {
+		log.Printf("Date: %s | Route: /api/batch | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	clientIP := trustedClientIP(r)
+	if !h.statsLimiter.IsAllowed(clientIP) {
+		log.Printf("Date: %s | Route: /api/batch | Status: RATE_LIMITED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Rate limit exceeded. Please wait before making another request.", http.StatusTooManyRequests)
+		return
+	}
+
+	var request map[string][]string
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		log.Printf("Date: %s | Route: /api/batch | Status: BAD_REQUEST | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Invalid JSON request", http.StatusBadRequest)
+		return
+	}
+	ids, err := parseBatchRequest(request)
+	if err != nil {
+		writeInvalidInput(w, err)
+		return
+	}
+
+	results, err := h.service.GetBatch(context.Background(), ids, isAdminRequest(r))
+	if err != nil {
+		log.Printf("Date: %s | Route: /api/batch | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	locale := h.locales.Negotiate(r)
+	if projects, ok := results["projects"].(*[]Project); ok {
+		localizeProjects(*projects, locale)
+	}
+	if education, ok := results["education"].(*[]Education); ok {
+		localizeEducation(*education, locale)
+	}
+	setLanguageHeaders(w, locale)
+
+	log.Printf("Date: %s | Route: /api/batch | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(results)
+}
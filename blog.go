@@ -0,0 +1,347 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// publishedPostsFilter matches posts that are visible to the public: not drafts and not scheduled for later
+func publishedPostsFilter() bson.M {
+	return bson.M{
+		"draft":        false,
+		"published_at": bson.M{"$lte": time.Now()},
+	}
+}
+
+// Post query methods; lists put featured and ordered posts first, newest first otherwise
+func (ps *PortfolioService) GetPosts(ctx context.Context, tag string, includeDrafts bool, author *Author) ([]Post, error) {
+	filter := bson.M{}
+	if !includeDrafts {
+		filter = publishedPostsFilter()
+	}
+	if tag != "" {
+		filter["tags"] = strings.ToLower(tag)
+	}
+
+	return findPinned[Post](ctx, ps.posts, hostScope(filter, "author_id", author), bson.E{Key: "published_at", Value: -1})
+}
+
+func (ps *PortfolioService) GetPostBySlug(ctx context.Context, slug string) (*Post, error) {
+	var post Post
+	err := ps.posts.FindOne(ctx, bson.M{"slug": slug}).Decode(&post)
+	if err != nil {
+		return nil, err
+	}
+	return &post, nil
+}
+
+func (ps *PortfolioService) InsertPost(ctx context.Context, post *Post) error {
+	if post.ID.IsZero() {
+		post.ID = primitive.NewObjectID()
+	}
+	post.stampCreated()
+	_, err := ps.posts.InsertOne(ctx, post)
+	return err
+}
+
+// UpdatePost replaces a post; the caller carries created_at over from the stored post
+func (ps *PortfolioService) UpdatePost(ctx context.Context, post *Post) error {
+	post.stampUpdated()
+	result, err := ps.posts.ReplaceOne(ctx, bson.M{"_id": post.ID}, post)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (ps *PortfolioService) DeletePost(ctx context.Context, id primitive.ObjectID) error {
+	result, err := ps.posts.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	ps.recordDeletions(ctx, "posts", id)
+	return nil
+}
+
+func (ps *PortfolioService) CountPosts(ctx context.Context) (int64, error) {
+	return ps.posts.CountDocuments(ctx, publishedPostsFilter())
+}
+
+// preparePost normalizes a post before it is written and renders its markdown body
+func preparePost(post *Post) error {
+	post.Title = strings.TrimSpace(post.Title)
+	if post.Slug == "" {
+		post.Slug = slugify(post.Title)
+	}
+
+	tags := []string{}
+	seen := make(map[string]bool)
+	for _, tag := range post.Tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	post.Tags = tags
+
+	if !post.Draft && post.PublishedAt == nil {
+		now := time.Now()
+		post.PublishedAt = &now
+	}
+
+	bodyHTML, err := renderMarkdown(post.Body)
+	if err != nil {
+		return fmt.Errorf("failed to render markdown: %w", err)
+	}
+	post.BodyHTML = bodyHTML
+	post.refreshReadingStats()
+	return nil
+}
+
+// validatePost checks a post submitted through the API
+func validatePost(post *Post) error {
+	var v Validator
+	if v.Required("title", post.Title) {
+		v.MaxLength("title", post.Title, 200)
+	}
+	v.Required("body", post.Body)
+	v.Check(post.Slug == "" || slugify(post.Slug) == post.Slug, "slug", "format", "slug may only contain lowercase letters, numbers and hyphens")
+	return v.Err()
+}
+
+// Posts endpoints: GET lists published posts (?tag= filters, ?drafts=true includes drafts for admins,
+// ?render=html adds summary_html), POST creates one
+func (h *APIHandler) handlePosts(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	ctx := context.Background()
+
+	switch r.Method {
+	case "GET":
+		includeDrafts := r.URL.Query().Get("drafts") == "true"
+		if includeDrafts && !isAdminRequest(r) {
+			log.Printf("Date: %s | Route: /api/posts | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		render, err := renderQuery(r)
+		if err != nil {
+			writeInvalidInput(w, err)
+			return
+		}
+
+		posts, err := h.service.GetPosts(ctx, r.URL.Query().Get("tag"), includeDrafts, h.hostAuthor(r))
+		if err != nil {
+			log.Printf("Date: %s | Route: /api/posts | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if render {
+			for i := range posts {
+				posts[i].renderHTML()
+			}
+		}
+
+		log.Printf("Date: %s | Route: /api/posts | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+		writeTimestampedList(w, r, posts)
+
+	case "POST":
+		if !isAdminRequest(r) {
+			log.Printf("Date: %s | Route: /api/posts | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		var post Post
+		if err := json.NewDecoder(r.Body).Decode(&post); err != nil {
+			log.Printf("Date: %s | Route: /api/posts | Status: BAD_REQUEST | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, "Invalid JSON request", http.StatusBadRequest)
+			return
+		}
+		if err := validatePost(&post); err != nil {
+			log.Printf("Date: %s | Route: /api/posts | Status: INVALID_INPUT | GPT Model: %s", currentTime, gptModel)
+			writeInvalidInput(w, err)
+			return
+		}
+		if err := preparePost(&post); err != nil {
+			log.Printf("Date: %s | Route: /api/posts | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if _, err := h.service.GetPostBySlug(ctx, post.Slug); err == nil {
+			log.Printf("Date: %s | Route: /api/posts | Status: CONFLICT | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, "A post with this slug already exists", http.StatusConflict)
+			return
+		}
+
+		post.ID = primitive.NilObjectID
+		if err := h.service.InsertPost(ctx, &post); err != nil {
+			log.Printf("Date: %s | Route: /api/posts | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Date: %s | Route: /api/posts | Status: CREATED | GPT Model: %s", currentTime, gptModel)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(post)
+
+	default:
+		log.Printf("Date: %s | Route: /api/posts | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// Single post endpoints: /api/posts/{slug} supports GET, and PUT/DELETE for admins
+func (h *APIHandler) handlePostRoutes(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	slug := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/posts/"), "/")
+	if slug == "" || strings.Contains(slug, "/") {
+		http.NotFound(w, r)
+		return
+	}
+
+	admin := isAdminRequest(r)
+	if r.Method != "GET" && !admin {
+		log.Printf("Date: %s | Route: /api/posts/{slug} | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ctx := context.Background()
+	post, err := h.service.GetPostByIDOrSlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, errInvalidIdentifier) {
+			writeInvalidInput(w, err)
+			return
+		}
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Post not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("Date: %s | Route: /api/posts/{slug} | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		// Drafts and scheduled posts are hidden from the public
+		published := !post.Draft && post.PublishedAt != nil && !post.PublishedAt.After(time.Now())
+		if !published && !admin {
+			http.Error(w, "Post not found", http.StatusNotFound)
+			return
+		}
+		render, err := renderQuery(r)
+		if err != nil {
+			writeInvalidInput(w, err)
+			return
+		}
+		if render {
+			post.renderHTML()
+		}
+
+		log.Printf("Date: %s | Route: /api/posts/{slug} | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(post)
+
+	case "PUT":
+		var updated Post
+		if err := json.NewDecoder(r.Body).Decode(&updated); err != nil {
+			log.Printf("Date: %s | Route: /api/posts/{slug} | Status: BAD_REQUEST | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, "Invalid JSON request", http.StatusBadRequest)
+			return
+		}
+		if err := validatePost(&updated); err != nil {
+			log.Printf("Date: %s | Route: /api/posts/{slug} | Status: INVALID_INPUT | GPT Model: %s", currentTime, gptModel)
+			writeInvalidInput(w, err)
+			return
+		}
+
+		// Keep the existing slug and publish date unless they are explicitly changed
+		updated.ID = post.ID
+		updated.CreatedAt = post.CreatedAt
+		updated.Pinning = post.Pinning // Changed through PUT /api/admin/order/posts
+		if updated.Slug == "" {
+			updated.Slug = post.Slug
+		}
+		if updated.PublishedAt == nil {
+			updated.PublishedAt = post.PublishedAt
+		}
+		if updated.Slug != post.Slug {
+			if _, err := h.service.GetPostBySlug(ctx, updated.Slug); err == nil {
+				log.Printf("Date: %s | Route: /api/posts/{slug} | Status: CONFLICT | GPT Model: %s", currentTime, gptModel)
+				http.Error(w, "A post with this slug already exists", http.StatusConflict)
+				return
+			}
+		}
+		if err := preparePost(&updated); err != nil {
+			log.Printf("Date: %s | Route: /api/posts/{slug} | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if err := h.service.UpdatePost(ctx, &updated); err != nil {
+			log.Printf("Date: %s | Route: /api/posts/{slug} | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Date: %s | Route: /api/posts/{slug} | Status: UPDATED | GPT Model: %s", currentTime, gptModel)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(updated)
+
+	case "DELETE":
+		if err := h.service.DeletePost(ctx, post.ID); err != nil {
+			log.Printf("Date: %s | Route: /api/posts/{slug} | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Date: %s | Route: /api/posts/{slug} | Status: DELETED | GPT Model: %s", currentTime, gptModel)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		log.Printf("Date: %s | Route: /api/posts/{slug} | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+)
+
+// TTLCache caches expensive third-party lookups in the state store, so every replica shares the results.
+// Values are stored as JSON, except []byte which is stored as is.
+type TTLCache[T any] struct {
+	store  StateStore
+	prefix string
+}
+
+// NewTTLCache creates a cache whose keys are namespaced by name within the store
+func NewTTLCache[T any](store StateStore, name string) *TTLCache[T] {
+	return &TTLCache[T]{store: store, prefix: name + ":"}
+}
+
+// Get returns a cached value if it exists and has not expired
+func (c *TTLCache[T]) Get(key string) (T, bool) {
+	value, expiresAt, ok := c.get(key)
+	if !ok || time.Now().After(expiresAt) {
+		var zero T
+		return zero, false
+	}
+	return value, true
+}
+
+// GetStale returns a cached value even if it has expired, for use when a refresh fails
+func (c *TTLCache[T]) GetStale(key string) (T, bool) {
+	value, _, ok := c.get(key)
+	return value, ok
+}
+
+func (c *TTLCache[T]) get(key string) (T, time.Time, bool) {
+	var value T
+	data, expiresAt, found, err := c.store.Get(context.Background(), c.prefix+key)
+	if err != nil {
+		log.Printf("Error reading cache entry %s%s: %v", c.prefix, key, err)
+		return value, expiresAt, false
+	}
+	if !found {
+		return value, expiresAt, false
+	}
+	if raw, ok := any(&value).(*[]byte); ok {
+		*raw = data
+		return value, expiresAt, true
+	}
+	if err := json.Unmarshal(data, &value); err != nil {
+		log.Printf("Error decoding cache entry %s%s: %v", c.prefix, key, err)
+		return value, expiresAt, false
+	}
+	return value, expiresAt, true
+}
+
+// Set stores a value for the given duration
+func (c *TTLCache[T]) Set(key string, value T, ttl time.Duration) {
+	data, ok := any(value).([]byte)
+	if !ok {
+		var err error
+		if data, err = json.Marshal(value); err != nil {
+			log.Printf("Error encoding cache entry %s%s: %v", c.prefix, key, err)
+			return
+		}
+	}
+	if err := c.store.Set(context.Background(), c.prefix+key, data, ttl); err != nil {
+		log.Printf("Error writing cache entry %s%s: %v", c.prefix, key, err)
+	}
+}
+
+// Delete removes a key so the next Get misses
+func (c *TTLCache[T]) Delete(key string) {
+	if err := c.store.Delete(context.Background(), c.prefix+key); err != nil {
+		log.Printf("Error deleting cache entry %s%s: %v", c.prefix, key, err)
+	}
+}
@@ -0,0 +1,287 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Certification query methods
+func (ps *PortfolioService) GetAllCertifications(ctx context.Context, author *Author) ([]Certification, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "issued_at", Value: -1}})
+	cursor, err := ps.certifications.Find(ctx, hostScope(bson.M{}, "author_id", author), boundedFind(opts))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var certifications []Certification
+	if err = cursor.All(ctx, &certifications); err != nil {
+		return nil, err
+	}
+	return certifications, nil
+}
+
+func (ps *PortfolioService) GetCertificationsByIssuer(ctx context.Context, issuer string, author *Author) ([]Certification, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "issued_at", Value: -1}})
+	filter := hostScope(bson.M{"issuer": bson.M{"$regex": issuer, "$options": "i"}}, "author_id", author)
+	cursor, err := ps.certifications.Find(ctx, filter, boundedFind(opts))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var certifications []Certification
+	if err = cursor.All(ctx, &certifications); err != nil {
+		return nil, err
+	}
+	return certifications, nil
+}
+
+func (ps *PortfolioService) InsertCertification(ctx context.Context, certification *Certification) error {
+	if certification.ID.IsZero() {
+		certification.ID = primitive.NewObjectID()
+	}
+	certification.stampCreated()
+	_, err := ps.certifications.InsertOne(ctx, certification)
+	return err
+}
+
+// UpsertCertificationBySource inserts or refreshes a certification imported from an external source
+func (ps *PortfolioService) UpsertCertificationBySource(ctx context.Context, certification *Certification) error {
+	filter := bson.M{"source": certification.Source, "external_id": certification.ExternalID}
+	now := time.Now().UTC()
+	update := bson.M{
+		"$set": bson.M{
+			"name":             certification.Name,
+			"issuer":           certification.Issuer,
+			"issued_at":        certification.IssuedAt,
+			"expires_at":       certification.ExpiresAt,
+			"description":      certification.Description,
+			"skills":           certification.Skills,
+			"badge_image_url":  certification.BadgeImageURL,
+			"verification_url": certification.VerificationURL,
+			"author_id":        certification.AuthorID,
+			"updated_at":       now,
+		},
+		"$setOnInsert": bson.M{"created_at": now},
+	}
+	_, err := ps.certifications.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	return err
+}
+
+func (ps *PortfolioService) CountCertifications(ctx context.Context) (int64, error) {
+	return ps.certifications.CountDocuments(ctx, bson.M{})
+}
+
+// validateCertification checks a manually entered certification
+func validateCertification(certification *Certification) error {
+	var v Validator
+	v.Required("name", certification.Name)
+	v.Required("issuer", certification.Issuer)
+	v.RequiredTime("issued_at", certification.IssuedAt)
+	if v.Required("verification_url", certification.VerificationURL) {
+		v.HTTPURL("verification_url", certification.VerificationURL)
+	}
+	v.HTTPURL("badge_image_url", certification.BadgeImageURL)
+	return v.Err()
+}
+
+// Certifications endpoints
+func (h *APIHandler) handleCertifications(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	ctx := context.Background()
+
+	switch r.Method {
+	case "GET":
+		issuer := r.URL.Query().Get("issuer")
+
+		var certifications []Certification
+		var err error
+		if issuer != "" {
+			certifications, err = h.service.GetCertificationsByIssuer(ctx, issuer, h.hostAuthor(r))
+		} else {
+			certifications, err = h.service.GetAllCertifications(ctx, h.hostAuthor(r))
+		}
+		if err != nil {
+			log.Printf("Date: %s | Route: /api/certifications | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Date: %s | Route: /api/certifications | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+		writeTimestampedList(w, r, certifications)
+
+	case "POST":
+		if !isAdminRequest(r) {
+			log.Printf("Date: %s | Route: /api/certifications | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		var certification Certification
+		if err := json.NewDecoder(r.Body).Decode(&certification); err != nil {
+			log.Printf("Date: %s | Route: /api/certifications | Status: BAD_REQUEST | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, "Invalid JSON request", http.StatusBadRequest)
+			return
+		}
+		if err := validateCertification(&certification); err != nil {
+			log.Printf("Date: %s | Route: /api/certifications | Status: INVALID_INPUT | GPT Model: %s", currentTime, gptModel)
+			writeInvalidInput(w, err)
+			return
+		}
+
+		certification.ID = primitive.NilObjectID
+		certification.Source = "manual"
+		certification.ExternalID = ""
+		if err := h.service.InsertCertification(ctx, &certification); err != nil {
+			log.Printf("Date: %s | Route: /api/certifications | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Date: %s | Route: /api/certifications | Status: CREATED | GPT Model: %s", currentTime, gptModel)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(certification)
+
+	default:
+		log.Printf("Date: %s | Route: /api/certifications | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// Credly public badge feed (only the fields we use)
+type credlyBadgesResponse struct {
+	Data []struct {
+		ID            string     `json:"id"`
+		IssuedAt      time.Time  `json:"issued_at"`
+		ExpiresAt     *time.Time `json:"expires_at"`
+		BadgeTemplate struct {
+			Name        string `json:"name"`
+			Description string `json:"description"`
+			ImageURL    string `json:"image_url"`
+			Skills      []struct {
+				Name string `json:"name"`
+			} `json:"skills"`
+		} `json:"badge_template"`
+		Issuer struct {
+			Summary  string `json:"summary"`
+			Entities []struct {
+				Entity struct {
+					Name string `json:"name"`
+				} `json:"entity"`
+			} `json:"entities"`
+		} `json:"issuer"`
+	} `json:"data"`
+}
+
+// CredlySyncer periodically imports public Credly badges into the certifications collection
+type CredlySyncer struct {
+	service    *PortfolioService
+	httpClient *http.Client
+	username   string
+	interval   time.Duration
+}
+
+// NewCredlySyncer creates a syncer from environment configuration, or nil if sync is disabled
+func NewCredlySyncer(service *PortfolioService) *CredlySyncer {
+	username := os.Getenv("CREDLY_USERNAME")
+	if username == "" {
+		log.Println("CREDLY_USERNAME not set, Credly badge sync disabled")
+		return nil
+	}
+
+	interval := 24 * time.Hour
+	if value := os.Getenv("CREDLY_SYNC_INTERVAL"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			log.Printf("Invalid CREDLY_SYNC_INTERVAL %q, using %s", value, interval)
+		} else {
+			interval = parsed
+		}
+	}
+
+	return &CredlySyncer{
+		service:    service,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		username:   username,
+		interval:   interval,
+	}
+}
+
+// Sync fetches the user's public badges and upserts them as certifications
+func (s *CredlySyncer) Sync(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	req, err := http.NewRequest("GET", fmt.Sprintf("https://www.credly.com/users/%s/badges.json", url.PathEscape(s.username)), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	var payload credlyBadgesResponse
+	if err := getJSON(ctx, s.httpClient, req, &payload); err != nil {
+		return fmt.Errorf("failed to fetch badges: %w", err)
+	}
+
+	// Credly badges belong to the single portfolio author when there is one
+	var authorID primitive.ObjectID
+	if authors, err := s.service.GetAllAuthors(ctx); err == nil && len(authors) == 1 {
+		authorID = authors[0].ID
+	}
+
+	synced := 0
+	for _, badge := range payload.Data {
+		issuer := badge.Issuer.Summary
+		if len(badge.Issuer.Entities) > 0 {
+			issuer = badge.Issuer.Entities[0].Entity.Name
+		}
+		skills := []string{}
+		for _, skill := range badge.BadgeTemplate.Skills {
+			skills = append(skills, skill.Name)
+		}
+
+		certification := &Certification{
+			Name:            badge.BadgeTemplate.Name,
+			Issuer:          issuer,
+			IssuedAt:        badge.IssuedAt,
+			ExpiresAt:       badge.ExpiresAt,
+			Description:     badge.BadgeTemplate.Description,
+			Skills:          skills,
+			BadgeImageURL:   badge.BadgeTemplate.ImageURL,
+			VerificationURL: "https://www.credly.com/badges/" + badge.ID,
+			Source:          "credly",
+			ExternalID:      badge.ID,
+			AuthorID:        authorID,
+		}
+		if err := s.service.UpsertCertificationBySource(ctx, certification); err != nil {
+			log.Printf("Credly sync: failed to save badge %s: %v", badge.ID, err)
+			continue
+		}
+		synced++
+	}
+
+	log.Printf("Credly sync complete: %d of %d badges saved", synced, len(payload.Data))
+	return nil
+}
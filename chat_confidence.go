@@ -0,0 +1,44 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Confidence levels the chatbot reports with each answer, from how well the portfolio context supports it
+const (
+	chatConfidenceHigh   = "high"   // The context states the answer
+	chatConfidenceMedium = "medium" // The answer is inferred from related facts
+	chatConfidenceLow    = "low"    // The context only loosely supports the answer
+	chatConfidenceNone   = "none"   // The context does not contain the answer, and the reply says so
+)
+
+// chatConfidencePattern matches the confidence line the model ends its answer with, tolerating markdown
+// emphasis around it
+var chatConfidencePattern = regexp.MustCompile(`(?i)\n?[ \t]*[*_]*confidence[*_]*[ \t]*:[ \t]*[*_]*(high|medium|low|none)[*_]*[ \t.]*$`)
+
+// chatAnswer is the chatbot's reply to one question
+type chatAnswer struct {
+	Text       string
+	Confidence string // Empty if the model did not rate its answer
+}
+
+// InsufficientData reports whether the portfolio did not contain the answer, so the UI can style the reply
+// as "I don't know" rather than as an answer
+func (a chatAnswer) InsufficientData() bool {
+	return a.Confidence == chatConfidenceNone
+}
+
+// parseChatAnswer splits the confidence line from a reply. A reply without one, e.g. from a variant model
+// that ignored the instruction, is kept whole with an empty confidence.
+func parseChatAnswer(reply string) chatAnswer {
+	trimmed := strings.TrimRight(reply, " \t\r\n")
+	match := chatConfidencePattern.FindStringSubmatchIndex(trimmed)
+	if match == nil {
+		return chatAnswer{Text: reply}
+	}
+	return chatAnswer{
+		Text:       strings.TrimRight(trimmed[:match[0]], " \t\r\n"),
+		Confidence: strings.ToLower(trimmed[match[2]:match[3]]),
+	}
+}
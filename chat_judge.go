@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ChatJudgeConfig controls which chatbot answers are scored for groundedness
+type ChatJudgeConfig struct {
+	rate     float64 // Share of answers to score, from CHAT_JUDGE_RATE
+	minScore int     // Answers scoring below this are flagged, from CHAT_JUDGE_MIN_SCORE
+}
+
+// NewChatJudgeConfig enables answer scoring when CHAT_JUDGE_RATE is between 0 and 1. Returns nil if disabled.
+func NewChatJudgeConfig() *ChatJudgeConfig {
+	rate, err := strconv.ParseFloat(os.Getenv("CHAT_JUDGE_RATE"), 64)
+	if err != nil || rate <= 0 {
+		log.Println("CHAT_JUDGE_RATE not set, chatbot answer scoring disabled")
+		return nil
+	}
+	if rate > 1 {
+		rate = 1
+	}
+
+	minScore := 3
+	if value := os.Getenv("CHAT_JUDGE_MIN_SCORE"); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil && parsed >= 1 && parsed <= 5 {
+			minScore = parsed
+		}
+	}
+	log.Printf("Scoring %.0f%% of chatbot answers, flagging scores below %d", rate*100, minScore)
+	return &ChatJudgeConfig{rate: rate, minScore: minScore}
+}
+
+// Sample reports whether the next answer should be scored
+func (c *ChatJudgeConfig) Sample() bool {
+	return c != nil && rand.Float64() < c.rate
+}
+
+// JudgeGroundedness grades how well an answer is supported by the portfolio context it was generated from
+func (l *LLMService) JudgeGroundedness(ctx context.Context, question, contextString, answer string) (*ChatJudgement, error) {
+	prompt := fmt.Sprintf(`You are reviewing answers from a portfolio chatbot for factual grounding.
+
+PORTFOLIO CONTEXT GIVEN TO THE CHATBOT:
+%s
+
+QUESTION: %s
+
+ANSWER:
+%s
+
+Score how well every factual claim in the answer about the portfolio owner is supported by the context:
+5 = every claim is supported, 4 = minor embellishment, 3 = some unsupported detail, 2 = significant unsupported claims, 1 = mostly invented or contradicts the context.
+Polite redirections and statements that information is not available count as supported.
+
+Respond with a JSON object of the form {"score": 1-5, "reason": "one sentence", "unsupported": ["claim", ...]}.`, contextString, question, answer)
+
+	reply, err := l.complete(ctx, prompt, true)
+	if err != nil {
+		return nil, err
+	}
+
+	judgement := ChatJudgement{Model: l.model, JudgedAt: time.Now()}
+	if err := json.Unmarshal([]byte(reply), &judgement); err != nil {
+		return nil, fmt.Errorf("invalid judge response: %w", err)
+	}
+	if judgement.Score < 1 || judgement.Score > 5 {
+		return nil, fmt.Errorf("judge returned score %d outside 1-5", judgement.Score)
+	}
+	return &judgement, nil
+}
+
+// SaveChatJudgement stores the grade of a chat turn
+func (ps *PortfolioService) SaveChatJudgement(ctx context.Context, id primitive.ObjectID, judgement *ChatJudgement, flagged bool) error {
+	_, err := ps.chatTurns.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"judgement": judgement, "flagged": flagged}},
+	)
+	return err
+}
+
+// GetReviewChatTurns returns scored and reported chat turns, newest first, optionally only the flagged or
+// the reported ones. Reported turns are sorted by when they were reported.
+func (ps *PortfolioService) GetReviewChatTurns(ctx context.Context, flaggedOnly, reportedOnly bool, limit int64) ([]ChatTurn, error) {
+	filter := bson.M{"$or": []bson.M{{"judgement": bson.M{"$exists": true}}, {"report": bson.M{"$exists": true}}}}
+	sort := bson.D{{Key: "created_at", Value: -1}}
+	if flaggedOnly {
+		filter["flagged"] = true
+	}
+	if reportedOnly {
+		filter["report"] = bson.M{"$exists": true}
+		sort = bson.D{{Key: "report.reported_at", Value: -1}}
+	}
+	opts := options.Find().SetSort(sort).SetLimit(limit)
+	cursor, err := ps.chatTurns.Find(ctx, filter, boundedFind(opts))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	turns := []ChatTurn{}
+	if err = cursor.All(ctx, &turns); err != nil {
+		return nil, err
+	}
+	return turns, nil
+}
+
+// judgeChatTurn scores a recorded answer; it runs after the response has been sent
+func (h *APIHandler) judgeChatTurn(id primitive.ObjectID, question, contextString, answer string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	judgement, err := h.llmService.JudgeGroundedness(ctx, question, contextString, answer)
+	if err != nil {
+		log.Printf("Error scoring chat turn %s: %v", id.Hex(), err)
+		return
+	}
+
+	flagged := judgement.Score < h.chatJudge.minScore
+	if err := h.service.SaveChatJudgement(ctx, id, judgement, flagged); err != nil {
+		log.Printf("Error saving chat turn score %s: %v", id.Hex(), err)
+		return
+	}
+	if flagged {
+		log.Printf("Chat turn %s flagged with groundedness score %d: %s", id.Hex(), judgement.Score, judgement.Reason)
+	}
+}
+
+// Chat review endpoint: GET /api/admin/chats?flagged=true&reported=true&limit= lists scored and reported
+// chatbot answers for prompt debugging, newest first (admin only)
+func (h *APIHandler) handleChatReview(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if r.Method != "GET" {
+		log.Printf("Date: %s | Route: /api/admin/chats | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !isAdminRequest(r) {
+		log.Printf("Date: %s | Route: /api/admin/chats | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var v Validator
+	limit := int64(v.QueryInt(r, "limit", 50, 1, 200))
+	if err := v.Err(); err != nil {
+		writeInvalidInput(w, err)
+		return
+	}
+	flaggedOnly := r.URL.Query().Get("flagged") == "true"
+	reportedOnly := r.URL.Query().Get("reported") == "true"
+
+	turns, err := h.service.GetReviewChatTurns(context.Background(), flaggedOnly, reportedOnly, limit)
+	if err != nil {
+		log.Printf("Date: %s | Route: /api/admin/chats | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Date: %s | Route: /api/admin/chats | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(turns)
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+)
+
+type chatPreviewKey struct{}
+
+// withChatPreview marks a context as a dry run: the prompt is built from cached summaries only, so no
+// OpenAI call is made
+func withChatPreview(ctx context.Context) context.Context {
+	return context.WithValue(ctx, chatPreviewKey{}, true)
+}
+
+// isChatPreview reports whether a context is a dry run
+func isChatPreview(ctx context.Context) bool {
+	preview, _ := ctx.Value(chatPreviewKey{}).(bool)
+	return preview
+}
+
+// estimateTokens approximates the tokens of English text and JSON at about four characters per token
+func estimateTokens(text string) int64 {
+	return int64((len(text) + 3) / 4)
+}
+
+// Chat preview endpoint: POST /api/admin/chat-preview {"query": ..., "session_id": ..., "style": ...} returns
+// the context retrieved for a question, the assembled prompt and estimated token counts without calling
+// OpenAI. The session ID, if given, picks the experiment variant the session would get (admin only).
+func (h *APIHandler) handleChatPreview(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if r.Method != "POST" {
+		log.Printf("Date: %s | Route: /api/admin/chat-preview | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !isAdminRequest(r) {
+		log.Printf("Date: %s | Route: /api/admin/chat-preview | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var request struct {
+		Query     string `json:"query"`
+		SessionID string `json:"session_id"`
+		Style     string `json:"style"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		log.Printf("Date: %s | Route: /api/admin/chat-preview | Status: BAD_REQUEST | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Invalid JSON request", http.StatusBadRequest)
+		return
+	}
+	if err := validateChatbotInput(request.Query, request.Style); err != nil {
+		writeInvalidInput(w, err)
+		return
+	}
+	if h.llmService == nil {
+		http.Error(w, "Chatbot is not available. OpenAI API key not configured.", http.StatusServiceUnavailable)
+		return
+	}
+
+	ctx := withChatPreview(r.Context())
+	var variant *PromptVariant
+	if chatSessionPattern.MatchString(request.SessionID) {
+		experiment, err := h.service.GetActiveExperiment(ctx)
+		if err != nil {
+			log.Printf("Error loading active experiment: %v", err)
+		} else if experiment != nil {
+			variant = assignVariant(experiment, request.SessionID)
+		}
+	}
+	locale := h.locales.Negotiate(r)
+	if locale == h.locales.Default() {
+		locale = ""
+	}
+
+	prompt, err := h.llmService.buildChatPrompt(ctx, request.Query, h.hostAuthor(r), variant, locale, request.Style)
+	if err != nil {
+		log.Printf("Date: %s | Route: /api/admin/chat-preview | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Search results are parsed back so the context reads as a document rather than an escaped string
+	var retrieved interface{} = prompt.Context
+	var parsed interface{}
+	if json.Unmarshal([]byte(prompt.Context), &parsed) == nil {
+		retrieved = parsed
+	}
+	variantName := ""
+	if variant != nil {
+		variantName = variant.Name
+	}
+	promptTokens := estimateTokens(prompt.Prompt)
+
+	log.Printf("Date: %s | Route: /api/admin/chat-preview | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"query":                  request.Query,
+		"model":                  prompt.Model,
+		"variant":                variantName,
+		"locale":                 locale,
+		"context":                retrieved,
+		"prompt":                 prompt.Prompt,
+		"context_chars":          len(prompt.Context),
+		"prompt_chars":           len(prompt.Prompt),
+		"context_tokens":         estimateTokens(prompt.Context),
+		"prompt_tokens":          promptTokens,
+		"estimated_prompt_cost":  estimateLLMCost(prompt.Model, promptTokens, 0),
+		"search_errors":          prompt.SearchFailures,
+		"token_counts_estimated": true,
+	})
+}
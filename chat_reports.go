@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Reasons a visitor can report a chatbot answer for
+var chatReportReasons = []string{"inaccurate", "inappropriate", "offensive", "other"}
+
+const (
+	maxChatReportComment  = 1000
+	maxChatReportQuestion = 500 // As long as a chatbot query can be
+	// maxChatReportAnswer caps the answer a visitor sends along with a report
+	maxChatReportAnswer = 10000
+)
+
+// ChatReport is a visitor's report of a problematic chatbot answer, such as a hallucination or inappropriate
+// output. It is stored on the reported turn.
+type ChatReport struct {
+	Reason  string `bson:"reason" json:"reason"`
+	Comment string `bson:"comment,omitempty" json:"comment,omitempty"`
+	// The question and answer as the visitor saw them, kept when the turn was not sampled for scoring and so
+	// did not store its own
+	Question   string    `bson:"question,omitempty" json:"question,omitempty"`
+	Answer     string    `bson:"answer,omitempty" json:"answer,omitempty"`
+	ReportedAt time.Time `bson:"reported_at" json:"reported_at"`
+}
+
+// ChatReportRequest is the body of POST /api/chatbot/report
+type ChatReportRequest struct {
+	TurnID    string `json:"turn_id"`
+	SessionID string `json:"session_id"`
+	Reason    string `json:"reason"` // inaccurate, inappropriate, offensive or other
+	Comment   string `json:"comment,omitempty"`
+	Question  string `json:"question,omitempty"` // As shown to the visitor
+	Answer    string `json:"answer,omitempty"`
+}
+
+// ReportChatTurn stores a report on a chatbot answer. As with feedback, the session must match so visitors
+// can only report answers they were given; a later report replaces an earlier one.
+func (ps *PortfolioService) ReportChatTurn(ctx context.Context, id primitive.ObjectID, sessionID string, report *ChatReport) error {
+	var turn ChatTurn
+	err := ps.chatTurns.FindOne(ctx, bson.M{"_id": id, "session_id": sessionID}).Decode(&turn)
+	if err != nil {
+		return err
+	}
+	if turn.Answer != "" {
+		report.Question, report.Answer = "", ""
+	}
+	_, err = ps.chatTurns.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"report": report}})
+	return err
+}
+
+// Chatbot report endpoint: POST /api/chatbot/report with {turn_id, session_id, reason, comment, question,
+// answer} flags an answer for review in GET /api/admin/chats?reported=true
+func (h *APIHandler) handleChatbotReport(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if r.Method != "POST" {
+		log.Printf("Date: %s | Route: /api/chatbot/report | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	clientIP := trustedClientIP(r)
+	if !h.submitLimiter.IsAllowed(clientIP) {
+		log.Printf("Date: %s | Route: /api/chatbot/report | Status: RATE_LIMITED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Rate limit exceeded. Please wait before making another request.", http.StatusTooManyRequests)
+		return
+	}
+
+	var request ChatReportRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		log.Printf("Date: %s | Route: /api/chatbot/report | Status: BAD_REQUEST | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Invalid JSON request", http.StatusBadRequest)
+		return
+	}
+	turnID, err := primitive.ObjectIDFromHex(request.TurnID)
+	if err != nil {
+		http.Error(w, "Invalid turn ID", http.StatusBadRequest)
+		return
+	}
+	var v Validator
+	if v.Required("reason", request.Reason) {
+		v.OneOf("reason", request.Reason, chatReportReasons...)
+	}
+	v.MaxLength("comment", request.Comment, maxChatReportComment)
+	v.MaxLength("question", request.Question, maxChatReportQuestion)
+	v.MaxLength("answer", request.Answer, maxChatReportAnswer)
+	if err := v.Err(); err != nil {
+		writeInvalidInput(w, err)
+		return
+	}
+
+	report := &ChatReport{
+		Reason:     request.Reason,
+		Comment:    request.Comment,
+		Question:   request.Question,
+		Answer:     request.Answer,
+		ReportedAt: time.Now(),
+	}
+	if err := h.service.ReportChatTurn(context.Background(), turnID, request.SessionID, report); err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Chat turn not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("Date: %s | Route: /api/chatbot/report | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Date: %s | Route: /api/chatbot/report | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+	log.Printf("Chat turn %s reported as %s", turnID.Hex(), request.Reason)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,30 @@
+package main
+
+// Chatbot response styles, picked by the visitor to suit their audience
+const (
+	ChatStyleRecruiter = "recruiter"
+	ChatStyleTechnical = "technical"
+	ChatStyleCasual    = "casual"
+)
+
+// chatStyleInstructions are the prompt instructions of each style. They are added after the general
+// instructions and take precedence over their tone and length guidance.
+var chatStyleInstructions = map[string][]string{
+	ChatStyleRecruiter: {
+		"The visitor is a recruiter or hiring manager: lead with roles, impact, seniority and availability",
+		"Favour outcomes and responsibilities over implementation details, and avoid jargon a non-engineer would not know",
+		"Keep answers short and skimmable, with the most relevant experience first",
+	},
+	ChatStyleTechnical: {
+		"The visitor is an engineer wanting a technical deep-dive: explain architecture, design decisions and trade-offs",
+		"Name the specific languages, frameworks and tools used, and how they fit together",
+		"Longer, detailed answers are welcome, but only describe technical details the portfolio data supports",
+	},
+	ChatStyleCasual: {
+		"Answer in a relaxed, friendly, conversational tone, as if chatting with someone met at a meetup",
+		"Keep it brief and light; skip formal lists unless asked for details",
+	},
+}
+
+// chatStyles lists the style names in the order they are documented
+var chatStyles = []string{ChatStyleRecruiter, ChatStyleTechnical, ChatStyleCasual}
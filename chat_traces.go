@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Chat trace outcomes
+const (
+	ChatTraceSuccess     = "success"
+	ChatTraceSearchError = "search_error"
+	ChatTraceLLMError    = "llm_error"
+)
+
+// ChatTrace breaks down where the time and tokens of one chatbot request went
+type ChatTrace struct {
+	RequestID        string    `bson:"_id" json:"request_id"`
+	SessionID        string    `bson:"session_id" json:"session_id"`
+	Question         string    `bson:"question" json:"question"`
+	Model            string    `bson:"model" json:"model"`
+	RetrievalMs      int64     `bson:"retrieval_ms" json:"retrieval_ms"` // Searching the portfolio and building the context
+	LLMMs            int64     `bson:"llm_ms" json:"llm_ms"`
+	LatencyMs        int64     `bson:"latency_ms" json:"latency_ms"`
+	ContextChars     int       `bson:"context_chars" json:"context_chars"`
+	LLMCalls         int       `bson:"llm_calls" json:"llm_calls"`
+	PromptTokens     int64     `bson:"prompt_tokens" json:"prompt_tokens"`
+	CompletionTokens int64     `bson:"completion_tokens" json:"completion_tokens"`
+	CostUSD          float64   `bson:"cost_usd" json:"cost_usd"`
+	Outcome          string    `bson:"outcome" json:"outcome"`
+	Error            string    `bson:"error,omitempty" json:"error,omitempty"`
+	SearchErrors     []string  `bson:"search_errors,omitempty" json:"search_errors,omitempty"` // Collections left out of the context
+	Confidence       string    `bson:"confidence,omitempty" json:"confidence,omitempty"`       // As rated by the model
+	CreatedAt        time.Time `bson:"created_at" json:"created_at"`
+
+	mutex sync.Mutex // Summaries can be generated concurrently while building the context
+}
+
+type chatTraceKey struct{}
+
+// withChatTrace attaches a trace to a context so the LLM calls made with it are added to the trace
+func withChatTrace(ctx context.Context, trace *ChatTrace) context.Context {
+	return context.WithValue(ctx, chatTraceKey{}, trace)
+}
+
+// chatTraceFrom returns the trace attached to a context, or nil
+func chatTraceFrom(ctx context.Context) *ChatTrace {
+	trace, _ := ctx.Value(chatTraceKey{}).(*ChatTrace)
+	return trace
+}
+
+// addCall adds an LLM call's tokens, cost and latency to the trace
+func (t *ChatTrace) addCall(call *LLMCall) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.LLMCalls++
+	t.LLMMs += call.LatencyMs
+	t.PromptTokens += call.PromptTokens
+	t.CompletionTokens += call.CompletionTokens
+	t.CostUSD += call.CostUSD
+}
+
+// requestIDPattern accepts the request IDs commonly set by proxies and load balancers
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{8,64}$`)
+
+// requestID returns the request's X-Request-ID, or a new one if it has none, and echoes it in the response
+func requestID(w http.ResponseWriter, r *http.Request) string {
+	id := r.Header.Get("X-Request-ID")
+	if !requestIDPattern.MatchString(id) {
+		random := make([]byte, 12)
+		rand.Read(random)
+		id = hex.EncodeToString(random)
+	}
+	w.Header().Set("X-Request-ID", id)
+	return id
+}
+
+// RecordChatTrace stores a trace and logs its summary
+func (ps *PortfolioService) RecordChatTrace(ctx context.Context, trace *ChatTrace) error {
+	log.Printf("Chat trace %s: %s in %dms (retrieval %dms, LLM %dms), %d LLM calls, %d prompt + %d completion tokens, ~$%.4f, model %s",
+		trace.RequestID, trace.Outcome, trace.LatencyMs, trace.RetrievalMs, trace.LLMMs, trace.LLMCalls,
+		trace.PromptTokens, trace.CompletionTokens, trace.CostUSD, trace.Model)
+	_, err := ps.chatTraces.InsertOne(ctx, trace)
+	return err
+}
+
+// GetChatTraces lists recent traces, newest first, optionally only those with an outcome or at least a latency
+func (ps *PortfolioService) GetChatTraces(ctx context.Context, outcome string, minLatencyMs int64, limit int64) ([]ChatTrace, error) {
+	filter := bson.M{}
+	if outcome != "" {
+		filter["outcome"] = outcome
+	}
+	if minLatencyMs > 0 {
+		filter["latency_ms"] = bson.M{"$gte": minLatencyMs}
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(limit)
+	cursor, err := ps.chatTraces.Find(ctx, filter, boundedFind(opts))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	traces := []ChatTrace{}
+	if err = cursor.All(ctx, &traces); err != nil {
+		return nil, err
+	}
+	return traces, nil
+}
+
+// GetChatTrace returns a trace with the LLM calls made for it
+func (ps *PortfolioService) GetChatTrace(ctx context.Context, requestID string) (*ChatTrace, []LLMCall, error) {
+	var trace ChatTrace
+	if err := ps.chatTraces.FindOne(ctx, bson.M{"_id": requestID}).Decode(&trace); err != nil {
+		return nil, nil, err
+	}
+	cursor, err := ps.llmCalls.Find(ctx, bson.M{"request_id": requestID}, boundedFind(options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})))
+	if err != nil {
+		return nil, nil, err
+	}
+	calls := []LLMCall{}
+	if err := cursor.All(ctx, &calls); err != nil {
+		return nil, nil, err
+	}
+	return &trace, calls, nil
+}
+
+// Chat traces endpoint: GET /api/admin/chat-traces?outcome=&min_latency_ms=&limit= lists recent chatbot
+// requests; GET /api/admin/chat-traces/{request_id} returns one with its LLM calls (admin only)
+func (h *APIHandler) handleChatTraces(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if r.Method != "GET" {
+		log.Printf("Date: %s | Route: /api/admin/chat-traces | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !isAdminRequest(r) {
+		log.Printf("Date: %s | Route: /api/admin/chat-traces | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if id := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/admin/chat-traces"), "/"); id != "" {
+		trace, calls, err := h.service.GetChatTrace(r.Context(), id)
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Chat trace not found", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			log.Printf("Date: %s | Route: /api/admin/chat-traces/{request_id} | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		log.Printf("Date: %s | Route: /api/admin/chat-traces/{request_id} | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"trace": trace, "llm_calls": calls})
+		return
+	}
+
+	var v Validator
+	limit := int64(v.QueryInt(r, "limit", 50, 1, 200))
+	minLatencyMs := int64(v.QueryInt(r, "min_latency_ms", 0, 0, 600000))
+	outcome := r.URL.Query().Get("outcome")
+	if outcome != "" {
+		v.OneOf("outcome", outcome, ChatTraceSuccess, ChatTraceSearchError, ChatTraceLLMError)
+	}
+	if err := v.Err(); err != nil {
+		writeInvalidInput(w, err)
+		return
+	}
+
+	traces, err := h.service.GetChatTraces(r.Context(), outcome, minLatencyMs, limit)
+	if err != nil {
+		log.Printf("Date: %s | Route: /api/admin/chat-traces | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Date: %s | Route: /api/admin/chat-traces | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(traces)
+}
@@ -0,0 +1,358 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// maxContextFieldLength caps long text fields such as post bodies once the context is over budget
+	maxContextFieldLength = 600
+	// contextIntentBoost multiplies the weight of the collections a question is about
+	contextIntentBoost = 4
+)
+
+// defaultContextWeights is each collection's share of the prompt budget when the context does not fit.
+// Override with CONTEXT_WEIGHTS, e.g. "projects=2,education=3".
+var defaultContextWeights = map[string]float64{
+	"authors":        1,
+	"now":            0.5,
+	"recent_changes": 0.5,
+	"projects":       3,
+	"resumes":        1,
+	"experience":     2,
+	"skills":         1,
+	"education":      1,
+	"certifications": 0.5,
+	"awards":         0.5,
+	"testimonials":   0.5,
+	"publications":   0.5,
+	"talks":          0.5,
+	"posts":          1,
+}
+
+// contextWeights loads CONTEXT_WEIGHTS once on top of the defaults
+var contextWeights = sync.OnceValue(func() map[string]float64 {
+	weights := make(map[string]float64, len(defaultContextWeights))
+	for name, weight := range defaultContextWeights {
+		weights[name] = weight
+	}
+	for _, pair := range strings.Split(os.Getenv("CONTEXT_WEIGHTS"), ",") {
+		name, value, found := strings.Cut(strings.TrimSpace(pair), "=")
+		if !found {
+			continue
+		}
+		weight, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || weight < 0 {
+			log.Printf("Invalid CONTEXT_WEIGHTS entry %q, ignoring it", pair)
+			continue
+		}
+		weights[strings.TrimSpace(name)] = weight
+	}
+	return weights
+})
+
+// contextIntent maps words in a question to the collections that answer it
+type contextIntent struct {
+	name        string
+	keywords    []string
+	collections []string
+}
+
+var contextIntents = []contextIntent{
+	{"education", []string{"education", "degree", "university", "college", "school", "study", "studied", "major", "graduate", "gpa"},
+		[]string{"education", "certifications"}},
+	{"projects", []string{"project", "built", "build", "portfolio", "app", "demo", "repo", "github", "side project"},
+		[]string{"projects"}},
+	{"experience", []string{"experience", "work", "job", "employer", "company", "role", "career", "resume", "position"},
+		[]string{"experience", "resumes"}},
+	{"skills", []string{"skill", "language", "technolog", "stack", "framework", "proficien", "know"},
+		[]string{"skills", "projects"}},
+	{"evidence", []string{"really", "actually", "proof", "prove", "evidence", "verif", "certif", "can she", "can he", "can they"},
+		[]string{"skills", "projects", "certifications"}},
+	{"writing", []string{"blog", "post", "article", "wrote", "write", "paper", "publication", "talk", "conference", "speak"},
+		[]string{"posts", "publications", "talks"}},
+	{"recognition", []string{"award", "prize", "hackathon", "honor", "recommend", "testimonial", "reference"},
+		[]string{"awards", "testimonials"}},
+	{"availability", []string{"available", "availability", "hire", "hiring", "right now", "currently", "learning", "reading"},
+		[]string{"now"}},
+	{"recent", []string{"lately", "recent", "new", "latest", "these days", "this month", "this week", "been working", "been up to"},
+		[]string{"recent_changes", "now"}},
+}
+
+// queryContextWeights returns the collection weights for a question, boosting the collections of every
+// intent it mentions, and the names of those intents
+func queryContextWeights(query string) (map[string]float64, []string) {
+	weights := make(map[string]float64)
+	for name, weight := range contextWeights() {
+		weights[name] = weight
+	}
+
+	// Keywords match at the start of a word, so "project" matches "projects" but "app" does not match "happy"
+	query = " " + strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return ' '
+	}, query)
+	var intents []string
+	boosted := make(map[string]bool)
+	for _, intent := range contextIntents {
+		for _, keyword := range intent.keywords {
+			if strings.Contains(query, " "+keyword) {
+				intents = append(intents, intent.name)
+				for _, collection := range intent.collections {
+					boosted[collection] = true
+				}
+				break
+			}
+		}
+	}
+	for collection := range boosted {
+		weights[collection] *= contextIntentBoost
+	}
+	return weights, intents
+}
+
+// allocateContextBudget splits budget across the collections in proportion to their weights. A collection
+// that needs less than its share gives the rest to the others, so the budget is never left unused while
+// something still does not fit. sizes holds the characters each collection needs in full.
+func allocateContextBudget(weights map[string]float64, sizes map[string]int, budget int) map[string]int {
+	allocation := make(map[string]int, len(sizes))
+	remaining := budget
+	pending := make(map[string]bool)
+	for name, size := range sizes {
+		if size > 0 && weights[name] > 0 {
+			pending[name] = true
+		}
+	}
+
+	// Each round fully funds the collections that fit in their share, until none do
+	for len(pending) > 0 && remaining > 0 {
+		total := 0.0
+		for name := range pending {
+			total += weights[name]
+		}
+		settled := false
+		for name := range pending {
+			share := int(float64(remaining) * weights[name] / total)
+			if need := sizes[name] - allocation[name]; need <= share {
+				allocation[name] += need
+				remaining -= need
+				delete(pending, name)
+				settled = true
+			}
+		}
+		if !settled {
+			for name := range pending {
+				allocation[name] += int(float64(remaining) * weights[name] / total)
+			}
+			break
+		}
+	}
+	return allocation
+}
+
+// verboseContextFields are left out of documents once the context is over budget; they are either
+// presentation details or duplicated in a more useful field
+var verboseContextFields = map[string]bool{
+	"body_html":        true,
+	"thumbnail":        true,
+	"badge_image_url":  true,
+	"name_i18n":        true,
+	"description_i18n": true,
+	"major_i18n":       true,
+	"created_at":       true,
+	"updated_at":       true,
+	"synced_at":        true,
+	"default_branch":   true,
+	"visibility":       true,
+	"display_order":    true,
+	"word_count":       true,
+	"reading_minutes":  true,
+	"approved":         true,
+	"draft":            true,
+	"source":           true,
+	"external_id":      true,
+}
+
+// trimContextDocument drops verbose fields and shortens long text in a serialized document
+func trimContextDocument(raw json.RawMessage) json.RawMessage {
+	var document interface{}
+	if err := json.Unmarshal(raw, &document); err != nil {
+		return raw
+	}
+	trimmed, err := json.Marshal(trimContextValue(document))
+	if err != nil {
+		return raw
+	}
+	return trimmed
+}
+
+func trimContextValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, field := range value {
+			if verboseContextFields[key] || field == nil {
+				delete(value, key)
+				continue
+			}
+			value[key] = trimContextValue(field)
+		}
+		return value
+	case []interface{}:
+		for i, item := range value {
+			value[i] = trimContextValue(item)
+		}
+		return value
+	case string:
+		if len(value) <= maxContextFieldLength {
+			return value
+		}
+		cut := value[:maxContextFieldLength]
+		for !utf8.ValidString(cut) {
+			cut = cut[:len(cut)-1]
+		}
+		if space := strings.LastIndexAny(cut, " \n"); space > maxContextFieldLength/2 {
+			cut = cut[:space]
+		}
+		return cut + "…"
+	default:
+		return value
+	}
+}
+
+// defaultContextPruneFields are left out of every context document, along with empty values: IDs and
+// references the model cannot resolve, and image and verification links it has no use for. A leading "*"
+// matches a suffix. Override with CONTEXT_PRUNE_FIELDS, e.g. "website,-slug" prunes website and keeps slug.
+var defaultContextPruneFields = []string{"id", "*_id", "*_ids", "slug", "thumbnail", "image_url", "badge_image_url", "verification_url"}
+
+// contextPruneRules are the exact field names and the suffixes pruned from context documents
+type contextPruneRules struct {
+	fields   map[string]bool
+	suffixes []string
+}
+
+// contextPrune loads CONTEXT_PRUNE_FIELDS once on top of the defaults
+var contextPrune = sync.OnceValue(func() contextPruneRules {
+	patterns := make(map[string]bool)
+	for _, pattern := range defaultContextPruneFields {
+		patterns[pattern] = true
+	}
+	for _, pattern := range strings.Split(os.Getenv("CONTEXT_PRUNE_FIELDS"), ",") {
+		pattern = strings.TrimSpace(pattern)
+		if keep, found := strings.CutPrefix(pattern, "-"); found {
+			delete(patterns, keep)
+		} else if pattern != "" {
+			patterns[pattern] = true
+		}
+	}
+
+	rules := contextPruneRules{fields: make(map[string]bool)}
+	for pattern := range patterns {
+		if suffix, found := strings.CutPrefix(pattern, "*"); found {
+			rules.suffixes = append(rules.suffixes, suffix)
+		} else {
+			rules.fields[pattern] = true
+		}
+	}
+	return rules
+})
+
+// prunes reports whether a field is left out of context documents
+func (r contextPruneRules) prunes(field string) bool {
+	if r.fields[field] {
+		return true
+	}
+	for _, suffix := range r.suffixes {
+		if strings.HasSuffix(field, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// compactContextJSON serializes a value for a prompt without indentation, pruned fields or empty values
+func compactContextJSON(value interface{}) (json.RawMessage, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var document interface{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+	document, _ = compactContextValue(document, contextPrune())
+	if document == nil {
+		return json.RawMessage("null"), nil
+	}
+	return json.Marshal(document)
+}
+
+// zeroTimeJSON is how an unset time.Time serializes
+const zeroTimeJSON = "0001-01-01T00:00:00Z"
+
+// compactContextValue prunes fields and empty values, reporting false when nothing is left of the value.
+// False and zero are kept since they can be meaningful, such as an unavailable "now" entry.
+func compactContextValue(value interface{}, rules contextPruneRules) (interface{}, bool) {
+	switch value := value.(type) {
+	case nil:
+		return nil, false
+	case string:
+		return value, value != "" && value != zeroTimeJSON
+	case map[string]interface{}:
+		for key, field := range value {
+			compacted, ok := compactContextValue(field, rules)
+			if !ok || rules.prunes(key) {
+				delete(value, key)
+				continue
+			}
+			value[key] = compacted
+		}
+		return value, len(value) > 0
+	case []interface{}:
+		items := value[:0]
+		for _, item := range value {
+			if compacted, ok := compactContextValue(item, rules); ok {
+				items = append(items, compacted)
+			}
+		}
+		return items, len(items) > 0
+	default:
+		return value, true
+	}
+}
+
+// contextCollectionNames orders the collections of the search results by weight, heaviest first, falling
+// back to contextCollectionOrder and then the name
+func contextCollectionNames(results map[string]interface{}, weights map[string]float64) []string {
+	rank := make(map[string]int, len(contextCollectionOrder))
+	for i, name := range contextCollectionOrder {
+		rank[name] = i + 1
+	}
+	names := make([]string, 0, len(results))
+	for name := range results {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		a, b := names[i], names[j]
+		if weights[a] != weights[b] {
+			return weights[a] > weights[b]
+		}
+		if rank[a] != rank[b] {
+			if rank[a] == 0 || rank[b] == 0 {
+				return rank[b] == 0
+			}
+			return rank[a] < rank[b]
+		}
+		return a < b
+	})
+	return names
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/openai/openai-go"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// chatContextLength is the character budget for the portfolio data in a chatbot prompt
+	chatContextLength = 8000
+	// maxSummaryLength caps each generated summary
+	maxSummaryLength = 400
+	// maxNewSummaries bounds how many summaries one request may generate; cached ones are free
+	maxNewSummaries = 10
+)
+
+// contextCollectionOrder breaks ties between collections of equal weight when the context is over budget.
+// Collections not listed follow in alphabetical order.
+var contextCollectionOrder = []string{"authors", "now", "recent_changes", "projects", "experience", "resumes", "skills", "education", "certifications",
+	"awards", "testimonials", "publications", "talks", "posts"}
+
+// ContextSummary is a cached summary of one portfolio document. The ID hashes the document's JSON and the
+// model, so an edited document is summarized again; stale summaries expire through the retention policy.
+type ContextSummary struct {
+	ID         string    `bson:"_id" json:"id"`
+	Collection string    `bson:"collection" json:"collection"`
+	Model      string    `bson:"model" json:"model"`
+	Summary    string    `bson:"summary" json:"summary"`
+	CreatedAt  time.Time `bson:"created_at" json:"created_at"`
+	UsedAt     time.Time `bson:"used_at" json:"used_at"`
+}
+
+func contextSummaryKey(collection string, document []byte, model string) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s\n%s\n", collection, model)
+	hash.Write(document)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// GetContextSummary returns a cached summary, marking it as used at most once a day
+func (ps *PortfolioService) GetContextSummary(ctx context.Context, key string) (*ContextSummary, error) {
+	var summary ContextSummary
+	if err := ps.contextSummaries.FindOne(ctx, bson.M{"_id": key}).Decode(&summary); err != nil {
+		return nil, err
+	}
+	if time.Since(summary.UsedAt) > 24*time.Hour {
+		if _, err := ps.contextSummaries.UpdateOne(ctx, bson.M{"_id": key}, bson.M{"$set": bson.M{"used_at": time.Now()}}); err != nil {
+			log.Printf("Error marking context summary as used: %v", err)
+		}
+	}
+	return &summary, nil
+}
+
+func (ps *PortfolioService) SaveContextSummary(ctx context.Context, summary *ContextSummary) error {
+	_, err := ps.contextSummaries.ReplaceOne(ctx, bson.M{"_id": summary.ID}, summary, options.Replace().SetUpsert(true))
+	return err
+}
+
+// summarizeDocument returns a short plain-text summary of a portfolio document, from the cache when possible.
+// The second result reports whether the summary was generated by this call.
+func (l *LLMService) summarizeDocument(ctx context.Context, collection string, document []byte, allowNew bool) (string, bool, error) {
+	key := contextSummaryKey(collection, document, l.summaryModel)
+	cached, err := l.portfolioService.GetContextSummary(ctx, key)
+	if err == nil {
+		return cached.Summary, false, nil
+	}
+	if err != mongo.ErrNoDocuments {
+		return "", false, err
+	}
+	if !allowNew {
+		return "", false, nil
+	}
+
+	prompt := fmt.Sprintf(`Summarize this %s document from a developer portfolio in at most 60 words of plain text. Keep names, titles, dates, technologies, employers and numbers; drop links and IDs. Do not add anything that is not in the document.
+
+DOCUMENT:
+%s`, collection, document)
+	completion, err := l.createCompletion(ctx, "summary", openai.ChatCompletionNewParams{
+		Messages: []openai.ChatCompletionMessageParamUnion{
+			openai.UserMessage(prompt),
+		},
+		Model: l.summaryModel,
+	})
+	if err != nil {
+		return "", false, fmt.Errorf("OpenAI API error: %w", err)
+	}
+	if len(completion.Choices) == 0 {
+		return "", false, fmt.Errorf("no choices returned from OpenAI")
+	}
+	text := strings.TrimSpace(completion.Choices[0].Message.Content)
+	if len(text) > maxSummaryLength {
+		text = strings.ToValidUTF8(text[:maxSummaryLength], "") + "…"
+	}
+
+	now := time.Now()
+	summary := &ContextSummary{ID: key, Collection: collection, Model: l.summaryModel, Summary: text, CreatedAt: now, UsedAt: now}
+	if err := l.portfolioService.SaveContextSummary(ctx, summary); err != nil {
+		log.Printf("Error caching context summary: %v", err)
+	}
+	return text, true, nil
+}
+
+// contextDocument is one document of the search results, serialized on its own
+type contextDocument struct {
+	collection string
+	raw        json.RawMessage // Trimmed but not pruned; summaries are keyed and generated from it
+	compact    json.RawMessage // As sent in the prompt
+}
+
+// buildContext serializes search results for a prompt within budget characters, compacted with
+// compactContextJSON. Projects and resumes are replaced by their precomputed summaries where available, and
+// results that then fit are sent as-is. Otherwise verbose fields are trimmed and the budget is split across collections by weight, favouring
+// the collections the query is about (see queryContextWeights). Three quarters of each share holds whole
+// documents; the documents that do not fit are replaced by cached or newly generated summaries, and whatever
+// still does not fit is left out and counted under "omitted_documents". The result is always valid JSON.
+func (l *LLMService) buildContext(ctx context.Context, query string, searchResults map[string]interface{}, budget int) (string, error) {
+	results := make(map[string]interface{}, len(searchResults))
+	for name, documents := range searchResults {
+		results[name] = documents
+	}
+	l.applyPrecomputedSummaries(ctx, results)
+
+	full, err := compactContextJSON(results)
+	if err != nil {
+		return "", err
+	}
+	if len(full) <= budget {
+		return string(full), nil
+	}
+
+	weights, intents := queryContextWeights(query)
+	for name := range results {
+		if _, ok := weights[name]; !ok {
+			weights[name] = 0.5
+		}
+	}
+	names := contextCollectionNames(results, weights)
+
+	documents := make(map[string][]contextDocument)
+	sizes := make(map[string]int)
+	for _, name := range names {
+		data, err := json.Marshal(results[name])
+		if err != nil {
+			return "", err
+		}
+		var collection []json.RawMessage
+		if err := json.Unmarshal(data, &collection); err != nil {
+			continue // null for a collection with no matches
+		}
+		for _, raw := range collection {
+			document := contextDocument{collection: name, raw: trimContextDocument(raw)}
+			if document.compact, err = compactContextJSON(document.raw); err != nil {
+				return "", err
+			}
+			if string(document.compact) == "null" {
+				continue // Nothing left after pruning
+			}
+			documents[name] = append(documents[name], document)
+			sizes[name] += len(document.compact)
+		}
+	}
+	allocation := allocateContextBudget(weights, sizes, budget*3/4)
+
+	packed := make(map[string][]json.RawMessage)
+	used := 0
+	var overflow []contextDocument
+	for _, name := range names {
+		if _, ok := documents[name]; !ok {
+			continue
+		}
+		packed[name] = []json.RawMessage{}
+		spent := 0
+		for _, document := range documents[name] {
+			if spent+len(document.compact) <= allocation[name] {
+				packed[name] = append(packed[name], document.compact)
+				spent += len(document.compact)
+			} else {
+				overflow = append(overflow, document)
+			}
+		}
+		used += spent
+	}
+
+	omitted, generated := 0, 0
+	for _, document := range overflow {
+		if isPrecomputedSummary(document.collection, document.raw) {
+			if used+len(document.compact) > budget {
+				omitted++
+				continue
+			}
+			packed[document.collection] = append(packed[document.collection], document.compact)
+			used += len(document.compact)
+			continue
+		}
+
+		// A dry run only uses summaries that are already cached
+		summary, isNew, err := l.summarizeDocument(ctx, document.collection, document.raw, generated < maxNewSummaries && !isChatPreview(ctx))
+		if err != nil {
+			log.Printf("Error summarizing %s document: %v", document.collection, err)
+		}
+		if isNew {
+			generated++
+		}
+		if summary == "" {
+			omitted++
+			continue
+		}
+
+		var ref struct {
+			ID json.RawMessage `json:"id"`
+		}
+		json.Unmarshal(document.compact, &ref) // No ID when IDs are pruned
+		entry, _ := json.Marshal(struct {
+			ID      json.RawMessage `json:"id,omitempty"`
+			Summary string          `json:"summary"`
+		}{ref.ID, summary})
+		if used+len(entry) > budget {
+			omitted++
+			continue
+		}
+		packed[document.collection] = append(packed[document.collection], entry)
+		used += len(entry)
+	}
+	log.Printf("Context over budget (%d characters, intents %v): %d documents summarized (%d new), %d omitted",
+		len(full), intents, len(overflow)-omitted, generated, omitted)
+
+	packedContext := make(map[string]interface{}, len(packed)+1)
+	for name, documents := range packed {
+		packedContext[name] = documents
+	}
+	if omitted > 0 {
+		packedContext["omitted_documents"] = omitted
+	}
+	data, err := json.Marshal(packedContext)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"golang.org/x/sync/errgroup"
+)
+
+// collectionCounter counts the documents of one collection a visitor can see; hidden projects are only
+// counted for admins
+type collectionCounter func(ctx context.Context, includeHidden bool) (int64, error)
+
+// collectionCounters returns the counter of every public collection, keyed by the name used in /api/counts
+func (ps *PortfolioService) collectionCounters() map[string]collectionCounter {
+	return map[string]collectionCounter{
+		"authors":        func(ctx context.Context, _ bool) (int64, error) { return ps.CountAuthors(ctx) },
+		"projects":       ps.CountProjects,
+		"education":      func(ctx context.Context, _ bool) (int64, error) { return ps.CountEducation(ctx) },
+		"resumes":        func(ctx context.Context, _ bool) (int64, error) { return ps.CountResumes(ctx) },
+		"experience":     func(ctx context.Context, _ bool) (int64, error) { return ps.CountExperience(ctx) },
+		"posts":          func(ctx context.Context, _ bool) (int64, error) { return ps.CountPosts(ctx) },
+		"awards":         func(ctx context.Context, _ bool) (int64, error) { return ps.CountAwards(ctx) },
+		"certifications": func(ctx context.Context, _ bool) (int64, error) { return ps.CountCertifications(ctx) },
+		"testimonials": func(ctx context.Context, _ bool) (int64, error) {
+			return ps.testimonials.CountDocuments(ctx, bson.M{"approved": true})
+		},
+		"publications": func(ctx context.Context, _ bool) (int64, error) {
+			return ps.publications.CountDocuments(ctx, bson.M{})
+		},
+		"talks":  func(ctx context.Context, _ bool) (int64, error) { return ps.talks.CountDocuments(ctx, bson.M{}) },
+		"skills": func(ctx context.Context, _ bool) (int64, error) { return ps.skills.CountDocuments(ctx, bson.M{}) },
+	}
+}
+
+// countedCollections lists the collection names accepted by /api/counts, sorted
+func countedCollections(counters map[string]collectionCounter) []string {
+	names := make([]string, 0, len(counters))
+	for name := range counters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CountCollections counts the named collections concurrently. Any failing count fails the whole call.
+func (ps *PortfolioService) CountCollections(ctx context.Context, names []string, includeHidden bool) (map[string]int64, error) {
+	counters := ps.collectionCounters()
+	counts := make(map[string]int64, len(names))
+	var mutex sync.Mutex
+
+	group, groupCtx := errgroup.WithContext(ctx)
+	for _, name := range names {
+		name, counter := name, counters[name]
+		group.Go(func() error {
+			count, err := counter(groupCtx, includeHidden)
+			if err != nil {
+				return err
+			}
+			mutex.Lock()
+			counts[name] = count
+			mutex.Unlock()
+			return nil
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// Counts endpoint: GET /api/counts returns the document count of every public collection in one round
+// trip, e.g. {"authors": 2, "projects": 14, ...}. ?collections=projects,posts restricts the response to
+// the listed collections. Hidden projects are counted for admins only.
+func (h *APIHandler) handleCounts(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	names := countedCollections(h.service.collectionCounters())
+	if value := r.URL.Query().Get("collections"); value != "" {
+		allowed := names
+		names = nil
+		seen := make(map[string]bool)
+		var v Validator
+		for _, name := range strings.Split(value, ",") {
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name == "" || seen[name] {
+				continue
+			}
+			if v.OneOf("collections", name, allowed...) {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+		if err := v.Err(); err != nil {
+			log.Printf("Date: %s | Route: /api/counts | Status: BAD_REQUEST | GPT Model: %s", currentTime, gptModel)
+			writeInvalidInput(w, err)
+			return
+		}
+	}
+
+	counts, err := h.service.CountCollections(r.Context(), names, isAdminRequest(r))
+	if err != nil {
+		log.Printf("Date: %s | Route: /api/counts | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Date: %s | Route: /api/counts | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(counts)
+}
+
+// handleCollectionCount serves the legacy GET /api/{collection}/count routes, kept as aliases of
+// /api/counts for existing clients; they answer {"count": n}
+func (h *APIHandler) handleCollectionCount(collection string) http.HandlerFunc {
+	route := "/api/" + collection + "/count"
+	return func(w http.ResponseWriter, r *http.Request) {
+		currentTime := time.Now().Format("2006-01-02 15:04:05")
+		gptModel := "DISABLED"
+		if h.llmService != nil {
+			gptModel = h.llmService.model
+		}
+
+		h.enableCORS(w)
+		if r.Method == "OPTIONS" {
+			return
+		}
+
+		counts, err := h.service.CountCollections(r.Context(), []string{collection}, isAdminRequest(r))
+		if err != nil {
+			log.Printf("Date: %s | Route: %s | Status: ERROR | GPT Model: %s", currentTime, route, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Date: %s | Route: %s | Status: SUCCESS | GPT Model: %s", currentTime, route, gptModel)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]int64{"count": counts[collection]})
+	}
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxSnapshotBytes is the largest response kept as a snapshot
+const maxSnapshotBytes = 1 << 20
+
+// snapshotQueryParams are the query parameters public reads are filtered by; requests with any other
+// parameter are not snapshotted, so arbitrary query strings cannot fill the store
+var snapshotQueryParams = map[string]bool{
+	"archived":    true,
+	"author_id":   true,
+	"category":    true,
+	"collections": true,
+	"company":     true,
+	"format":      true,
+	"issuer":      true,
+	"lang":        true,
+	"limit":       true,
+	"major":       true,
+	"name":        true,
+	"render":      true,
+	"skill":       true,
+	"sort":        true,
+	"student_id":  true,
+	"tag":         true,
+	"technology":  true,
+	"type":        true,
+	"types":       true,
+	"university":  true,
+}
+
+// snapshotExcludedPrefixes are GET routes that are not portfolio reads: admin, media, third-party data with
+// its own stale cache, and per-visitor endpoints
+var snapshotExcludedPrefixes = []string{
+	"/api/admin/",
+	"/api/media/",
+	"/api/chatbot",
+	"/api/events",
+	"/api/analytics/",
+	"/api/availability",
+	"/api/github/",
+	"/api/stats/",
+	"/api/tools/",
+	"/api/webhooks/",
+}
+
+// readSnapshot is a successful response to a read endpoint
+type readSnapshot struct {
+	header  http.Header
+	body    []byte
+	takenAt time.Time
+}
+
+// ReadSnapshots keeps the latest successful response of each public read endpoint in memory, so the site
+// keeps serving its (rarely changing) data while MongoDB is unreachable. Snapshots are per replica and
+// only served when a request fails and MongoDB does not answer a ping.
+type ReadSnapshots struct {
+	service    *PortfolioService
+	maxEntries int
+	maxBytes   int
+	mutex      sync.Mutex
+	entries    map[string]*readSnapshot
+	bytes      int // Total body size of the entries
+	pingedAt   time.Time
+	reachable  bool
+}
+
+// NewReadSnapshots creates the snapshot store, holding up to READ_SNAPSHOT_ENTRIES responses (default 500)
+// of READ_SNAPSHOT_BYTES in total (default 32MB)
+func NewReadSnapshots(service *PortfolioService) *ReadSnapshots {
+	maxEntries := 500
+	if value := os.Getenv("READ_SNAPSHOT_ENTRIES"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			log.Printf("Invalid READ_SNAPSHOT_ENTRIES %q, using %d", value, maxEntries)
+		} else {
+			maxEntries = parsed
+		}
+	}
+	maxBytes := 32 << 20
+	if value := os.Getenv("READ_SNAPSHOT_BYTES"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			log.Printf("Invalid READ_SNAPSHOT_BYTES %q, using %d", value, maxBytes)
+		} else {
+			maxBytes = parsed
+		}
+	}
+	return &ReadSnapshots{
+		service:    service,
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+		entries:    make(map[string]*readSnapshot),
+	}
+}
+
+// snapshotKey identifies a response by everything public read endpoints vary on, or "" if the request
+// is not snapshotted. Authenticated requests are skipped so admin-only data is never served to visitors.
+func snapshotKey(r *http.Request) string {
+	if r.Method != "GET" || r.Header.Get("Authorization") != "" || wantsNDJSON(r) {
+		return "" // Streamed responses are not buffered
+	}
+	path := r.URL.Path
+	if !strings.HasPrefix(path, "/api/") && path != "/feed.xml" && path != "/resume" && !strings.HasPrefix(path, "/projects/") {
+		return ""
+	}
+	for _, prefix := range snapshotExcludedPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return ""
+		}
+	}
+	query := r.URL.Query()
+	for name := range query {
+		if !snapshotQueryParams[name] {
+			return ""
+		}
+	}
+	// Encode sorts the parameters, so their order in the request does not matter
+	key := normalizeHost(r.Host) + "|" + path
+	if len(query) > 0 {
+		key += "?" + url.Values(query).Encode()
+	}
+	return key + "|" + r.Header.Get("Accept-Language")
+}
+
+// bufferedResponse holds a handler's response until it is known whether to send it or a snapshot
+type bufferedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedResponse) Header() http.Header { return b.header }
+
+func (b *bufferedResponse) WriteHeader(status int) {
+	if b.status == 0 {
+		b.status = status
+	}
+}
+
+func (b *bufferedResponse) Write(p []byte) (int, error) {
+	if b.status == 0 {
+		b.status = http.StatusOK
+	}
+	return b.body.Write(p)
+}
+
+// Middleware snapshots successful reads and answers failed ones from the latest snapshot, marked with
+// X-Data-Stale and an Age header, when MongoDB is unreachable
+func (s *ReadSnapshots) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := snapshotKey(r)
+		if key == "" || s.maxEntries == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		response := &bufferedResponse{header: make(http.Header)}
+		next.ServeHTTP(response, r)
+		if response.status == 0 {
+			response.status = http.StatusOK
+		}
+
+		if response.status >= 500 && !s.mongoReachable(r.Context()) {
+			if snapshot := s.get(key); snapshot != nil {
+				log.Printf("MongoDB unreachable, serving %s from a snapshot taken %s", r.URL.Path, snapshot.takenAt.Format(time.RFC3339))
+				for name, values := range snapshot.header {
+					w.Header()[name] = values
+				}
+				w.Header().Set("X-Data-Stale", "true")
+				w.Header().Set("Age", strconv.Itoa(int(time.Since(snapshot.takenAt).Seconds())))
+				w.WriteHeader(http.StatusOK)
+				w.Write(snapshot.body)
+				return
+			}
+		}
+
+		for name, values := range response.header {
+			w.Header()[name] = values
+		}
+		w.WriteHeader(response.status)
+		w.Write(response.body.Bytes())
+
+		if response.status == http.StatusOK && response.body.Len() <= min(maxSnapshotBytes, s.maxBytes) {
+			s.put(key, &readSnapshot{header: response.header, body: response.body.Bytes(), takenAt: time.Now()})
+		}
+	})
+}
+
+func (s *ReadSnapshots) get(key string) *readSnapshot {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.entries[key]
+}
+
+// put stores a snapshot, evicting the oldest ones while the store is over its entry or byte limit
+func (s *ReadSnapshots) put(key string, snapshot *readSnapshot) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if previous, exists := s.entries[key]; exists {
+		s.bytes -= len(previous.body)
+		delete(s.entries, key)
+	}
+	for len(s.entries) > 0 && (len(s.entries) >= s.maxEntries || s.bytes+len(snapshot.body) > s.maxBytes) {
+		var oldestKey string
+		var oldest time.Time
+		for k, entry := range s.entries {
+			if oldestKey == "" || entry.takenAt.Before(oldest) {
+				oldestKey, oldest = k, entry.takenAt
+			}
+		}
+		s.bytes -= len(s.entries[oldestKey].body)
+		delete(s.entries, oldestKey)
+	}
+	s.entries[key] = snapshot
+	s.bytes += len(snapshot.body)
+}
+
+// mongoReachable pings MongoDB, reusing the result for a few seconds so an outage does not add a ping
+// to every failing request
+func (s *ReadSnapshots) mongoReachable(ctx context.Context) bool {
+	s.mutex.Lock()
+	if time.Since(s.pingedAt) < 5*time.Second {
+		reachable := s.reachable
+		s.mutex.Unlock()
+		return reachable
+	}
+	s.mutex.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	reachable := s.service.client.Ping(ctx, nil) == nil
+
+	s.mutex.Lock()
+	s.pingedAt = time.Now()
+	s.reachable = reachable
+	s.mutex.Unlock()
+	return reachable
+}
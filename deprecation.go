@@ -0,0 +1,164 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// changelogRoute documents every deprecation; deprecated responses link to it
+const changelogRoute = "/api/changelog"
+
+// Deprecation marks a route, or a field in a route's responses, as deprecated. Deprecated routes keep
+// working until their sunset date and answer 410 Gone afterwards; deprecated fields are only announced,
+// removing them is a code change.
+type Deprecation struct {
+	Routes      []string   `json:"routes"`          // Exact paths; a trailing slash matches the subtree
+	Field       string     `json:"field,omitempty"` // Empty when the whole route is deprecated
+	Since       time.Time  `json:"since"`
+	Sunset      *time.Time `json:"sunset,omitempty"` // When the route stops answering; nil if not scheduled
+	Replacement string     `json:"replacement,omitempty"`
+	Note        string     `json:"note"`
+
+	requests atomic.Int64 // Requests to a deprecated route or route with a deprecated field since startup
+}
+
+// deprecationDate parses a date of the registry below, which is fixed at compile time
+func deprecationDate(value string) time.Time {
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		panic(fmt.Sprintf("invalid deprecation date %q", value))
+	}
+	return date
+}
+
+// deprecationSunset is deprecationDate for an optional sunset
+func deprecationSunset(value string) *time.Time {
+	date := deprecationDate(value)
+	return &date
+}
+
+// deprecations is the registry of deprecated routes and fields, newest last. Add an entry here when
+// changing the API, give consumers at least three months before the sunset, and remove the route or
+// field once it has passed.
+var deprecations = []*Deprecation{
+	{
+		Routes:      []string{"/api/resumes", "/api/experience", "/api/experience/"},
+		Field:       "time_present",
+		Since:       deprecationDate("2026-10-16"),
+		Replacement: "start_date, end_date",
+		Note:        "Experience now has start and end dates; time_present is derived from them and will be removed once clients compute durations themselves.",
+	},
+}
+
+// matches reports whether the deprecation applies to a request path
+func (d *Deprecation) matches(path string) bool {
+	for _, route := range d.Routes {
+		if path == route || (strings.HasSuffix(route, "/") && strings.HasPrefix(path, route)) {
+			return true
+		}
+	}
+	return false
+}
+
+// sunsetPassed reports whether a deprecated route has stopped answering
+func (d *Deprecation) sunsetPassed(now time.Time) bool {
+	return d.Field == "" && d.Sunset != nil && !now.Before(*d.Sunset)
+}
+
+// deprecationMiddleware announces deprecations on the affected responses: a deprecated route gets the
+// Deprecation (RFC 9745) and Sunset (RFC 8594) headers, a deprecated field the X-Deprecated-Fields header,
+// and both a Link to the changelog. A route past its sunset answers 410 Gone.
+func deprecationMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		now := time.Now()
+		var fields []string
+		announced := false
+		for _, deprecation := range deprecations {
+			if !deprecation.matches(r.URL.Path) {
+				continue
+			}
+			announced = true
+			deprecation.requests.Add(1)
+			if deprecation.Field != "" {
+				fields = append(fields, deprecation.Field)
+				continue
+			}
+
+			w.Header().Set("Deprecation", fmt.Sprintf("@%d", deprecation.Since.Unix()))
+			if deprecation.Sunset != nil {
+				w.Header().Set("Sunset", deprecation.Sunset.UTC().Format(http.TimeFormat))
+			}
+			if deprecation.Replacement != "" {
+				w.Header().Add("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", deprecation.Replacement))
+			}
+			if deprecation.sunsetPassed(now) {
+				w.Header().Add("Link", fmt.Sprintf("<%s>; rel=\"deprecation\"", changelogRoute))
+				w.Header().Set("Access-Control-Allow-Origin", "*")
+				http.Error(w, fmt.Sprintf("This endpoint was removed on %s. %s", deprecation.Sunset.Format("2006-01-02"), deprecation.Note), http.StatusGone)
+				return
+			}
+		}
+
+		if announced {
+			if len(fields) > 0 {
+				w.Header().Set("X-Deprecated-Fields", strings.Join(fields, ", "))
+			}
+			w.Header().Add("Link", fmt.Sprintf("<%s>; rel=\"deprecation\"", changelogRoute))
+			w.Header().Set("Access-Control-Expose-Headers", "Deprecation, Sunset, Link, X-Deprecated-Fields")
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// changelogEntry is a deprecation as listed by the changelog
+type changelogEntry struct {
+	*Deprecation
+	Status   string `json:"status"`             // "deprecated", or "removed" once the sunset has passed
+	Requests *int64 `json:"requests,omitempty"` // Admins only: requests since startup, to tell when consumers have migrated
+}
+
+// Changelog endpoint: GET /api/changelog lists deprecated routes and fields, newest first
+func (h *APIHandler) handleChangelog(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if r.Method != "GET" {
+		log.Printf("Date: %s | Route: /api/changelog | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	admin := isAdminRequest(r)
+	now := time.Now()
+	entries := make([]changelogEntry, 0, len(deprecations))
+	for _, deprecation := range deprecations {
+		entry := changelogEntry{Deprecation: deprecation, Status: "deprecated"}
+		if deprecation.sunsetPassed(now) {
+			entry.Status = "removed"
+		}
+		if admin {
+			requests := deprecation.requests.Load()
+			entry.Requests = &requests
+		}
+		entries = append(entries, entry)
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Since.After(entries[j].Since) })
+
+	log.Printf("Date: %s | Route: /api/changelog | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"deprecations": entries})
+}
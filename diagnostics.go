@@ -0,0 +1,297 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Diagnostic check results; a report fails if any check fails, warnings only flag degraded features
+const (
+	DiagnosticPass = "pass"
+	DiagnosticWarn = "warn"
+	DiagnosticFail = "fail"
+)
+
+// DiagnosticCheck is the result of one self-check
+type DiagnosticCheck struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Message    string `json:"message,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// DiagnosticsReport summarizes every self-check, with the document count of each collection
+type DiagnosticsReport struct {
+	Status    string            `json:"status"`
+	CheckedAt time.Time         `json:"checked_at"`
+	Checks    []DiagnosticCheck `json:"checks"`
+	Counts    map[string]int64  `json:"counts,omitempty"`
+}
+
+// durationSettings are the environment variables read with time.ParseDuration
+var durationSettings = []string{
+	"REPO_SYNC_INTERVAL", "CREDLY_SYNC_INTERVAL", "DOCUMENT_SUMMARY_INTERVAL", "RETENTION_INTERVAL",
+	"JOB_POLL_INTERVAL", "GITHUB_ACTIVITY_CACHE_TTL", "WAKATIME_CACHE_TTL", "PROFILE_STATS_CACHE_TTL",
+	"ALERT_WINDOW", "ALERT_COOLDOWN", "QUERY_MAX_TIME", "SEARCH_COLLECTION_TIMEOUT",
+	"LLM_QUEUE_TIMEOUT",
+}
+
+// expectedIndexes are the indexes the busiest queries rely on. The API does not create them, so a missing
+// one is a warning: queries still work, only slower.
+var expectedIndexes = []struct {
+	collection string
+	keys       []string
+}{
+	{"domains", []string{"host"}},
+	{"projects", []string{"slug"}},
+	{"chat_turns", []string{"created_at"}},
+	{"analytics", []string{"created_at"}},
+	{"jobs", []string{"status", "run_at"}},
+	{"context_summaries", []string{"used_at"}},
+}
+
+// RunDiagnostics checks configuration, MongoDB and the LLM. service is nil when MongoDB could not be
+// reached, in which case connectErr explains why and the database checks are skipped.
+func RunDiagnostics(ctx context.Context, service *PortfolioService, connectErr error, llm *LLMService) DiagnosticsReport {
+	report := DiagnosticsReport{Status: DiagnosticPass, CheckedAt: time.Now()}
+	run := func(name string, check func() (string, string)) {
+		start := time.Now()
+		status, message := check()
+		report.Checks = append(report.Checks, DiagnosticCheck{
+			Name:       name,
+			Status:     status,
+			Message:    message,
+			DurationMs: time.Since(start).Milliseconds(),
+		})
+		if status == DiagnosticFail || (status == DiagnosticWarn && report.Status == DiagnosticPass) {
+			report.Status = status
+		}
+	}
+
+	run("config", checkConfig)
+
+	run("mongodb", func() (string, string) {
+		if service == nil {
+			return DiagnosticFail, fmt.Sprintf("cannot connect: %v", connectErr)
+		}
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		if err := service.client.Ping(ctx, nil); err != nil {
+			return DiagnosticFail, fmt.Sprintf("ping failed: %v", err)
+		}
+		return DiagnosticPass, "database " + service.database.Name()
+	})
+
+	if service != nil {
+		run("indexes", func() (string, string) { return service.checkIndexes(ctx) })
+		run("collections", func() (string, string) {
+			counts, err := service.collectionCounts(ctx)
+			if err != nil {
+				return DiagnosticFail, err.Error()
+			}
+			report.Counts = counts
+			if counts["authors"] == 0 {
+				return DiagnosticWarn, "no authors, the portfolio is empty"
+			}
+			return DiagnosticPass, fmt.Sprintf("%d collections", len(counts))
+		})
+	}
+
+	run("llm", func() (string, string) {
+		if llm == nil {
+			return DiagnosticWarn, "OPENAI_API_KEY not set, chatbot disabled"
+		}
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+		for _, model := range []string{llm.model, llm.summaryModel} {
+			if _, err := llm.client.Models.Get(ctx, model); err != nil {
+				return DiagnosticFail, fmt.Sprintf("model %s unavailable: %v", model, err)
+			}
+		}
+		return DiagnosticPass, "models " + llm.model + " and " + llm.summaryModel
+	})
+
+	return report
+}
+
+// checkConfig validates the settings that would otherwise only be logged and replaced by a default
+func checkConfig() (string, string) {
+	var problems, warnings []string
+
+	if _, err := fieldCipher(); err != nil {
+		problems = append(problems, err.Error())
+	}
+	if value := os.Getenv("PORT"); value != "" {
+		if port, err := strconv.Atoi(value); err != nil || port < 1 || port > 65535 {
+			problems = append(problems, fmt.Sprintf("invalid PORT %q", value))
+		}
+	}
+	for _, key := range durationSettings {
+		if value := os.Getenv(key); value != "" {
+			if parsed, err := time.ParseDuration(value); err != nil || parsed <= 0 {
+				problems = append(problems, fmt.Sprintf("invalid %s %q", key, value))
+			}
+		}
+	}
+	for key, allowed := range map[string][]string{
+		"STATE_STORE":   {"", "memory", "mongo", "mongodb"},
+		"MEDIA_STORAGE": {"", "gridfs", "s3"},
+	} {
+		value := strings.ToLower(os.Getenv(key))
+		known := false
+		for _, option := range allowed {
+			known = known || value == option
+		}
+		if !known {
+			problems = append(problems, fmt.Sprintf("unknown %s %q", key, value))
+		}
+	}
+	for _, entry := range os.Environ() {
+		key, value, _ := strings.Cut(entry, "=")
+		if !strings.HasPrefix(key, "SCHEDULE_") || strings.TrimSpace(value) == "off" {
+			continue
+		}
+		if _, err := ParseSchedule(value); err != nil {
+			problems = append(problems, fmt.Sprintf("invalid %s: %v", key, err))
+		}
+	}
+
+	if os.Getenv("MONGODB_URI") == "" {
+		warnings = append(warnings, "MONGODB_URI not set, using localhost")
+	}
+	if os.Getenv("ADMIN_TOKEN") == "" {
+		warnings = append(warnings, "ADMIN_TOKEN not set, admin endpoints disabled")
+	}
+
+	sort.Strings(problems)
+	switch {
+	case len(problems) > 0:
+		return DiagnosticFail, strings.Join(append(problems, warnings...), "; ")
+	case len(warnings) > 0:
+		return DiagnosticWarn, strings.Join(warnings, "; ")
+	default:
+		return DiagnosticPass, ""
+	}
+}
+
+// checkIndexes reports which expectedIndexes are missing. An index matches if it starts with the keys.
+func (ps *PortfolioService) checkIndexes(ctx context.Context) (string, string) {
+	var missing []string
+	for _, expected := range expectedIndexes {
+		cursor, err := ps.database.Collection(expected.collection).Indexes().List(ctx)
+		if err != nil {
+			return DiagnosticFail, fmt.Sprintf("listing %s indexes: %v", expected.collection, err)
+		}
+		var indexes []struct {
+			Key bson.D `bson:"key"`
+		}
+		if err := cursor.All(ctx, &indexes); err != nil {
+			return DiagnosticFail, fmt.Sprintf("listing %s indexes: %v", expected.collection, err)
+		}
+
+		found := false
+		for _, index := range indexes {
+			if len(index.Key) < len(expected.keys) {
+				continue
+			}
+			matches := true
+			for i, key := range expected.keys {
+				matches = matches && index.Key[i].Key == key
+			}
+			found = found || matches
+		}
+		if !found {
+			missing = append(missing, expected.collection+"("+strings.Join(expected.keys, ", ")+")")
+		}
+	}
+	if len(missing) > 0 {
+		return DiagnosticWarn, "missing indexes: " + strings.Join(missing, ", ")
+	}
+	return DiagnosticPass, fmt.Sprintf("%d expected indexes present", len(expectedIndexes))
+}
+
+// collectionCounts estimates the number of documents in every collection of the database
+func (ps *PortfolioService) collectionCounts(ctx context.Context) (map[string]int64, error) {
+	names, err := ps.database.ListCollectionNames(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("listing collections: %w", err)
+	}
+	counts := make(map[string]int64, len(names))
+	for _, name := range names {
+		count, err := ps.database.Collection(name).EstimatedDocumentCount(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("counting %s: %w", name, err)
+		}
+		counts[name] = count
+	}
+	return counts, nil
+}
+
+// runCheckCommand implements `portfolio --check`, which prints the diagnostics and exits non-zero if any
+// check failed, so deploy pipelines can gate on it
+func runCheckCommand() int {
+	var service *PortfolioService
+	var llm *LLMService
+	client, err := connectToMongoDB()
+	if err == nil {
+		defer client.Disconnect(context.TODO())
+		service = NewPortfolioService(client)
+		llm = NewLLMService(os.Getenv("OPENAI_API_KEY"), service)
+	}
+
+	report := RunDiagnostics(context.Background(), service, err, llm)
+	for _, check := range report.Checks {
+		fmt.Printf("[%s] %-12s %s\n", strings.ToUpper(check.Status), check.Name, check.Message)
+	}
+	fmt.Printf("\nResult: %s\n", strings.ToUpper(report.Status))
+
+	if report.Status == DiagnosticFail {
+		return 1
+	}
+	return 0
+}
+
+// Diagnostics endpoint: GET /api/admin/diagnostics runs the self-checks. The status is 503 if any check
+// failed (admin only)
+func (h *APIHandler) handleDiagnostics(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if r.Method != "GET" {
+		log.Printf("Date: %s | Route: /api/admin/diagnostics | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !isAdminRequest(r) {
+		log.Printf("Date: %s | Route: /api/admin/diagnostics | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	report := RunDiagnostics(r.Context(), h.service, nil, h.llmService)
+
+	log.Printf("Date: %s | Route: /api/admin/diagnostics | Status: %s | GPT Model: %s", currentTime, strings.ToUpper(report.Status), gptModel)
+	w.Header().Set("Content-Type", "application/json")
+	if report.Status == DiagnosticFail {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Deletion is the tombstone of a deleted portfolio document, kept so the diff endpoint can report it
+type Deletion struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"-"`
+	Collection string             `bson:"collection" json:"collection"`
+	DocumentID primitive.ObjectID `bson:"document_id" json:"id"`
+	DeletedAt  time.Time          `bson:"deleted_at" json:"deleted_at"`
+}
+
+// recordDeletions stores the tombstones of documents deleted from a portfolio collection. Failing to do so
+// only leaves the diff incomplete, so it is logged rather than failing the delete.
+func (ps *PortfolioService) recordDeletions(ctx context.Context, collection string, ids ...primitive.ObjectID) {
+	if len(ids) == 0 {
+		return
+	}
+	now := time.Now().UTC()
+	documents := make([]interface{}, len(ids))
+	for i, id := range ids {
+		documents[i] = Deletion{ID: primitive.NewObjectID(), Collection: collection, DocumentID: id, DeletedAt: now}
+	}
+	if _, err := ps.deletions.InsertMany(ctx, documents); err != nil {
+		log.Printf("Error recording deletion of %d %s: %v", len(ids), collection, err)
+	}
+}
+
+// PortfolioDiff lists the portfolio documents created, updated and deleted since a time, by collection.
+// Documents written before timestamps were introduced have none and are never listed.
+type PortfolioDiff struct {
+	Since     time.Time              `json:"since"`
+	Until     time.Time              `json:"until"` // Pass as since to fetch the next changes; changes at the boundary may be listed twice
+	Created   map[string]interface{} `json:"created"`
+	Updated   map[string]interface{} `json:"updated"`
+	Deleted   []Deletion             `json:"deleted"`
+	Truncated []string               `json:"truncated,omitempty"` // Collections with more changes than QUERY_MAX_DOCUMENTS; fetch a shorter period
+}
+
+// diffCollection adds a collection's documents created and updated since a time to a diff, oldest change
+// first. Documents created in the period are only listed as created.
+func diffCollection[T any](ctx context.Context, collection *mongo.Collection, name string, since time.Time, diff *PortfolioDiff) error {
+	find := func(filter bson.M, field string) ([]T, error) {
+		opts := options.Find().SetSort(bson.D{{Key: field, Value: 1}, {Key: "_id", Value: 1}})
+		cursor, err := collection.Find(ctx, filter, boundedFind(opts))
+		if err != nil {
+			return nil, err
+		}
+		documents := []T{}
+		if err = cursor.All(ctx, &documents); err != nil {
+			return nil, err
+		}
+		if int64(len(documents)) >= queryLimitsConfig().maxDocuments && !slices.Contains(diff.Truncated, name) {
+			diff.Truncated = append(diff.Truncated, name)
+		}
+		return documents, nil
+	}
+
+	created, err := find(bson.M{"created_at": bson.M{"$gte": since}}, "created_at")
+	if err != nil {
+		return err
+	}
+	updated, err := find(bson.M{
+		"updated_at": bson.M{"$gte": since},
+		"$or":        []bson.M{{"created_at": bson.M{"$lt": since}}, {"created_at": bson.M{"$exists": false}}},
+	}, "updated_at")
+	if err != nil {
+		return err
+	}
+	if len(created) > 0 {
+		diff.Created[name] = created
+	}
+	if len(updated) > 0 {
+		diff.Updated[name] = updated
+	}
+	return nil
+}
+
+// GetPortfolioDiff lists the changes to the portfolio collections since a time, or only to the given ones
+// (see searchTypes)
+func (ps *PortfolioService) GetPortfolioDiff(ctx context.Context, since time.Time, types ...string) (*PortfolioDiff, error) {
+	// Taken first, so writes made while the diff is collected are listed again next time rather than missed
+	diff := &PortfolioDiff{Since: since, Until: time.Now().UTC(), Created: map[string]interface{}{}, Updated: map[string]interface{}{}, Deleted: []Deletion{}}
+	if len(types) == 0 {
+		types = searchTypes
+	}
+
+	for _, name := range types {
+		var err error
+		switch name {
+		case "authors":
+			err = diffCollection[Author](ctx, ps.authors, name, since, diff)
+		case "projects":
+			err = diffCollection[Project](ctx, ps.projects, name, since, diff)
+		case "education":
+			err = diffCollection[Education](ctx, ps.education, name, since, diff)
+		case "resumes":
+			err = diffCollection[Resume](ctx, ps.resumes, name, since, diff)
+		case "experience":
+			err = diffCollection[Experience](ctx, ps.experience, name, since, diff)
+		case "posts":
+			err = diffCollection[Post](ctx, ps.posts, name, since, diff)
+		case "testimonials":
+			err = diffCollection[Testimonial](ctx, ps.testimonials, name, since, diff)
+		case "awards":
+			err = diffCollection[Award](ctx, ps.awards, name, since, diff)
+		case "certifications":
+			err = diffCollection[Certification](ctx, ps.certifications, name, since, diff)
+		case "publications":
+			err = diffCollection[Publication](ctx, ps.publications, name, since, diff)
+		case "talks":
+			err = diffCollection[Talk](ctx, ps.talks, name, since, diff)
+		case "skills":
+			err = diffCollection[Skill](ctx, ps.skills, name, since, diff)
+		case "now":
+			err = diffCollection[Now](ctx, ps.now, name, since, diff)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "deleted_at", Value: 1}})
+	cursor, err := ps.deletions.Find(ctx, bson.M{"deleted_at": bson.M{"$gte": since}, "collection": bson.M{"$in": types}}, boundedFind(opts))
+	if err != nil {
+		return nil, err
+	}
+	if err = cursor.All(ctx, &diff.Deleted); err != nil {
+		return nil, err
+	}
+	if int64(len(diff.Deleted)) >= queryLimitsConfig().maxDocuments {
+		diff.Truncated = append(diff.Truncated, "deletions")
+	}
+	return diff, nil
+}
+
+// parseSince reads a point in time given as RFC 3339 or as a date, which means midnight UTC
+func parseSince(value string) (time.Time, bool) {
+	if since, err := time.Parse(time.RFC3339, value); err == nil {
+		return since.UTC(), true
+	}
+	if since, err := time.Parse("2006-01-02", value); err == nil {
+		return since, true
+	}
+	return time.Time{}, false
+}
+
+// Portfolio diff endpoint: GET /api/admin/diff?since=2024-05-01T00:00:00Z&types=projects,posts lists the
+// documents created, updated and deleted since a time, for sync tools and static site rebuilds; pass the
+// returned until as the next since (admin only)
+func (h *APIHandler) handlePortfolioDiff(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if r.Method != "GET" {
+		log.Printf("Date: %s | Route: /api/admin/diff | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !isAdminRequest(r) {
+		log.Printf("Date: %s | Route: /api/admin/diff | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var v Validator
+	value := r.URL.Query().Get("since")
+	var since time.Time
+	if v.Required("since", value) {
+		var ok bool
+		since, ok = parseSince(value)
+		v.Check(ok, "since", "format", "since must be an RFC 3339 time or a YYYY-MM-DD date")
+	}
+	var types []string
+	if value := r.URL.Query().Get("types"); value != "" {
+		for _, name := range strings.Split(value, ",") {
+			name = strings.TrimSpace(name)
+			v.OneOf("types", name, searchTypes...)
+			types = append(types, name)
+		}
+	}
+	if err := v.Err(); err != nil {
+		writeInvalidInput(w, err)
+		return
+	}
+
+	diff, err := h.service.GetPortfolioDiff(r.Context(), since, types...)
+	if err != nil {
+		log.Printf("Date: %s | Route: /api/admin/diff | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Date: %s | Route: /api/admin/diff | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(diff)
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// maxDisplayOrderItems caps the documents of one reorder request
+const maxDisplayOrderItems = 500
+
+// Pinning is the curated order embedded in projects and posts. Lists show featured documents first, then
+// those with a display order, lowest first, then the rest in their usual order.
+type Pinning struct {
+	Featured     bool `bson:"featured,omitempty" json:"featured,omitempty"`
+	DisplayOrder *int `bson:"display_order,omitempty" json:"display_order,omitempty"`
+}
+
+// pinnedSortStages sort an aggregation in the curated order (see Pinning), the unpinned documents by then
+// and their ID
+func pinnedSortStages(then ...bson.E) []bson.M {
+	order := bson.D{{Key: "featured", Value: -1}, {Key: "_display_order", Value: 1}}
+	order = append(order, then...)
+	order = append(order, bson.E{Key: "_id", Value: 1})
+	return []bson.M{
+		{"$addFields": bson.M{"_display_order": bson.M{"$ifNull": bson.A{"$display_order", math.MaxInt32}}}},
+		{"$sort": order},
+		{"$unset": "_display_order"},
+	}
+}
+
+// findPinned lists the documents matching filter in their curated order. The order is applied before the
+// QUERY_MAX_DOCUMENTS cap, so pinned documents are never the ones cut off.
+func findPinned[T any](ctx context.Context, collection *mongo.Collection, filter bson.M, then ...bson.E) ([]T, error) {
+	pipeline := append([]bson.M{{"$match": filter}}, pinnedSortStages(then...)...)
+	pipeline = append(pipeline, bson.M{"$limit": queryLimitsConfig().maxDocuments})
+	cursor, err := collection.Aggregate(ctx, pipeline, timedAggregate())
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var items []T
+	if err = cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// DisplayOrderItem places one document in a curated order
+type DisplayOrderItem struct {
+	ID       string `json:"id"`
+	Featured bool   `json:"featured"`
+}
+
+// DisplayOrderRequest is the body of PUT /api/admin/order/{collection}
+type DisplayOrderRequest struct {
+	AuthorID string             `json:"author_id"` // Only reorder this author's documents
+	Items    []DisplayOrderItem `json:"items"`
+}
+
+// SetDisplayOrder replaces the curated order of a collection: the listed documents are numbered in order
+// and featured as requested, and every other document (of the author, if set) loses its place and feature.
+// It returns how many of the listed documents exist.
+func (ps *PortfolioService) SetDisplayOrder(ctx context.Context, collection *mongo.Collection, authorID primitive.ObjectID, ids []primitive.ObjectID, featured []bool) (int64, error) {
+	scope := bson.M{}
+	if !authorID.IsZero() {
+		scope["author_id"] = authorID
+	}
+
+	// Order changes move updated_at like any edit, so cached lists are refetched
+	unpinned := bson.M{
+		"_id": bson.M{"$nin": ids},
+		"$or": []bson.M{{"featured": bson.M{"$exists": true}}, {"display_order": bson.M{"$exists": true}}},
+	}
+	for key, value := range scope {
+		unpinned[key] = value
+	}
+	if _, err := collection.UpdateMany(ctx, unpinned, withUpdatedAt(bson.M{"$unset": bson.M{"featured": "", "display_order": ""}})); err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	models := make([]mongo.WriteModel, 0, len(ids))
+	for i, id := range ids {
+		filter := bson.M{"_id": id}
+		for key, value := range scope {
+			filter[key] = value
+		}
+		set := bson.M{"display_order": i + 1}
+		update := bson.M{"$set": set}
+		if featured[i] {
+			set["featured"] = true
+		} else {
+			update["$unset"] = bson.M{"featured": ""}
+		}
+		models = append(models, mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(withUpdatedAt(update)))
+	}
+	result, err := collection.BulkWrite(ctx, models)
+	if err != nil {
+		return 0, err
+	}
+	return result.MatchedCount, nil
+}
+
+// Display order endpoint: PUT /api/admin/order/{projects|posts} with {"author_id": "", "items": [{"id": "",
+// "featured": true}]} sets the order of the collection's lists; documents left out go back to their usual
+// order after the listed ones (admin only)
+func (h *APIHandler) handleDisplayOrder(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if r.Method != "PUT" {
+		log.Printf("Date: %s | Route: /api/admin/order | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !isAdminRequest(r) {
+		log.Printf("Date: %s | Route: /api/admin/order | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var collection *mongo.Collection
+	switch name := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/admin/order/"), "/"); name {
+	case "projects":
+		collection = h.service.projects
+	case "posts":
+		collection = h.service.posts
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	var req DisplayOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("Date: %s | Route: /api/admin/order | Status: BAD_REQUEST | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Invalid JSON request", http.StatusBadRequest)
+		return
+	}
+
+	var v Validator
+	var authorID primitive.ObjectID
+	if req.AuthorID != "" {
+		parsed, err := primitive.ObjectIDFromHex(req.AuthorID)
+		v.Check(err == nil, "author_id", "format", "author_id must be an ID")
+		authorID = parsed
+	}
+	v.MaxItems("items", len(req.Items), maxDisplayOrderItems)
+	ids := make([]primitive.ObjectID, 0, len(req.Items))
+	featured := make([]bool, 0, len(req.Items))
+	seen := make(map[primitive.ObjectID]bool, len(req.Items))
+	for i, item := range req.Items {
+		field := fmt.Sprintf("items[%d].id", i)
+		id, err := primitive.ObjectIDFromHex(item.ID)
+		if !v.Check(err == nil, field, "format", "id must be an ID") {
+			continue
+		}
+		v.Check(!seen[id], field, "duplicate", "each document can only be listed once")
+		seen[id] = true
+		ids = append(ids, id)
+		featured = append(featured, item.Featured)
+	}
+	if err := v.Err(); err != nil {
+		writeInvalidInput(w, err)
+		return
+	}
+
+	matched, err := h.service.SetDisplayOrder(r.Context(), collection, authorID, ids, featured)
+	if err != nil {
+		log.Printf("Date: %s | Route: /api/admin/order | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Date: %s | Route: /api/admin/order | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]int64{"ordered": matched, "missing": int64(len(ids)) - matched})
+}
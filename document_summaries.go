@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"os"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// summarizedContextCollections are sent to the chatbot as precomputed summaries instead of raw documents,
+// keeping only the listed fields alongside the summary. Documents without a summary yet are sent raw.
+var summarizedContextCollections = map[string][]string{
+	"projects": {"id", "name", "slug", "technologies_used", "repo_url"},
+	"resumes":  {"id", "author_id", "author_name"},
+}
+
+// DocumentSummarizer periodically generates the summaries of projects and resumes used in chatbot context,
+// so chat requests never wait for them
+type DocumentSummarizer struct {
+	llm      *LLMService
+	interval time.Duration
+}
+
+// NewDocumentSummarizer creates a summarizer from environment configuration, or nil if the LLM is disabled
+func NewDocumentSummarizer(llm *LLMService) *DocumentSummarizer {
+	if llm == nil {
+		return nil
+	}
+
+	interval := time.Hour
+	if value := os.Getenv("DOCUMENT_SUMMARY_INTERVAL"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			log.Printf("Invalid DOCUMENT_SUMMARY_INTERVAL %q, using %s", value, interval)
+		} else {
+			interval = parsed
+		}
+	}
+	return &DocumentSummarizer{llm: llm, interval: interval}
+}
+
+// Summarize generates the missing summaries of listed projects and of resumes, returning how many it generated.
+// Documents are serialized exactly as in search results (resumes redacted, projects in the default locale)
+// so the chatbot finds the summaries by content hash; an edited document gets a new summary on the next run.
+func (s *DocumentSummarizer) Summarize(ctx context.Context) (int, error) {
+	ps := s.llm.portfolioService
+	projects, err := ps.GetAllProjects(ctx, false, nil)
+	if err != nil {
+		return 0, err
+	}
+	resumes, err := ps.GetAllResumes(ctx)
+	if err != nil {
+		return 0, err
+	}
+	redactResumes(resumes)
+
+	generated := 0
+	for name, documents := range map[string]interface{}{"projects": projects, "resumes": resumes} {
+		data, err := json.Marshal(documents)
+		if err != nil {
+			return generated, err
+		}
+		var raw []json.RawMessage
+		if err := json.Unmarshal(data, &raw); err != nil {
+			continue // null when the collection is empty
+		}
+		for _, document := range raw {
+			_, isNew, err := s.llm.summarizeDocument(ctx, name, trimContextDocument(document), true)
+			if err != nil {
+				return generated, err
+			}
+			if isNew {
+				generated++
+			}
+		}
+	}
+	return generated, nil
+}
+
+// GetContextSummaries returns the cached summaries with the given keys, keyed by ID
+func (ps *PortfolioService) GetContextSummaries(ctx context.Context, keys []string) (map[string]ContextSummary, error) {
+	cursor, err := ps.contextSummaries.Find(ctx, bson.M{"_id": bson.M{"$in": keys}}, boundedFind())
+	if err != nil {
+		return nil, err
+	}
+	var summaries []ContextSummary
+	if err := cursor.All(ctx, &summaries); err != nil {
+		return nil, err
+	}
+	byKey := make(map[string]ContextSummary, len(summaries))
+	for _, summary := range summaries {
+		byKey[summary.ID] = summary
+	}
+	return byKey, nil
+}
+
+// applyPrecomputedSummaries replaces the documents of summarizedContextCollections with their summaries where
+// one exists. Replaced collections become []json.RawMessage; the others are left untouched.
+func (l *LLMService) applyPrecomputedSummaries(ctx context.Context, results map[string]interface{}) {
+	for name, keep := range summarizedContextCollections {
+		data, err := json.Marshal(results[name])
+		if err != nil {
+			continue
+		}
+		var raw []json.RawMessage
+		if err := json.Unmarshal(data, &raw); err != nil || len(raw) == 0 {
+			continue
+		}
+
+		keys := make([]string, len(raw))
+		for i := range raw {
+			raw[i] = trimContextDocument(raw[i])
+			keys[i] = contextSummaryKey(name, raw[i], l.summaryModel)
+		}
+		summaries, err := l.portfolioService.GetContextSummaries(ctx, keys)
+		if err != nil {
+			log.Printf("Error loading %s summaries: %v", name, err)
+			continue
+		}
+
+		for i, document := range raw {
+			summary, ok := summaries[keys[i]]
+			if !ok {
+				continue
+			}
+			var fields map[string]json.RawMessage
+			if err := json.Unmarshal(document, &fields); err != nil {
+				continue
+			}
+			entry := make(map[string]interface{}, len(keep)+1)
+			for _, field := range keep {
+				if value, ok := fields[field]; ok {
+					entry[field] = value
+				}
+			}
+			entry["summary"] = summary.Summary
+			if compact, err := json.Marshal(entry); err == nil {
+				raw[i] = compact
+			}
+		}
+		results[name] = raw
+	}
+}
+
+// isPrecomputedSummary reports whether a context document was already replaced by its summary
+func isPrecomputedSummary(collection string, document json.RawMessage) bool {
+	if _, ok := summarizedContextCollections[collection]; !ok {
+		return false
+	}
+	var entry struct {
+		Summary *string `json:"summary"`
+	}
+	return json.Unmarshal(document, &entry) == nil && entry.Summary != nil
+}
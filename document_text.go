@@ -0,0 +1,350 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+)
+
+// Decompressed PDF streams larger than this are skipped; text content streams are far smaller
+const maxPDFStreamSize = 10 << 20
+
+// extractDocumentText returns the plain text of a PDF or DOCX file, detected from its content
+func extractDocumentText(data []byte) (string, error) {
+	switch {
+	case bytes.HasPrefix(data, []byte("%PDF-")):
+		return extractPDFText(data)
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")):
+		return extractDOCXText(data)
+	default:
+		return "", fmt.Errorf("unsupported document type (expected PDF or DOCX)")
+	}
+}
+
+// extractDOCXText reads the paragraphs of word/document.xml
+func extractDOCXText(data []byte) (string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", fmt.Errorf("invalid DOCX: %w", err)
+	}
+
+	var document *zip.File
+	for _, file := range archive.File {
+		if file.Name == "word/document.xml" {
+			document = file
+			break
+		}
+	}
+	if document == nil {
+		return "", fmt.Errorf("invalid DOCX: word/document.xml not found")
+	}
+
+	reader, err := document.Open()
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+
+	var out strings.Builder
+	decoder := xml.NewDecoder(io.LimitReader(reader, maxPDFStreamSize))
+	inText := false
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("invalid DOCX: %w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "t":
+				inText = true
+			case "tab":
+				out.WriteString("\t")
+			case "br", "cr":
+				out.WriteString("\n")
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				out.WriteString("\n")
+			}
+		case xml.CharData:
+			if inText {
+				out.Write(t)
+			}
+		}
+	}
+	return cleanExtractedText(out.String()), nil
+}
+
+// extractPDFText pulls the text drawn by content streams. It handles the uncompressed and Flate-encoded
+// streams that resume builders and word processors produce; scanned PDFs have no text to extract.
+func extractPDFText(data []byte) (string, error) {
+	var out strings.Builder
+	pos := 0
+	for {
+		index := bytes.Index(data[pos:], []byte("stream"))
+		if index < 0 {
+			break
+		}
+		keyword := pos + index
+		pos = keyword + len("stream")
+		if keyword >= 3 && string(data[keyword-3:keyword]) == "end" {
+			continue
+		}
+
+		start := pos
+		if start < len(data) && data[start] == '\r' {
+			start++
+		}
+		if start < len(data) && data[start] == '\n' {
+			start++
+		}
+		end := bytes.Index(data[start:], []byte("endstream"))
+		if end < 0 {
+			break
+		}
+		raw := data[start : start+end]
+		pos = start + end + len("endstream")
+
+		// The stream dictionary sits between the object header and the stream keyword
+		dictStart := bytes.LastIndex(data[:keyword], []byte("obj"))
+		if dictStart < 0 {
+			continue
+		}
+		dict := string(data[dictStart:keyword])
+		if strings.Contains(dict, "/Image") || strings.Contains(dict, "/ObjStm") || strings.Contains(dict, "/XRef") ||
+			strings.Contains(dict, "/FontFile") || strings.Contains(dict, "/Length1") {
+			continue
+		}
+
+		content := raw
+		if strings.Contains(dict, "/Filter") {
+			if !strings.Contains(dict, "/FlateDecode") || strings.Count(dict, "Decode") > 1 {
+				continue
+			}
+			reader, err := zlib.NewReader(bytes.NewReader(raw))
+			if err != nil {
+				continue
+			}
+			// Truncated streams still yield whatever text precedes the damage
+			content, _ = io.ReadAll(io.LimitReader(reader, maxPDFStreamSize))
+			reader.Close()
+		}
+
+		if bytes.Contains(content, []byte("BT")) {
+			extractPDFContentText(content, &out)
+		}
+	}
+
+	text := cleanExtractedText(out.String())
+	if text == "" {
+		return "", fmt.Errorf("no text found in PDF (scanned documents are not supported)")
+	}
+	return text, nil
+}
+
+// extractPDFContentText interprets the text-showing operators of a content stream
+func extractPDFContentText(content []byte, out *strings.Builder) {
+	var pending strings.Builder
+	var numbers []float64
+	inArray := false
+
+	isDelimiter := func(c byte) bool {
+		return strings.IndexByte("()<>[]{}/% \t\r\n\f\x00", c) >= 0
+	}
+
+	for i := 0; i < len(content); {
+		c := content[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == '\f' || c == 0:
+			i++
+		case c == '%':
+			for i < len(content) && content[i] != '\n' && content[i] != '\r' {
+				i++
+			}
+		case c == '(':
+			str, next := readPDFLiteralString(content, i)
+			pending.WriteString(decodePDFString(str))
+			i = next
+		case c == '<' && i+1 < len(content) && content[i+1] == '<':
+			i += 2
+		case c == '>' && i+1 < len(content) && content[i+1] == '>':
+			i += 2
+		case c == '<':
+			end := bytes.IndexByte(content[i:], '>')
+			if end < 0 {
+				return
+			}
+			hexDigits := strings.Map(func(r rune) rune {
+				if unicode.IsSpace(r) {
+					return -1
+				}
+				return r
+			}, string(content[i+1:i+end]))
+			if len(hexDigits)%2 == 1 {
+				hexDigits += "0"
+			}
+			if decoded, err := hex.DecodeString(hexDigits); err == nil {
+				pending.WriteString(decodePDFString(decoded))
+			}
+			i += end + 1
+		case c == '[':
+			inArray = true
+			i++
+		case c == ']':
+			inArray = false
+			i++
+		case c == '/':
+			i++
+			for i < len(content) && !isDelimiter(content[i]) {
+				i++
+			}
+		default:
+			start := i
+			for i < len(content) && !isDelimiter(content[i]) {
+				i++
+			}
+			if i == start {
+				i++
+				continue
+			}
+			word := string(content[start:i])
+			if number, err := strconv.ParseFloat(word, 64); err == nil {
+				// Large negative kerning inside a TJ array is how most generators encode a space
+				if inArray && number < -200 {
+					pending.WriteString(" ")
+				}
+				numbers = append(numbers, number)
+				continue
+			}
+
+			switch word {
+			case "Tj", "TJ":
+				out.WriteString(pending.String())
+			case "'", "\"":
+				out.WriteString("\n")
+				out.WriteString(pending.String())
+			case "T*", "Tm", "ET":
+				out.WriteString("\n")
+			case "Td", "TD":
+				if len(numbers) >= 2 && numbers[len(numbers)-1] != 0 {
+					out.WriteString("\n")
+				} else {
+					out.WriteString(" ")
+				}
+			case "BI":
+				// Inline image data is binary; skip to the end marker
+				end := bytes.Index(content[i:], []byte("EI"))
+				if end < 0 {
+					return
+				}
+				i += end + 2
+			}
+			pending.Reset()
+			numbers = numbers[:0]
+		}
+	}
+}
+
+// readPDFLiteralString reads a (...) string starting at content[start], returning its bytes and the index after it
+func readPDFLiteralString(content []byte, start int) ([]byte, int) {
+	var str []byte
+	depth := 0
+	for i := start; i < len(content); i++ {
+		c := content[i]
+		switch c {
+		case '(':
+			if depth > 0 {
+				str = append(str, c)
+			}
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return str, i + 1
+			}
+			str = append(str, c)
+		case '\\':
+			i++
+			if i >= len(content) {
+				return str, i
+			}
+			switch escaped := content[i]; escaped {
+			case 'n':
+				str = append(str, '\n')
+			case 'r':
+				str = append(str, '\r')
+			case 't':
+				str = append(str, '\t')
+			case 'b', 'f':
+			case '\r', '\n':
+				// Line continuation
+			default:
+				if escaped >= '0' && escaped <= '7' {
+					value := 0
+					for n := 0; n < 3 && i < len(content) && content[i] >= '0' && content[i] <= '7'; n++ {
+						value = value*8 + int(content[i]-'0')
+						i++
+					}
+					i--
+					str = append(str, byte(value))
+				} else {
+					str = append(str, escaped)
+				}
+			}
+		default:
+			str = append(str, c)
+		}
+	}
+	return str, len(content)
+}
+
+// decodePDFString decodes UTF-16BE strings (with a byte order mark) and treats anything else as Latin-1
+func decodePDFString(str []byte) string {
+	if len(str) >= 2 && str[0] == 0xFE && str[1] == 0xFF {
+		units := make([]uint16, 0, len(str)/2)
+		for i := 2; i+1 < len(str); i += 2 {
+			units = append(units, binary.BigEndian.Uint16(str[i:]))
+		}
+		return string(utf16.Decode(units))
+	}
+
+	runes := make([]rune, 0, len(str))
+	for _, b := range str {
+		if b >= 0x20 || b == '\n' || b == '\t' {
+			runes = append(runes, rune(b))
+		}
+	}
+	return string(runes)
+}
+
+var (
+	extractedSpaces     = regexp.MustCompile(`[ \t]+`)
+	extractedBlankLines = regexp.MustCompile(`\n{3,}`)
+)
+
+// cleanExtractedText collapses runs of whitespace and blank lines left by extraction
+func cleanExtractedText(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(extractedSpaces.ReplaceAllString(line, " "))
+	}
+	return strings.TrimSpace(extractedBlankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var hostnamePattern = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]*[a-z0-9])?\.)*[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+// normalizeHost lowercases a Host header value and strips the port and any trailing dot
+func normalizeHost(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(host)), ".")
+}
+
+// Domain query methods
+
+func (ps *PortfolioService) GetAllDomains(ctx context.Context) ([]Domain, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "host", Value: 1}})
+	cursor, err := ps.domains.Find(ctx, bson.M{}, boundedFind(opts))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	domains := []Domain{}
+	if err = cursor.All(ctx, &domains); err != nil {
+		return nil, err
+	}
+	return domains, nil
+}
+
+func (ps *PortfolioService) GetDomainByHost(ctx context.Context, host string) (*Domain, error) {
+	var domain Domain
+	if err := ps.domains.FindOne(ctx, bson.M{"host": host}).Decode(&domain); err != nil {
+		return nil, err
+	}
+	return &domain, nil
+}
+
+func (ps *PortfolioService) InsertDomain(ctx context.Context, domain *Domain) error {
+	domain.ID = primitive.NewObjectID()
+	_, err := ps.domains.InsertOne(ctx, domain)
+	return err
+}
+
+func (ps *PortfolioService) DeleteDomain(ctx context.Context, host string) error {
+	result, err := ps.domains.DeleteOne(ctx, bson.M{"host": host})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// hostAuthor returns the author mapped to the request's host, or nil when the host has no mapping and
+// the unscoped portfolio should be served. Lookups are cached for five minutes, including misses.
+func (h *APIHandler) hostAuthor(r *http.Request) *Author {
+	host := normalizeHost(r.Host)
+	if host == "" {
+		return nil
+	}
+	if cached, ok := h.domainCache.Get(host); ok {
+		return cached
+	}
+
+	ctx := context.Background()
+	var author *Author
+	domain, err := h.service.GetDomainByHost(ctx, host)
+	if err == nil {
+		author, err = h.service.GetAuthorByID(ctx, domain.AuthorID)
+	}
+	if err != nil && err != mongo.ErrNoDocuments {
+		// Not cached, so the next request retries the lookup
+		log.Printf("Error resolving author for host %s: %v", host, err)
+		return nil
+	}
+
+	h.domainCache.Set(host, author, 5*time.Minute)
+	return author
+}
+
+// Domains endpoint: GET lists host mappings, POST {host, author_id} adds one (admin only)
+func (h *APIHandler) handleDomains(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if !isAdminRequest(r) {
+		log.Printf("Date: %s | Route: /api/admin/domains | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ctx := context.Background()
+
+	switch r.Method {
+	case "GET":
+		domains, err := h.service.GetAllDomains(ctx)
+		if err != nil {
+			log.Printf("Date: %s | Route: /api/admin/domains | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Date: %s | Route: /api/admin/domains | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(domains)
+
+	case "POST":
+		var domain Domain
+		if err := json.NewDecoder(r.Body).Decode(&domain); err != nil {
+			log.Printf("Date: %s | Route: /api/admin/domains | Status: BAD_REQUEST | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, "Invalid JSON request", http.StatusBadRequest)
+			return
+		}
+		domain.Host = normalizeHost(domain.Host)
+		if len(domain.Host) > 253 || !hostnamePattern.MatchString(domain.Host) {
+			writeInvalidInput(w, invalidField("host", "format", "host must be a host name such as alice.example.com"))
+			return
+		}
+		if _, err := h.service.GetAuthorByID(ctx, domain.AuthorID); err != nil {
+			http.Error(w, "Author not found", http.StatusBadRequest)
+			return
+		}
+		if _, err := h.service.GetDomainByHost(ctx, domain.Host); err == nil {
+			http.Error(w, fmt.Sprintf("Host %s is already mapped", domain.Host), http.StatusConflict)
+			return
+		}
+
+		domain.CreatedAt = time.Now()
+		if err := h.service.InsertDomain(ctx, &domain); err != nil {
+			log.Printf("Date: %s | Route: /api/admin/domains | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		h.domainCache.Delete(domain.Host)
+
+		log.Printf("Date: %s | Route: /api/admin/domains | Status: CREATED | GPT Model: %s", currentTime, gptModel)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(domain)
+
+	default:
+		log.Printf("Date: %s | Route: /api/admin/domains | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// Domain removal: DELETE /api/admin/domains/{host} (admin only)
+func (h *APIHandler) handleDomainRoutes(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if !isAdminRequest(r) {
+		log.Printf("Date: %s | Route: /api/admin/domains/{host} | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if r.Method != "DELETE" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	host := normalizeHost(strings.TrimPrefix(r.URL.Path, "/api/admin/domains/"))
+	if err := h.service.DeleteDomain(context.Background(), host); err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Domain not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("Date: %s | Route: /api/admin/domains/{host} | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.domainCache.Delete(host)
+
+	log.Printf("Date: %s | Route: /api/admin/domains/{host} | Status: DELETED | GPT Model: %s", currentTime, gptModel)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// hostScope adds a condition on field to filter keeping the author's documents and the shared ones (no
+// owner), for requests on a domain mapped to the author. filter is unchanged when author is nil.
+func hostScope(filter bson.M, field string, author *Author) bson.M {
+	if author != nil {
+		filter[field] = bson.M{"$in": bson.A{author.ID, primitive.NilObjectID, nil}}
+	}
+	return filter
+}
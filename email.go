@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"net/smtp"
+	"os"
+	"strings"
+	"time"
+)
+
+// Mailer sends plain-text email through an SMTP server
+type Mailer struct {
+	address  string
+	host     string
+	username string
+	password string
+	from     string
+}
+
+// NewMailerFromEnv reads SMTP_HOST (required), SMTP_PORT (default 587), SMTP_USERNAME, SMTP_PASSWORD and
+// SMTP_FROM, or returns nil if SMTP_HOST is not set
+func NewMailerFromEnv() *Mailer {
+	host := os.Getenv("SMTP_HOST")
+	if host == "" {
+		return nil
+	}
+	port := os.Getenv("SMTP_PORT")
+	if port == "" {
+		port = "587"
+	}
+	from := os.Getenv("SMTP_FROM")
+	if from == "" {
+		from = os.Getenv("SMTP_USERNAME")
+	}
+	if from == "" {
+		log.Println("SMTP_FROM not set, email disabled")
+		return nil
+	}
+	return &Mailer{
+		address:  net.JoinHostPort(host, port),
+		host:     host,
+		username: os.Getenv("SMTP_USERNAME"),
+		password: os.Getenv("SMTP_PASSWORD"),
+		from:     from,
+	}
+}
+
+// Send emails a plain-text message. The connection is upgraded with STARTTLS when the server offers it.
+func (m *Mailer) Send(to []string, subject, body string) error {
+	var auth smtp.Auth
+	if m.username != "" {
+		auth = smtp.PlainAuth("", m.username, m.password, m.host)
+	}
+	message := strings.Join([]string{
+		"From: " + m.from,
+		"To: " + strings.Join(to, ", "),
+		"Subject: " + strings.NewReplacer("\r", "", "\n", " ").Replace(subject),
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		strings.ReplaceAll(body, "\n", "\r\n"),
+	}, "\r\n")
+	if err := smtp.SendMail(m.address, auth, m.from, to, []byte(message)); err != nil {
+		return fmt.Errorf("sending email: %w", err)
+	}
+	return nil
+}
+
+// emailList splits a comma-separated list of addresses
+func emailList(value string) []string {
+	var addresses []string
+	for _, address := range strings.Split(value, ",") {
+		if address = strings.TrimSpace(address); address != "" {
+			addresses = append(addresses, address)
+		}
+	}
+	return addresses
+}
@@ -0,0 +1,305 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxReportedBodyBytes is how much of a 5xx response body is included in its error report
+const maxReportedBodyBytes = 1024
+
+// ErrorReporter sends panics and 5xx responses to Sentry, or any service accepting Sentry's store API
+// such as GlitchTip. Events are sent in the background and dropped if the queue is full, so reporting
+// never slows down or fails a request. A nil reporter reports nothing.
+type ErrorReporter struct {
+	httpClient  *http.Client
+	endpoint    string
+	auth        string
+	environment string
+	release     string
+	events      chan sentryEvent
+}
+
+// errorReporter is configured once from SENTRY_DSN, SENTRY_ENVIRONMENT and SENTRY_RELEASE; it is nil when
+// SENTRY_DSN is not set
+var errorReporter = sync.OnceValue(func() *ErrorReporter {
+	dsn := os.Getenv("SENTRY_DSN")
+	if dsn == "" {
+		return nil
+	}
+	reporter, err := newErrorReporter(dsn, os.Getenv("SENTRY_ENVIRONMENT"), os.Getenv("SENTRY_RELEASE"))
+	if err != nil {
+		log.Printf("Invalid SENTRY_DSN, errors are not reported: %v", err)
+		return nil
+	}
+	go reporter.send()
+	return reporter
+})
+
+// newErrorReporter parses a DSN of the form https://<key>@<host>[/<path>]/<project>
+func newErrorReporter(dsn, environment, release string) (*ErrorReporter, error) {
+	parsed, err := url.Parse(dsn)
+	if err != nil {
+		return nil, err
+	}
+	project := path.Base(parsed.Path)
+	if parsed.Scheme == "" || parsed.Host == "" || parsed.User == nil || parsed.User.Username() == "" || project == "/" || project == "." {
+		return nil, fmt.Errorf("expected https://<key>@<host>/<project>")
+	}
+	prefix := strings.TrimSuffix(path.Dir(parsed.Path), "/")
+
+	return &ErrorReporter{
+		httpClient:  &http.Client{Timeout: 10 * time.Second},
+		endpoint:    fmt.Sprintf("%s://%s%s/api/%s/store/", parsed.Scheme, parsed.Host, prefix, project),
+		auth:        fmt.Sprintf("Sentry sentry_version=7, sentry_client=portfolio/1.0, sentry_key=%s", parsed.User.Username()),
+		environment: environment,
+		release:     release,
+		events:      make(chan sentryEvent, 100),
+	}, nil
+}
+
+// Sentry event payload; see https://develop.sentry.dev/sdk/event-payloads/
+type sentryEvent struct {
+	EventID     string            `json:"event_id"`
+	Timestamp   string            `json:"timestamp"`
+	Level       string            `json:"level"`
+	Platform    string            `json:"platform"`
+	Logger      string            `json:"logger,omitempty"`
+	ServerName  string            `json:"server_name,omitempty"`
+	Environment string            `json:"environment,omitempty"`
+	Release     string            `json:"release,omitempty"`
+	Message     string            `json:"message,omitempty"`
+	Exception   *sentryExceptions `json:"exception,omitempty"`
+	Request     *sentryRequest    `json:"request,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	Extra       map[string]string `json:"extra,omitempty"`
+}
+
+type sentryExceptions struct {
+	Values []sentryException `json:"values"`
+}
+
+type sentryException struct {
+	Type       string            `json:"type"`
+	Value      string            `json:"value"`
+	Stacktrace *sentryStacktrace `json:"stacktrace,omitempty"`
+}
+
+type sentryStacktrace struct {
+	Frames []sentryFrame `json:"frames"`
+}
+
+type sentryFrame struct {
+	Function string `json:"function"`
+	Module   string `json:"module,omitempty"`
+	Filename string `json:"filename"`
+	AbsPath  string `json:"abs_path"`
+	Lineno   int    `json:"lineno"`
+	InApp    bool   `json:"in_app"`
+}
+
+type sentryRequest struct {
+	URL         string            `json:"url"`
+	Method      string            `json:"method"`
+	QueryString string            `json:"query_string,omitempty"`
+	Headers     map[string]string `json:"headers,omitempty"`
+}
+
+// reportedHeaders are the request headers included in reports; credentials and cookies never are
+var reportedHeaders = []string{"User-Agent", "Referer", "Content-Type", "Accept", "Accept-Language", "Origin"}
+
+// newEvent fills in the fields shared by every event
+func (e *ErrorReporter) newEvent(level string, r *http.Request) sentryEvent {
+	id := make([]byte, 16)
+	rand.Read(id)
+	event := sentryEvent{
+		EventID:     hex.EncodeToString(id),
+		Timestamp:   time.Now().UTC().Format(time.RFC3339),
+		Level:       level,
+		Platform:    "go",
+		ServerName:  instanceID,
+		Environment: e.environment,
+		Release:     e.release,
+	}
+	if r != nil {
+		scheme := "http"
+		if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+			scheme = "https"
+		}
+		request := &sentryRequest{
+			URL:         scheme + "://" + r.Host + r.URL.Path,
+			Method:      r.Method,
+			QueryString: r.URL.RawQuery,
+			Headers:     make(map[string]string),
+		}
+		for _, name := range reportedHeaders {
+			if value := r.Header.Get(name); value != "" {
+				request.Headers[name] = value
+			}
+		}
+		event.Request = request
+	}
+	return event
+}
+
+// stackFrames returns the calling goroutine's stack, outermost call first as Sentry expects, skipping the
+// given number of innermost frames
+func stackFrames(skip int) []sentryFrame {
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(skip+2, pcs)
+	iterator := runtime.CallersFrames(pcs[:n])
+	var frames []sentryFrame
+	for {
+		frame, more := iterator.Next()
+		module, function := "", frame.Function
+		if dot := strings.LastIndex(function, "/"); dot >= 0 {
+			if i := strings.Index(function[dot:], "."); i >= 0 {
+				module, function = function[:dot+i], function[dot+i+1:]
+			}
+		} else if i := strings.Index(function, "."); i >= 0 {
+			module, function = function[:i], function[i+1:]
+		}
+		frames = append([]sentryFrame{{
+			Function: function,
+			Module:   module,
+			Filename: path.Base(frame.File),
+			AbsPath:  frame.File,
+			Lineno:   frame.Line,
+			InApp:    module == "main",
+		}}, frames...)
+		if !more {
+			break
+		}
+	}
+	return frames
+}
+
+// CapturePanic reports a recovered panic with the stack of the goroutine that panicked; call it from the
+// deferred function that recovered. r is the request being served, or nil for background work.
+func (e *ErrorReporter) CapturePanic(recovered interface{}, r *http.Request, tags map[string]string) {
+	if e == nil {
+		return
+	}
+	event := e.newEvent("fatal", r)
+	event.Tags = tags
+	event.Exception = &sentryExceptions{Values: []sentryException{{
+		Type:       "panic",
+		Value:      fmt.Sprint(recovered),
+		Stacktrace: &sentryStacktrace{Frames: stackFrames(2)},
+	}}}
+	e.enqueue(event)
+}
+
+// CaptureError reports an error that did not panic, such as a failed background job
+func (e *ErrorReporter) CaptureError(err error, r *http.Request, tags map[string]string) {
+	if e == nil || err == nil {
+		return
+	}
+	event := e.newEvent("error", r)
+	event.Tags = tags
+	event.Exception = &sentryExceptions{Values: []sentryException{{
+		Type:       fmt.Sprintf("%T", err),
+		Value:      err.Error(),
+		Stacktrace: &sentryStacktrace{Frames: stackFrames(1)},
+	}}}
+	e.enqueue(event)
+}
+
+// captureServerError reports a 5xx response with the start of its body, which holds the error message
+func (e *ErrorReporter) captureServerError(r *http.Request, status int, body []byte) {
+	event := e.newEvent("error", r)
+	event.Message = fmt.Sprintf("%s %s returned %d", r.Method, r.URL.Path, status)
+	event.Tags = map[string]string{"status": fmt.Sprint(status)}
+	if message := strings.TrimSpace(string(body)); message != "" {
+		event.Extra = map[string]string{"response": message}
+	}
+	e.enqueue(event)
+}
+
+func (e *ErrorReporter) enqueue(event sentryEvent) {
+	select {
+	case e.events <- event:
+	default:
+		log.Printf("Error report queue is full, dropping event %s", event.EventID)
+	}
+}
+
+// send delivers queued events one at a time
+func (e *ErrorReporter) send() {
+	for event := range e.events {
+		body, err := json.Marshal(event)
+		if err != nil {
+			log.Printf("Error encoding error report: %v", err)
+			continue
+		}
+		req, err := http.NewRequest("POST", e.endpoint, bytes.NewReader(body))
+		if err != nil {
+			log.Printf("Error creating error report request: %v", err)
+			continue
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Sentry-Auth", e.auth)
+		resp, err := e.httpClient.Do(req)
+		if err != nil {
+			log.Printf("Error sending error report: %v", err)
+			continue
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			log.Printf("Error report %s rejected with status %d", event.EventID, resp.StatusCode)
+		}
+	}
+}
+
+// errorRecorder captures the status and the start of the body of a response
+type errorRecorder struct {
+	statusRecorder
+	body []byte
+}
+
+func (e *errorRecorder) Write(b []byte) (int, error) {
+	if room := maxReportedBodyBytes - len(e.body); room > 0 {
+		e.body = append(e.body, b[:min(room, len(b))]...)
+	}
+	return e.statusRecorder.Write(b)
+}
+
+// Middleware reports panics, answering them with a 500 instead of dropping the connection, and every
+// response with a 5xx status
+func (e *ErrorReporter) Middleware(next http.Handler) http.Handler {
+	if e == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorder := &errorRecorder{statusRecorder: statusRecorder{ResponseWriter: w}}
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				if recovered == http.ErrAbortHandler {
+					panic(recovered)
+				}
+				log.Printf("Panic serving %s %s: %v", r.Method, r.URL.Path, recovered)
+				e.CapturePanic(recovered, r, nil)
+				if recorder.status == 0 {
+					http.Error(w, "Internal server error", http.StatusInternalServerError)
+				}
+				return
+			}
+			if recorder.status >= 500 {
+				e.captureServerError(r, recorder.status, recorder.body)
+			}
+		}()
+		next.ServeHTTP(recorder, r)
+	})
+}
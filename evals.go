@@ -0,0 +1,475 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// maxEvalCases caps the golden set so a run stays within a reasonable time and token budget
+const maxEvalCases = 50
+
+// Eval query methods
+
+func (ps *PortfolioService) GetAllEvalCases(ctx context.Context) ([]EvalCase, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := ps.evalCases.Find(ctx, bson.M{}, boundedFind(opts))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	cases := []EvalCase{}
+	if err = cursor.All(ctx, &cases); err != nil {
+		return nil, err
+	}
+	return cases, nil
+}
+
+func (ps *PortfolioService) InsertEvalCase(ctx context.Context, evalCase *EvalCase) error {
+	evalCase.ID = primitive.NewObjectID()
+	_, err := ps.evalCases.InsertOne(ctx, evalCase)
+	return err
+}
+
+func (ps *PortfolioService) DeleteEvalCase(ctx context.Context, id primitive.ObjectID) error {
+	result, err := ps.evalCases.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (ps *PortfolioService) CountEvalCases(ctx context.Context) (int64, error) {
+	return ps.evalCases.CountDocuments(ctx, bson.M{})
+}
+
+// GetEvalRuns returns recent runs, newest first, without the per-case answers
+func (ps *PortfolioService) GetEvalRuns(ctx context.Context, limit int64) ([]EvalRun, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "started_at", Value: -1}}).
+		SetLimit(limit).
+		SetProjection(bson.M{"results": 0})
+	cursor, err := ps.evalRuns.Find(ctx, bson.M{}, boundedFind(opts))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	runs := []EvalRun{}
+	if err = cursor.All(ctx, &runs); err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
+
+func (ps *PortfolioService) GetEvalRunByID(ctx context.Context, id primitive.ObjectID) (*EvalRun, error) {
+	var run EvalRun
+	if err := ps.evalRuns.FindOne(ctx, bson.M{"_id": id}).Decode(&run); err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+func (ps *PortfolioService) InsertEvalRun(ctx context.Context, run *EvalRun) error {
+	run.ID = primitive.NewObjectID()
+	_, err := ps.evalRuns.InsertOne(ctx, run)
+	return err
+}
+
+// normalizeEvalText lowercases text and reduces punctuation and whitespace to single spaces, so facts
+// match regardless of formatting such as bullet points or bold markers
+func normalizeEvalText(text string) string {
+	var out strings.Builder
+	space := true
+	for _, r := range strings.ToLower(text) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '+' || r == '#' {
+			out.WriteRune(r)
+			space = false
+		} else if !space {
+			out.WriteRune(' ')
+			space = true
+		}
+	}
+	return strings.TrimSpace(out.String())
+}
+
+// JudgeFacts asks the model which of the expected facts an answer states, allowing for paraphrase
+func (l *LLMService) JudgeFacts(ctx context.Context, question, answer string, facts []string) ([]bool, error) {
+	factList, _ := json.Marshal(facts)
+	prompt := fmt.Sprintf(`You are grading an assistant's answer about a software engineer's portfolio.
+
+QUESTION: %s
+
+ANSWER:
+%s
+
+EXPECTED FACTS (JSON array): %s
+
+For each expected fact, decide whether the answer states it, allowing for paraphrase but not for guesses or contradictions.
+Respond with a JSON object of the form {"present": [true, false, ...]} with one boolean per expected fact, in order.`, question, answer, factList)
+
+	reply, err := l.complete(ctx, prompt, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var verdict struct {
+		Present []bool `json:"present"`
+	}
+	if err := json.Unmarshal([]byte(reply), &verdict); err != nil {
+		return nil, fmt.Errorf("invalid judge response: %w", err)
+	}
+	if len(verdict.Present) != len(facts) {
+		return nil, fmt.Errorf("judge returned %d verdicts for %d facts", len(verdict.Present), len(facts))
+	}
+	return verdict.Present, nil
+}
+
+// RunEvals answers every golden question with the current prompt (or a variant of it) and scores the
+// answers against their expected facts. With judge, facts missed by string matching are checked by the
+// model. The run is stored for comparison with earlier runs.
+func (l *LLMService) RunEvals(ctx context.Context, variant *PromptVariant, judge bool) (*EvalRun, error) {
+	cases, err := l.portfolioService.GetAllEvalCases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(cases) == 0 {
+		return nil, fmt.Errorf("no eval cases defined")
+	}
+
+	run := &EvalRun{Model: l.model, Variant: variant, Judge: judge, Results: []EvalResult{}, StartedAt: time.Now()}
+	if variant != nil && variant.Model != "" {
+		run.Model = variant.Model
+	}
+
+	total := 0.0
+	for _, evalCase := range cases {
+		result := EvalResult{CaseID: evalCase.ID, Question: evalCase.Question, Facts: []EvalFactCheck{}}
+
+		caseCtx, cancel := context.WithTimeout(ctx, 60*time.Second)
+		answer, err := l.ProcessQueryWithVariant(caseCtx, evalCase.Question, variant)
+		if err != nil {
+			cancel()
+			log.Printf("Eval case %s failed: %v", evalCase.ID.Hex(), err)
+			result.Error = err.Error()
+			run.Results = append(run.Results, result)
+			continue
+		}
+		result.Answer = answer
+
+		normalizedAnswer := normalizeEvalText(answer)
+		var missed []string
+		for _, fact := range evalCase.ExpectedFacts {
+			check := EvalFactCheck{Fact: fact, StringMatch: strings.Contains(normalizedAnswer, normalizeEvalText(fact))}
+			if !check.StringMatch {
+				missed = append(missed, fact)
+			}
+			result.Facts = append(result.Facts, check)
+		}
+
+		if judge && len(missed) > 0 {
+			verdicts, err := l.JudgeFacts(caseCtx, evalCase.Question, answer, missed)
+			if err != nil {
+				log.Printf("Eval judge failed for case %s: %v", evalCase.ID.Hex(), err)
+				result.Error = "judge: " + err.Error()
+			} else {
+				next := 0
+				for i := range result.Facts {
+					if !result.Facts[i].StringMatch {
+						present := verdicts[next]
+						result.Facts[i].JudgeMatch = &present
+						next++
+					}
+				}
+			}
+		}
+		cancel()
+
+		found := 0
+		for _, check := range result.Facts {
+			if check.StringMatch || (check.JudgeMatch != nil && *check.JudgeMatch) {
+				found++
+			}
+		}
+		if len(result.Facts) > 0 {
+			result.Score = float64(found) / float64(len(result.Facts))
+		}
+		total += result.Score
+		run.Results = append(run.Results, result)
+	}
+
+	run.Score = total / float64(len(cases))
+	run.FinishedAt = time.Now()
+	if err := l.portfolioService.InsertEvalRun(ctx, run); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// validateEvalCase checks a golden question created through the admin API
+func validateEvalCase(evalCase *EvalCase) error {
+	var v Validator
+	v.ChatInput("question", evalCase.Question, 500)
+	v.Check(len(evalCase.ExpectedFacts) >= 1 && len(evalCase.ExpectedFacts) <= 10, "expected_facts", "range", "between 1 and 10 expected facts are required")
+	for i, fact := range evalCase.ExpectedFacts {
+		v.MaxLength(fmt.Sprintf("expected_facts[%d]", i), fact, 200)
+	}
+	return v.Err()
+}
+
+// runEvalCommand implements `portfolio eval`, which runs the golden set from the command line and exits
+// non-zero when the score is below -min-score, so prompt or model changes can be checked in CI
+func runEvalCommand(llmService *LLMService, args []string) int {
+	flags := flag.NewFlagSet("eval", flag.ContinueOnError)
+	judge := flags.Bool("judge", false, "check facts missed by string matching with the model")
+	minScore := flags.Float64("min-score", 0.8, "lowest passing mean score")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if llmService == nil {
+		fmt.Println("OPENAI_API_KEY is not set, cannot run evals")
+		return 2
+	}
+
+	run, err := llmService.RunEvals(context.Background(), nil, *judge)
+	if err != nil {
+		fmt.Printf("Eval run failed: %v\n", err)
+		return 2
+	}
+
+	for _, result := range run.Results {
+		status := "PASS"
+		if result.Score < 1 {
+			status = "FAIL"
+		}
+		fmt.Printf("[%s] %.2f  %s\n", status, result.Score, result.Question)
+		if result.Error != "" {
+			fmt.Printf("       error: %s\n", result.Error)
+		}
+		for _, check := range result.Facts {
+			if !check.StringMatch && (check.JudgeMatch == nil || !*check.JudgeMatch) {
+				fmt.Printf("       missing: %s\n", check.Fact)
+			}
+		}
+	}
+	fmt.Printf("\nModel: %s | Score: %.2f | Minimum: %.2f | Run: %s\n", run.Model, run.Score, *minScore, run.ID.Hex())
+
+	if run.Score < *minScore {
+		return 1
+	}
+	return 0
+}
+
+// Evals endpoint: GET lists the golden questions, POST adds one (admin only)
+func (h *APIHandler) handleEvals(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if !isAdminRequest(r) {
+		log.Printf("Date: %s | Route: /api/admin/evals | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ctx := context.Background()
+
+	switch r.Method {
+	case "GET":
+		cases, err := h.service.GetAllEvalCases(ctx)
+		if err != nil {
+			log.Printf("Date: %s | Route: /api/admin/evals | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Date: %s | Route: /api/admin/evals | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(cases)
+
+	case "POST":
+		var evalCase EvalCase
+		if err := json.NewDecoder(r.Body).Decode(&evalCase); err != nil {
+			log.Printf("Date: %s | Route: /api/admin/evals | Status: BAD_REQUEST | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, "Invalid JSON request", http.StatusBadRequest)
+			return
+		}
+		evalCase.Question = strings.TrimSpace(evalCase.Question)
+		evalCase.ExpectedFacts = trimList(evalCase.ExpectedFacts)
+		evalCase.Tags = trimList(evalCase.Tags)
+		if err := validateEvalCase(&evalCase); err != nil {
+			log.Printf("Date: %s | Route: /api/admin/evals | Status: INVALID_INPUT | GPT Model: %s", currentTime, gptModel)
+			writeInvalidInput(w, err)
+			return
+		}
+
+		count, err := h.service.CountEvalCases(ctx)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if count >= maxEvalCases {
+			writeInvalidInput(w, invalidField("question", "max_items", fmt.Sprintf("the golden set is limited to %d questions", maxEvalCases)))
+			return
+		}
+
+		evalCase.CreatedAt = time.Now()
+		if err := h.service.InsertEvalCase(ctx, &evalCase); err != nil {
+			log.Printf("Date: %s | Route: /api/admin/evals | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Date: %s | Route: /api/admin/evals | Status: CREATED | GPT Model: %s", currentTime, gptModel)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(evalCase)
+
+	default:
+		log.Printf("Date: %s | Route: /api/admin/evals | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// Eval sub-routes (admin only):
+//
+//	POST   /api/admin/evals/run         runs the golden set; optional body {"judge": bool, "variant": {...}}
+//	GET    /api/admin/evals/runs        lists recent runs with their scores
+//	GET    /api/admin/evals/runs/{id}   returns a run with every answer
+//	DELETE /api/admin/evals/{id}        removes a golden question
+func (h *APIHandler) handleEvalRoutes(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if !isAdminRequest(r) {
+		log.Printf("Date: %s | Route: /api/admin/evals/ | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ctx := context.Background()
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/admin/evals/"), "/"), "/")
+
+	switch {
+	case len(parts) == 1 && parts[0] == "run":
+		if r.Method != "POST" {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if h.llmService == nil {
+			log.Printf("Date: %s | Route: /api/admin/evals/run | Status: LLM_DISABLED | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, "Evals require OPENAI_API_KEY", http.StatusServiceUnavailable)
+			return
+		}
+
+		var request struct {
+			Judge   bool           `json:"judge"`
+			Variant *PromptVariant `json:"variant"`
+		}
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+				http.Error(w, "Invalid JSON request", http.StatusBadRequest)
+				return
+			}
+		}
+
+		run, err := h.llmService.RunEvals(ctx, request.Variant, request.Judge)
+		if err != nil {
+			log.Printf("Date: %s | Route: /api/admin/evals/run | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Date: %s | Route: /api/admin/evals/run | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(run)
+
+	case parts[0] == "runs" && len(parts) <= 2:
+		if r.Method != "GET" {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if len(parts) == 1 {
+			runs, err := h.service.GetEvalRuns(ctx, 20)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(runs)
+			return
+		}
+
+		id, err := primitive.ObjectIDFromHex(parts[1])
+		if err != nil {
+			http.Error(w, "Invalid run ID", http.StatusBadRequest)
+			return
+		}
+		run, err := h.service.GetEvalRunByID(ctx, id)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				http.Error(w, "Eval run not found", http.StatusNotFound)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(run)
+
+	case len(parts) == 1:
+		if r.Method != "DELETE" {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		id, err := primitive.ObjectIDFromHex(parts[0])
+		if err != nil {
+			http.Error(w, "Invalid eval case ID", http.StatusBadRequest)
+			return
+		}
+		if err := h.service.DeleteEvalCase(ctx, id); err != nil {
+			if err == mongo.ErrNoDocuments {
+				http.Error(w, "Eval case not found", http.StatusNotFound)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Date: %s | Route: /api/admin/evals/{id} | Status: DELETED | GPT Model: %s", currentTime, gptModel)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		http.Error(w, "Not found", http.StatusNotFound)
+	}
+}
@@ -0,0 +1,393 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// eventsReplaySize is how many recent events are kept to replay to a client reconnecting with Last-Event-ID
+	eventsReplaySize = 100
+	// eventsHeartbeat keeps idle connections open through proxies that close silent ones
+	eventsHeartbeat = 25 * time.Second
+	// eventsRetryMs is the reconnection delay suggested to clients
+	eventsRetryMs = 5000
+	// eventsSubscriberBuffer is how many events a slow client may lag behind before it is disconnected
+	eventsSubscriberBuffer = 16
+	// eventsWatchMaxBackoff caps the delay between attempts to reopen the change stream
+	eventsWatchMaxBackoff = 5 * time.Minute
+	// changeStreamsUnsupported is the MongoDB error code for change streams on a standalone server
+	changeStreamsUnsupported = 40573
+)
+
+// Data change event types
+const (
+	EventProjectAdded   = "project.added"
+	EventProjectUpdated = "project.updated"
+	EventProjectRemoved = "project.removed" // Deleted, or no longer listed
+	EventPostPublished  = "post.published"
+	EventPostUpdated    = "post.updated"
+	EventPostRemoved    = "post.removed" // Deleted, unpublished or back to draft
+)
+
+// DataEvent tells the frontend that a public list changed, so it refetches instead of polling
+type DataEvent struct {
+	ID         string             `json:"-"` // Resume token of the change, the same on every replica
+	Type       string             `json:"type"`
+	DocumentID string             `json:"id"`
+	AuthorID   primitive.ObjectID `json:"author_id"`
+	Title      string             `json:"title,omitempty"`
+	URL        string             `json:"url,omitempty"`
+	At         time.Time          `json:"at"`
+}
+
+// EventHub fans data change events out to the SSE clients connected to this replica. Every replica watches
+// the change streams itself, so clients receive every change whichever replica they are connected to.
+type EventHub struct {
+	service    *PortfolioService
+	maxClients int
+
+	mutex       sync.Mutex
+	subscribers map[chan DataEvent]struct{}
+	recent      []DataEvent // Ring of the last eventsReplaySize events, oldest first
+}
+
+// NewEventHub reads the cap on concurrent SSE clients per replica from EVENTS_MAX_CLIENTS (default 500)
+func NewEventHub(service *PortfolioService) *EventHub {
+	maxClients := 500
+	if value := os.Getenv("EVENTS_MAX_CLIENTS"); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil && parsed > 0 {
+			maxClients = parsed
+		} else {
+			log.Printf("Invalid EVENTS_MAX_CLIENTS %q, using %d", value, maxClients)
+		}
+	}
+	return &EventHub{
+		service:     service,
+		maxClients:  maxClients,
+		subscribers: make(map[chan DataEvent]struct{}),
+	}
+}
+
+// Publish sends an event to every subscriber; a subscriber whose buffer is full is dropped, and its client
+// reconnects and catches up from Last-Event-ID
+func (hub *EventHub) Publish(event DataEvent) {
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+	hub.recent = append(hub.recent, event)
+	if len(hub.recent) > eventsReplaySize {
+		hub.recent = hub.recent[len(hub.recent)-eventsReplaySize:]
+	}
+	for ch := range hub.subscribers {
+		select {
+		case ch <- event:
+		default:
+			delete(hub.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// Subscribe registers a client and returns its channel and the events it missed after lastEventID, or
+// false when the replica already serves the maximum number of clients
+func (hub *EventHub) Subscribe(lastEventID string) (chan DataEvent, []DataEvent, bool) {
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+	if len(hub.subscribers) >= hub.maxClients {
+		return nil, nil, false
+	}
+	ch := make(chan DataEvent, eventsSubscriberBuffer)
+	hub.subscribers[ch] = struct{}{}
+
+	var missed []DataEvent
+	if lastEventID != "" {
+		for i, event := range hub.recent {
+			if event.ID == lastEventID {
+				missed = append(missed, hub.recent[i+1:]...)
+				break
+			}
+		}
+	}
+	return ch, missed, true
+}
+
+// Unsubscribe removes a client, unless Publish already dropped it
+func (hub *EventHub) Unsubscribe(ch chan DataEvent) {
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+	if _, ok := hub.subscribers[ch]; ok {
+		delete(hub.subscribers, ch)
+		close(ch)
+	}
+}
+
+// changeEvent is the part of a change stream document the hub reads
+type changeEvent struct {
+	ID            bson.Raw `bson:"_id"`
+	OperationType string   `bson:"operationType"`
+	Namespace     struct {
+		Collection string `bson:"coll"`
+	} `bson:"ns"`
+	DocumentKey struct {
+		ID primitive.ObjectID `bson:"_id"`
+	} `bson:"documentKey"`
+	FullDocument      bson.Raw `bson:"fullDocument"`
+	UpdateDescription struct {
+		UpdatedFields bson.M `bson:"updatedFields"`
+	} `bson:"updateDescription"`
+}
+
+// Watch follows the project and post change streams and publishes their public changes until ctx is done.
+// It reopens the stream after errors from where it left off, and gives up on a standalone server, where
+// change streams are not available.
+func (hub *EventHub) Watch(ctx context.Context) {
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: bson.M{
+		"ns.coll":       bson.M{"$in": bson.A{"projects", "posts"}},
+		"operationType": bson.M{"$in": bson.A{"insert", "update", "replace", "delete"}},
+	}}}}
+	var resumeToken bson.Raw
+	backoff := 5 * time.Second
+
+	for ctx.Err() == nil {
+		opts := options.ChangeStream().SetFullDocument(options.UpdateLookup)
+		if resumeToken != nil {
+			opts.SetStartAfter(resumeToken)
+		}
+		stream, err := hub.service.database.Watch(ctx, pipeline, opts)
+		if err == nil {
+			backoff = 5 * time.Second
+			for stream.Next(ctx) {
+				var change changeEvent
+				if err := stream.Decode(&change); err != nil {
+					log.Printf("Error decoding change event: %v", err)
+					continue
+				}
+				resumeToken = stream.ResumeToken()
+				if event, ok := dataEventFor(&change); ok {
+					hub.Publish(event)
+				}
+			}
+			err = stream.Err()
+			stream.Close(context.Background())
+		}
+		if ctx.Err() != nil {
+			return
+		}
+
+		var commandErr mongo.CommandError
+		if errors.As(err, &commandErr) && commandErr.Code == changeStreamsUnsupported {
+			log.Printf("Change streams are not available on this MongoDB server, /api/events will not send data events")
+			return
+		}
+		log.Printf("Change stream error, reopening in %v: %v", backoff, err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > eventsWatchMaxBackoff {
+			backoff = eventsWatchMaxBackoff
+		}
+	}
+}
+
+// dataEventFor turns a change into the event visitors may see: hidden projects and unpublished posts are
+// only reported when they leave the public lists
+func dataEventFor(change *changeEvent) (DataEvent, bool) {
+	event := DataEvent{
+		ID:         fmt.Sprintf("%x", change.ID),
+		DocumentID: change.DocumentKey.ID.Hex(),
+		At:         time.Now(),
+	}
+	if data, err := change.ID.LookupErr("_data"); err == nil {
+		if token, ok := data.StringValueOK(); ok {
+			event.ID = token
+		}
+	}
+	updated := func(fields ...string) bool {
+		if change.OperationType == "replace" {
+			return true // A replaced document may have changed any field
+		}
+		for _, field := range fields {
+			if _, ok := change.UpdateDescription.UpdatedFields[field]; ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch change.Namespace.Collection {
+	case "projects":
+		var project Project
+		if change.OperationType == "delete" || change.FullDocument == nil || bson.Unmarshal(change.FullDocument, &project) != nil {
+			event.Type = EventProjectRemoved
+			return event, change.OperationType == "delete"
+		}
+		event.AuthorID, event.Title = project.AuthorID, project.Name
+		slug := project.Slug
+		if slug == "" {
+			slug = slugify(project.Name)
+		}
+		event.URL = "/projects/" + slug
+		switch {
+		case project.Visibility == "unlisted" || project.Visibility == "private":
+			// Only a change of visibility is news: the project left the lists
+			event.Type, event.Title, event.URL = EventProjectRemoved, "", ""
+			return event, updated("visibility")
+		case change.OperationType == "insert" || updated("visibility"):
+			event.Type = EventProjectAdded
+		default:
+			event.Type = EventProjectUpdated
+		}
+		return event, true
+
+	case "posts":
+		var post Post
+		if change.OperationType == "delete" || change.FullDocument == nil || bson.Unmarshal(change.FullDocument, &post) != nil {
+			event.Type = EventPostRemoved
+			return event, change.OperationType == "delete"
+		}
+		event.AuthorID, event.Title, event.URL = post.AuthorID, post.Title, "/api/posts/"+post.Slug
+		switch {
+		case post.Draft || post.PublishedAt == nil || post.PublishedAt.After(time.Now()):
+			// Scheduled posts are announced when the list endpoints start returning them, on the next poll
+			event.Type, event.Title, event.URL = EventPostRemoved, "", ""
+			return event, updated("draft", "published_at")
+		case change.OperationType == "insert" || updated("draft", "published_at"):
+			event.Type = EventPostPublished
+		default:
+			event.Type = EventPostUpdated
+		}
+		return event, true
+	}
+	return event, false
+}
+
+// Events endpoint: GET /api/events?types=project.added,post.published&author_id= streams data change
+// events as server-sent events, so the frontend refreshes its lists as they change. Reconnecting clients
+// get the events they missed from Last-Event-ID, as long as this replica still has them.
+func (h *APIHandler) handleEvents(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if r.Method != "GET" {
+		log.Printf("Date: %s | Route: /api/events | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var v Validator
+	types := map[string]bool{}
+	if value := r.URL.Query().Get("types"); value != "" {
+		for _, eventType := range strings.Split(value, ",") {
+			eventType = strings.TrimSpace(eventType)
+			v.OneOf("types", eventType, EventProjectAdded, EventProjectUpdated, EventProjectRemoved, EventPostPublished, EventPostUpdated, EventPostRemoved)
+			types[eventType] = true
+		}
+	}
+	var authorID primitive.ObjectID
+	if value := r.URL.Query().Get("author_id"); value != "" {
+		parsed, err := primitive.ObjectIDFromHex(value)
+		v.Check(err == nil, "author_id", "format", "author_id must be an ID")
+		authorID = parsed
+	}
+	if err := v.Err(); err != nil {
+		writeInvalidInput(w, err)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		log.Printf("Date: %s | Route: /api/events | Status: STREAMING_UNSUPPORTED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("last_event_id") // EventSource polyfills cannot always set headers
+	}
+	events, missed, ok := h.events.Subscribe(lastEventID)
+	if !ok {
+		log.Printf("Date: %s | Route: /api/events | Status: TOO_MANY_CLIENTS | GPT Model: %s", currentTime, gptModel)
+		w.Header().Set("Retry-After", strconv.Itoa(eventsRetryMs/1000))
+		http.Error(w, "Too many event stream clients, please retry shortly", http.StatusServiceUnavailable)
+		return
+	}
+	defer h.events.Unsubscribe(events)
+
+	log.Printf("Date: %s | Route: /api/events | Status: STREAMING | GPT Model: %s", currentTime, gptModel)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no") // Keep nginx from buffering the stream
+	w.WriteHeader(http.StatusOK)
+
+	send := func(event DataEvent) error {
+		if len(types) > 0 && !types[event.Type] {
+			return nil
+		}
+		if !authorID.IsZero() && event.AuthorID != authorID {
+			return nil
+		}
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+		return err
+	}
+
+	if _, err := fmt.Fprintf(w, "retry: %d\n\n", eventsRetryMs); err != nil {
+		return
+	}
+	for _, event := range missed {
+		if err := send(event); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(eventsHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, open := <-events:
+			if !open {
+				// Dropped for falling behind; the client reconnects and catches up from Last-Event-ID
+				return
+			}
+			if err := send(event); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
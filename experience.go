@@ -0,0 +1,487 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// refreshDuration derives the months in a position from its dates, so the current position keeps counting.
+// Positions without a start date keep the stored value.
+func (e *Experience) refreshDuration() {
+	if e.StartDate != nil && !e.StartDate.IsZero() {
+		e.TimePresent = monthsBetween(*e.StartDate, e.EndDate)
+	}
+	if e.Projects == nil {
+		e.Projects = []Project{}
+	}
+}
+
+// Experience query methods; ?company= matches part of the company name and ?technology= one technology,
+// both case-insensitively. The most recent position comes first.
+func (ps *PortfolioService) GetExperience(ctx context.Context, authorID primitive.ObjectID, company, technology string) ([]Experience, error) {
+	filter := bson.M{}
+	if !authorID.IsZero() {
+		filter["author_id"] = authorID
+	}
+	if company != "" {
+		filter["company"] = bson.M{"$regex": regexp.QuoteMeta(company), "$options": "i"}
+	}
+	if technology != "" {
+		filter["technologies"] = bson.M{"$regex": "^" + regexp.QuoteMeta(technology) + "$", "$options": "i"}
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "start_date", Value: -1}, {Key: "_id", Value: -1}})
+	cursor, err := ps.experience.Find(ctx, filter, boundedFind(opts))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var experience []Experience
+	if err = cursor.All(ctx, &experience); err != nil {
+		return nil, err
+	}
+	for i := range experience {
+		experience[i].refreshDuration()
+	}
+	return experience, nil
+}
+
+func (ps *PortfolioService) GetExperienceByID(ctx context.Context, id primitive.ObjectID) (*Experience, error) {
+	var experience Experience
+	if err := ps.experience.FindOne(ctx, bson.M{"_id": id}).Decode(&experience); err != nil {
+		return nil, err
+	}
+	experience.refreshDuration()
+	return &experience, nil
+}
+
+func (ps *PortfolioService) InsertExperience(ctx context.Context, experience *Experience) error {
+	if experience.ID.IsZero() {
+		experience.ID = primitive.NewObjectID()
+	}
+	experience.refreshDuration()
+	experience.stampCreated()
+	_, err := ps.experience.InsertOne(ctx, experience)
+	return err
+}
+
+// UpdateExperience replaces a position; created_at must already be carried over from the stored document
+func (ps *PortfolioService) UpdateExperience(ctx context.Context, experience *Experience) error {
+	experience.refreshDuration()
+	experience.stampUpdated()
+	result, err := ps.experience.ReplaceOne(ctx, bson.M{"_id": experience.ID}, experience)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// DeleteExperience deletes a position and removes it from the resume referencing it
+func (ps *PortfolioService) DeleteExperience(ctx context.Context, id primitive.ObjectID) error {
+	result, err := ps.experience.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	ps.recordDeletions(ctx, "experience", id)
+	_, err = ps.resumes.UpdateMany(ctx, bson.M{"experience_ids": id}, withUpdatedAt(bson.M{"$pull": bson.M{"experience_ids": id}}))
+	return err
+}
+
+func (ps *PortfolioService) CountExperience(ctx context.Context) (int64, error) {
+	return ps.experience.CountDocuments(ctx, bson.M{})
+}
+
+// attachExperience lists a new position first on its author's resume, if the author has one
+func (ps *PortfolioService) attachExperience(ctx context.Context, experience *Experience) error {
+	_, err := ps.resumes.UpdateOne(ctx, bson.M{"author_id": experience.AuthorID}, withUpdatedAt(bson.M{
+		"$push": bson.M{"experience_ids": bson.M{"$each": []primitive.ObjectID{experience.ID}, "$position": 0}},
+	}))
+	return err
+}
+
+// assembleResumes fills in the experience of resumes from the positions they reference, in resume order,
+// with one query for all of them. Resumes still embedding their experience are left as they are.
+func (ps *PortfolioService) assembleResumes(ctx context.Context, resumes ...*Resume) error {
+	var ids []primitive.ObjectID
+	for _, resume := range resumes {
+		ids = append(ids, resume.ExperienceIDs...)
+	}
+	if len(ids) == 0 {
+		for _, resume := range resumes {
+			if resume.Experience == nil {
+				resume.Experience = []Experience{}
+			}
+		}
+		return nil
+	}
+
+	cursor, err := ps.experience.Find(ctx, bson.M{"_id": bson.M{"$in": ids}}, boundedFind())
+	if err != nil {
+		return err
+	}
+	var found []Experience
+	if err = cursor.All(ctx, &found); err != nil {
+		return err
+	}
+	byID := make(map[primitive.ObjectID]Experience, len(found))
+	for _, experience := range found {
+		experience.refreshDuration()
+		byID[experience.ID] = experience
+	}
+
+	for _, resume := range resumes {
+		if len(resume.ExperienceIDs) == 0 {
+			if resume.Experience == nil {
+				resume.Experience = []Experience{}
+			}
+			continue
+		}
+		resume.Experience = make([]Experience, 0, len(resume.ExperienceIDs))
+		for _, id := range resume.ExperienceIDs {
+			if experience, ok := byID[id]; ok {
+				resume.Experience = append(resume.Experience, experience)
+			}
+		}
+	}
+	return nil
+}
+
+// assembleResumeList assembles every resume of a list
+func (ps *PortfolioService) assembleResumeList(ctx context.Context, resumes []Resume) error {
+	pointers := make([]*Resume, len(resumes))
+	for i := range resumes {
+		pointers[i] = &resumes[i]
+	}
+	return ps.assembleResumes(ctx, pointers...)
+}
+
+// saveResumeExperience stores the positions of a resume being written in the experience collection and
+// references them from the resume: new positions are inserted, known ones replaced, and positions the
+// resume referenced before but no longer lists are deleted. A resume written with references only is kept
+// as it is.
+func (ps *PortfolioService) saveResumeExperience(ctx context.Context, resume *Resume, previous []primitive.ObjectID) error {
+	if resume.Experience != nil {
+		resume.ExperienceIDs = make([]primitive.ObjectID, 0, len(resume.Experience))
+		for i := range resume.Experience {
+			experience := &resume.Experience[i]
+			experience.AuthorID = resume.AuthorID
+			if experience.ID.IsZero() {
+				if err := ps.InsertExperience(ctx, experience); err != nil {
+					return err
+				}
+			} else {
+				stored, err := ps.GetExperienceByID(ctx, experience.ID)
+				if err == mongo.ErrNoDocuments {
+					if err := ps.InsertExperience(ctx, experience); err != nil {
+						return err
+					}
+				} else if err != nil {
+					return err
+				} else {
+					experience.CreatedAt = stored.CreatedAt
+					if err := ps.UpdateExperience(ctx, experience); err != nil {
+						return err
+					}
+				}
+			}
+			resume.ExperienceIDs = append(resume.ExperienceIDs, experience.ID)
+		}
+	}
+
+	kept := make(map[primitive.ObjectID]bool, len(resume.ExperienceIDs))
+	for _, id := range resume.ExperienceIDs {
+		kept[id] = true
+	}
+	var removed []primitive.ObjectID
+	for _, id := range previous {
+		if !kept[id] {
+			removed = append(removed, id)
+		}
+	}
+	if len(removed) > 0 {
+		if _, err := ps.experience.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": removed}, "author_id": resume.AuthorID}); err != nil {
+			return err
+		}
+		ps.recordDeletions(ctx, "experience", removed...)
+	}
+	return nil
+}
+
+// MigrateResumeExperience moves experience embedded in resumes into the experience collection. A resume is
+// claimed by setting its references first, so replicas starting together do not both migrate it, and the
+// embedded copy is only removed once the positions are stored. Returns the number of resumes migrated.
+func (ps *PortfolioService) MigrateResumeExperience(ctx context.Context) (int64, error) {
+	cursor, err := ps.resumes.Find(ctx, bson.M{"experience.0": bson.M{"$exists": true}}, timedFind())
+	if err != nil {
+		return 0, err
+	}
+	var resumes []Resume
+	if err = cursor.All(ctx, &resumes); err != nil {
+		return 0, err
+	}
+
+	var migrated int64
+	for _, resume := range resumes {
+		ids := resume.ExperienceIDs
+		if len(ids) == 0 {
+			ids = make([]primitive.ObjectID, len(resume.Experience))
+			for i := range ids {
+				ids[i] = primitive.NewObjectID()
+			}
+			result, err := ps.resumes.UpdateOne(ctx,
+				bson.M{"_id": resume.ID, "experience_ids": bson.M{"$exists": false}},
+				bson.M{"$set": bson.M{"experience_ids": ids}})
+			if err != nil {
+				return migrated, err
+			}
+			if result.ModifiedCount == 0 {
+				continue
+			}
+		} else if len(ids) != len(resume.Experience) {
+			log.Printf("Resume %s references %d positions but embeds %d, leaving it for review", resume.ID.Hex(), len(ids), len(resume.Experience))
+			continue
+		}
+
+		documents := make([]interface{}, len(resume.Experience))
+		for i := range resume.Experience {
+			experience := resume.Experience[i]
+			experience.ID = ids[i]
+			experience.AuthorID = resume.AuthorID
+			experience.refreshDuration()
+			experience.stampCreated()
+			documents[i] = experience
+		}
+		// A replica that crashed after claiming the resume may have stored some positions already
+		if _, err := ps.experience.InsertMany(ctx, documents, options.InsertMany().SetOrdered(false)); err != nil && !mongo.IsDuplicateKeyError(err) {
+			return migrated, err
+		}
+		if _, err := ps.resumes.UpdateOne(ctx, bson.M{"_id": resume.ID}, bson.M{"$unset": bson.M{"experience": ""}}); err != nil {
+			return migrated, err
+		}
+		migrated++
+	}
+	return migrated, nil
+}
+
+// validateExperience checks a manually entered position
+func validateExperience(experience *Experience) error {
+	var v Validator
+	v.Required("job_title", experience.JobTitle)
+	v.MaxLength("job_title", experience.JobTitle, 200)
+	v.Required("company", experience.Company)
+	v.MaxLength("company", experience.Company, 200)
+	v.MaxLength("location", experience.Location, 200)
+	v.Check(!experience.AuthorID.IsZero(), "author_id", "required", "author_id is required")
+	v.Check(experience.StartDate != nil && !experience.StartDate.IsZero(), "start_date", "required", "start_date is required")
+	if experience.StartDate != nil && experience.EndDate != nil {
+		v.Check(!experience.EndDate.Before(*experience.StartDate), "end_date", "range", "end_date must not be before start_date")
+	}
+	v.MaxLength("description", experience.Description, 5000)
+	if v.MaxItems("technologies", len(experience.Technologies), 50) {
+		for _, technology := range experience.Technologies {
+			if !v.Check(strings.TrimSpace(technology) != "", "technologies", "required", "technologies entries must not be empty") ||
+				!v.MaxLength("technologies", technology, 100) {
+				break
+			}
+		}
+	}
+	return v.Err()
+}
+
+// Experience endpoints: GET lists positions (?author_id=, ?company= and ?technology= filter), POST creates
+// one and lists it first on the author's resume (admin only)
+func (h *APIHandler) handleExperience(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	ctx := context.Background()
+
+	switch r.Method {
+	case "GET":
+		var authorID primitive.ObjectID
+		if value := r.URL.Query().Get("author_id"); value != "" {
+			parsed, err := primitive.ObjectIDFromHex(value)
+			if err != nil {
+				writeInvalidInput(w, invalidField("author_id", "format", "author_id must be an ID"))
+				return
+			}
+			authorID = parsed
+		} else if author := h.hostAuthor(r); author != nil {
+			authorID = author.ID
+		}
+
+		experience, err := h.service.GetExperience(ctx, authorID, r.URL.Query().Get("company"), r.URL.Query().Get("technology"))
+		if err != nil {
+			log.Printf("Date: %s | Route: /api/experience | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Date: %s | Route: /api/experience | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+		writeTimestampedList(w, r, experience)
+
+	case "POST":
+		if !isAdminRequest(r) {
+			log.Printf("Date: %s | Route: /api/experience | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		var experience Experience
+		if err := json.NewDecoder(r.Body).Decode(&experience); err != nil {
+			log.Printf("Date: %s | Route: /api/experience | Status: BAD_REQUEST | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, "Invalid JSON request", http.StatusBadRequest)
+			return
+		}
+		if err := validateExperience(&experience); err != nil {
+			log.Printf("Date: %s | Route: /api/experience | Status: INVALID_INPUT | GPT Model: %s", currentTime, gptModel)
+			writeInvalidInput(w, err)
+			return
+		}
+		if _, err := h.service.GetAuthorByID(ctx, experience.AuthorID); err == mongo.ErrNoDocuments {
+			writeInvalidInput(w, invalidField("author_id", "exists", "author_id does not match an author"))
+			return
+		} else if err != nil {
+			log.Printf("Date: %s | Route: /api/experience | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		experience.ID = primitive.NilObjectID
+		if err := h.service.InsertExperience(ctx, &experience); err != nil {
+			log.Printf("Date: %s | Route: /api/experience | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if err := h.service.attachExperience(ctx, &experience); err != nil {
+			log.Printf("Error adding experience %s to the resume: %v", experience.ID.Hex(), err)
+		}
+
+		log.Printf("Date: %s | Route: /api/experience | Status: CREATED | GPT Model: %s", currentTime, gptModel)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(experience)
+
+	default:
+		log.Printf("Date: %s | Route: /api/experience | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// Experience detail endpoints: GET /api/experience/{id}; PUT replaces the position and DELETE removes it
+// from the collection and the resume (admin only)
+func (h *APIHandler) handleExperienceRoutes(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	key := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/experience/"), "/")
+	if key == "" || strings.Contains(key, "/") {
+		http.NotFound(w, r)
+		return
+	}
+	id, err := primitive.ObjectIDFromHex(key)
+	if err != nil {
+		http.Error(w, "Invalid experience ID", http.StatusBadRequest)
+		return
+	}
+
+	if r.Method != "GET" && !isAdminRequest(r) {
+		log.Printf("Date: %s | Route: /api/experience/{id} | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ctx := context.Background()
+	experience, err := h.service.GetExperienceByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			http.Error(w, "Experience not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("Date: %s | Route: /api/experience/{id} | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		log.Printf("Date: %s | Route: /api/experience/{id} | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(experience)
+
+	case "PUT":
+		var updated Experience
+		if err := json.NewDecoder(r.Body).Decode(&updated); err != nil {
+			log.Printf("Date: %s | Route: /api/experience/{id} | Status: BAD_REQUEST | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, "Invalid JSON request", http.StatusBadRequest)
+			return
+		}
+		// A position stays with its author; only the author's resume references it
+		updated.ID = experience.ID
+		updated.AuthorID = experience.AuthorID
+		updated.CreatedAt = experience.CreatedAt
+		if err := validateExperience(&updated); err != nil {
+			log.Printf("Date: %s | Route: /api/experience/{id} | Status: INVALID_INPUT | GPT Model: %s", currentTime, gptModel)
+			writeInvalidInput(w, err)
+			return
+		}
+
+		if err := h.service.UpdateExperience(ctx, &updated); err != nil {
+			log.Printf("Date: %s | Route: /api/experience/{id} | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Date: %s | Route: /api/experience/{id} | Status: UPDATED | GPT Model: %s", currentTime, gptModel)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(updated)
+
+	case "DELETE":
+		if err := h.service.DeleteExperience(ctx, experience.ID); err != nil {
+			log.Printf("Date: %s | Route: /api/experience/{id} | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Date: %s | Route: /api/experience/{id} | Status: DELETED | GPT Model: %s", currentTime, gptModel)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		log.Printf("Date: %s | Route: /api/experience/{id} | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,427 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// chatSessionPattern matches session IDs issued by the chatbot endpoint (and reasonable client-made ones)
+var chatSessionPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{8,64}$`)
+
+// newChatSessionID returns a random session ID for a visitor starting a conversation
+func newChatSessionID() string {
+	buf := make([]byte, 16)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// assignVariant picks a variant for a session by weight. The choice is a hash of the experiment and
+// session, so a session keeps its variant for every question without storing the assignment.
+func assignVariant(experiment *Experiment, sessionID string) *PromptVariant {
+	total := 0
+	for _, variant := range experiment.Variants {
+		total += variant.Weight
+	}
+	if total == 0 {
+		return nil
+	}
+
+	hash := fnv.New32a()
+	hash.Write([]byte(experiment.ID.Hex() + ":" + sessionID))
+	point := int(hash.Sum32() % uint32(total))
+	for i := range experiment.Variants {
+		point -= experiment.Variants[i].Weight
+		if point < 0 {
+			return &experiment.Variants[i]
+		}
+	}
+	return nil
+}
+
+// Experiment query methods
+
+// GetActiveExperiment returns the running experiment, or nil if none is active
+func (ps *PortfolioService) GetActiveExperiment(ctx context.Context) (*Experiment, error) {
+	var experiment Experiment
+	err := ps.experiments.FindOne(ctx, bson.M{"active": true}).Decode(&experiment)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &experiment, nil
+}
+
+func (ps *PortfolioService) GetAllExperiments(ctx context.Context) ([]Experiment, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := ps.experiments.Find(ctx, bson.M{}, boundedFind(opts))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	experiments := []Experiment{}
+	if err = cursor.All(ctx, &experiments); err != nil {
+		return nil, err
+	}
+	return experiments, nil
+}
+
+func (ps *PortfolioService) GetExperimentByID(ctx context.Context, id primitive.ObjectID) (*Experiment, error) {
+	var experiment Experiment
+	if err := ps.experiments.FindOne(ctx, bson.M{"_id": id}).Decode(&experiment); err != nil {
+		return nil, err
+	}
+	return &experiment, nil
+}
+
+// InsertExperiment stores a new experiment; an active one stops whichever experiment was running
+func (ps *PortfolioService) InsertExperiment(ctx context.Context, experiment *Experiment) error {
+	if experiment.Active {
+		if _, err := ps.experiments.UpdateMany(ctx, bson.M{"active": true}, bson.M{"$set": bson.M{"active": false}}); err != nil {
+			return err
+		}
+	}
+	experiment.ID = primitive.NewObjectID()
+	_, err := ps.experiments.InsertOne(ctx, experiment)
+	return err
+}
+
+// SetExperimentActive starts or stops an experiment, stopping any other running experiment when starting
+func (ps *PortfolioService) SetExperimentActive(ctx context.Context, id primitive.ObjectID, active bool) error {
+	if active {
+		if _, err := ps.experiments.UpdateMany(ctx, bson.M{"active": true, "_id": bson.M{"$ne": id}}, bson.M{"$set": bson.M{"active": false}}); err != nil {
+			return err
+		}
+	}
+	result, err := ps.experiments.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"active": active}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// RecordChatTurn stores a chatbot answer for experiment comparison and feedback
+func (ps *PortfolioService) RecordChatTurn(ctx context.Context, turn *ChatTurn) error {
+	turn.ID = primitive.NewObjectID()
+	_, err := ps.chatTurns.InsertOne(ctx, turn)
+	return err
+}
+
+// SetChatTurnFeedback records the visitor's rating of an answer. The session must match so visitors can
+// only rate their own answers.
+func (ps *PortfolioService) SetChatTurnFeedback(ctx context.Context, id primitive.ObjectID, sessionID, feedback string) error {
+	result, err := ps.chatTurns.UpdateOne(ctx,
+		bson.M{"_id": id, "session_id": sessionID},
+		bson.M{"$set": bson.M{"feedback": feedback}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// VariantResult compares the outcomes of one experiment variant
+type VariantResult struct {
+	Variant      string  `bson:"_id" json:"variant"`
+	Turns        int     `bson:"turns" json:"turns"`
+	Sessions     int     `bson:"sessions" json:"sessions"`
+	AvgLatencyMs float64 `bson:"avg_latency_ms" json:"avg_latency_ms"`
+	MaxLatencyMs int64   `bson:"max_latency_ms" json:"max_latency_ms"`
+	ThumbsUp     int     `bson:"thumbs_up" json:"thumbs_up"`
+	ThumbsDown   int     `bson:"thumbs_down" json:"thumbs_down"`
+	ApprovalRate float64 `bson:"-" json:"approval_rate"` // Share of rated answers rated up
+}
+
+// CompareExperimentVariants summarizes latency and feedback per variant of an experiment
+func (ps *PortfolioService) CompareExperimentVariants(ctx context.Context, experimentID primitive.ObjectID) ([]VariantResult, error) {
+	pipeline := []bson.M{
+		{"$match": bson.M{"experiment_id": experimentID}},
+		{"$group": bson.M{
+			"_id":            "$variant",
+			"turns":          bson.M{"$sum": 1},
+			"sessions":       bson.M{"$addToSet": "$session_id"},
+			"avg_latency_ms": bson.M{"$avg": "$latency_ms"},
+			"max_latency_ms": bson.M{"$max": "$latency_ms"},
+			"thumbs_up":      bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$feedback", "up"}}, 1, 0}}},
+			"thumbs_down":    bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$feedback", "down"}}, 1, 0}}},
+		}},
+		{"$addFields": bson.M{"sessions": bson.M{"$size": "$sessions"}}},
+		{"$sort": bson.M{"_id": 1}},
+	}
+	cursor, err := ps.chatTurns.Aggregate(ctx, pipeline, timedAggregate())
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	results := []VariantResult{}
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	for i := range results {
+		if rated := results[i].ThumbsUp + results[i].ThumbsDown; rated > 0 {
+			results[i].ApprovalRate = float64(results[i].ThumbsUp) / float64(rated)
+		}
+	}
+	return results, nil
+}
+
+// validateExperiment checks an experiment created through the admin API
+func validateExperiment(experiment *Experiment) error {
+	var v Validator
+	if v.Required("name", experiment.Name) {
+		v.MaxLength("name", experiment.Name, 100)
+	}
+	v.Check(len(experiment.Variants) >= 2 && len(experiment.Variants) <= 5, "variants", "range", "an experiment needs between 2 and 5 variants")
+	names := make(map[string]bool)
+	for i, variant := range experiment.Variants {
+		field := fmt.Sprintf("variants[%d]", i)
+		if v.Required(field+".name", variant.Name) && v.MaxLength(field+".name", variant.Name, 50) {
+			v.Check(!names[variant.Name], field+".name", "unique", fmt.Sprintf("duplicate variant name %q", variant.Name))
+			names[variant.Name] = true
+		}
+		v.Range(field+".weight", float64(variant.Weight), 1, 100)
+		v.MaxLength(field+".instructions", variant.Instructions, 2000)
+	}
+	return v.Err()
+}
+
+// Experiments endpoint: GET lists experiments, POST creates one (admin only)
+func (h *APIHandler) handleExperiments(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if !isAdminRequest(r) {
+		log.Printf("Date: %s | Route: /api/admin/experiments | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ctx := context.Background()
+
+	switch r.Method {
+	case "GET":
+		experiments, err := h.service.GetAllExperiments(ctx)
+		if err != nil {
+			log.Printf("Date: %s | Route: /api/admin/experiments | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Date: %s | Route: /api/admin/experiments | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(experiments)
+
+	case "POST":
+		var experiment Experiment
+		if err := json.NewDecoder(r.Body).Decode(&experiment); err != nil {
+			log.Printf("Date: %s | Route: /api/admin/experiments | Status: BAD_REQUEST | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, "Invalid JSON request", http.StatusBadRequest)
+			return
+		}
+		experiment.Name = strings.TrimSpace(experiment.Name)
+		for i := range experiment.Variants {
+			experiment.Variants[i].Name = strings.TrimSpace(experiment.Variants[i].Name)
+			experiment.Variants[i].Model = strings.TrimSpace(experiment.Variants[i].Model)
+		}
+		if err := validateExperiment(&experiment); err != nil {
+			log.Printf("Date: %s | Route: /api/admin/experiments | Status: INVALID_INPUT | GPT Model: %s", currentTime, gptModel)
+			writeInvalidInput(w, err)
+			return
+		}
+
+		experiment.CreatedAt = time.Now()
+		if err := h.service.InsertExperiment(ctx, &experiment); err != nil {
+			log.Printf("Date: %s | Route: /api/admin/experiments | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Date: %s | Route: /api/admin/experiments | Status: CREATED | GPT Model: %s", currentTime, gptModel)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(experiment)
+
+	default:
+		log.Printf("Date: %s | Route: /api/admin/experiments | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// Experiment endpoint: GET /api/admin/experiments/{id} compares the variants of an experiment,
+// PUT with {"active": bool} starts or stops it (admin only)
+func (h *APIHandler) handleExperimentRoutes(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if !isAdminRequest(r) {
+		log.Printf("Date: %s | Route: /api/admin/experiments/{id} | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := primitive.ObjectIDFromHex(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/admin/experiments/"), "/"))
+	if err != nil {
+		http.Error(w, "Invalid experiment ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx := context.Background()
+
+	switch r.Method {
+	case "GET":
+		experiment, err := h.service.GetExperimentByID(ctx, id)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				http.Error(w, "Experiment not found", http.StatusNotFound)
+				return
+			}
+			log.Printf("Date: %s | Route: /api/admin/experiments/{id} | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		results, err := h.service.CompareExperimentVariants(ctx, id)
+		if err != nil {
+			log.Printf("Date: %s | Route: /api/admin/experiments/{id} | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Date: %s | Route: /api/admin/experiments/{id} | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"experiment": experiment,
+			"results":    results,
+		})
+
+	case "PUT":
+		var request struct {
+			Active *bool `json:"active"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Active == nil {
+			log.Printf("Date: %s | Route: /api/admin/experiments/{id} | Status: BAD_REQUEST | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, "Invalid JSON request (expected {\"active\": true|false})", http.StatusBadRequest)
+			return
+		}
+
+		if err := h.service.SetExperimentActive(ctx, id, *request.Active); err != nil {
+			if err == mongo.ErrNoDocuments {
+				http.Error(w, "Experiment not found", http.StatusNotFound)
+				return
+			}
+			log.Printf("Date: %s | Route: /api/admin/experiments/{id} | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Date: %s | Route: /api/admin/experiments/{id} | Status: UPDATED | GPT Model: %s", currentTime, gptModel)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		log.Printf("Date: %s | Route: /api/admin/experiments/{id} | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// ChatFeedbackRequest is the body of POST /api/chatbot/feedback
+type ChatFeedbackRequest struct {
+	TurnID    string `json:"turn_id"`
+	SessionID string `json:"session_id"`
+	Feedback  string `json:"feedback"` // up or down
+}
+
+// Chatbot feedback endpoint: POST /api/chatbot/feedback with {turn_id, session_id, feedback: up|down}
+func (h *APIHandler) handleChatbotFeedback(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if r.Method != "POST" {
+		log.Printf("Date: %s | Route: /api/chatbot/feedback | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	clientIP := trustedClientIP(r)
+	if !h.submitLimiter.IsAllowed(clientIP) {
+		log.Printf("Date: %s | Route: /api/chatbot/feedback | Status: RATE_LIMITED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Rate limit exceeded. Please wait before making another request.", http.StatusTooManyRequests)
+		return
+	}
+
+	var request ChatFeedbackRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		log.Printf("Date: %s | Route: /api/chatbot/feedback | Status: BAD_REQUEST | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Invalid JSON request", http.StatusBadRequest)
+		return
+	}
+	turnID, err := primitive.ObjectIDFromHex(request.TurnID)
+	if err != nil {
+		http.Error(w, "Invalid turn ID", http.StatusBadRequest)
+		return
+	}
+	var v Validator
+	v.OneOf("feedback", request.Feedback, "up", "down")
+	if err := v.Err(); err != nil {
+		writeInvalidInput(w, err)
+		return
+	}
+
+	if err := h.service.SetChatTurnFeedback(context.Background(), turnID, request.SessionID, request.Feedback); err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Chat turn not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("Date: %s | Route: /api/chatbot/feedback | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Date: %s | Route: /api/chatbot/feedback | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"encoding/xml"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Maximum number of entries in the feed
+const feedEntryLimit = 20
+
+// Atom feed structures (RFC 4287)
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Author  *atomPerson `xml:"author,omitempty"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri,omitempty"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomEntry struct {
+	Title      string         `xml:"title"`
+	ID         string         `xml:"id"`
+	Link       atomLink       `xml:"link"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Summary    string         `xml:"summary"`
+	Categories []atomCategory `xml:"category"`
+}
+
+// projectUpdatedAt is the most recent known change to a project: its creation or latest repository activity
+func projectUpdatedAt(project *Project) time.Time {
+	updated := project.ID.Timestamp()
+	if project.Repo != nil {
+		if project.Repo.LastCommitAt != nil && project.Repo.LastCommitAt.After(updated) {
+			updated = *project.Repo.LastCommitAt
+		}
+		if project.Repo.LastPushedAt != nil && project.Repo.LastPushedAt.After(updated) {
+			updated = *project.Repo.LastPushedAt
+		}
+	}
+	return updated
+}
+
+// publicBaseURL returns the public site URL from PORTFOLIO_URL, or derives it from the request
+func publicBaseURL(r *http.Request) string {
+	if base := os.Getenv("PORTFOLIO_URL"); base != "" {
+		return strings.TrimSuffix(base, "/")
+	}
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
+
+// buildProjectFeed builds an Atom feed of the most recently added or updated projects
+func buildProjectFeed(baseURL string, projects []Project, author *Author) *atomFeed {
+	sort.Slice(projects, func(i, j int) bool {
+		return projectUpdatedAt(&projects[i]).After(projectUpdatedAt(&projects[j]))
+	})
+	if len(projects) > feedEntryLimit {
+		projects = projects[:feedEntryLimit]
+	}
+
+	title := "Portfolio updates"
+	feed := &atomFeed{
+		ID: baseURL + "/feed.xml",
+		Links: []atomLink{
+			{Href: baseURL + "/feed.xml", Rel: "self", Type: "application/atom+xml"},
+			{Href: baseURL, Rel: "alternate", Type: "text/html"},
+		},
+		Entries: []atomEntry{},
+	}
+	if author != nil {
+		title = author.Name + " – portfolio updates"
+		feed.Author = &atomPerson{Name: author.Name, URI: author.GithubURL}
+	}
+	feed.Title = title
+
+	// An empty feed still needs a valid updated timestamp
+	feedUpdated := time.Unix(0, 0).UTC()
+	for i := range projects {
+		project := &projects[i]
+		slug := project.Slug
+		if slug == "" {
+			slug = slugify(project.Name)
+		}
+		link := baseURL + "/projects/" + slug
+		updated := projectUpdatedAt(project)
+		if updated.After(feedUpdated) {
+			feedUpdated = updated
+		}
+
+		entry := atomEntry{
+			Title:     project.Name,
+			ID:        link,
+			Link:      atomLink{Href: link, Rel: "alternate", Type: "text/html"},
+			Published: project.ID.Timestamp().UTC().Format(time.RFC3339),
+			Updated:   updated.UTC().Format(time.RFC3339),
+			Summary:   project.Description,
+		}
+		for _, technology := range project.TechnologiesUsed {
+			entry.Categories = append(entry.Categories, atomCategory{Term: technology})
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+	feed.Updated = feedUpdated.UTC().Format(time.RFC3339)
+
+	return feed
+}
+
+// Atom feed endpoint
+func (h *APIHandler) handleFeed(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	if r.Method != "GET" && r.Method != "HEAD" {
+		log.Printf("Date: %s | Route: /feed.xml | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx := context.Background()
+	// Domains mapped to an author get that author's feed; single-author portfolios get a named feed
+	author := h.hostAuthor(r)
+	projects, err := h.service.GetAllProjects(ctx, false, author)
+	if err != nil {
+		log.Printf("Date: %s | Route: /feed.xml | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if author == nil {
+		if authors, err := h.service.GetAllAuthors(ctx); err == nil && len(authors) == 1 {
+			author = &authors[0]
+		}
+	}
+
+	output, err := xml.MarshalIndent(buildProjectFeed(publicBaseURL(r), projects, author), "", "  ")
+	if err != nil {
+		log.Printf("Date: %s | Route: /feed.xml | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Date: %s | Route: /feed.xml | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+	w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+	w.Header().Set("Cache-Control", "public, max-age=900")
+	w.Write([]byte(xml.Header))
+	w.Write(output)
+}
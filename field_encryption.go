@@ -0,0 +1,212 @@
+package main
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// encryptedPrefix marks a stored value as ciphertext, so documents written before encryption was enabled
+// can still be read
+const encryptedPrefix = "enc:v1:"
+
+// fieldCipherKeys holds the keys derived from FIELD_ENCRYPTION_KEY. The AEAD is nil when encryption is disabled.
+type fieldCipherKeys struct {
+	aead     cipher.AEAD
+	nonceKey []byte
+}
+
+// fieldCipher loads FIELD_ENCRYPTION_KEY once: 32 bytes, base64 or hex encoded
+var fieldCipher = sync.OnceValues(func() (*fieldCipherKeys, error) {
+	value := strings.TrimSpace(os.Getenv("FIELD_ENCRYPTION_KEY"))
+	if value == "" {
+		log.Println("FIELD_ENCRYPTION_KEY not set, contact details are stored unencrypted")
+		return &fieldCipherKeys{}, nil
+	}
+
+	key, err := base64.StdEncoding.DecodeString(value)
+	if err != nil || len(key) != 32 {
+		key, err = hex.DecodeString(value)
+	}
+	if err != nil || len(key) != 32 {
+		return nil, fmt.Errorf("FIELD_ENCRYPTION_KEY must be 32 bytes, base64 or hex encoded")
+	}
+
+	// Separate keys for encryption and for deriving nonces
+	derive := func(label string) []byte {
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(label))
+		return mac.Sum(nil)
+	}
+	block, err := aes.NewCipher(derive("portfolio field encryption"))
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &fieldCipherKeys{aead: aead, nonceKey: derive("portfolio field nonce")}, nil
+})
+
+// EncryptedString is a string field stored AES-GCM encrypted in MongoDB and plain in memory. Encryption is
+// deterministic (the nonce is derived from the value) so equality lookups such as GetAuthorByEmail still
+// work, at the cost of revealing which documents share a value.
+type EncryptedString string
+
+// MarshalBSONValue encrypts the value when FIELD_ENCRYPTION_KEY is set
+func (s EncryptedString) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	keys, err := fieldCipher()
+	if err != nil {
+		return 0, nil, err
+	}
+	if keys.aead == nil || s == "" {
+		return bson.MarshalValue(string(s))
+	}
+
+	mac := hmac.New(sha256.New, keys.nonceKey)
+	mac.Write([]byte(s))
+	nonce := mac.Sum(nil)[:keys.aead.NonceSize()]
+	sealed := keys.aead.Seal(nonce, nonce, []byte(s), nil)
+	return bson.MarshalValue(encryptedPrefix + base64.RawStdEncoding.EncodeToString(sealed))
+}
+
+// UnmarshalBSONValue decrypts ciphertext and passes unencrypted values through
+func (s *EncryptedString) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	if t == bsontype.Null || t == bsontype.Undefined {
+		*s = ""
+		return nil
+	}
+	value, ok := bson.RawValue{Type: t, Value: data}.StringValueOK()
+	if !ok {
+		return fmt.Errorf("cannot decode %s into an encrypted string", t)
+	}
+	if !strings.HasPrefix(value, encryptedPrefix) {
+		*s = EncryptedString(value)
+		return nil
+	}
+
+	keys, err := fieldCipher()
+	if err != nil {
+		return err
+	}
+	if keys.aead == nil {
+		return fmt.Errorf("field is encrypted but FIELD_ENCRYPTION_KEY is not set")
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(strings.TrimPrefix(value, encryptedPrefix))
+	if err != nil || len(sealed) < keys.aead.NonceSize() {
+		return fmt.Errorf("malformed encrypted field")
+	}
+	nonceSize := keys.aead.NonceSize()
+	plain, err := keys.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], nil)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt field: wrong FIELD_ENCRYPTION_KEY?")
+	}
+	*s = EncryptedString(plain)
+	return nil
+}
+
+// EncryptContactFields rewrites contact details stored before FIELD_ENCRYPTION_KEY was set. Returns the number
+// of documents updated.
+func (ps *PortfolioService) EncryptContactFields(ctx context.Context) (int64, error) {
+	if keys, err := fieldCipher(); err != nil || keys.aead == nil {
+		return 0, err
+	}
+
+	plain := func(field string) bson.M {
+		return bson.M{field: bson.M{"$type": "string", "$ne": "", "$not": primitive.Regex{Pattern: "^" + encryptedPrefix}}}
+	}
+
+	var updated int64
+	var authors []Author
+	cursor, err := ps.authors.Find(ctx, plain("email"), timedFind())
+	if err != nil {
+		return updated, err
+	}
+	if err = cursor.All(ctx, &authors); err != nil {
+		return updated, err
+	}
+	for _, author := range authors {
+		if _, err := ps.authors.UpdateOne(ctx, bson.M{"_id": author.ID}, bson.M{"$set": bson.M{"email": author.Email}}); err != nil {
+			return updated, err
+		}
+		updated++
+	}
+
+	var resumes []Resume
+	cursor, err = ps.resumes.Find(ctx, bson.M{"$or": []bson.M{plain("contact.email"), plain("contact.phone")}}, timedFind())
+	if err != nil {
+		return updated, err
+	}
+	if err = cursor.All(ctx, &resumes); err != nil {
+		return updated, err
+	}
+	for _, resume := range resumes {
+		if _, err := ps.resumes.UpdateOne(ctx, bson.M{"_id": resume.ID}, bson.M{"$set": bson.M{"contact": resume.Contact}}); err != nil {
+			return updated, err
+		}
+		updated++
+	}
+	return updated, nil
+}
+
+// maskEmail keeps the first character and the domain, e.g. b***@example.com
+func maskEmail(email EncryptedString) EncryptedString {
+	local, domain, found := strings.Cut(string(email), "@")
+	if !found || local == "" {
+		return ""
+	}
+	return EncryptedString(string([]rune(local)[:1]) + "***@" + domain)
+}
+
+// maskPhone keeps the last two digits, e.g. ***12
+func maskPhone(phone EncryptedString) EncryptedString {
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, string(phone))
+	if len(digits) < 2 {
+		return ""
+	}
+	return EncryptedString("***" + digits[len(digits)-2:])
+}
+
+// redactContact masks the author's email for responses to unauthenticated clients
+func (a *Author) redactContact() {
+	if !a.publishes("email") {
+		a.Email = maskEmail(a.Email)
+	}
+}
+
+// publishes reports whether the author made a contact detail public
+func (a *Author) publishes(field string) bool {
+	return slices.Contains(a.PublicContact, field)
+}
+
+// redactContact masks the resume's contact details for responses to unauthenticated clients
+func (r *Resume) redactContact() {
+	r.Contact.Email = maskEmail(r.Contact.Email)
+	r.Contact.Phone = maskPhone(r.Contact.Phone)
+}
+
+func redactResumes(resumes []Resume) {
+	for i := range resumes {
+		resumes[i].redactContact()
+	}
+}
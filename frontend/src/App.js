@@ -13,6 +13,10 @@ const App = () => {
   const [chatbotRequests, setChatbotRequests] = useState([]);
   const [isRateLimited, setIsRateLimited] = useState(false);
 
+  // Chat session, kept across questions so answers can be rated
+  const chatSessionRef = useRef(null);
+  const lastTurnRef = useRef(null);
+
 const welcomeMessage = (
   <div>
     <pre style={{margin: 0}} className="ascii-title">
@@ -41,7 +45,18 @@ const welcomeMessage = (
   </div>
 );
 
+  // Report an anonymous analytics event; failures are ignored so tracking never gets in the way
+  const trackEvent = (type, extra = {}) => {
+    const body = JSON.stringify({ type, path: window.location.pathname, referrer: document.referrer, ...extra });
+    if (navigator.sendBeacon) {
+      navigator.sendBeacon('/api/analytics/event', body);
+    } else {
+      fetch('/api/analytics/event', { method: 'POST', body, keepalive: true }).catch(() => {});
+    }
+  };
+
   useEffect(() => {
+    trackEvent('page_view');
     setHistory([{ type: 'output', content: welcomeMessage, isComponent: true }]);
     if (inputRef.current) {
       inputRef.current.focus();
@@ -129,6 +144,9 @@ const welcomeMessage = (
           '  help                          - Show this help message\n' +
           '  chatbot <question>            - Ask BILLIEBOT a question about the portfolio\n' +
           '                                  (Limited: 3 requests/minute, 10 requests/5min)\n' +
+          '  now                           - Show what Billie is working on right now\n' +
+          '  book                          - Show open slots and a link to schedule a call\n' +
+          '  rate <up|down>                - Rate BILLIEBOT\'s last answer\n' +
           '  clear                         - Clear the terminal\n' +
           '  list <collection>             - List all documents in collection\n' +
           '  find <collection> <field> <value> - Find documents matching criteria\n' +
@@ -144,6 +162,22 @@ const welcomeMessage = (
           '  count projects';
         break;
 
+      case 'now':
+        response = await handleNowCommand();
+        break;
+
+      case 'rate':
+        if (args[0] !== 'up' && args[0] !== 'down') {
+          response = 'Usage: rate <up|down>';
+        } else {
+          response = await handleRateCommand(args[0]);
+        }
+        break;
+
+      case 'book':
+        response = await handleBookCommand();
+        break;
+
       case 'clear':
         setHistory([{ type: 'output', content: welcomeMessage, isComponent: true }]);
         return;
@@ -280,7 +314,78 @@ const welcomeMessage = (
     }
   };
 
+  const handleNowCommand = async () => {
+    try {
+      const response = await fetch('/api/now');
+      if (response.status === 404) {
+        return 'Nothing posted yet. Check back soon!';
+      }
+      if (!response.ok) {
+        throw new Error(`HTTP error! status: ${response.status}`);
+      }
+      const data = await response.json();
+
+      const lines = [`Now (updated ${new Date(data.updated_at).toLocaleDateString()}):`];
+      if (data.focus) lines.push(`  Focus:        ${data.focus}`);
+      if (data.learning.length > 0) lines.push(`  Learning:     ${data.learning.join(', ')}`);
+      if (data.reading.length > 0) lines.push(`  Reading:      ${data.reading.join(', ')}`);
+      lines.push(`  Availability: ${data.availability}${data.availability_note ? ` - ${data.availability_note}` : ''}`);
+      return lines.join('\n');
+    } catch (error) {
+      return `Error: ${error.message}`;
+    }
+  };
+
+  const handleBookCommand = async () => {
+    try {
+      const response = await fetch('/api/availability');
+      if (!response.ok) {
+        throw new Error(`HTTP error! status: ${response.status}`);
+      }
+      const data = await response.json();
+
+      const lines = [`Availability: ${data.status}${data.note ? ` - ${data.note}` : ''}`];
+      if (data.status === 'unavailable') {
+        return lines.join('\n');
+      }
+      if (data.slots.length > 0) {
+        lines.push(`Next open slots (${data.timezone}):`);
+        data.slots.slice(0, 5).forEach(slot => {
+          lines.push(`  ${new Date(slot.start).toLocaleString()}`);
+        });
+      }
+      if (data.booking_url) {
+        lines.push(`Schedule a call: ${window.location.origin}${data.booking_url}`);
+      }
+      return lines.join('\n');
+    } catch (error) {
+      return `Error: ${error.message}`;
+    }
+  };
+
+  const handleRateCommand = async (feedback) => {
+    if (!lastTurnRef.current) {
+      return 'Ask BILLIEBOT a question first, then rate the answer.';
+    }
+    try {
+      const response = await fetch('/api/chatbot/feedback', {
+        method: 'POST',
+        headers: {
+          'Content-Type': 'application/json'
+        },
+        body: JSON.stringify({ turn_id: lastTurnRef.current, session_id: chatSessionRef.current, feedback })
+      });
+      if (!response.ok) {
+        throw new Error(`HTTP error! status: ${response.status}`);
+      }
+      return 'Thanks for the feedback!';
+    } catch (error) {
+      return `Error: ${error.message}`;
+    }
+  };
+
   const handleChatbotCommand = async (question) => {
+    trackEvent('chat_open');
     try {
       setHistory(prev => [...prev, { type: 'output', content: '🤖 BILLIEBOT is thinking...', thinking: true }]);
       
@@ -289,7 +394,7 @@ const welcomeMessage = (
         headers: {
           'Content-Type': 'application/json'
         },
-        body: JSON.stringify({ query: question })
+        body: JSON.stringify({ query: question, session_id: chatSessionRef.current })
       });
       
       if (!response.ok) {
@@ -297,6 +402,8 @@ const welcomeMessage = (
       }
       
       const data = await response.json();
+      if (data.session_id) chatSessionRef.current = data.session_id;
+      if (data.turn_id) lastTurnRef.current = data.turn_id;
       
       // Remove the "thinking" message
       setHistory(prev => {
@@ -308,7 +415,7 @@ const welcomeMessage = (
         return prev.filter((_, i) => i !== removeIndex);
       });
       
-      return `🤖 BILLIEBOT:\n${data.response}`;
+      return `🤖 BILLIEBOT:\n${data.response}${data.turn_id ? "\n\n(Was this helpful? Type 'rate up' or 'rate down')" : ''}`;
     } catch (error) {
       // Remove the "thinking" message on error too
       setHistory(prev => {
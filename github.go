@@ -0,0 +1,329 @@
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Maximum webhook body we are willing to read
+const maxWebhookBodySize = 5 << 20
+
+// GitHub webhook payload (only the fields we use)
+type githubWebhookPayload struct {
+	Ref        string `json:"ref"`
+	Action     string `json:"action"`
+	Repository struct {
+		FullName      string   `json:"full_name"`
+		HTMLURL       string   `json:"html_url"`
+		Description   *string  `json:"description"`
+		Language      *string  `json:"language"`
+		Topics        []string `json:"topics"`
+		DefaultBranch string   `json:"default_branch"`
+	} `json:"repository"`
+	HeadCommit *struct {
+		Timestamp time.Time `json:"timestamp"`
+	} `json:"head_commit"`
+	Release *struct {
+		TagName string `json:"tag_name"`
+		Name    string `json:"name"`
+		Draft   bool   `json:"draft"`
+	} `json:"release"`
+}
+
+// verifyGitHubSignature checks the X-Hub-Signature-256 header against the body
+func verifyGitHubSignature(secret string, body []byte, signature string) bool {
+	if !strings.HasPrefix(signature, "sha256=") {
+		return false
+	}
+	expected, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+// repoURLFilter matches stored repo URLs regardless of scheme, www prefix, .git suffix or trailing slash
+func repoURLFilter(repoURL string) bson.M {
+	normalized := strings.ToLower(strings.TrimSpace(repoURL))
+	normalized = strings.TrimPrefix(normalized, "https://")
+	normalized = strings.TrimPrefix(normalized, "http://")
+	normalized = strings.TrimPrefix(normalized, "www.")
+	normalized = strings.TrimSuffix(normalized, "/")
+	normalized = strings.TrimSuffix(normalized, ".git")
+
+	pattern := fmt.Sprintf(`^(https?://)?(www\.)?%s(\.git)?/?$`, regexp.QuoteMeta(normalized))
+	return bson.M{"repo_url": bson.M{"$regex": pattern, "$options": "i"}}
+}
+
+// curatedChangeFilter matches the projects an update would change curated fields of, that is fields outside
+// the repo metadata; nil when it changes none
+func curatedChangeFilter(update bson.M) bson.M {
+	var changes []bson.M
+	if set, ok := update["$set"].(bson.M); ok {
+		for field, value := range set {
+			if field != "repo" && !strings.HasPrefix(field, "repo.") {
+				changes = append(changes, bson.M{field: bson.M{"$ne": value}})
+			}
+		}
+	}
+	if added, ok := update["$addToSet"].(bson.M); ok {
+		for field, value := range added {
+			var values interface{} = []interface{}{value}
+			if each, ok := value.(bson.M); ok {
+				values = each["$each"]
+			}
+			changes = append(changes, bson.M{field: bson.M{"$not": bson.M{"$all": values}}})
+		}
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	return bson.M{"$or": changes}
+}
+
+// UpdateProjectsByRepo applies an update to every project linked to the given repository URL. Projects are
+// only stamped as updated when curated fields change, so syncing repo metadata does not list them as new.
+func (ps *PortfolioService) UpdateProjectsByRepo(ctx context.Context, repoURL string, update bson.M) (int64, error) {
+	filter := repoURLFilter(repoURL)
+	if changed := curatedChangeFilter(update); changed != nil {
+		// Stamped first, since once the update is applied the changed projects can no longer be told apart
+		if _, err := ps.projects.UpdateMany(ctx, bson.M{"$and": []bson.M{filter, changed}}, withUpdatedAt(bson.M{})); err != nil {
+			return 0, err
+		}
+	}
+	result, err := ps.projects.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, err
+	}
+	return result.MatchedCount, nil
+}
+
+// buildGitHubWebhookUpdate turns a push or release payload into a project update
+func buildGitHubWebhookUpdate(event string, payload *githubWebhookPayload) (bson.M, bool) {
+	repo := payload.Repository
+	set := bson.M{
+		"repo.provider":       "GitHub",
+		"repo.full_name":      repo.FullName,
+		"repo.default_branch": repo.DefaultBranch,
+		"repo.synced_at":      time.Now(),
+	}
+
+	switch event {
+	case "push":
+		// Only pushes to the default branch reflect the published state of the project
+		if payload.Ref != "refs/heads/"+repo.DefaultBranch {
+			return nil, false
+		}
+		if payload.HeadCommit != nil {
+			set["repo.last_pushed_at"] = payload.HeadCommit.Timestamp
+		}
+	case "release":
+		if payload.Action != "published" || payload.Release == nil || payload.Release.Draft {
+			return nil, false
+		}
+		set["repo.latest_release"] = payload.Release.TagName
+	default:
+		return nil, false
+	}
+
+	update := bson.M{"$set": set}
+
+	// The reading stats follow the description, which only long descriptions get a reading time from
+	if repo.Description != nil && *repo.Description != "" {
+		stats := readingStatsOf(*repo.Description, longDescriptionWords)
+		set["description"] = *repo.Description
+		set["word_count"] = stats.WordCount
+		if stats.ReadingMinutes > 0 {
+			set["reading_minutes"] = stats.ReadingMinutes
+		} else {
+			update["$unset"] = bson.M{"reading_minutes": ""}
+		}
+	}
+
+	// Merge the repository language and topics into the curated technology list
+	var technologies []string
+	if repo.Language != nil && *repo.Language != "" {
+		technologies = append(technologies, *repo.Language)
+	}
+	technologies = append(technologies, repo.Topics...)
+	if len(technologies) > 0 {
+		update["$addToSet"] = bson.M{"technologies_used": bson.M{"$each": technologies}}
+	}
+
+	return update, true
+}
+
+// GitHub webhook endpoint
+func (h *APIHandler) handleGitHubWebhook(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	if r.Method != "POST" {
+		log.Printf("Date: %s | Route: /api/webhooks/github | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	secret := os.Getenv("GITHUB_WEBHOOK_SECRET")
+	if secret == "" {
+		log.Printf("Date: %s | Route: /api/webhooks/github | Status: DISABLED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "GitHub webhook is not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBodySize))
+	if err != nil {
+		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+		return
+	}
+
+	if !verifyGitHubSignature(secret, body, r.Header.Get("X-Hub-Signature-256")) {
+		log.Printf("Date: %s | Route: /api/webhooks/github | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	event := r.Header.Get("X-GitHub-Event")
+	if event == "ping" {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"status": "pong"})
+		return
+	}
+
+	var payload githubWebhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		log.Printf("Date: %s | Route: /api/webhooks/github | Status: BAD_REQUEST | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Invalid JSON payload", http.StatusBadRequest)
+		return
+	}
+
+	update, ok := buildGitHubWebhookUpdate(event, &payload)
+	if !ok || payload.Repository.HTMLURL == "" {
+		log.Printf("Date: %s | Route: /api/webhooks/github | Status: IGNORED | GPT Model: %s", currentTime, gptModel)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(map[string]string{"status": "ignored", "event": event})
+		return
+	}
+
+	ctx := context.Background()
+	matched, err := h.service.UpdateProjectsByRepo(ctx, payload.Repository.HTMLURL, update)
+	if err != nil {
+		log.Printf("Date: %s | Route: /api/webhooks/github | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Date: %s | Route: /api/webhooks/github | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+	log.Printf("GitHub %s event for %s updated %d project(s)", event, payload.Repository.FullName, matched)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":  "updated",
+		"event":   event,
+		"matched": matched,
+	})
+}
+
+// GitHub REST API repository representation (only the fields we use)
+type githubRepo struct {
+	Name            string    `json:"name"`
+	FullName        string    `json:"full_name"`
+	HTMLURL         string    `json:"html_url"`
+	Description     *string   `json:"description"`
+	Language        *string   `json:"language"`
+	Topics          []string  `json:"topics"`
+	StargazersCount int       `json:"stargazers_count"`
+	DefaultBranch   string    `json:"default_branch"`
+	Fork            bool      `json:"fork"`
+	Archived        bool      `json:"archived"`
+	CreatedAt       time.Time `json:"created_at"`
+	PushedAt        time.Time `json:"pushed_at"`
+}
+
+// GitHubClient is a minimal client for the GitHub REST API
+type GitHubClient struct {
+	httpClient *http.Client
+	baseURL    string
+	token      string
+}
+
+// NewGitHubClient creates a GitHub API client; the token is optional but raises rate limits
+func NewGitHubClient(token string) *GitHubClient {
+	return &GitHubClient{
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		baseURL:    "https://api.github.com",
+		token:      token,
+	}
+}
+
+// get performs an authenticated GET request and decodes the JSON response into out
+func (c *GitHubClient) get(ctx context.Context, path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GitHub API %s returned status %d", path, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// ListUserRepos returns all public repositories owned by the given user
+func (c *GitHubClient) ListUserRepos(ctx context.Context, username string) ([]githubRepo, error) {
+	var repos []githubRepo
+	for page := 1; ; page++ {
+		var batch []githubRepo
+		path := fmt.Sprintf("/users/%s/repos?type=owner&per_page=100&page=%d", url.PathEscape(username), page)
+		if err := c.get(ctx, path, &batch); err != nil {
+			return nil, err
+		}
+		repos = append(repos, batch...)
+		if len(batch) < 100 {
+			break
+		}
+	}
+	return repos, nil
+}
+
+func (ps *PortfolioService) GetAuthorByGithubUsername(ctx context.Context, username string) (*Author, error) {
+	var author Author
+	pattern := fmt.Sprintf(`github\.com/%s/?$`, regexp.QuoteMeta(username))
+	filter := bson.M{"github_url": bson.M{"$regex": pattern, "$options": "i"}}
+	err := ps.authors.FindOne(ctx, filter).Decode(&author)
+	if err != nil {
+		return nil, err
+	}
+	return &author, nil
+}
@@ -0,0 +1,289 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"time"
+)
+
+// GitHubActivity summarizes public GitHub activity for the frontend
+type GitHubActivity struct {
+	Username      string                `json:"username"`
+	Contributions GitHubContributions   `json:"contributions"`
+	Languages     []GitHubLanguageShare `json:"languages"`
+	RecentRepos   []GitHubRecentRepo    `json:"recent_repos"`
+	FetchedAt     time.Time             `json:"fetched_at"`
+}
+
+type GitHubContributions struct {
+	Total        int `json:"total"`
+	Commits      int `json:"commits"`
+	PullRequests int `json:"pull_requests"`
+	Issues       int `json:"issues"`
+	Reviews      int `json:"reviews"`
+	Private      int `json:"private"`
+}
+
+type GitHubLanguageShare struct {
+	Name    string  `json:"name"`
+	Bytes   int64   `json:"bytes"`
+	Percent float64 `json:"percent"`
+}
+
+type GitHubRecentRepo struct {
+	Name     string    `json:"name"`
+	URL      string    `json:"url"`
+	PushedAt time.Time `json:"pushed_at"`
+}
+
+const githubActivityQuery = `query($login: String!) {
+  user(login: $login) {
+    contributionsCollection {
+      totalCommitContributions
+      totalPullRequestContributions
+      totalIssueContributions
+      totalPullRequestReviewContributions
+      restrictedContributionsCount
+      contributionCalendar { totalContributions }
+    }
+    repositories(first: 100, ownerAffiliations: OWNER, isFork: false, privacy: PUBLIC, orderBy: {field: PUSHED_AT, direction: DESC}) {
+      nodes {
+        name
+        url
+        pushedAt
+        languages(first: 10, orderBy: {field: SIZE, direction: DESC}) {
+          edges { size node { name } }
+        }
+      }
+    }
+  }
+}`
+
+type githubActivityResponse struct {
+	Data struct {
+		User *struct {
+			ContributionsCollection struct {
+				TotalCommitContributions            int `json:"totalCommitContributions"`
+				TotalPullRequestContributions       int `json:"totalPullRequestContributions"`
+				TotalIssueContributions             int `json:"totalIssueContributions"`
+				TotalPullRequestReviewContributions int `json:"totalPullRequestReviewContributions"`
+				RestrictedContributionsCount        int `json:"restrictedContributionsCount"`
+				ContributionCalendar                struct {
+					TotalContributions int `json:"totalContributions"`
+				} `json:"contributionCalendar"`
+			} `json:"contributionsCollection"`
+			Repositories struct {
+				Nodes []struct {
+					Name      string    `json:"name"`
+					URL       string    `json:"url"`
+					PushedAt  time.Time `json:"pushedAt"`
+					Languages struct {
+						Edges []struct {
+							Size int64 `json:"size"`
+							Node struct {
+								Name string `json:"name"`
+							} `json:"node"`
+						} `json:"edges"`
+					} `json:"languages"`
+				} `json:"nodes"`
+			} `json:"repositories"`
+		} `json:"user"`
+	} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// graphql sends a GraphQL query to the GitHub API; a token is required
+func (c *GitHubClient) graphql(ctx context.Context, query string, variables map[string]interface{}, out interface{}) error {
+	if c.token == "" {
+		return fmt.Errorf("GitHub GraphQL API requires a token")
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"query":     query,
+		"variables": variables,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/graphql", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GitHub GraphQL API returned status %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// FetchActivity retrieves contribution stats, language breakdown and recent repositories
+func (c *GitHubClient) FetchActivity(ctx context.Context, username string) (*GitHubActivity, error) {
+	var resp githubActivityResponse
+	if err := c.graphql(ctx, githubActivityQuery, map[string]interface{}{"login": username}, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Errors) > 0 {
+		return nil, fmt.Errorf("GitHub GraphQL error: %s", resp.Errors[0].Message)
+	}
+	if resp.Data.User == nil {
+		return nil, fmt.Errorf("GitHub user %s not found", username)
+	}
+
+	user := resp.Data.User
+	contributions := user.ContributionsCollection
+	activity := &GitHubActivity{
+		Username: username,
+		Contributions: GitHubContributions{
+			Total:        contributions.ContributionCalendar.TotalContributions,
+			Commits:      contributions.TotalCommitContributions,
+			PullRequests: contributions.TotalPullRequestContributions,
+			Issues:       contributions.TotalIssueContributions,
+			Reviews:      contributions.TotalPullRequestReviewContributions,
+			Private:      contributions.RestrictedContributionsCount,
+		},
+		Languages:   []GitHubLanguageShare{},
+		RecentRepos: []GitHubRecentRepo{},
+		FetchedAt:   time.Now(),
+	}
+
+	// Aggregate language sizes across all repositories
+	languageBytes := make(map[string]int64)
+	var totalBytes int64
+	for _, repo := range user.Repositories.Nodes {
+		for _, edge := range repo.Languages.Edges {
+			languageBytes[edge.Node.Name] += edge.Size
+			totalBytes += edge.Size
+		}
+	}
+	for name, size := range languageBytes {
+		activity.Languages = append(activity.Languages, GitHubLanguageShare{
+			Name:    name,
+			Bytes:   size,
+			Percent: float64(size) * 100 / float64(totalBytes),
+		})
+	}
+	sort.Slice(activity.Languages, func(i, j int) bool {
+		return activity.Languages[i].Bytes > activity.Languages[j].Bytes
+	})
+
+	// Repositories are already ordered by most recent push
+	for _, repo := range user.Repositories.Nodes[:min(5, len(user.Repositories.Nodes))] {
+		activity.RecentRepos = append(activity.RecentRepos, GitHubRecentRepo{
+			Name:     repo.Name,
+			URL:      repo.URL,
+			PushedAt: repo.PushedAt,
+		})
+	}
+
+	return activity, nil
+}
+
+// GitHubActivityService serves cached GitHub activity so the browser never needs a token
+type GitHubActivityService struct {
+	client   *GitHubClient
+	username string
+	cache    *TTLCache[*GitHubActivity]
+	ttl      time.Duration
+}
+
+// NewGitHubActivityService creates the service from environment configuration, or nil if disabled
+func NewGitHubActivityService(store StateStore) *GitHubActivityService {
+	username := os.Getenv("GITHUB_USERNAME")
+	token := os.Getenv("GITHUB_TOKEN")
+	if username == "" || token == "" {
+		log.Println("GITHUB_USERNAME or GITHUB_TOKEN not set, GitHub activity endpoint disabled")
+		return nil
+	}
+
+	ttl := time.Hour
+	if value := os.Getenv("GITHUB_ACTIVITY_CACHE_TTL"); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil && parsed > 0 {
+			ttl = parsed
+		}
+	}
+
+	return &GitHubActivityService{
+		client:   NewGitHubClient(token),
+		username: username,
+		cache:    NewTTLCache[*GitHubActivity](store, "github_activity"),
+		ttl:      ttl,
+	}
+}
+
+// GetActivity returns cached activity, refreshing it from GitHub when expired
+func (s *GitHubActivityService) GetActivity(ctx context.Context) (*GitHubActivity, error) {
+	if cached, ok := s.cache.Get("activity"); ok {
+		return cached, nil
+	}
+
+	activity, err := s.client.FetchActivity(ctx, s.username)
+	if err != nil {
+		// Serve stale data rather than failing if GitHub is unavailable
+		if stale, ok := s.cache.GetStale("activity"); ok {
+			log.Printf("GitHub activity refresh failed, serving stale data: %v", err)
+			return stale, nil
+		}
+		return nil, err
+	}
+
+	s.cache.Set("activity", activity, s.ttl)
+	return activity, nil
+}
+
+// GitHub activity endpoint
+func (h *APIHandler) handleGitHubActivity(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if r.Method != "GET" {
+		log.Printf("Date: %s | Route: /api/github/activity | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if h.githubActivity == nil {
+		log.Printf("Date: %s | Route: /api/github/activity | Status: DISABLED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "GitHub activity is not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	activity, err := h.githubActivity.GetActivity(ctx)
+	if err != nil {
+		log.Printf("Date: %s | Route: /api/github/activity | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		log.Printf("Error fetching GitHub activity: %v", err)
+		http.Error(w, "Failed to fetch GitHub activity", http.StatusBadGateway)
+		return
+	}
+
+	log.Printf("Date: %s | Route: /api/github/activity | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(activity)
+}
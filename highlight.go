@@ -0,0 +1,224 @@
+package main
+
+import (
+	"html"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/util"
+)
+
+// Token classes of highlighted code; pages style them, and API clients can too
+const (
+	highlightKeyword = "hl-k"
+	highlightString  = "hl-s"
+	highlightComment = "hl-c"
+	highlightNumber  = "hl-n"
+)
+
+// highlightLanguage is the lexical syntax the highlighter knows of a language: enough to tell keywords,
+// strings, comments and numbers apart, which is all the styling distinguishes
+type highlightLanguage struct {
+	keywords     map[string]bool
+	lineComments []string
+	blockComment [2]string // Start and end; empty when the language has none
+	quotes       string    // Characters that open a string closed by the same character
+}
+
+func keywordSet(words string) map[string]bool {
+	set := make(map[string]bool)
+	for _, word := range strings.Fields(words) {
+		set[word] = true
+	}
+	return set
+}
+
+var (
+	cLikeKeywords = "break case continue default do else for goto if return switch while const static struct enum union typedef sizeof void int char float double long short unsigned signed"
+	jsKeywords    = "async await break case catch class const continue default delete do else export extends false finally for from function if import in instanceof let new null of return static super switch this throw true try typeof undefined var void while yield"
+
+	highlightLanguages = map[string]*highlightLanguage{
+		"go": {
+			keywords:     keywordSet("break case chan const continue default defer else fallthrough for func go goto if import interface map package range return select struct switch type var nil true false iota"),
+			lineComments: []string{"//"}, blockComment: [2]string{"/*", "*/"}, quotes: "\"'`",
+		},
+		"javascript": {
+			keywords:     keywordSet(jsKeywords),
+			lineComments: []string{"//"}, blockComment: [2]string{"/*", "*/"}, quotes: "\"'`",
+		},
+		"typescript": {
+			keywords:     keywordSet(jsKeywords + " interface type enum implements private protected public readonly as any unknown never number string boolean"),
+			lineComments: []string{"//"}, blockComment: [2]string{"/*", "*/"}, quotes: "\"'`",
+		},
+		"python": {
+			keywords:     keywordSet("and as assert async await break class continue def del elif else except False finally for from global if import in is lambda None nonlocal not or pass raise return True try while with yield"),
+			lineComments: []string{"#"}, quotes: "\"'",
+		},
+		"bash": {
+			keywords:     keywordSet("if then else elif fi case esac for while until do done in function return export local echo set unset"),
+			lineComments: []string{"#"}, quotes: "\"'",
+		},
+		"sql": {
+			keywords:     keywordSet("SELECT FROM WHERE AND OR NOT INSERT INTO VALUES UPDATE SET DELETE CREATE TABLE INDEX DROP ALTER JOIN LEFT RIGHT INNER OUTER ON GROUP BY ORDER HAVING LIMIT AS NULL IS IN PRIMARY KEY select from where and or not insert into values update set delete create table index drop alter join left right inner outer on group by order having limit as null is in primary key"),
+			lineComments: []string{"--"}, blockComment: [2]string{"/*", "*/"}, quotes: "'\"",
+		},
+		"rust": {
+			keywords:     keywordSet("as async await break const continue crate else enum extern false fn for if impl in let loop match mod move mut pub ref return self Self static struct super trait true type unsafe use where while"),
+			lineComments: []string{"//"}, blockComment: [2]string{"/*", "*/"}, quotes: "\"",
+		},
+		"java": {
+			keywords:     keywordSet("abstract boolean break byte case catch char class const continue default do double else enum extends final finally float for if implements import instanceof int interface long new null package private protected public return short static super switch this throw throws true false try void while var"),
+			lineComments: []string{"//"}, blockComment: [2]string{"/*", "*/"}, quotes: "\"'",
+		},
+		"c": {
+			keywords:     keywordSet(cLikeKeywords + " include define"),
+			lineComments: []string{"//"}, blockComment: [2]string{"/*", "*/"}, quotes: "\"'",
+		},
+		"cpp": {
+			keywords:     keywordSet(cLikeKeywords + " include define class namespace template typename public private protected virtual new delete this true false nullptr auto using"),
+			lineComments: []string{"//"}, blockComment: [2]string{"/*", "*/"}, quotes: "\"'",
+		},
+		"json": {
+			keywords: keywordSet("true false null"),
+			quotes:   "\"",
+		},
+		"yaml": {
+			keywords:     keywordSet("true false null yes no"),
+			lineComments: []string{"#"}, quotes: "\"'",
+		},
+	}
+
+	// highlightAliases map the other names fenced code blocks use to a language
+	highlightAliases = map[string]string{
+		"golang": "go", "js": "javascript", "jsx": "javascript", "ts": "typescript", "tsx": "typescript",
+		"py": "python", "sh": "bash", "shell": "bash", "zsh": "bash", "rs": "rust", "h": "c", "c++": "cpp",
+		"cc": "cpp", "hpp": "cpp", "yml": "yaml", "postgresql": "sql", "mysql": "sql",
+	}
+)
+
+// highlightCode returns code as escaped HTML, with the tokens of a known language wrapped in spans of the
+// highlight classes; code of other languages is only escaped
+func highlightCode(language, code string) string {
+	language = strings.ToLower(language)
+	if alias, ok := highlightAliases[language]; ok {
+		language = alias
+	}
+	lang, ok := highlightLanguages[language]
+	if !ok {
+		return html.EscapeString(code)
+	}
+
+	var out strings.Builder
+	span := func(class, text string) {
+		out.WriteString(`<span class="` + class + `">`)
+		out.WriteString(html.EscapeString(text))
+		out.WriteString(`</span>`)
+	}
+	isWord := func(b byte) bool {
+		return b == '_' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= '0' && b <= '9'
+	}
+
+	for i := 0; i < len(code); {
+		rest := code[i:]
+		if start := lang.blockComment[0]; start != "" && strings.HasPrefix(rest, start) {
+			end := strings.Index(rest[len(start):], lang.blockComment[1])
+			if end < 0 {
+				end = len(rest)
+			} else {
+				end += len(start) + len(lang.blockComment[1])
+			}
+			span(highlightComment, rest[:end])
+			i += end
+			continue
+		}
+		if lineComment := func() bool {
+			for _, prefix := range lang.lineComments {
+				if strings.HasPrefix(rest, prefix) {
+					return true
+				}
+			}
+			return false
+		}(); lineComment {
+			end := strings.IndexByte(rest, '\n')
+			if end < 0 {
+				end = len(rest)
+			}
+			span(highlightComment, rest[:end])
+			i += end
+			continue
+		}
+
+		c := rest[0]
+		switch {
+		case strings.IndexByte(lang.quotes, c) >= 0:
+			// Strings end at the closing quote, or at the end of the line unless they are backquoted
+			end := 1
+			for end < len(rest) && rest[end] != c && (c == '`' || rest[end] != '\n') {
+				if rest[end] == '\\' && c != '`' {
+					end++
+				}
+				end++
+			}
+			if end < len(rest) && rest[end] == c {
+				end++
+			}
+			end = min(end, len(rest))
+			span(highlightString, rest[:end])
+			i += end
+		case c >= '0' && c <= '9' && (i == 0 || !isWord(code[i-1])):
+			end := 1
+			for end < len(rest) && (isWord(rest[end]) || rest[end] == '.') {
+				end++
+			}
+			span(highlightNumber, rest[:end])
+			i += end
+		case isWord(c):
+			end := 1
+			for end < len(rest) && isWord(rest[end]) {
+				end++
+			}
+			if word := rest[:end]; lang.keywords[word] && (i == 0 || !isWord(code[i-1])) {
+				span(highlightKeyword, word)
+			} else {
+				out.WriteString(html.EscapeString(word))
+			}
+			i += end
+		default:
+			out.WriteString(html.EscapeString(rest[:1])) // Bytes of multibyte characters pass through unchanged
+			i++
+		}
+	}
+	return out.String()
+}
+
+// highlightRenderer renders fenced code blocks with highlightCode in place of goldmark's plain rendering
+type highlightRenderer struct{}
+
+func (r *highlightRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindFencedCodeBlock, r.renderFencedCodeBlock)
+}
+
+func (r *highlightRenderer) renderFencedCodeBlock(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	block := node.(*ast.FencedCodeBlock)
+	language := string(block.Language(source))
+
+	var code strings.Builder
+	lines := block.Lines()
+	for i := 0; i < lines.Len(); i++ {
+		line := lines.At(i)
+		code.Write(line.Value(source))
+	}
+
+	w.WriteString("<pre><code")
+	if language != "" {
+		w.WriteString(` class="language-` + html.EscapeString(language) + `"`)
+	}
+	w.WriteString(">")
+	w.WriteString(highlightCode(language, code.String()))
+	w.WriteString("</code></pre>\n")
+	return ast.WalkSkipChildren, nil
+}
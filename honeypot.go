@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// honeypotRoutes are paths no visitor of the portfolio has reason to request but vulnerability scanners
+// probe for; a trailing slash matches the subtree. Requesting one bans the IP straight away.
+var honeypotRoutes = []string{
+	"/wp-login.php",
+	"/wp-admin/",
+	"/xmlrpc.php",
+	"/.env",
+	"/.git/",
+	"/phpmyadmin/",
+	"/admin.php",
+	"/config.php",
+	"/server-status",
+	"/api/admin/backup.zip",
+	"/api/admin/dump.sql",
+}
+
+// Honeypot endpoints: any request to a decoy route bans the calling IP through the automatic ban
+// subsystem, escalating like other automatic bans, and answers 404 as if nothing was there. Whitelisted
+// IPs and admins are only logged.
+func (h *APIHandler) handleHoneypot(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	ip := trustedClientIP(r)
+	if h.ipRules.Action(ip) == "" && !isAdminRequest(r) {
+		ctx, cancel := context.WithTimeout(context.Background(), autoBanTimeout)
+		defer cancel()
+		if err := h.autoBanner.ban(ctx, ip, fmt.Sprintf("honeypot %s %s", r.Method, r.URL.Path)); err != nil {
+			log.Printf("Error banning %s: %v", ip, err)
+		}
+	}
+
+	log.Printf("Date: %s | Route: honeypot %s | Status: TRAPPED %s | GPT Model: %s", currentTime, r.URL.Path, ip, gptModel)
+	http.NotFound(w, r)
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Locales holds the languages the portfolio is published in. The first is the language of the base fields
+// (e.g. Description); the others come from the *_i18n translation maps.
+type Locales struct {
+	supported []string
+}
+
+// NewLocales reads the comma separated PORTFOLIO_LOCALES (e.g. "en,fr"), defaulting to English only
+func NewLocales() *Locales {
+	var supported []string
+	for _, tag := range strings.Split(os.Getenv("PORTFOLIO_LOCALES"), ",") {
+		if tag = strings.ToLower(strings.TrimSpace(tag)); tag != "" {
+			supported = append(supported, tag)
+		}
+	}
+	if len(supported) == 0 {
+		supported = []string{"en"}
+	}
+	if len(supported) > 1 {
+		log.Printf("Serving content in %s (default %s)", strings.Join(supported, ", "), supported[0])
+	}
+	return &Locales{supported: supported}
+}
+
+// Default returns the language of the untranslated fields
+func (l *Locales) Default() string {
+	return l.supported[0]
+}
+
+// match returns the supported locale for a language tag, falling back from a region (fr-CA) to its language (fr)
+func (l *Locales) match(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	for _, candidate := range []string{tag, strings.SplitN(tag, "-", 2)[0]} {
+		for _, supported := range l.supported {
+			if candidate == supported {
+				return supported
+			}
+		}
+	}
+	return ""
+}
+
+// Negotiate picks the response locale from ?lang= or else the Accept-Language header
+func (l *Locales) Negotiate(r *http.Request) string {
+	if lang := r.URL.Query().Get("lang"); lang != "" {
+		if locale := l.match(lang); locale != "" {
+			return locale
+		}
+	}
+
+	type preference struct {
+		tag     string
+		quality float64
+	}
+	var preferences []preference
+	for _, part := range strings.Split(r.Header.Get("Accept-Language"), ",") {
+		fields := strings.Split(part, ";")
+		tag := strings.TrimSpace(fields[0])
+		if tag == "" || tag == "*" {
+			continue
+		}
+		quality := 1.0
+		for _, param := range fields[1:] {
+			if value, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+					quality = parsed
+				}
+			}
+		}
+		if quality > 0 {
+			preferences = append(preferences, preference{tag: tag, quality: quality})
+		}
+	}
+	sort.SliceStable(preferences, func(i, j int) bool { return preferences[i].quality > preferences[j].quality })
+
+	for _, pref := range preferences {
+		if locale := l.match(pref.tag); locale != "" {
+			return locale
+		}
+	}
+	return l.Default()
+}
+
+// setLanguageHeaders tells clients and caches which language the response is in
+func setLanguageHeaders(w http.ResponseWriter, locale string) {
+	w.Header().Set("Content-Language", locale)
+	w.Header().Add("Vary", "Accept-Language")
+}
+
+// translate returns the translation for a locale, or the base value when there is none
+func translate(base string, translations map[string]string, locale string) string {
+	if text := strings.TrimSpace(translations[locale]); text != "" {
+		return text
+	}
+	return base
+}
+
+// Localize replaces the project's name and description with their translations for the locale
+func (p *Project) Localize(locale string) {
+	p.Name = translate(p.Name, p.NameI18n, locale)
+	p.Description = translate(p.Description, p.DescriptionI18n, locale)
+}
+
+// Localize replaces the education record's major and description with their translations for the locale
+func (e *Education) Localize(locale string) {
+	e.Major = translate(e.Major, e.MajorI18n, locale)
+	e.Description = translate(e.Description, e.DescriptionI18n, locale)
+}
+
+func localizeProjects(projects []Project, locale string) {
+	for i := range projects {
+		projects[i].Localize(locale)
+	}
+}
+
+func localizeEducation(education []Education, locale string) {
+	for i := range education {
+		education[i].Localize(locale)
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// errInvalidIdentifier is returned for a detail route key that can be neither an ObjectID nor a slug.
+// Handlers answer it with 400, while a well-formed key that matches nothing is a 404.
+var errInvalidIdentifier = errors.New("invalid identifier")
+
+// parseIdentifier accepts an ObjectID hex string, a slug or a name (e.g. "Billie Mallady"). It returns the ID
+// when the key is a valid ObjectID and the slug form of the key.
+func parseIdentifier(key string) (primitive.ObjectID, string, error) {
+	id, _ := primitive.ObjectIDFromHex(key)
+	slug := slugify(key)
+	if len(key) > 200 || (id.IsZero() && slug == "") {
+		return primitive.NilObjectID, "", fmt.Errorf("%w %q: expected an ID or a slug", errInvalidIdentifier, key)
+	}
+	return id, slug, nil
+}
+
+// GetProjectByIDOrSlug resolves a detail route key to a project, trying the ObjectID before the slug.
+// Hidden projects are included; callers check visibility.
+func (ps *PortfolioService) GetProjectByIDOrSlug(ctx context.Context, key string) (*Project, error) {
+	id, slug, err := parseIdentifier(key)
+	if err != nil {
+		return nil, err
+	}
+	if !id.IsZero() {
+		var project Project
+		err := ps.projects.FindOne(ctx, bson.M{"_id": id}).Decode(&project)
+		if err == nil {
+			return &project, nil
+		}
+		if err != mongo.ErrNoDocuments {
+			return nil, err
+		}
+	}
+	return ps.GetProjectBySlug(ctx, slug)
+}
+
+// GetPostByIDOrSlug resolves a detail route key to a post, trying the ObjectID before the slug
+func (ps *PortfolioService) GetPostByIDOrSlug(ctx context.Context, key string) (*Post, error) {
+	id, slug, err := parseIdentifier(key)
+	if err != nil {
+		return nil, err
+	}
+	if !id.IsZero() {
+		var post Post
+		err := ps.posts.FindOne(ctx, bson.M{"_id": id}).Decode(&post)
+		if err == nil {
+			return &post, nil
+		}
+		if err != mongo.ErrNoDocuments {
+			return nil, err
+		}
+	}
+	return ps.GetPostBySlug(ctx, slug)
+}
+
+// GetResumeByIDOrAuthor resolves a detail route key to a resume by its own ID, or else to the resume of the
+// author with that ID, slug or name
+func (ps *PortfolioService) GetResumeByIDOrAuthor(ctx context.Context, key string) (*Resume, error) {
+	id, _, err := parseIdentifier(key)
+	if err != nil {
+		return nil, err
+	}
+	if !id.IsZero() {
+		resume, err := ps.GetResumeByID(ctx, id)
+		if err != mongo.ErrNoDocuments {
+			return resume, err
+		}
+	}
+
+	author, err := ps.GetAuthorBySlugOrID(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return ps.GetResumeByAuthor(ctx, author.ID)
+}
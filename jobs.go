@@ -0,0 +1,483 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Job statuses
+const (
+	JobQueued    = "queued"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed"
+)
+
+// Job is one unit of background work stored in the jobs collection. A queued job runs once RunAt has
+// passed; a running job whose lease expired (the worker crashed) is picked up again.
+type Job struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Type        string             `bson:"type" json:"type"`
+	Payload     bson.M             `bson:"payload,omitempty" json:"payload,omitempty"`
+	Status      string             `bson:"status" json:"status"`
+	Attempts    int                `bson:"attempts" json:"attempts"`
+	MaxAttempts int                `bson:"max_attempts" json:"max_attempts"`
+	RunAt       time.Time          `bson:"run_at" json:"run_at"`
+	LeaseUntil  *time.Time         `bson:"lease_until,omitempty" json:"lease_until,omitempty"`
+	LastError   string             `bson:"last_error,omitempty" json:"last_error,omitempty"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+	StartedAt   *time.Time         `bson:"started_at,omitempty" json:"started_at,omitempty"`
+	FinishedAt  *time.Time         `bson:"finished_at,omitempty" json:"finished_at,omitempty"`
+}
+
+// JobHandler runs a job; returning an error schedules a retry until the job runs out of attempts
+type JobHandler func(ctx context.Context, payload bson.M) error
+
+// JobOptions adjust how a job is enqueued
+type JobOptions struct {
+	RunAt       time.Time // Zero runs as soon as a worker is free
+	MaxAttempts int       // Zero uses defaultJobMaxAttempts
+}
+
+const (
+	defaultJobMaxAttempts = 5
+	jobLease              = 10 * time.Minute // Longest a job may run before another worker may take it over
+	jobRetryBase          = 30 * time.Second // Retries back off exponentially from here
+)
+
+// JobQueue runs background jobs from the jobs collection with a pool of workers. Several instances can share
+// the collection: a job is claimed atomically by one worker at a time.
+type JobQueue struct {
+	service      *PortfolioService
+	workers      int
+	pollInterval time.Duration
+
+	mutex    sync.RWMutex
+	handlers map[string]JobHandler
+}
+
+// NewJobQueue creates a queue from environment configuration (JOB_WORKERS, JOB_POLL_INTERVAL)
+func NewJobQueue(service *PortfolioService) *JobQueue {
+	workers := 2
+	if value := os.Getenv("JOB_WORKERS"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			log.Printf("Invalid JOB_WORKERS %q, using %d", value, workers)
+		} else {
+			workers = parsed
+		}
+	}
+
+	pollInterval := 5 * time.Second
+	if value := os.Getenv("JOB_POLL_INTERVAL"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			log.Printf("Invalid JOB_POLL_INTERVAL %q, using %s", value, pollInterval)
+		} else {
+			pollInterval = parsed
+		}
+	}
+
+	return &JobQueue{
+		service:      service,
+		workers:      workers,
+		pollInterval: pollInterval,
+		handlers:     make(map[string]JobHandler),
+	}
+}
+
+// Register sets the handler for a job type. Jobs of unregistered types stay queued.
+func (q *JobQueue) Register(jobType string, handler JobHandler) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	q.handlers[jobType] = handler
+}
+
+// Types lists the registered job types
+func (q *JobQueue) Types() []string {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+	types := make([]string, 0, len(q.handlers))
+	for jobType := range q.handlers {
+		types = append(types, jobType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+func (q *JobQueue) handler(jobType string) JobHandler {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+	return q.handlers[jobType]
+}
+
+// Enqueue stores a new job and returns it
+func (q *JobQueue) Enqueue(ctx context.Context, jobType string, payload bson.M, opts JobOptions) (*Job, error) {
+	if q.handler(jobType) == nil {
+		return nil, fmt.Errorf("unknown job type %q", jobType)
+	}
+	now := time.Now()
+	job := &Job{
+		Type:        jobType,
+		Payload:     payload,
+		Status:      JobQueued,
+		MaxAttempts: opts.MaxAttempts,
+		RunAt:       opts.RunAt,
+		CreatedAt:   now,
+	}
+	if job.MaxAttempts <= 0 {
+		job.MaxAttempts = defaultJobMaxAttempts
+	}
+	if job.RunAt.IsZero() {
+		job.RunAt = now
+	}
+	result, err := q.service.jobs.InsertOne(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+	job.ID = result.InsertedID.(primitive.ObjectID)
+	return job, nil
+}
+
+// EnqueueUnlessPending enqueues a job unless one of the same type is already queued or running. It returns
+// nil when nothing was enqueued.
+func (q *JobQueue) EnqueueUnlessPending(ctx context.Context, jobType string) (*Job, error) {
+	pending, err := q.service.jobs.CountDocuments(ctx, bson.M{"type": jobType, "status": bson.M{"$in": []string{JobQueued, JobRunning}}})
+	if err != nil {
+		return nil, err
+	}
+	if pending > 0 {
+		return nil, nil
+	}
+	return q.Enqueue(ctx, jobType, nil, JobOptions{})
+}
+
+// Run starts the workers and blocks until the context is cancelled
+func (q *JobQueue) Run(ctx context.Context) {
+	if q.workers == 0 {
+		log.Println("JOB_WORKERS is 0, background jobs are not run by this instance")
+		return
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < q.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			q.work(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+// work runs jobs back to back, polling when the queue is empty
+func (q *JobQueue) work(ctx context.Context) {
+	for {
+		job, err := q.claim(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("Error claiming job: %v", err)
+		}
+		if job != nil {
+			q.execute(ctx, job)
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(q.pollInterval):
+		}
+	}
+}
+
+// claim atomically takes the oldest due job of a registered type, or returns nil if there is none
+func (q *JobQueue) claim(ctx context.Context) (*Job, error) {
+	now := time.Now()
+	filter := bson.M{
+		"type": bson.M{"$in": q.Types()},
+		"$or": []bson.M{
+			{"status": JobQueued, "run_at": bson.M{"$lte": now}},
+			{"status": JobRunning, "lease_until": bson.M{"$lt": now}},
+		},
+	}
+	update := bson.M{
+		"$set": bson.M{"status": JobRunning, "started_at": now, "lease_until": now.Add(jobLease)},
+		"$inc": bson.M{"attempts": 1},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "run_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var job Job
+	err := q.service.jobs.FindOneAndUpdate(ctx, filter, update, opts).Decode(&job)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// execute runs a claimed job and records the outcome, rescheduling it with backoff after a failure
+func (q *JobQueue) execute(ctx context.Context, job *Job) {
+	jobCtx, cancel := context.WithTimeout(ctx, jobLease)
+	defer cancel()
+
+	err := q.runHandler(jobCtx, job)
+	now := time.Now()
+	set := bson.M{"finished_at": now}
+	switch {
+	case err == nil:
+		set["status"] = JobSucceeded
+		set["last_error"] = ""
+	case job.Attempts < job.MaxAttempts:
+		delay := jobRetryBase << (job.Attempts - 1)
+		set = bson.M{"status": JobQueued, "last_error": err.Error(), "run_at": now.Add(delay)}
+		log.Printf("Job %s (%s) failed, retrying in %s: %v", job.ID.Hex(), job.Type, delay, err)
+	default:
+		set["status"] = JobFailed
+		set["last_error"] = err.Error()
+		log.Printf("Job %s (%s) failed after %d attempts: %v", job.ID.Hex(), job.Type, job.Attempts, err)
+		errorReporter().CaptureError(err, nil, map[string]string{"job_type": job.Type, "job_id": job.ID.Hex()})
+	}
+
+	update := bson.M{"$set": set, "$unset": bson.M{"lease_until": ""}}
+	if _, err := q.service.jobs.UpdateOne(context.Background(), bson.M{"_id": job.ID}, update); err != nil {
+		log.Printf("Error recording outcome of job %s: %v", job.ID.Hex(), err)
+	}
+}
+
+// runHandler calls the job's handler, turning a panic into an error so one bad job cannot stop a worker
+func (q *JobQueue) runHandler(ctx context.Context, job *Job) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			errorReporter().CapturePanic(recovered, nil, map[string]string{"job_type": job.Type})
+			err = fmt.Errorf("panic: %v", recovered)
+		}
+	}()
+	handler := q.handler(job.Type)
+	if handler == nil {
+		return fmt.Errorf("no handler registered for job type %q", job.Type)
+	}
+	return handler(ctx, job.Payload)
+}
+
+// ListJobs returns the most recent jobs, optionally filtered by status and type
+func (ps *PortfolioService) ListJobs(ctx context.Context, status, jobType string, limit int64) ([]Job, error) {
+	filter := bson.M{}
+	if status != "" {
+		filter["status"] = status
+	}
+	if jobType != "" {
+		filter["type"] = jobType
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(limit)
+	cursor, err := ps.jobs.Find(ctx, filter, boundedFind(opts))
+	if err != nil {
+		return nil, err
+	}
+	jobs := []Job{}
+	if err = cursor.All(ctx, &jobs); err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// CountJobsByStatus returns the number of jobs in each status
+func (ps *PortfolioService) CountJobsByStatus(ctx context.Context) (map[string]int64, error) {
+	cursor, err := ps.jobs.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{"_id": "$status", "count": bson.M{"$sum": 1}}}},
+	}, timedAggregate())
+	if err != nil {
+		return nil, err
+	}
+	var groups []struct {
+		Status string `bson:"_id"`
+		Count  int64  `bson:"count"`
+	}
+	if err = cursor.All(ctx, &groups); err != nil {
+		return nil, err
+	}
+	counts := map[string]int64{JobQueued: 0, JobRunning: 0, JobSucceeded: 0, JobFailed: 0}
+	for _, group := range groups {
+		counts[group.Status] = group.Count
+	}
+	return counts, nil
+}
+
+// RetryJob requeues a failed job with a fresh set of attempts
+func (ps *PortfolioService) RetryJob(ctx context.Context, id primitive.ObjectID) error {
+	result, err := ps.jobs.UpdateOne(ctx, bson.M{"_id": id, "status": JobFailed}, bson.M{
+		"$set":   bson.M{"status": JobQueued, "attempts": 0, "run_at": time.Now()},
+		"$unset": bson.M{"finished_at": ""},
+	})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// Jobs endpoint: GET /api/admin/jobs?status=&type=&limit= lists recent jobs with counts per status and the
+// scheduled tasks, POST
+// enqueues a job of a registered type, e.g. {"type": "repo_sync"} (admin only)
+func (h *APIHandler) handleJobs(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if !isAdminRequest(r) {
+		log.Printf("Date: %s | Route: /api/admin/jobs | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ctx := r.Context()
+	switch r.Method {
+	case "GET":
+		var v Validator
+		status := r.URL.Query().Get("status")
+		if status != "" {
+			v.OneOf("status", status, JobQueued, JobRunning, JobSucceeded, JobFailed)
+		}
+		limit := v.QueryInt(r, "limit", 50, 1, 500)
+		if err := v.Err(); err != nil {
+			writeInvalidInput(w, err)
+			return
+		}
+
+		jobs, err := h.service.ListJobs(ctx, status, r.URL.Query().Get("type"), int64(limit))
+		if err != nil {
+			log.Printf("Date: %s | Route: /api/admin/jobs | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		counts, err := h.service.CountJobsByStatus(ctx)
+		if err != nil {
+			log.Printf("Date: %s | Route: /api/admin/jobs | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Date: %s | Route: /api/admin/jobs | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"counts":    counts,
+			"types":     h.jobs.Types(),
+			"schedules": h.scheduler.Status(),
+			"jobs":      jobs,
+		})
+
+	case "POST":
+		var request struct {
+			Type        string     `json:"type"`
+			Payload     bson.M     `json:"payload"`
+			RunAt       *time.Time `json:"run_at"`
+			MaxAttempts int        `json:"max_attempts"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			log.Printf("Date: %s | Route: /api/admin/jobs | Status: BAD_REQUEST | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, "Invalid JSON request", http.StatusBadRequest)
+			return
+		}
+		var v Validator
+		v.OneOf("type", request.Type, h.jobs.Types()...)
+		v.Range("max_attempts", float64(request.MaxAttempts), 0, 20)
+		if err := v.Err(); err != nil {
+			writeInvalidInput(w, err)
+			return
+		}
+
+		opts := JobOptions{MaxAttempts: request.MaxAttempts}
+		if request.RunAt != nil {
+			opts.RunAt = *request.RunAt
+		}
+		job, err := h.jobs.Enqueue(ctx, request.Type, request.Payload, opts)
+		if err != nil {
+			log.Printf("Date: %s | Route: /api/admin/jobs | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Date: %s | Route: /api/admin/jobs | Status: CREATED | GPT Model: %s", currentTime, gptModel)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(job)
+
+	default:
+		log.Printf("Date: %s | Route: /api/admin/jobs | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// Job routes: POST /api/admin/jobs/{id}/retry requeues a failed job (admin only)
+func (h *APIHandler) handleJobRoutes(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/admin/jobs/"), "/"), "/")
+	if len(parts) != 2 || parts[1] != "retry" {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+	if r.Method != "POST" {
+		log.Printf("Date: %s | Route: /api/admin/jobs/{id}/retry | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !isAdminRequest(r) {
+		log.Printf("Date: %s | Route: /api/admin/jobs/{id}/retry | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := primitive.ObjectIDFromHex(parts[0])
+	if err != nil {
+		http.Error(w, "Invalid job ID", http.StatusBadRequest)
+		return
+	}
+	if err := h.service.RetryJob(r.Context(), id); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			http.Error(w, "Failed job not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("Date: %s | Route: /api/admin/jobs/{id}/retry | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Date: %s | Route: /api/admin/jobs/{id}/retry | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,307 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Maximum JSON Resume document we accept
+const maxJSONResumeSize = 2 << 20
+
+// JSONResume is the subset of the JSON Resume schema (https://jsonresume.org/schema) we import
+type JSONResume struct {
+	Basics struct {
+		Name     string `json:"name"`
+		Label    string `json:"label"`
+		Email    string `json:"email"`
+		Phone    string `json:"phone"`
+		URL      string `json:"url"`
+		Summary  string `json:"summary"`
+		Profiles []struct {
+			Network  string `json:"network"`
+			Username string `json:"username"`
+			URL      string `json:"url"`
+		} `json:"profiles"`
+	} `json:"basics"`
+	Work []struct {
+		Name       string   `json:"name"`
+		Position   string   `json:"position"`
+		StartDate  string   `json:"startDate"`
+		EndDate    string   `json:"endDate"`
+		Summary    string   `json:"summary"`
+		Highlights []string `json:"highlights"`
+	} `json:"work"`
+	Education []struct {
+		Institution string   `json:"institution"`
+		Area        string   `json:"area"`
+		StudyType   string   `json:"studyType"`
+		StartDate   string   `json:"startDate"`
+		EndDate     string   `json:"endDate"`
+		Score       string   `json:"score"`
+		Courses     []string `json:"courses"`
+	} `json:"education"`
+	Skills []struct {
+		Name     string   `json:"name"`
+		Keywords []string `json:"keywords"`
+	} `json:"skills"`
+	Projects []struct {
+		Name        string   `json:"name"`
+		Description string   `json:"description"`
+		Highlights  []string `json:"highlights"`
+		Keywords    []string `json:"keywords"`
+		StartDate   string   `json:"startDate"`
+		EndDate     string   `json:"endDate"`
+		URL         string   `json:"url"`
+		Type        string   `json:"type"`
+	} `json:"projects"`
+	Interests []struct {
+		Name string `json:"name"`
+	} `json:"interests"`
+}
+
+// JSONResumeImport is the result of mapping a JSON Resume document onto portfolio documents
+type JSONResumeImport struct {
+	DryRun    bool        `json:"dry_run"`
+	Author    Author      `json:"author"`
+	Resume    Resume      `json:"resume"`
+	Education []Education `json:"education"`
+	Projects  []Project   `json:"projects"`
+	Warnings  []string    `json:"warnings"`
+}
+
+// joinNonEmpty joins the non-empty parts with sep
+func joinNonEmpty(sep string, parts ...string) string {
+	var kept []string
+	for _, part := range parts {
+		if strings.TrimSpace(part) != "" {
+			kept = append(kept, strings.TrimSpace(part))
+		}
+	}
+	return strings.Join(kept, sep)
+}
+
+// nonEmptyStrings returns the trimmed non-empty values, or nil if there are none
+func nonEmptyStrings(values []string) []string {
+	var kept []string
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			kept = append(kept, strings.TrimSpace(value))
+		}
+	}
+	return kept
+}
+
+// mapJSONResume converts a JSON Resume document into a new author with resume, education and projects
+func mapJSONResume(doc *JSONResume) (*JSONResumeImport, error) {
+	if strings.TrimSpace(doc.Basics.Name) == "" {
+		return nil, fmt.Errorf("basics.name is required")
+	}
+
+	result := &JSONResumeImport{
+		Author: Author{
+			Name:     doc.Basics.Name,
+			JobTitle: doc.Basics.Label,
+			Email:    EncryptedString(doc.Basics.Email),
+			Hobbies:  []string{},
+		},
+		Education: []Education{},
+		Projects:  []Project{},
+		Warnings:  []string{},
+	}
+	for _, profile := range doc.Basics.Profiles {
+		switch strings.ToLower(profile.Network) {
+		case "linkedin":
+			result.Author.LinkedinURL = profile.URL
+		case "github":
+			result.Author.GithubURL = profile.URL
+		}
+	}
+	for _, interest := range doc.Interests {
+		result.Author.Hobbies = append(result.Author.Hobbies, interest.Name)
+	}
+
+	result.Resume = Resume{
+		AuthorName: doc.Basics.Name,
+		Contact:    Contact{Phone: EncryptedString(doc.Basics.Phone), Email: EncryptedString(doc.Basics.Email)},
+		Experience: []Experience{},
+		Skills:     []string{},
+	}
+	for _, work := range doc.Work {
+		started, err := parseImportDate(work.StartDate)
+		if err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("work at %s: %v", work.Name, err))
+		}
+		finished, err := parseImportDate(work.EndDate)
+		if err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("work at %s: %v", work.Name, err))
+		}
+
+		experience := Experience{
+			JobTitle:    work.Position,
+			Company:     work.Name,
+			StartDate:   started,
+			EndDate:     finished,
+			Description: joinNonEmpty("\n", append([]string{work.Summary}, work.Highlights...)...),
+			Projects:    []Project{},
+		}
+		experience.refreshDuration()
+		result.Resume.Experience = append(result.Resume.Experience, experience)
+	}
+
+	seenSkills := make(map[string]bool)
+	for _, skill := range doc.Skills {
+		for _, name := range append([]string{skill.Name}, skill.Keywords...) {
+			key := strings.ToLower(strings.TrimSpace(name))
+			if key == "" || seenSkills[key] {
+				continue
+			}
+			seenSkills[key] = true
+			result.Resume.Skills = append(result.Resume.Skills, strings.TrimSpace(name))
+		}
+	}
+
+	for _, entry := range doc.Education {
+		started, err := parseImportDate(entry.StartDate)
+		if err != nil || started == nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("education at %s: missing or invalid start date", entry.Institution))
+			started = &time.Time{}
+		}
+		finished, err := parseImportDate(entry.EndDate)
+		if err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("education at %s: %v", entry.Institution, err))
+		}
+
+		result.Education = append(result.Education, Education{
+			UniversityName: entry.Institution,
+			Major:          joinNonEmpty(", ", entry.StudyType, entry.Area),
+			StartDate:      *started,
+			EndDate:        finished,
+			GPA:            strings.TrimSpace(entry.Score),
+			Coursework:     nonEmptyStrings(entry.Courses),
+			StudentName:    doc.Basics.Name,
+		})
+	}
+
+	for _, entry := range doc.Projects {
+		started, err := parseImportDate(entry.StartDate)
+		if err != nil || started == nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("project %s: missing or invalid start date", entry.Name))
+			started = &time.Time{}
+		}
+		finished, err := parseImportDate(entry.EndDate)
+		if err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("project %s: %v", entry.Name, err))
+		}
+
+		category := entry.Type
+		if category == "" {
+			category = "Project"
+		}
+		project := Project{
+			Name:             entry.Name,
+			Category:         category,
+			StartDate:        *started,
+			EndDate:          finished,
+			Description:      joinNonEmpty("\n", entry.Description, strings.Join(entry.Highlights, "\n")),
+			TechnologiesUsed: entry.Keywords,
+		}
+		if project.TechnologiesUsed == nil {
+			project.TechnologiesUsed = []string{}
+		}
+		if entry.URL != "" {
+			repoURL := entry.URL
+			project.RepoURL = &repoURL
+		}
+		result.Projects = append(result.Projects, project)
+	}
+
+	return result, nil
+}
+
+// saveJSONResumeImport inserts the author first so every other document can reference it
+func (ps *PortfolioService) saveJSONResumeImport(ctx context.Context, result *JSONResumeImport) error {
+	if err := ps.InsertAuthor(ctx, &result.Author); err != nil {
+		return err
+	}
+
+	for i := range result.Education {
+		result.Education[i].StudentID = result.Author.ID
+		if err := ps.InsertEducation(ctx, &result.Education[i]); err != nil {
+			return err
+		}
+	}
+	for i := range result.Projects {
+		result.Projects[i].AuthorID = result.Author.ID
+		if err := ps.InsertProject(ctx, &result.Projects[i]); err != nil {
+			return err
+		}
+	}
+
+	result.Resume.AuthorID = result.Author.ID
+	result.Resume.Education = result.Education
+	return ps.UpsertResumeByAuthor(ctx, &result.Resume)
+}
+
+// JSON Resume import endpoint (admin only)
+func (h *APIHandler) handleJSONResumeImport(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if r.Method != "POST" {
+		log.Printf("Date: %s | Route: /api/admin/import/jsonresume | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !isAdminRequest(r) {
+		log.Printf("Date: %s | Route: /api/admin/import/jsonresume | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+
+	var doc JSONResume
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxJSONResumeSize)).Decode(&doc); err != nil {
+		log.Printf("Date: %s | Route: /api/admin/import/jsonresume | Status: BAD_REQUEST | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Invalid JSON request", http.StatusBadRequest)
+		return
+	}
+
+	result, err := mapJSONResume(&doc)
+	if err != nil {
+		log.Printf("Date: %s | Route: /api/admin/import/jsonresume | Status: INVALID_INPUT | GPT Model: %s", currentTime, gptModel)
+		writeInvalidInput(w, err)
+		return
+	}
+	result.DryRun = dryRun
+
+	if !dryRun {
+		ctx := context.Background()
+		if err := h.service.saveJSONResumeImport(ctx, result); err != nil {
+			log.Printf("Date: %s | Route: /api/admin/import/jsonresume | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	log.Printf("Date: %s | Route: /api/admin/import/jsonresume | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+	w.Header().Set("Content-Type", "application/json")
+	if !dryRun {
+		w.WriteHeader(http.StatusCreated)
+	}
+	json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,321 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Maximum LinkedIn export archive we accept
+const maxLinkedInArchiveSize = 20 << 20
+
+// Maximum uncompressed size of a single CSV file in the archive, so a small zip cannot expand without bound
+const maxLinkedInCSVSize = 10 << 20
+
+// LinkedInImport is the result of mapping a LinkedIn data export onto portfolio documents
+type LinkedInImport struct {
+	DryRun    bool        `json:"dry_run"`
+	Resume    Resume      `json:"resume"`
+	Education []Education `json:"education"`
+	Warnings  []string    `json:"warnings"`
+}
+
+// parseImportDate handles the date formats used by LinkedIn exports and JSON Resume documents
+func parseImportDate(value string) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	for _, layout := range []string{"Jan 2006", "January 2006", "2006", "Jan 02, 2006", "2006-01-02", "2006-01", "01/2006"} {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return &parsed, nil
+		}
+	}
+	return nil, fmt.Errorf("unrecognized date %q", value)
+}
+
+// monthsBetween returns whole months from start to end (or now if end is nil)
+func monthsBetween(start time.Time, end *time.Time) int {
+	finish := time.Now()
+	if end != nil {
+		finish = *end
+	}
+	months := (finish.Year()-start.Year())*12 + int(finish.Month()-start.Month())
+	if months < 0 {
+		return 0
+	}
+	return months
+}
+
+// readLinkedInCSV finds a CSV file in the archive by name and returns its rows keyed by header.
+// Some export files start with free-text notes, so the header is the first row containing headerColumn.
+func readLinkedInCSV(archive *zip.Reader, name, headerColumn string) ([]map[string]string, error) {
+	var file *zip.File
+	for _, candidate := range archive.File {
+		if strings.EqualFold(path.Base(candidate.Name), name) {
+			file = candidate
+			break
+		}
+	}
+	if file == nil {
+		return nil, nil
+	}
+
+	reader, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(io.LimitReader(reader, maxLinkedInCSVSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	if len(data) > maxLinkedInCSVSize {
+		return nil, fmt.Errorf("%s: larger than %d MB", name, maxLinkedInCSVSize>>20)
+	}
+
+	csvReader := csv.NewReader(bytes.NewReader(data))
+	csvReader.FieldsPerRecord = -1
+	csvReader.LazyQuotes = true
+	records, err := csvReader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	headerIndex := -1
+	for i, record := range records {
+		for _, column := range record {
+			if strings.TrimSpace(column) == headerColumn {
+				headerIndex = i
+				break
+			}
+		}
+		if headerIndex >= 0 {
+			break
+		}
+	}
+	if headerIndex < 0 {
+		return nil, fmt.Errorf("%s: header %q not found", name, headerColumn)
+	}
+
+	header := records[headerIndex]
+	var rows []map[string]string
+	for _, record := range records[headerIndex+1:] {
+		row := make(map[string]string)
+		for i, column := range header {
+			if i < len(record) {
+				row[strings.TrimSpace(column)] = strings.TrimSpace(record[i])
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// mapLinkedInArchive converts Positions.csv, Education.csv, Skills.csv and Email Addresses.csv
+// into a resume and education records for the given author
+func mapLinkedInArchive(archive *zip.Reader, author *Author) (*LinkedInImport, error) {
+	result := &LinkedInImport{
+		Resume: Resume{
+			AuthorID:   author.ID,
+			AuthorName: author.Name,
+			Contact:    Contact{Email: author.Email},
+			Experience: []Experience{},
+			Skills:     []string{},
+		},
+		Education: []Education{},
+		Warnings:  []string{},
+	}
+
+	positions, err := readLinkedInCSV(archive, "Positions.csv", "Company Name")
+	if err != nil {
+		return nil, err
+	}
+	if positions == nil {
+		result.Warnings = append(result.Warnings, "Positions.csv not found in archive")
+	}
+	for _, row := range positions {
+		started, err := parseImportDate(row["Started On"])
+		if err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("position at %s: %v", row["Company Name"], err))
+		}
+		finished, err := parseImportDate(row["Finished On"])
+		if err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("position at %s: %v", row["Company Name"], err))
+		}
+
+		experience := Experience{
+			JobTitle:    row["Title"],
+			Company:     row["Company Name"],
+			Location:    row["Location"],
+			StartDate:   started,
+			EndDate:     finished,
+			Description: row["Description"],
+			Projects:    []Project{},
+		}
+		experience.refreshDuration()
+		result.Resume.Experience = append(result.Resume.Experience, experience)
+	}
+
+	schools, err := readLinkedInCSV(archive, "Education.csv", "School Name")
+	if err != nil {
+		return nil, err
+	}
+	if schools == nil {
+		result.Warnings = append(result.Warnings, "Education.csv not found in archive")
+	}
+	for _, row := range schools {
+		started, err := parseImportDate(row["Start Date"])
+		if err != nil || started == nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("education at %s: missing or invalid start date", row["School Name"]))
+			started = &time.Time{}
+		}
+		finished, err := parseImportDate(row["End Date"])
+		if err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("education at %s: %v", row["School Name"], err))
+		}
+
+		description := row["Notes"]
+		if activities := row["Activities"]; activities != "" {
+			description = strings.TrimSpace(description + "\n" + activities)
+		}
+
+		education := Education{
+			UniversityName: row["School Name"],
+			Major:          row["Degree Name"],
+			StartDate:      *started,
+			EndDate:        finished,
+			Description:    description,
+			StudentName:    author.Name,
+			StudentID:      author.ID,
+		}
+		result.Education = append(result.Education, education)
+	}
+	result.Resume.Education = result.Education
+
+	skills, err := readLinkedInCSV(archive, "Skills.csv", "Name")
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range skills {
+		if name := row["Name"]; name != "" {
+			result.Resume.Skills = append(result.Resume.Skills, name)
+		}
+	}
+
+	emails, err := readLinkedInCSV(archive, "Email Addresses.csv", "Email Address")
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range emails {
+		if row["Primary"] == "Yes" && row["Email Address"] != "" {
+			result.Resume.Contact.Email = EncryptedString(row["Email Address"])
+		}
+	}
+
+	return result, nil
+}
+
+// LinkedIn import endpoint (admin only)
+func (h *APIHandler) handleLinkedInImport(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if r.Method != "POST" {
+		log.Printf("Date: %s | Route: /api/admin/import/linkedin | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !isAdminRequest(r) {
+		log.Printf("Date: %s | Route: /api/admin/import/linkedin | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	authorID, err := primitive.ObjectIDFromHex(r.URL.Query().Get("author_id"))
+	if err != nil {
+		http.Error(w, "Invalid author ID", http.StatusBadRequest)
+		return
+	}
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxLinkedInArchiveSize)
+	if err := r.ParseMultipartForm(maxLinkedInArchiveSize); err != nil {
+		log.Printf("Date: %s | Route: /api/admin/import/linkedin | Status: BAD_REQUEST | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Expected a multipart upload with an 'archive' file (max 20MB)", http.StatusBadRequest)
+		return
+	}
+	file, _, err := r.FormFile("archive")
+	if err != nil {
+		http.Error(w, "Missing 'archive' file", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		http.Error(w, "Failed to read archive", http.StatusBadRequest)
+		return
+	}
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		http.Error(w, "Archive is not a valid LinkedIn data export (zip)", http.StatusBadRequest)
+		return
+	}
+
+	ctx := context.Background()
+	author, err := h.service.GetAuthorByID(ctx, authorID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	result, err := mapLinkedInArchive(archive, author)
+	if err != nil {
+		log.Printf("Date: %s | Route: /api/admin/import/linkedin | Status: INVALID_INPUT | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, fmt.Sprintf("Invalid archive: %v", err), http.StatusBadRequest)
+		return
+	}
+	result.DryRun = dryRun
+
+	if !dryRun {
+		for i := range result.Education {
+			if err := h.service.UpsertEducation(ctx, &result.Education[i]); err != nil {
+				log.Printf("Date: %s | Route: /api/admin/import/linkedin | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+		result.Resume.Education = result.Education
+		if err := h.service.UpsertResumeByAuthor(ctx, &result.Resume); err != nil {
+			log.Printf("Date: %s | Route: /api/admin/import/linkedin | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	log.Printf("Date: %s | Route: /api/admin/import/linkedin | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
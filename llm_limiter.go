@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// errLLMBusy is returned instead of calling OpenAI when every slot is taken and the queue is full, or the
+// wait for a slot timed out
+var errLLMBusy = errors.New("too many concurrent OpenAI requests")
+
+// llmRetryAfter is the Retry-After sent with a 503 when the LLM is busy, in seconds
+const llmRetryAfter = 5
+
+// LLMLimiter caps the OpenAI calls in flight on this replica. Calls beyond the cap wait in a short
+// bounded queue for a free slot; when the queue is full or the wait times out they fail with errLLMBusy.
+type LLMLimiter struct {
+	slots    chan struct{}
+	queued   atomic.Int64
+	maxQueue int64
+	maxWait  time.Duration
+}
+
+// NewLLMLimiter creates the limiter from LLM_MAX_CONCURRENCY (default 4), LLM_QUEUE_SIZE (8) and
+// LLM_QUEUE_TIMEOUT (10s)
+func NewLLMLimiter() *LLMLimiter {
+	concurrency, maxQueue := 4, 8
+	for key, target := range map[string]*int{"LLM_MAX_CONCURRENCY": &concurrency, "LLM_QUEUE_SIZE": &maxQueue} {
+		if value := os.Getenv(key); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil || parsed < 0 || (key == "LLM_MAX_CONCURRENCY" && parsed == 0) {
+				log.Printf("Invalid %s %q, using %d", key, value, *target)
+			} else {
+				*target = parsed
+			}
+		}
+	}
+	maxWait := 10 * time.Second
+	if value := os.Getenv("LLM_QUEUE_TIMEOUT"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			log.Printf("Invalid LLM_QUEUE_TIMEOUT %q, using %s", value, maxWait)
+		} else {
+			maxWait = parsed
+		}
+	}
+	return &LLMLimiter{slots: make(chan struct{}, concurrency), maxQueue: int64(maxQueue), maxWait: maxWait}
+}
+
+// Acquire takes a slot, waiting in the queue if needed, and returns the function that releases it
+func (l *LLMLimiter) Acquire(ctx context.Context) (func(), error) {
+	release := func() { <-l.slots }
+	select {
+	case l.slots <- struct{}{}:
+		return release, nil
+	default:
+	}
+
+	if l.queued.Add(1) > l.maxQueue {
+		l.queued.Add(-1)
+		return nil, errLLMBusy
+	}
+	defer l.queued.Add(-1)
+
+	timer := time.NewTimer(l.maxWait)
+	defer timer.Stop()
+	select {
+	case l.slots <- struct{}{}:
+		return release, nil
+	case <-timer.C:
+		return nil, errLLMBusy
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// writeLLMBusy tells the client to retry once the OpenAI calls in flight have finished
+func writeLLMBusy(w http.ResponseWriter) {
+	w.Header().Set("Retry-After", strconv.Itoa(llmRetryAfter))
+	http.Error(w, "The assistant is busy right now. Please try again in a few seconds.", http.StatusServiceUnavailable)
+}
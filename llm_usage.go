@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openai/openai-go"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// LLMCall records one OpenAI completion, for alerting on failures and estimating spend
+type LLMCall struct {
+	ID               primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	RequestID        string             `bson:"request_id,omitempty" json:"request_id,omitempty"` // Chatbot request the call was made for
+	Purpose          string             `bson:"purpose" json:"purpose"`                           // chat, summary or completion
+	Model            string             `bson:"model" json:"model"`
+	PromptTokens     int64              `bson:"prompt_tokens" json:"prompt_tokens"`
+	CompletionTokens int64              `bson:"completion_tokens" json:"completion_tokens"`
+	CostUSD          float64            `bson:"cost_usd" json:"cost_usd"` // Estimated from llmPrices
+	LatencyMs        int64              `bson:"latency_ms" json:"latency_ms"`
+	Error            string             `bson:"error,omitempty" json:"error,omitempty"`
+	CreatedAt        time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// llmPrice is the price in USD per million prompt and completion tokens
+type llmPrice struct {
+	prompt, completion float64
+}
+
+// defaultLLMPrices are OpenAI list prices; models are matched by the longest prefix, so dated snapshots
+// such as gpt-4o-2024-08-06 use their family's price. Override with LLM_PRICES, e.g. "gpt-4o=2.5/10".
+var defaultLLMPrices = map[string]llmPrice{
+	"gpt-3.5-turbo": {0.50, 1.50},
+	"gpt-4o":        {2.50, 10},
+	"gpt-4o-mini":   {0.15, 0.60},
+	"gpt-4.1":       {2, 8},
+	"gpt-4.1-mini":  {0.40, 1.60},
+	"gpt-4.1-nano":  {0.10, 0.40},
+	"gpt-4-turbo":   {10, 30},
+}
+
+// llmPrices loads LLM_PRICES once on top of the defaults
+var llmPrices = sync.OnceValue(func() map[string]llmPrice {
+	prices := make(map[string]llmPrice, len(defaultLLMPrices))
+	for model, price := range defaultLLMPrices {
+		prices[model] = price
+	}
+	for _, pair := range strings.Split(os.Getenv("LLM_PRICES"), ",") {
+		model, value, found := strings.Cut(strings.TrimSpace(pair), "=")
+		if !found {
+			continue
+		}
+		promptValue, completionValue, _ := strings.Cut(value, "/")
+		prompt, err1 := strconv.ParseFloat(strings.TrimSpace(promptValue), 64)
+		completion, err2 := strconv.ParseFloat(strings.TrimSpace(completionValue), 64)
+		if err1 != nil || err2 != nil || prompt < 0 || completion < 0 {
+			log.Printf("Invalid LLM_PRICES entry %q, ignoring it", pair)
+			continue
+		}
+		prices[strings.TrimSpace(model)] = llmPrice{prompt, completion}
+	}
+	return prices
+})
+
+// estimateLLMCost prices a call by the longest matching model prefix; unknown models cost 0
+func estimateLLMCost(model string, promptTokens, completionTokens int64) float64 {
+	var price llmPrice
+	matched := ""
+	for prefix, candidate := range llmPrices() {
+		if strings.HasPrefix(model, prefix) && len(prefix) > len(matched) {
+			price, matched = candidate, prefix
+		}
+	}
+	return (float64(promptTokens)*price.prompt + float64(completionTokens)*price.completion) / 1e6
+}
+
+// createCompletion calls the chat completions API within the concurrency limit and records the call's tokens, latency and outcome
+func (l *LLMService) createCompletion(ctx context.Context, purpose string, params openai.ChatCompletionNewParams) (*openai.ChatCompletion, error) {
+	// Calls turned away by the limiter never reached OpenAI, so they are not recorded
+	release, err := l.limiter.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	start := time.Now()
+	completion, err := l.client.Chat.Completions.New(ctx, params)
+
+	call := &LLMCall{
+		Purpose:   purpose,
+		Model:     params.Model,
+		LatencyMs: time.Since(start).Milliseconds(),
+		CreatedAt: start,
+	}
+	if err != nil {
+		call.Error = err.Error()
+	} else {
+		call.PromptTokens = completion.Usage.PromptTokens
+		call.CompletionTokens = completion.Usage.CompletionTokens
+		call.CostUSD = estimateLLMCost(completion.Model, call.PromptTokens, call.CompletionTokens)
+	}
+	if trace := chatTraceFrom(ctx); trace != nil {
+		call.RequestID = trace.RequestID
+		trace.addCall(call)
+	}
+	// Recorded even if the request was cancelled, since the tokens were still spent
+	if _, insertErr := l.portfolioService.llmCalls.InsertOne(context.Background(), call); insertErr != nil {
+		log.Printf("Error recording LLM call: %v", insertErr)
+	}
+	return completion, err
+}
+
+// LLMUsage totals the LLM calls over a period
+type LLMUsage struct {
+	Calls            int64   `bson:"calls" json:"calls"`
+	Failures         int64   `bson:"failures" json:"failures"`
+	PromptTokens     int64   `bson:"prompt_tokens" json:"prompt_tokens"`
+	CompletionTokens int64   `bson:"completion_tokens" json:"completion_tokens"`
+	CostUSD          float64 `bson:"cost_usd" json:"cost_usd"`
+}
+
+// GetLLMUsage totals the LLM calls made since the given time
+func (ps *PortfolioService) GetLLMUsage(ctx context.Context, since time.Time) (LLMUsage, error) {
+	pipeline := []bson.M{
+		{"$match": bson.M{"created_at": bson.M{"$gte": since}}},
+		{"$group": bson.M{
+			"_id":               nil,
+			"calls":             bson.M{"$sum": 1},
+			"failures":          bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$gt": bson.A{"$error", nil}}, 1, 0}}},
+			"prompt_tokens":     bson.M{"$sum": "$prompt_tokens"},
+			"completion_tokens": bson.M{"$sum": "$completion_tokens"},
+			"cost_usd":          bson.M{"$sum": "$cost_usd"},
+		}},
+	}
+	cursor, err := ps.llmCalls.Aggregate(ctx, pipeline, timedAggregate())
+	if err != nil {
+		return LLMUsage{}, err
+	}
+	var totals []LLMUsage
+	if err := cursor.All(ctx, &totals); err != nil {
+		return LLMUsage{}, err
+	}
+	if len(totals) == 0 {
+		return LLMUsage{}, nil
+	}
+	return totals[0], nil
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// shedRetryAfter is the Retry-After sent with a shed request, in seconds
+	shedRetryAfter = 10
+	// shedLatencyWeight is the weight of each new request in the moving average latency
+	shedLatencyWeight = 0.1
+	// shedLatencyMaxAge is how long the average stays meaningful without new requests; an idle server is
+	// never overloaded
+	shedLatencyMaxAge = 10 * time.Second
+)
+
+// lowPriorityRoutes are shed first under overload: searching and analytics ingestion can be retried or
+// lost without visitors noticing, unlike page reads
+var lowPriorityRoutes = []string{"/api/search", "/api/analytics/event"}
+
+// shedLatencyExcludedPrefixes are slow by design (LLM calls, exports, long admin jobs), so their latency
+// says nothing about overload
+var shedLatencyExcludedPrefixes = []string{"/api/chatbot", "/api/tools/", "/api/admin/", "/healthz"}
+
+// shedLatencyExcludedSuffixes catch the same slow routes under an author scope, e.g. /api/{author}/chatbot
+var shedLatencyExcludedSuffixes = []string{"/chatbot"}
+
+// LoadShedder tracks the requests in flight and a moving average of their latency on this replica, and
+// rejects low-priority requests with 503 while either is over its threshold
+type LoadShedder struct {
+	maxInFlight int64
+	maxLatency  time.Duration
+	inFlight    atomic.Int64
+	shedding    atomic.Bool
+
+	mutex     sync.Mutex
+	latencyMs float64
+	updatedAt time.Time
+}
+
+// NewLoadShedder creates the shedder from SHED_MAX_IN_FLIGHT (default 100) and SHED_MAX_LATENCY (2s);
+// 0 disables a threshold
+func NewLoadShedder() *LoadShedder {
+	shedder := &LoadShedder{maxInFlight: 100, maxLatency: 2 * time.Second}
+	if value := os.Getenv("SHED_MAX_IN_FLIGHT"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || parsed < 0 {
+			log.Printf("Invalid SHED_MAX_IN_FLIGHT %q, using %d", value, shedder.maxInFlight)
+		} else {
+			shedder.maxInFlight = parsed
+		}
+	}
+	if value := os.Getenv("SHED_MAX_LATENCY"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed < 0 {
+			log.Printf("Invalid SHED_MAX_LATENCY %q, using %s", value, shedder.maxLatency)
+		} else {
+			shedder.maxLatency = parsed
+		}
+	}
+	return shedder
+}
+
+// isLowPriority reports whether a request is shed under overload, including author-scoped searches
+func isLowPriority(r *http.Request) bool {
+	for _, route := range lowPriorityRoutes {
+		if r.URL.Path == route {
+			return true
+		}
+	}
+	return strings.HasPrefix(r.URL.Path, "/api/") && strings.HasSuffix(r.URL.Path, "/search")
+}
+
+// overloaded reports whether the in-flight or latency threshold is exceeded
+func (s *LoadShedder) overloaded() (bool, int64, float64) {
+	inFlight := s.inFlight.Load()
+	s.mutex.Lock()
+	latencyMs := s.latencyMs
+	if time.Since(s.updatedAt) > shedLatencyMaxAge {
+		latencyMs = 0
+	}
+	s.mutex.Unlock()
+
+	over := (s.maxInFlight > 0 && inFlight > s.maxInFlight) ||
+		(s.maxLatency > 0 && latencyMs > float64(s.maxLatency.Milliseconds()))
+	return over, inFlight, latencyMs
+}
+
+// observe adds a finished request's latency to the moving average
+func (s *LoadShedder) observe(elapsed time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	ms := float64(elapsed.Milliseconds())
+	if time.Since(s.updatedAt) > shedLatencyMaxAge {
+		s.latencyMs = ms
+	} else {
+		s.latencyMs += shedLatencyWeight * (ms - s.latencyMs)
+	}
+	s.updatedAt = time.Now()
+}
+
+// Middleware sheds low-priority requests under overload; health checks and reads are always served
+func (s *LoadShedder) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isLowPriority(r) {
+			over, inFlight, latencyMs := s.overloaded()
+			if s.shedding.Swap(over) != over {
+				if over {
+					log.Printf("Overloaded (%d requests in flight, %.0fms average latency), shedding low-priority requests", inFlight, latencyMs)
+				} else {
+					log.Printf("Load back to normal, no longer shedding requests")
+				}
+			}
+			if over {
+				w.Header().Set("Retry-After", strconv.Itoa(shedRetryAfter))
+				http.Error(w, "Server is busy, please retry shortly", http.StatusServiceUnavailable)
+				return
+			}
+		}
+
+		// An event stream stays open for as long as the page does, idle most of the time
+		if r.URL.Path == "/api/events" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		s.inFlight.Add(1)
+		defer s.inFlight.Add(-1)
+		start := time.Now()
+		next.ServeHTTP(w, r)
+
+		for _, prefix := range shedLatencyExcludedPrefixes {
+			if strings.HasPrefix(r.URL.Path, prefix) {
+				return
+			}
+		}
+		for _, suffix := range shedLatencyExcludedSuffixes {
+			if strings.HasSuffix(strings.TrimSuffix(r.URL.Path, "/"), suffix) {
+				return
+			}
+		}
+		if !wantsNDJSON(r) {
+			s.observe(time.Since(start))
+		}
+	})
+}
+
+// Health endpoint: GET /healthz answers as long as the process serves requests; it never touches MongoDB
+// and is never shed
+func handleHealthz(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Write([]byte("ok\n"))
+}
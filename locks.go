@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// instanceID identifies this replica in claims and logs
+var instanceID = func() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}()
+
+// ClaimScheduledRun claims one occurrence of a scheduled task for this replica. Claims are keyed by task and
+// occurrence time, so only the first replica to insert one gets true; the others skip the run.
+func (ps *PortfolioService) ClaimScheduledRun(ctx context.Context, task string, occurrence time.Time) (bool, error) {
+	_, err := ps.scheduleClaims.InsertOne(ctx, bson.M{
+		"_id":        fmt.Sprintf("%s@%d", task, occurrence.Unix()),
+		"task":       task,
+		"occurrence": occurrence,
+		"instance":   instanceID,
+		"claimed_at": time.Now(),
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -3,23 +3,30 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net"
 	"net/http"
 	"os"
+	"os/signal"
 	"regexp"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/openai/openai-go"
 	"github.com/openai/openai-go/option"
+	"github.com/openai/openai-go/shared"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"golang.org/x/sync/errgroup"
 )
 
 // Helper function
@@ -30,209 +37,525 @@ func min(a, b int) int {
 	return b
 }
 
+var slugInvalidChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// slugify converts a name into a URL-friendly slug
+func slugify(name string) string {
+	return strings.Trim(slugInvalidChars.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
+
 type Author struct {
 	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
 	Name        string             `bson:"name" json:"name"`
 	JobTitle    string             `bson:"job_title" json:"job_title"`
-	Email       string             `bson:"email" json:"email"`
+	Email       EncryptedString    `bson:"email" json:"email"` // Encrypted at rest, masked for unauthenticated clients
 	LinkedinURL string             `bson:"linkedin_url" json:"linkedin_url"`
 	GithubURL   string             `bson:"github_url" json:"github_url"`
 	Hobbies     []string           `bson:"hobbies" json:"hobbies"`
+	Pronouns    string             `bson:"pronouns,omitempty" json:"pronouns,omitempty"`   // e.g. "she/her", used by the chatbot
+	BotName     string             `bson:"bot_name,omitempty" json:"bot_name,omitempty"`   // Chatbot name; defaults to the first name + "BOT"
+	ChatTone    string             `bson:"chat_tone,omitempty" json:"chat_tone,omitempty"` // Tone hints for the chatbot, e.g. "warm and concise"
+	// Contact details the author publishes: "email" and "phone" are shown to everyone instead of masked
+	PublicContact []string `bson:"public_contact,omitempty" json:"public_contact,omitempty"`
+	Timestamps    `bson:",inline"`
 }
 
 // Project represents a project in the database
 type Project struct {
 	ID               primitive.ObjectID `bson:"_id,omitempty" json:"id"`
 	Name             string             `bson:"name" json:"name"`
+	Slug             string             `bson:"slug,omitempty" json:"slug,omitempty"`
 	Category         string             `bson:"category" json:"category"`
 	StartDate        time.Time          `bson:"start_date" json:"start_date"`
 	EndDate          *time.Time         `bson:"end_date,omitempty" json:"end_date,omitempty"` // Pointer for nullable field
 	Description      string             `bson:"description" json:"description"`
 	AuthorID         primitive.ObjectID `bson:"author_id" json:"author_id"`
 	TechnologiesUsed []string           `bson:"technologies_used" json:"technologies_used"`
-	RepoURL          *string            `bson:"repo_url,omitempty" json:"repo_url,omitempty"` // Pointer for nullable field
+	RepoURL          *string            `bson:"repo_url,omitempty" json:"repo_url,omitempty"`                 // Pointer for nullable field
+	Repo             *RepoMetadata      `bson:"repo,omitempty" json:"repo,omitempty"`                         // Synced from the code host
+	Thumbnail        string             `bson:"thumbnail,omitempty" json:"thumbnail,omitempty"`               // URL of the project's cover image
+	Visibility       string             `bson:"visibility,omitempty" json:"visibility,omitempty"`             // public (default), unlisted or private
+	Archived         bool               `bson:"archived,omitempty" json:"archived,omitempty"`                 // Past work, no longer maintained
+	ArchivedAt       *time.Time         `bson:"archived_at,omitempty" json:"archived_at,omitempty"`           // When it was archived
+	NameI18n         map[string]string  `bson:"name_i18n,omitempty" json:"name_i18n,omitempty"`               // Translations keyed by locale, e.g. "fr"
+	DescriptionI18n  map[string]string  `bson:"description_i18n,omitempty" json:"description_i18n,omitempty"` // Translations keyed by locale
+	DescriptionHTML  string             `bson:"-" json:"description_html,omitempty"`                          // Only with ?render=html
+	ReadingStats     `bson:",inline"`
+	Pinning          `bson:",inline"`
+	Timestamps       `bson:",inline"`
 }
 
-// Contact represents contact information
+// RepoMetadata holds repository details synced from the code host
+type RepoMetadata struct {
+	Provider      string     `bson:"provider,omitempty" json:"provider,omitempty"`
+	FullName      string     `bson:"full_name" json:"full_name"`
+	DefaultBranch string     `bson:"default_branch" json:"default_branch"`
+	LastPushedAt  *time.Time `bson:"last_pushed_at,omitempty" json:"last_pushed_at,omitempty"`
+	LatestRelease string     `bson:"latest_release,omitempty" json:"latest_release,omitempty"`
+	Stars         int        `bson:"stars" json:"stars"`
+	Language      string     `bson:"language,omitempty" json:"language,omitempty"`
+	Topics        []string   `bson:"topics,omitempty" json:"topics,omitempty"`
+	LastCommitAt  *time.Time `bson:"last_commit_at,omitempty" json:"last_commit_at,omitempty"`
+	SyncedAt      time.Time  `bson:"synced_at" json:"synced_at"`
+}
+
+// Contact represents contact information; both fields are encrypted at rest and masked for unauthenticated clients
 type Contact struct {
-	Phone string `bson:"phone" json:"phone"`
-	Email string `bson:"email" json:"email"`
+	Phone EncryptedString `bson:"phone" json:"phone"`
+	Email EncryptedString `bson:"email" json:"email"`
 }
 
 // Experience represents work experience
 type Experience struct {
-	JobTitle    string    `bson:"job_title" json:"job_title"`
-	Company     string    `bson:"company" json:"company"`
-	TimePresent int       `bson:"time_present" json:"time_present"` // in months
-	Projects    []Project `bson:"projects" json:"projects"`
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	AuthorID     primitive.ObjectID `bson:"author_id,omitempty" json:"author_id"`
+	JobTitle     string             `bson:"job_title" json:"job_title"`
+	Company      string             `bson:"company" json:"company"`
+	Location     string             `bson:"location,omitempty" json:"location,omitempty"`
+	StartDate    *time.Time         `bson:"start_date,omitempty" json:"start_date,omitempty"` // Unknown for positions imported without dates
+	EndDate      *time.Time         `bson:"end_date,omitempty" json:"end_date,omitempty"`     // Nil for the current position
+	Description  string             `bson:"description,omitempty" json:"description,omitempty"`
+	Technologies []string           `bson:"technologies,omitempty" json:"technologies,omitempty"`
+	TimePresent  int                `bson:"time_present" json:"time_present"` // in months, derived from the dates when known
+	Projects     []Project          `bson:"projects" json:"projects"`
+	Timestamps   `bson:",inline"`
 }
 
 // Education represents educational background
 type Education struct {
-	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	UniversityName string             `bson:"university_name" json:"university_name"`
-	Major          string             `bson:"major" json:"major"`
-	StartDate      time.Time          `bson:"start_date" json:"start_date"`
-	EndDate        *time.Time         `bson:"end_date,omitempty" json:"end_date,omitempty"` // Pointer for nullable field
-	Description    string             `bson:"description" json:"description"`
-	StudentName    string             `bson:"student_name" json:"student_name"`
-	StudentID      primitive.ObjectID `bson:"student_id" json:"student_id"`
+	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UniversityName  string             `bson:"university_name" json:"university_name"`
+	Major           string             `bson:"major" json:"major"` // Degree and field of study; also accepted as field_of_study
+	StartDate       time.Time          `bson:"start_date" json:"start_date"`
+	EndDate         *time.Time         `bson:"end_date,omitempty" json:"end_date,omitempty"` // Pointer for nullable field
+	Description     string             `bson:"description" json:"description"`
+	StudentName     string             `bson:"student_name" json:"student_name"`
+	StudentID       primitive.ObjectID `bson:"student_id" json:"student_id"`
+	GPA             string             `bson:"gpa,omitempty" json:"gpa,omitempty"`                                 // As printed on the transcript, e.g. "3.8/4.0" or "1.3"
+	Honors          string             `bson:"honors,omitempty" json:"honors,omitempty"`                           // e.g. "magna cum laude", "First Class Honours"
+	Coursework      []string           `bson:"relevant_coursework,omitempty" json:"relevant_coursework,omitempty"` // Courses worth mentioning, not the whole transcript
+	Thesis          string             `bson:"thesis,omitempty" json:"thesis,omitempty"`                           // Thesis title
+	MajorI18n       map[string]string  `bson:"major_i18n,omitempty" json:"major_i18n,omitempty"`                   // Translations keyed by locale, e.g. "fr"
+	DescriptionI18n map[string]string  `bson:"description_i18n,omitempty" json:"description_i18n,omitempty"`       // Translations keyed by locale
+	Timestamps      `bson:",inline"`
 }
 
 // Resume represents a complete resume
 type Resume struct {
+	ID            primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
+	Contact       Contact              `bson:"contact" json:"contact"`
+	Experience    []Experience         `bson:"experience,omitempty" json:"experience"`                   // Assembled from ExperienceIDs on reads; embedded only in resumes not yet migrated
+	ExperienceIDs []primitive.ObjectID `bson:"experience_ids,omitempty" json:"experience_ids,omitempty"` // References into the experience collection, in resume order
+	Skills        []string             `bson:"skills" json:"skills"`
+	Education     []Education          `bson:"education" json:"education"`
+	AuthorID      primitive.ObjectID   `bson:"author_id" json:"author_id"`
+	AuthorName    string               `bson:"author_name" json:"author_name"`
+	Timestamps    `bson:",inline"`
+}
+
+// Certification represents a certification or credential badge
+type Certification struct {
+	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name            string             `bson:"name" json:"name"`
+	Issuer          string             `bson:"issuer" json:"issuer"`
+	IssuedAt        time.Time          `bson:"issued_at" json:"issued_at"`
+	ExpiresAt       *time.Time         `bson:"expires_at,omitempty" json:"expires_at,omitempty"` // Pointer for nullable field
+	Description     string             `bson:"description" json:"description"`
+	Skills          []string           `bson:"skills" json:"skills"`
+	BadgeImageURL   string             `bson:"badge_image_url" json:"badge_image_url"`
+	VerificationURL string             `bson:"verification_url" json:"verification_url"`
+	Source          string             `bson:"source" json:"source"`                               // "credly" or "manual"
+	ExternalID      string             `bson:"external_id,omitempty" json:"external_id,omitempty"` // Badge ID at the source
+	AuthorID        primitive.ObjectID `bson:"author_id" json:"author_id"`
+	Timestamps      `bson:",inline"`
+}
+
+// Post represents a blog post written in markdown
+type Post struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Title        string             `bson:"title" json:"title"`
+	Slug         string             `bson:"slug" json:"slug"`
+	Summary      string             `bson:"summary,omitempty" json:"summary,omitempty"`
+	SummaryHTML  string             `bson:"-" json:"summary_html,omitempty"`            // Only with ?render=html
+	Body         string             `bson:"body" json:"body"`                           // Markdown source
+	BodyHTML     string             `bson:"body_html" json:"body_html"`                 // Sanitized HTML rendered on write
+	Tags         []string           `bson:"tags" json:"tags"`                           // Lowercase
+	PublishedAt  *time.Time         `bson:"published_at,omitempty" json:"published_at"` // Pointer for nullable field
+	Draft        bool               `bson:"draft" json:"draft"`
+	AuthorID     primitive.ObjectID `bson:"author_id" json:"author_id"`
+	ReadingStats `bson:",inline"`
+	Pinning      `bson:",inline"`
+	Timestamps   `bson:",inline"`
+}
+
+// Testimonial represents a recommendation from a colleague or client. Public submissions
+// stay hidden until approved by an admin.
+type Testimonial struct {
+	ID           primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	Author       string              `bson:"author" json:"author"`             // Person giving the testimonial
+	Relationship string              `bson:"relationship" json:"relationship"` // e.g. "Manager at Acme"
+	Quote        string              `bson:"quote" json:"quote"`
+	Link         string              `bson:"link,omitempty" json:"link,omitempty"` // e.g. LinkedIn recommendation URL
+	Approved     bool                `bson:"approved" json:"approved"`
+	SubmittedAt  time.Time           `bson:"submitted_at" json:"submitted_at"`
+	ApprovedAt   *time.Time          `bson:"approved_at,omitempty" json:"approved_at,omitempty"` // Pointer for nullable field
+	AuthorID     *primitive.ObjectID `bson:"author_id,omitempty" json:"author_id,omitempty"`     // Portfolio the testimonial is for
+	Timestamps   `bson:",inline"`
+}
+
+// Award represents a hackathon win, honor or other recognition
+type Award struct {
+	ID          primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	Title       string              `bson:"title" json:"title"`
+	Issuer      string              `bson:"issuer" json:"issuer"`     // Organization or event granting the award
+	Category    string              `bson:"category" json:"category"` // e.g. "hackathon", "honor", "scholarship"
+	AwardedAt   time.Time           `bson:"awarded_at" json:"awarded_at"`
+	Description string              `bson:"description" json:"description"`
+	URL         string              `bson:"url,omitempty" json:"url,omitempty"`
+	ProjectID   *primitive.ObjectID `bson:"project_id,omitempty" json:"project_id,omitempty"` // Winning project, if any
+	AuthorID    primitive.ObjectID  `bson:"author_id" json:"author_id"`
+	Timestamps  `bson:",inline"`
+}
+
+// Publication represents a paper, article or book chapter
+type Publication struct {
 	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	Contact    Contact            `bson:"contact" json:"contact"`
-	Experience []Experience       `bson:"experience" json:"experience"`
-	Skills     []string           `bson:"skills" json:"skills"`
-	Education  []Education        `bson:"education" json:"education"`
+	Title      string             `bson:"title" json:"title"`
+	Venue      string             `bson:"venue" json:"venue"` // Journal, conference or publisher
+	Year       int                `bson:"year" json:"year"`
+	Coauthors  []string           `bson:"coauthors,omitempty" json:"coauthors,omitempty"`
+	DOI        string             `bson:"doi,omitempty" json:"doi,omitempty"`
+	URL        string             `bson:"url,omitempty" json:"url,omitempty"`
+	Abstract   string             `bson:"abstract,omitempty" json:"abstract,omitempty"`
 	AuthorID   primitive.ObjectID `bson:"author_id" json:"author_id"`
-	AuthorName string             `bson:"author_name" json:"author_name"`
+	Timestamps `bson:",inline"`
 }
 
-type APIHandler struct {
-	service     *PortfolioService
-	llmService  *LLMService
-	rateLimiter *RateLimiter
+// Talk represents a conference or meetup presentation
+type Talk struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Title       string             `bson:"title" json:"title"`
+	Event       string             `bson:"event" json:"event"`
+	Location    string             `bson:"location,omitempty" json:"location,omitempty"`
+	Date        time.Time          `bson:"date" json:"date"`
+	Description string             `bson:"description,omitempty" json:"description,omitempty"`
+	SlidesURL   string             `bson:"slides_url,omitempty" json:"slides_url,omitempty"`
+	VideoURL    string             `bson:"video_url,omitempty" json:"video_url,omitempty"`
+	AuthorID    primitive.ObjectID `bson:"author_id" json:"author_id"`
+	Timestamps  `bson:",inline"`
 }
 
-// Rate limiting structures
-type RateLimiter struct {
-	clients map[string]*ClientLimiter
-	mutex   sync.RWMutex
+// Skill represents a skill with its proficiency and the projects that demonstrate it
+type Skill struct {
+	ID          primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
+	Name        string               `bson:"name" json:"name"`
+	Category    string               `bson:"category" json:"category"`       // e.g. "Languages", "Frameworks", "Tools"
+	Proficiency string               `bson:"proficiency" json:"proficiency"` // beginner, intermediate, advanced or expert
+	Years       float64              `bson:"years" json:"years"`
+	ProjectIDs  []primitive.ObjectID `bson:"project_ids" json:"project_ids"`
+	AuthorID    primitive.ObjectID   `bson:"author_id" json:"author_id"`
+	// Verifications link the skill to the projects, certifications and repositories showing it
+	Verifications []SkillVerification `bson:"verifications,omitempty" json:"verifications,omitempty"`
+	Evidence      []SkillEvidence     `bson:"-" json:"evidence,omitempty"` // Resolved evidence, only from /api/skills/evidence and in chat context
+	Timestamps    `bson:",inline"`
 }
 
-type ClientLimiter struct {
-	requests  []time.Time
-	lastReset time.Time
+// Media represents an uploaded project image; the file content is kept in a MediaStore under the same ID
+type Media struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ProjectID   primitive.ObjectID `bson:"project_id" json:"project_id"`
+	Filename    string             `bson:"filename" json:"filename"`
+	ContentType string             `bson:"content_type" json:"content_type"`
+	Size        int64              `bson:"size" json:"size"`
+	Width       int                `bson:"width" json:"width"`
+	Height      int                `bson:"height" json:"height"`
+	Caption     string             `bson:"caption,omitempty" json:"caption,omitempty"`
+	Storage     string             `bson:"storage,omitempty" json:"storage,omitempty"` // MediaStore holding the content; empty means GridFS
+	URL         string             `bson:"-" json:"url"`                               // Derived when returned from the API
+	UploadedAt  time.Time          `bson:"uploaded_at" json:"uploaded_at"`
 }
 
-// NewRateLimiter creates a new rate limiter
-func NewRateLimiter() *RateLimiter {
-	return &RateLimiter{
-		clients: make(map[string]*ClientLimiter),
-	}
+// Now describes what the author is focused on at the moment, in the spirit of a /now page
+type Now struct {
+	ID               primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Focus            string             `bson:"focus" json:"focus"` // Current role focus
+	Learning         []string           `bson:"learning" json:"learning"`
+	Reading          []string           `bson:"reading" json:"reading"`
+	Availability     string             `bson:"availability" json:"availability"` // available, limited or unavailable
+	AvailabilityNote string             `bson:"availability_note,omitempty" json:"availability_note,omitempty"`
+	UpdatedAt        time.Time          `bson:"updated_at" json:"updated_at"`
+	AuthorID         primitive.ObjectID `bson:"author_id" json:"author_id"`
 }
 
-// IsAllowed checks if a client is allowed to make a request
-func (rl *RateLimiter) IsAllowed(clientIP string) bool {
-	rl.mutex.Lock()
-	defer rl.mutex.Unlock()
+// AnalyticsEvent is a single visitor interaction. Only the anonymized IP and a daily visitor hash are
+// stored, never the full address.
+type AnalyticsEvent struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Type           string             `bson:"type" json:"type"` // page_view, project_click or chat_open
+	Path           string             `bson:"path" json:"path"`
+	ProjectSlug    string             `bson:"project_slug,omitempty" json:"project_slug,omitempty"`
+	Referrer       string             `bson:"referrer,omitempty" json:"referrer,omitempty"`
+	ReferrerDomain string             `bson:"referrer_domain,omitempty" json:"referrer_domain,omitempty"` // Without "www.", empty for direct visits
+	Country        string             `bson:"country,omitempty" json:"country,omitempty"`                 // ISO 3166-1 alpha-2, when known
+	IP             string             `bson:"ip" json:"-"`                                                // Anonymized: last IPv4 octet or last 80 IPv6 bits zeroed
+	VisitorHash    string             `bson:"visitor_hash" json:"-"`                                      // Rotates daily, for counting unique visitors
+	CreatedAt      time.Time          `bson:"created_at" json:"created_at"`
+}
 
-	now := time.Now()
+// PromptVariant is one arm of a prompt experiment. Empty fields fall back to the default prompt and model.
+type PromptVariant struct {
+	Name         string `bson:"name" json:"name"`
+	Model        string `bson:"model,omitempty" json:"model,omitempty"`
+	Instructions string `bson:"instructions,omitempty" json:"instructions,omitempty"` // Extra instructions appended to the prompt, one per line
+	Weight       int    `bson:"weight" json:"weight"`                                 // Relative share of sessions assigned to the variant
+}
 
-	// Get or create client limiter
-	client, exists := rl.clients[clientIP]
-	if !exists {
-		client = &ClientLimiter{
-			requests:  []time.Time{},
-			lastReset: now,
-		}
-		rl.clients[clientIP] = client
-	}
+// Experiment splits chat sessions between prompt variants. At most one experiment is active at a time.
+type Experiment struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name        string             `bson:"name" json:"name"`
+	Description string             `bson:"description,omitempty" json:"description,omitempty"`
+	Variants    []PromptVariant    `bson:"variants" json:"variants"`
+	Active      bool               `bson:"active" json:"active"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+}
 
-	// Clean old requests (older than 5 minutes)
-	fiveMinutesAgo := now.Add(-5 * time.Minute)
-	oneMinuteAgo := now.Add(-1 * time.Minute)
+// ChatTurn records a chatbot answer with the variant that produced it, its latency and the visitor's feedback
+type ChatTurn struct {
+	ID           primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	SessionID    string              `bson:"session_id" json:"session_id"`
+	ExperimentID *primitive.ObjectID `bson:"experiment_id,omitempty" json:"experiment_id,omitempty"`
+	Variant      string              `bson:"variant,omitempty" json:"variant,omitempty"`
+	Style        string              `bson:"style,omitempty" json:"style,omitempty"` // The response style asked for
+	Model        string              `bson:"model" json:"model"`
+	LatencyMs    int64               `bson:"latency_ms" json:"latency_ms"`
+	Feedback     string              `bson:"feedback,omitempty" json:"feedback,omitempty"`     // up or down
+	Confidence   string              `bson:"confidence,omitempty" json:"confidence,omitempty"` // As rated by the model: high, medium, low or none
+	CreatedAt    time.Time           `bson:"created_at" json:"created_at"`
+
+	// The conversation and its grade are only stored for turns sampled for quality scoring
+	Question  string         `bson:"question,omitempty" json:"question,omitempty"`
+	Answer    string         `bson:"answer,omitempty" json:"answer,omitempty"`
+	Judgement *ChatJudgement `bson:"judgement,omitempty" json:"judgement,omitempty"`
+	Flagged   bool           `bson:"flagged" json:"flagged"` // Scored below CHAT_JUDGE_MIN_SCORE
+
+	Report *ChatReport `bson:"report,omitempty" json:"report,omitempty"` // Set when the visitor reported the answer
+}
 
-	// Filter out old requests
-	validRequests := []time.Time{}
-	for _, reqTime := range client.requests {
-		if reqTime.After(fiveMinutesAgo) {
-			validRequests = append(validRequests, reqTime)
-		}
-	}
-	client.requests = validRequests
+// ChatJudgement is an LLM grade of how well a chatbot answer is grounded in the portfolio context it was given
+type ChatJudgement struct {
+	Score       int       `bson:"score" json:"score"` // 1 (unsupported) to 5 (fully grounded)
+	Reason      string    `bson:"reason" json:"reason"`
+	Unsupported []string  `bson:"unsupported,omitempty" json:"unsupported,omitempty"` // Claims not backed by the context
+	Model       string    `bson:"model" json:"model"`
+	JudgedAt    time.Time `bson:"judged_at" json:"judged_at"`
+}
 
-	// Count recent requests
-	recentRequests := 0
-	for _, reqTime := range client.requests {
-		if reqTime.After(oneMinuteAgo) {
-			recentRequests++
-		}
-	}
+// EvalCase is a golden question about the portfolio with the facts a correct answer must contain
+type EvalCase struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Question      string             `bson:"question" json:"question"`
+	ExpectedFacts []string           `bson:"expected_facts" json:"expected_facts"`
+	Tags          []string           `bson:"tags,omitempty" json:"tags,omitempty"`
+	CreatedAt     time.Time          `bson:"created_at" json:"created_at"`
+}
 
-	// Rate limits: 3 per minute, 10 per 5 minutes
-	if recentRequests >= 3 || len(client.requests) >= 10 {
-		return false
-	}
+// EvalFactCheck records whether an answer contained an expected fact
+type EvalFactCheck struct {
+	Fact        string `bson:"fact" json:"fact"`
+	StringMatch bool   `bson:"string_match" json:"string_match"`
+	JudgeMatch  *bool  `bson:"judge_match,omitempty" json:"judge_match,omitempty"` // Set when the run used the LLM judge
+}
 
-	// Add current request
-	client.requests = append(client.requests, now)
-	return true
+// EvalResult is the answer to one eval case and how it scored
+type EvalResult struct {
+	CaseID   primitive.ObjectID `bson:"case_id" json:"case_id"`
+	Question string             `bson:"question" json:"question"`
+	Answer   string             `bson:"answer" json:"answer"`
+	Facts    []EvalFactCheck    `bson:"facts" json:"facts"`
+	Score    float64            `bson:"score" json:"score"` // Share of expected facts found
+	Error    string             `bson:"error,omitempty" json:"error,omitempty"`
 }
 
-// Clean up old client records periodically
-func (rl *RateLimiter) Cleanup() {
-	rl.mutex.Lock()
-	defer rl.mutex.Unlock()
+// EvalRun is one run of every eval case against a prompt and model
+type EvalRun struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Model      string             `bson:"model" json:"model"`
+	Variant    *PromptVariant     `bson:"variant,omitempty" json:"variant,omitempty"`
+	Judge      bool               `bson:"judge" json:"judge"`
+	Results    []EvalResult       `bson:"results" json:"results"`
+	Score      float64            `bson:"score" json:"score"` // Mean case score
+	StartedAt  time.Time          `bson:"started_at" json:"started_at"`
+	FinishedAt time.Time          `bson:"finished_at" json:"finished_at"`
+}
 
-	now := time.Now()
-	fiveMinutesAgo := now.Add(-5 * time.Minute)
+// Domain maps a host name to the author whose portfolio it serves
+type Domain struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Host      string             `bson:"host" json:"host"` // Lowercase, without port, e.g. alice.example.com
+	AuthorID  primitive.ObjectID `bson:"author_id" json:"author_id"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
 
-	for ip, client := range rl.clients {
-		if client.lastReset.Before(fiveMinutesAgo) && len(client.requests) == 0 {
-			delete(rl.clients, ip)
-		}
-	}
+type APIHandler struct {
+	service          *PortfolioService
+	llmService       *LLMService
+	ipRules          *IPRules
+	autoBanner       *AutoBanner
+	rateLimiter      *RateLimiter
+	chatIPLimiter    *RateLimiter // Caps all the visitors of one IP together, however many tokens they hold
+	statsLimiter     *RateLimiter
+	submitLimiter    *RateLimiter
+	toolsLimiter     *RateLimiter
+	analyticsLimiter *RateLimiter
+	ogImageCache     *TTLCache[[]byte]
+	mediaResizeCache *TTLCache[*resizedMedia]
+	toolsCache       *TTLCache[[]InterviewQuestion]
+	githubActivity   *GitHubActivityService
+	codingStats      *WakaTimeService
+	profileStats     *ProfileStatsService
+	availability     *AvailabilityService
+	geoIP            *GeoIPDatabase
+	metrics          *RequestMetrics
+	chatJudge        *ChatJudgeConfig
+	domainCache      *TTLCache[*Author]
+	locales          *Locales
+	retention        *RetentionPurger
+	jobs             *JobQueue
+	scheduler        *Scheduler
+	snapshots        *ReadSnapshots
+	events           *EventHub
+	visitors         *VisitorTokens
 }
 
-// Input validation
-func validateChatbotInput(input string) error {
-	// Length check
-	if len(input) > 500 {
-		return fmt.Errorf("input too long (max 500 characters)")
+// RateLimiter limits requests per client; counts live in the state store so limits hold across replicas.
+// Whitelisted IPs are never limited.
+type RateLimiter struct {
+	store         StateStore
+	rules         *IPRules
+	name          string
+	perMinute     int
+	perFiveMinute int
+}
+
+// NewRateLimiter creates a new rate limiter with the chatbot limits
+func NewRateLimiter(store StateStore, rules *IPRules, name string) *RateLimiter {
+	return NewRateLimiterWithLimits(store, rules, name, 3, 10)
+}
+
+// NewRateLimiterWithLimits creates a rate limiter allowing perMinute requests per minute
+// and perFiveMinute requests per 5 minutes for each client
+func NewRateLimiterWithLimits(store StateStore, rules *IPRules, name string, perMinute, perFiveMinute int) *RateLimiter {
+	return &RateLimiter{
+		store:         store,
+		rules:         rules,
+		name:          name,
+		perMinute:     perMinute,
+		perFiveMinute: perFiveMinute,
 	}
+}
+
+// IsAllowed checks if a client is allowed to make a request. Requests are allowed if the store fails,
+// so an outage does not lock every visitor out. clientIP must come from trustedClientIP, since a forged
+// X-Forwarded-For could otherwise claim a whitelisted address or a fresh limit.
+func (rl *RateLimiter) IsAllowed(clientIP string) bool {
+	return rl.IsAllowedFor(clientIP, clientIP)
+}
 
-	if len(strings.TrimSpace(input)) == 0 {
-		return fmt.Errorf("input cannot be empty")
+// IsAllowedFor checks a request counted against key, such as a visitor ID, rather than the client's IP;
+// whitelisting still goes by the IP
+func (rl *RateLimiter) IsAllowedFor(clientIP, key string) bool {
+	if rl.rules.Action(clientIP) == IPRuleAllow {
+		return true
 	}
+	allowed, err := rl.store.Allow(context.Background(), rl.name+":"+key, []RateLimit{
+		{Window: time.Minute, Max: rl.perMinute},
+		{Window: 5 * time.Minute, Max: rl.perFiveMinute},
+	})
+	if err != nil {
+		log.Printf("Error checking %s rate limit: %v", rl.name, err)
+		return true
+	}
+	return allowed
+}
 
-	// Check for suspicious patterns
-	suspiciousPatterns := []*regexp.Regexp{
-		regexp.MustCompile(`(?i)(hack|exploit|attack|inject|<script|javascript:|data:|vbscript:)`), // Common attack terms
+// Input validation
+func validateChatbotInput(input, style string) error {
+	var v Validator
+	v.ChatInput("query", input, 500)
+	if style != "" {
+		v.OneOf("style", style, chatStyles...)
 	}
+	return v.Err()
+}
 
-	// Check for repeated characters manually (since Go regexp doesn't support backreferences)
-	for i := 0; i < len(input)-10; i++ {
-		char := input[i]
-		count := 1
-		for j := i + 1; j < len(input) && input[j] == char; j++ {
-			count++
+// trustedProxies loads TRUSTED_PROXIES once: the comma-separated IPs or CIDR ranges of the reverse proxies
+// whose X-Forwarded-For and X-Real-IP headers are believed
+var trustedProxies = sync.OnceValue(func() []*net.IPNet {
+	var networks []*net.IPNet
+	for _, value := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
 		}
-		if count > 10 {
-			return fmt.Errorf("invalid input detected")
+		if !strings.Contains(value, "/") {
+			if ip := net.ParseIP(value); ip != nil && ip.To4() != nil {
+				value += "/32"
+			} else {
+				value += "/128"
+			}
 		}
+		_, network, err := net.ParseCIDR(value)
+		if err != nil {
+			log.Printf("Invalid TRUSTED_PROXIES entry %q, ignoring it", value)
+			continue
+		}
+		networks = append(networks, network)
+	}
+	if len(networks) == 0 {
+		log.Println("TRUSTED_PROXIES not set, bans go by the connecting address")
 	}
+	return networks
+})
 
-	for _, pattern := range suspiciousPatterns {
-		if pattern.MatchString(input) {
-			return fmt.Errorf("invalid input detected")
+// isTrustedProxy reports whether an address belongs to a configured trusted proxy
+func isTrustedProxy(address string) bool {
+	ip := net.ParseIP(strings.TrimSpace(address))
+	if ip == nil {
+		return false
+	}
+	for _, network := range trustedProxies() {
+		if network.Contains(ip) {
+			return true
 		}
 	}
-
-	return nil
+	return false
 }
 
-// Get client IP address
-func getClientIP(r *http.Request) string {
-	// Check X-Forwarded-For header
+// trustedClientIP returns the client IP for bans, which must not be forgeable: forwarded headers only count
+// when the connection comes from a trusted proxy, and the nearest X-Forwarded-For entry that is not itself a
+// trusted proxy is taken, since clients can prepend whatever they like.
+func trustedClientIP(r *http.Request) string {
+	remote, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		remote = r.RemoteAddr
+	}
+	if !isTrustedProxy(remote) {
+		return remote
+	}
+
 	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
 		parts := strings.Split(xff, ",")
-		return strings.TrimSpace(parts[0])
+		for i := len(parts) - 1; i >= 0; i-- {
+			if hop := strings.TrimSpace(parts[i]); hop != "" && !isTrustedProxy(hop) {
+				return hop
+			}
+		}
 	}
-
-	// Check X-Real-IP header
-	if xri := r.Header.Get("X-Real-IP"); xri != "" {
+	if xri := strings.TrimSpace(r.Header.Get("X-Real-IP")); xri != "" {
 		return xri
 	}
-
-	// Fall back to remote address
-	ip, _, err := net.SplitHostPort(r.RemoteAddr)
-	if err != nil {
-		return r.RemoteAddr
-	}
-	return ip
+	return remote
 }
 
 // Database connection
@@ -263,12 +586,47 @@ func connectToMongoDB() (*mongo.Client, error) {
 
 // PortfolioService handles all database operations
 type PortfolioService struct {
-	client    *mongo.Client
-	database  *mongo.Database
-	authors   *mongo.Collection
-	projects  *mongo.Collection
-	resumes   *mongo.Collection
-	education *mongo.Collection
+	client     *mongo.Client
+	database   *mongo.Database
+	authors    *mongo.Collection
+	projects   *mongo.Collection
+	resumes    *mongo.Collection
+	education  *mongo.Collection
+	experience *mongo.Collection
+
+	certifications *mongo.Collection
+	posts          *mongo.Collection
+	testimonials   *mongo.Collection
+	awards         *mongo.Collection
+	publications   *mongo.Collection
+	talks          *mongo.Collection
+	skills         *mongo.Collection
+	media          *mongo.Collection
+	now            *mongo.Collection
+	analytics      *mongo.Collection
+	experiments    *mongo.Collection
+	chatTurns      *mongo.Collection
+	evalCases      *mongo.Collection
+	evalRuns       *mongo.Collection
+	domains        *mongo.Collection
+
+	contextSummaries *mongo.Collection
+	jobs             *mongo.Collection
+	scheduleClaims   *mongo.Collection
+	llmCalls         *mongo.Collection
+	alerts           *mongo.Collection
+	chatTraces       *mongo.Collection
+	requestStats     *mongo.Collection
+	rateLimits       *mongo.Collection
+	ipRules          *mongo.Collection
+	auditLog         *mongo.Collection
+	apiTokens        *mongo.Collection
+	deletions        *mongo.Collection
+	settings         *mongo.Collection
+
+	mediaStores map[string]MediaStore // Every configured backend, for reading files uploaded before a switch
+	mediaStore  MediaStore            // Backend new uploads are written to
+	state       StateStore            // Caches and rate limits, shared between replicas when STATE_STORE=mongo
 }
 
 // NewPortfolioService creates a new portfolio service instance
@@ -280,19 +638,56 @@ func NewPortfolioService(client *mongo.Client) *PortfolioService {
 	}
 
 	db := client.Database(dbName)
+	mediaStores, mediaStore := newMediaStores(db)
+	state := newStateStore(db)
 	return &PortfolioService{
-		client:    client,
-		database:  db,
-		authors:   db.Collection("authors"),
-		projects:  db.Collection("projects"),
-		resumes:   db.Collection("resumes"),
-		education: db.Collection("education"),
+		client:     client,
+		database:   db,
+		authors:    db.Collection("authors"),
+		projects:   db.Collection("projects"),
+		resumes:    db.Collection("resumes"),
+		education:  db.Collection("education"),
+		experience: db.Collection("experience"),
+
+		certifications: db.Collection("certifications"),
+		posts:          db.Collection("posts"),
+		testimonials:   db.Collection("testimonials"),
+		awards:         db.Collection("awards"),
+		publications:   db.Collection("publications"),
+		talks:          db.Collection("talks"),
+		skills:         db.Collection("skills"),
+		media:          db.Collection("media"),
+		now:            db.Collection("now"),
+		analytics:      db.Collection("analytics"),
+		experiments:    db.Collection("experiments"),
+		chatTurns:      db.Collection("chat_turns"),
+		evalCases:      db.Collection("eval_cases"),
+		evalRuns:       db.Collection("eval_runs"),
+		domains:        db.Collection("domains"),
+
+		contextSummaries: db.Collection("context_summaries"),
+		jobs:             db.Collection("jobs"),
+		scheduleClaims:   db.Collection("schedule_claims"),
+		llmCalls:         db.Collection("llm_calls"),
+		alerts:           db.Collection("alerts"),
+		chatTraces:       db.Collection("chat_traces"),
+		requestStats:     db.Collection("request_stats"),
+		rateLimits:       db.Collection("rate_limit_snapshots"),
+		ipRules:          db.Collection("ip_rules"),
+		auditLog:         db.Collection("audit_log"),
+		apiTokens:        db.Collection("api_tokens"),
+		deletions:        db.Collection("deletions"),
+		settings:         db.Collection("settings"),
+
+		mediaStores: mediaStores,
+		mediaStore:  mediaStore,
+		state:       state,
 	}
 }
 
 // Author query methods
 func (ps *PortfolioService) GetAllAuthors(ctx context.Context) ([]Author, error) {
-	cursor, err := ps.authors.Find(ctx, bson.M{})
+	cursor, err := ps.authors.Find(ctx, bson.M{}, boundedFind())
 	if err != nil {
 		return nil, err
 	}
@@ -317,7 +712,8 @@ func (ps *PortfolioService) GetAuthorByName(ctx context.Context, name string) (*
 
 func (ps *PortfolioService) GetAuthorByEmail(ctx context.Context, email string) (*Author, error) {
 	var author Author
-	filter := bson.M{"email": email}
+	// Matches the encrypted value and, for documents written before encryption was enabled, the plain one
+	filter := bson.M{"email": bson.M{"$in": bson.A{EncryptedString(email), email}}}
 	err := ps.authors.FindOne(ctx, filter).Decode(&author)
 	if err != nil {
 		return nil, err
@@ -335,28 +731,59 @@ func (ps *PortfolioService) GetAuthorByID(ctx context.Context, id primitive.Obje
 	return &author, nil
 }
 
+func (ps *PortfolioService) InsertAuthor(ctx context.Context, author *Author) error {
+	if author.ID.IsZero() {
+		author.ID = primitive.NewObjectID()
+	}
+	author.stampCreated()
+	_, err := ps.authors.InsertOne(ctx, author)
+	return err
+}
+
 func (ps *PortfolioService) CountAuthors(ctx context.Context) (int64, error) {
 	return ps.authors.CountDocuments(ctx, bson.M{})
 }
 
-// Project query methods
-func (ps *PortfolioService) GetAllProjects(ctx context.Context) ([]Project, error) {
-	cursor, err := ps.projects.Find(ctx, bson.M{})
-	if err != nil {
-		return nil, err
-	}
-	defer cursor.Close(ctx)
+// projectVisibilities are the accepted values of Project.Visibility; empty means public
+var projectVisibilities = map[string]bool{
+	"public":   true,
+	"unlisted": true,
+	"private":  true,
+}
 
-	var projects []Project
-	if err = cursor.All(ctx, &projects); err != nil {
-		return nil, err
+// listedProjectsFilter matches projects that appear in lists, search and chatbot context. Unlisted projects
+// are only reachable by slug and private ones only by admins.
+func listedProjectsFilter() bson.M {
+	return bson.M{"visibility": bson.M{"$nin": bson.A{"unlisted", "private"}}}
+}
+
+// projectVisibilityFilter restricts a project filter to listed projects unless includeHidden is set
+func projectVisibilityFilter(filter bson.M, includeHidden bool) bson.M {
+	if includeHidden {
+		return filter
 	}
-	return projects, nil
+	filter["visibility"] = listedProjectsFilter()["visibility"]
+	return filter
+}
+
+// projectVisibleTo reports whether a project fetched by slug may be shown; private projects are admin only
+func projectVisibleTo(project *Project, admin bool) bool {
+	return admin || project.Visibility != "private"
+}
+
+// isPublic reports whether a project is listed and may be cached by anyone
+func (project *Project) isPublic() bool {
+	return project.Visibility == "" || project.Visibility == "public"
+}
+
+// Project query methods; lists come in their curated order (see Pinning)
+func (ps *PortfolioService) GetAllProjects(ctx context.Context, includeHidden bool, author *Author) ([]Project, error) {
+	return findPinned[Project](ctx, ps.projects, projectVisibilityFilter(hostScope(bson.M{}, "author_id", author), includeHidden))
 }
 
-func (ps *PortfolioService) GetProjectByName(ctx context.Context, name string) (*Project, error) {
+func (ps *PortfolioService) GetProjectByName(ctx context.Context, name string, includeHidden bool) (*Project, error) {
 	var project Project
-	filter := bson.M{"name": bson.M{"$regex": name, "$options": "i"}}
+	filter := projectVisibilityFilter(bson.M{"name": bson.M{"$regex": name, "$options": "i"}}, includeHidden)
 	err := ps.projects.FindOne(ctx, filter).Decode(&project)
 	if err != nil {
 		return nil, err
@@ -364,55 +791,83 @@ func (ps *PortfolioService) GetProjectByName(ctx context.Context, name string) (
 	return &project, nil
 }
 
-func (ps *PortfolioService) GetProjectsByCategory(ctx context.Context, category string) ([]Project, error) {
-	cursor, err := ps.projects.Find(ctx, bson.M{"category": bson.M{"$regex": category, "$options": "i"}})
-	if err != nil {
-		return nil, err
-	}
-	defer cursor.Close(ctx)
-
-	var projects []Project
-	if err = cursor.All(ctx, &projects); err != nil {
+// GetProjectBySlug finds a project by its stored slug (see BackfillProjectSlugs). Hidden projects are
+// included; callers check projectVisibleTo.
+func (ps *PortfolioService) GetProjectBySlug(ctx context.Context, slug string) (*Project, error) {
+	var project Project
+	if err := ps.projects.FindOne(ctx, bson.M{"slug": slug}).Decode(&project); err != nil {
 		return nil, err
 	}
-	return projects, nil
+	return &project, nil
 }
 
-func (ps *PortfolioService) GetProjectsByAuthor(ctx context.Context, authorID primitive.ObjectID) ([]Project, error) {
-	cursor, err := ps.projects.Find(ctx, bson.M{"author_id": authorID})
+// BackfillProjectSlugs stores the slug of projects written before slugs were stored, so slug lookups only
+// need the projects(slug) index. A project whose slug is already taken is left for review. updated_at is
+// left alone. Returns the number of projects updated.
+func (ps *PortfolioService) BackfillProjectSlugs(ctx context.Context) (int64, error) {
+	cursor, err := ps.projects.Find(ctx, bson.M{"$or": []bson.M{{"slug": bson.M{"$exists": false}}, {"slug": ""}}}, timedFind())
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
-	defer cursor.Close(ctx)
-
 	var projects []Project
 	if err = cursor.All(ctx, &projects); err != nil {
-		return nil, err
+		return 0, err
 	}
-	return projects, nil
-}
 
-func (ps *PortfolioService) GetProjectsByTechnology(ctx context.Context, technology string) ([]Project, error) {
-	cursor, err := ps.projects.Find(ctx, bson.M{"technologies_used": bson.M{"$regex": technology, "$options": "i"}})
-	if err != nil {
-		return nil, err
+	var updated int64
+	for _, project := range projects {
+		slug := slugify(project.Name)
+		taken, err := ps.projectSlugTaken(ctx, slug, project.ID)
+		if err != nil {
+			return updated, err
+		}
+		if taken {
+			log.Printf("Project %s would get the slug %q, which is taken, leaving it for review", project.ID.Hex(), slug)
+			continue
+		}
+		result, err := ps.projects.UpdateOne(ctx, bson.M{"_id": project.ID}, bson.M{"$set": bson.M{"slug": slug}})
+		if err != nil {
+			return updated, err
+		}
+		updated += result.ModifiedCount
 	}
-	defer cursor.Close(ctx)
+	return updated, nil
+}
 
-	var projects []Project
-	if err = cursor.All(ctx, &projects); err != nil {
-		return nil, err
+func (ps *PortfolioService) GetProjectsByCategory(ctx context.Context, category string, includeHidden bool, author *Author) ([]Project, error) {
+	filter := hostScope(bson.M{"category": bson.M{"$regex": category, "$options": "i"}}, "author_id", author)
+	return findPinned[Project](ctx, ps.projects, projectVisibilityFilter(filter, includeHidden))
+}
+
+func (ps *PortfolioService) GetProjectsByAuthor(ctx context.Context, authorID primitive.ObjectID, includeHidden bool) ([]Project, error) {
+	return findPinned[Project](ctx, ps.projects, projectVisibilityFilter(bson.M{"author_id": authorID}, includeHidden))
+}
+
+func (ps *PortfolioService) GetProjectsByTechnology(ctx context.Context, technology string, includeHidden bool, author *Author) ([]Project, error) {
+	filter := hostScope(bson.M{"technologies_used": bson.M{"$regex": technology, "$options": "i"}}, "author_id", author)
+	return findPinned[Project](ctx, ps.projects, projectVisibilityFilter(filter, includeHidden))
+}
+
+func (ps *PortfolioService) InsertProject(ctx context.Context, project *Project) error {
+	if project.ID.IsZero() {
+		project.ID = primitive.NewObjectID()
 	}
-	return projects, nil
+	if project.Slug == "" {
+		project.Slug = slugify(project.Name)
+	}
+	project.refreshReadingStats()
+	project.stampCreated()
+	_, err := ps.projects.InsertOne(ctx, project)
+	return err
 }
 
-func (ps *PortfolioService) CountProjects(ctx context.Context) (int64, error) {
-	return ps.projects.CountDocuments(ctx, bson.M{})
+func (ps *PortfolioService) CountProjects(ctx context.Context, includeHidden bool) (int64, error) {
+	return ps.projects.CountDocuments(ctx, projectVisibilityFilter(bson.M{}, includeHidden))
 }
 
 // Education query methods
-func (ps *PortfolioService) GetAllEducation(ctx context.Context) ([]Education, error) {
-	cursor, err := ps.education.Find(ctx, bson.M{})
+func (ps *PortfolioService) GetAllEducation(ctx context.Context, author *Author) ([]Education, error) {
+	cursor, err := ps.education.Find(ctx, hostScope(bson.M{}, "student_id", author), boundedFind())
 	if err != nil {
 		return nil, err
 	}
@@ -425,8 +880,9 @@ func (ps *PortfolioService) GetAllEducation(ctx context.Context) ([]Education, e
 	return education, nil
 }
 
-func (ps *PortfolioService) GetEducationByUniversity(ctx context.Context, university string) ([]Education, error) {
-	cursor, err := ps.education.Find(ctx, bson.M{"university_name": bson.M{"$regex": university, "$options": "i"}})
+func (ps *PortfolioService) GetEducationByUniversity(ctx context.Context, university string, author *Author) ([]Education, error) {
+	filter := hostScope(bson.M{"university_name": bson.M{"$regex": university, "$options": "i"}}, "student_id", author)
+	cursor, err := ps.education.Find(ctx, filter, boundedFind())
 	if err != nil {
 		return nil, err
 	}
@@ -439,8 +895,9 @@ func (ps *PortfolioService) GetEducationByUniversity(ctx context.Context, univer
 	return education, nil
 }
 
-func (ps *PortfolioService) GetEducationByMajor(ctx context.Context, major string) ([]Education, error) {
-	cursor, err := ps.education.Find(ctx, bson.M{"major": bson.M{"$regex": major, "$options": "i"}})
+func (ps *PortfolioService) GetEducationByMajor(ctx context.Context, major string, author *Author) ([]Education, error) {
+	filter := hostScope(bson.M{"major": bson.M{"$regex": major, "$options": "i"}}, "student_id", author)
+	cursor, err := ps.education.Find(ctx, filter, boundedFind())
 	if err != nil {
 		return nil, err
 	}
@@ -454,7 +911,7 @@ func (ps *PortfolioService) GetEducationByMajor(ctx context.Context, major strin
 }
 
 func (ps *PortfolioService) GetEducationByStudent(ctx context.Context, studentID primitive.ObjectID) ([]Education, error) {
-	cursor, err := ps.education.Find(ctx, bson.M{"student_id": studentID})
+	cursor, err := ps.education.Find(ctx, bson.M{"student_id": studentID}, boundedFind())
 	if err != nil {
 		return nil, err
 	}
@@ -467,13 +924,88 @@ func (ps *PortfolioService) GetEducationByStudent(ctx context.Context, studentID
 	return education, nil
 }
 
+func (ps *PortfolioService) InsertEducation(ctx context.Context, education *Education) error {
+	if education.ID.IsZero() {
+		education.ID = primitive.NewObjectID()
+	}
+	education.stampCreated()
+	_, err := ps.education.InsertOne(ctx, education)
+	return err
+}
+
+// UpsertEducation saves an imported education entry, matching an existing one by student, institution,
+// degree and start date. Only the imported fields are set, so a re-import keeps GPA, honors and the
+// other details added by hand. education is updated with the stored document.
+func (ps *PortfolioService) UpsertEducation(ctx context.Context, education *Education) error {
+	now := time.Now().UTC()
+	filter := bson.M{
+		"student_id":      education.StudentID,
+		"university_name": education.UniversityName,
+		"major":           education.Major,
+		"start_date":      education.StartDate,
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"end_date":     education.EndDate,
+			"description":  education.Description,
+			"student_name": education.StudentName,
+			"updated_at":   now,
+		},
+		"$setOnInsert": bson.M{"created_at": now},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	return ps.education.FindOneAndUpdate(ctx, filter, update, opts).Decode(education)
+}
+
 func (ps *PortfolioService) CountEducation(ctx context.Context) (int64, error) {
 	return ps.education.CountDocuments(ctx, bson.M{})
 }
 
+// UnmarshalJSON accepts field_of_study as an alias of major, the name used by many resume formats and
+// older clients; major wins when both are sent
+func (e *Education) UnmarshalJSON(data []byte) error {
+	type education Education
+	var decoded struct {
+		education
+		FieldOfStudy string `json:"field_of_study"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*e = Education(decoded.education)
+	if strings.TrimSpace(e.Major) == "" {
+		e.Major = decoded.FieldOfStudy
+	}
+	return nil
+}
+
+// validateEducation checks a manually entered education record
+func validateEducation(education *Education) error {
+	var v Validator
+	v.Required("university_name", education.UniversityName)
+	v.Required("major", education.Major)
+	v.RequiredTime("start_date", education.StartDate)
+	if education.EndDate != nil {
+		v.Check(!education.EndDate.Before(education.StartDate), "end_date", "range", "end_date must not be before start_date")
+	}
+	v.Check(!education.StudentID.IsZero(), "student_id", "required", "student_id is required")
+	v.MaxLength("gpa", education.GPA, 20)
+	v.MaxLength("honors", education.Honors, 200)
+	v.MaxLength("thesis", education.Thesis, 500)
+	if v.MaxItems("relevant_coursework", len(education.Coursework), 50) {
+		for _, course := range education.Coursework {
+			if !v.Check(strings.TrimSpace(course) != "", "relevant_coursework", "required", "relevant_coursework entries must not be empty") ||
+				!v.MaxLength("relevant_coursework", course, 200) {
+				break
+			}
+		}
+	}
+	return v.Err()
+}
+
 // Resume query methods
 func (ps *PortfolioService) GetAllResumes(ctx context.Context) ([]Resume, error) {
-	cursor, err := ps.resumes.Find(ctx, bson.M{})
+	cursor, err := ps.resumes.Find(ctx, bson.M{}, boundedFind())
 	if err != nil {
 		return nil, err
 	}
@@ -483,9 +1015,25 @@ func (ps *PortfolioService) GetAllResumes(ctx context.Context) ([]Resume, error)
 	if err = cursor.All(ctx, &resumes); err != nil {
 		return nil, err
 	}
+	if err = ps.assembleResumeList(ctx, resumes); err != nil {
+		return nil, err
+	}
 	return resumes, nil
 }
 
+func (ps *PortfolioService) GetResumeByID(ctx context.Context, id primitive.ObjectID) (*Resume, error) {
+	var resume Resume
+	filter := bson.M{"_id": id}
+	err := ps.resumes.FindOne(ctx, filter).Decode(&resume)
+	if err != nil {
+		return nil, err
+	}
+	if err = ps.assembleResumes(ctx, &resume); err != nil {
+		return nil, err
+	}
+	return &resume, nil
+}
+
 func (ps *PortfolioService) GetResumeByAuthor(ctx context.Context, authorID primitive.ObjectID) (*Resume, error) {
 	var resume Resume
 	filter := bson.M{"author_id": authorID}
@@ -493,11 +1041,15 @@ func (ps *PortfolioService) GetResumeByAuthor(ctx context.Context, authorID prim
 	if err != nil {
 		return nil, err
 	}
+	if err = ps.assembleResumes(ctx, &resume); err != nil {
+		return nil, err
+	}
 	return &resume, nil
 }
 
-func (ps *PortfolioService) GetResumesBySkill(ctx context.Context, skill string) ([]Resume, error) {
-	cursor, err := ps.resumes.Find(ctx, bson.M{"skills": bson.M{"$regex": skill, "$options": "i"}})
+func (ps *PortfolioService) GetResumesBySkill(ctx context.Context, skill string, author *Author) ([]Resume, error) {
+	filter := hostScope(bson.M{"skills": bson.M{"$regex": skill, "$options": "i"}}, "author_id", author)
+	cursor, err := ps.resumes.Find(ctx, filter, boundedFind())
 	if err != nil {
 		return nil, err
 	}
@@ -507,32 +1059,92 @@ func (ps *PortfolioService) GetResumesBySkill(ctx context.Context, skill string)
 	if err = cursor.All(ctx, &resumes); err != nil {
 		return nil, err
 	}
+	if err = ps.assembleResumeList(ctx, resumes); err != nil {
+		return nil, err
+	}
 	return resumes, nil
 }
 
+// UpsertResumeByAuthor replaces the author's resume, creating it if it does not exist. Its experience is
+// stored in the experience collection and referenced from the resume.
+func (ps *PortfolioService) UpsertResumeByAuthor(ctx context.Context, resume *Resume) error {
+	var previous []primitive.ObjectID
+	existing, err := ps.GetResumeByAuthor(ctx, resume.AuthorID)
+	if err == nil {
+		resume.ID = existing.ID
+		resume.CreatedAt = existing.CreatedAt
+		resume.stampUpdated()
+		previous = existing.ExperienceIDs
+	} else if err == mongo.ErrNoDocuments {
+		resume.ID = primitive.NewObjectID()
+		resume.stampCreated()
+	} else {
+		return err
+	}
+	if err := ps.saveResumeExperience(ctx, resume, previous); err != nil {
+		return err
+	}
+
+	// The positions are only referenced; the caller keeps the assembled resume
+	experience := resume.Experience
+	resume.Experience = nil
+	_, err = ps.resumes.ReplaceOne(ctx, bson.M{"_id": resume.ID}, resume, options.Replace().SetUpsert(true))
+	resume.Experience = experience
+	return err
+}
+
 func (ps *PortfolioService) CountResumes(ctx context.Context) (int64, error) {
 	return ps.resumes.CountDocuments(ctx, bson.M{})
 }
 
-// Generic search method for LLM integration
-func (ps *PortfolioService) SearchAll(ctx context.Context, query string) (map[string]interface{}, error) {
+// searchTypes are the collections SearchAll searches, and the values of the search endpoint's types filter
+var searchTypes = []string{"authors", "projects", "education", "resumes", "experience", "posts", "testimonials",
+	"awards", "certifications", "publications", "talks", "skills", "now"}
+
+// Generic search method for LLM integration. Collections whose search failed are empty in the results
+// and listed in the failures.
+func (ps *PortfolioService) SearchAll(ctx context.Context, query string) (map[string]interface{}, []SearchFailure, error) {
+	return ps.SearchAllForAuthor(ctx, query, primitive.NilObjectID)
+}
+
+// SearchAllForAuthor searches like SearchAll, limited to one author's documents unless authorID is zero,
+// and to the given searchTypes if any
+func (ps *PortfolioService) SearchAllForAuthor(ctx context.Context, query string, authorID primitive.ObjectID, types ...string) (map[string]interface{}, []SearchFailure, error) {
 	results := make(map[string]interface{})
+	wanted := func(name string) bool {
+		return len(types) == 0 || slices.Contains(types, name)
+	}
 
-	// Create search terms from the query
+	// scope restricts a filter to the author through the collection's author reference field
+	scope := func(filter bson.M, field string) bson.M {
+		if authorID.IsZero() {
+			return filter
+		}
+		return bson.M{"$and": []bson.M{filter, {field: authorID}}}
+	}
+
+	// Create search terms from the query, escaped so they match literally, and capped so a long query
+	// cannot build an expensive pattern
 	searchTerms := strings.Fields(strings.ToLower(query))
+	if len(searchTerms) > maxSearchTerms {
+		searchTerms = searchTerms[:maxSearchTerms]
+	}
+	for i, term := range searchTerms {
+		searchTerms[i] = regexp.QuoteMeta(term)
+	}
 
 	// Build regex pattern for case-insensitive search
 	searchPattern := strings.Join(searchTerms, "|")
 	regex := bson.M{"$regex": searchPattern, "$options": "i"}
 
 	// Smart filtering based on query content
-	var authorFilter, projectFilter, educationFilter, resumeFilter bson.M
+	var authorFilter, projectFilter, educationFilter, resumeFilter, postFilter, testimonialFilter, awardFilter bson.M
+	var certificationFilter, publicationFilter, talkFilter, skillFilter, experienceFilter bson.M
 
-	// Search authors (name, job_title, email, hobbies)
+	// Search authors (name, job_title, hobbies); email is encrypted and cannot be matched
 	authorFilter = bson.M{
 		"$or": []bson.M{
 			{"name": regex},
-			{"email": regex},
 			{"phone": regex},
 			{"job_title": regex},
 			{"linkedin_url": regex},
@@ -557,22 +1169,106 @@ func (ps *PortfolioService) SearchAll(ctx context.Context, query string) (map[st
 	educationFilter = bson.M{
 		"$or": []bson.M{
 			{"university_name": regex},
-			{"field_of_study": regex},
+			{"major": regex},
 			{"description": regex},
 			{"student_name": regex},
 			{"gpa": regex},
+			{"honors": regex},
+			{"relevant_coursework": regex},
+			{"thesis": regex},
 			{"start_date": regex}, // Assuming start_date is a string for search purposes
 			{"end_date": regex},   // Assuming end_date is a string for search purposes
 		},
 	}
 
-	// Search resumes (skills, author_name, experience)
+	// Search resumes (skills, author_name); their experience is searched in its own collection
 	resumeFilter = bson.M{
 		"$or": []bson.M{
 			{"skills": regex},
 			{"author_name": regex},
-			{"experience.job_title": regex},
-			{"experience.company": regex},
+		},
+	}
+
+	// Search experience (job_title, company, location, description, technologies)
+	experienceFilter = bson.M{
+		"$or": []bson.M{
+			{"job_title": regex},
+			{"company": regex},
+			{"location": regex},
+			{"description": regex},
+			{"technologies": regex},
+		},
+	}
+
+	// Search posts (title, summary, body, tags); drafts are never searchable
+	postFilter = bson.M{
+		"$and": []bson.M{
+			publishedPostsFilter(),
+			{"$or": []bson.M{
+				{"title": regex},
+				{"summary": regex},
+				{"body": regex},
+				{"tags": regex},
+			}},
+		},
+	}
+
+	// Search testimonials (author, relationship, quote); only approved ones are public
+	testimonialFilter = bson.M{
+		"approved": true,
+		"$or": []bson.M{
+			{"author": regex},
+			{"relationship": regex},
+			{"quote": regex},
+		},
+	}
+
+	// Search awards (title, issuer, category, description)
+	awardFilter = bson.M{
+		"$or": []bson.M{
+			{"title": regex},
+			{"issuer": regex},
+			{"category": regex},
+			{"description": regex},
+		},
+	}
+
+	// Search certifications (name, issuer, description, skills)
+	certificationFilter = bson.M{
+		"$or": []bson.M{
+			{"name": regex},
+			{"issuer": regex},
+			{"description": regex},
+			{"skills": regex},
+		},
+	}
+
+	// Search publications (title, venue, coauthors, abstract)
+	publicationFilter = bson.M{
+		"$or": []bson.M{
+			{"title": regex},
+			{"venue": regex},
+			{"coauthors": regex},
+			{"abstract": regex},
+		},
+	}
+
+	// Search talks (title, event, location, description)
+	talkFilter = bson.M{
+		"$or": []bson.M{
+			{"title": regex},
+			{"event": regex},
+			{"location": regex},
+			{"description": regex},
+		},
+	}
+
+	// Search skills (name, category, proficiency)
+	skillFilter = bson.M{
+		"$or": []bson.M{
+			{"name": regex},
+			{"category": regex},
+			{"proficiency": regex},
 		},
 	}
 
@@ -582,52 +1278,199 @@ func (ps *PortfolioService) SearchAll(ctx context.Context, query string) (map[st
 		projectFilter = bson.M{}
 		educationFilter = bson.M{}
 		resumeFilter = bson.M{}
+		experienceFilter = bson.M{}
+		postFilter = publishedPostsFilter()
+		testimonialFilter = bson.M{"approved": true}
+		awardFilter = bson.M{}
+		certificationFilter = bson.M{}
+		publicationFilter = bson.M{}
+		talkFilter = bson.M{}
+		skillFilter = bson.M{}
+	}
+
+	// Each collection is searched concurrently; the results map is only written once all are done
+	var (
+		authorResults      []Author
+		projectResults     []Project
+		educationResults   []Education
+		resumeResults      []Resume
+		experienceResults  []Experience
+		postResults        []Post
+		testimonialResults []Testimonial
+		awardResults       []Award
+		certResults        []Certification
+		publicationResults []Publication
+		talkResults        []Talk
+		skillResults       []Skill
+		nowResults         []Now
+	)
+	// Posts: the rendered HTML duplicates the markdown body, so it is left out of results
+	postOpts := options.Find().SetProjection(bson.M{"body_html": 0}).SetSort(bson.D{{Key: "published_at", Value: -1}})
+	// Experience: the most recent positions come first when there are more than fit
+	experienceOpts := options.Find().SetSort(bson.D{{Key: "start_date", Value: -1}})
+	// The now entry is small and always relevant, so it is included whatever the query
+	nowOpts := options.Find().SetSort(bson.D{{Key: "updated_at", Value: -1}})
+
+	failures := &searchFailures{}
+	group, groupCtx := errgroup.WithContext(ctx)
+	if wanted("authors") {
+		group.Go(func() error {
+			authorResults = searchCollection[Author](groupCtx, ps.authors, scope(authorFilter, "_id"), nil, failures)
+			return nil
+		})
+	}
+	if wanted("projects") {
+		group.Go(func() error {
+			// Unlisted and private projects never reach search results or chatbot context
+			projectResults = searchCollection[Project](groupCtx, ps.projects, scope(bson.M{"$and": []bson.M{projectFilter, listedProjectsFilter()}}, "author_id"), nil, failures)
+			return nil
+		})
+	}
+	if wanted("education") {
+		group.Go(func() error {
+			educationResults = searchCollection[Education](groupCtx, ps.education, scope(educationFilter, "student_id"), nil, failures)
+			return nil
+		})
+	}
+	if wanted("resumes") {
+		group.Go(func() error {
+			resumeResults = searchCollection[Resume](groupCtx, ps.resumes, scope(resumeFilter, "author_id"), nil, failures)
+			return nil
+		})
+	}
+	if wanted("experience") {
+		group.Go(func() error {
+			experienceResults = searchCollection[Experience](groupCtx, ps.experience, scope(experienceFilter, "author_id"), experienceOpts, failures)
+			return nil
+		})
+	}
+	if wanted("posts") {
+		group.Go(func() error {
+			postResults = searchCollection[Post](groupCtx, ps.posts, scope(postFilter, "author_id"), postOpts, failures)
+			return nil
+		})
+	}
+	if wanted("testimonials") {
+		group.Go(func() error {
+			testimonialResults = searchCollection[Testimonial](groupCtx, ps.testimonials, scope(testimonialFilter, "author_id"), nil, failures)
+			return nil
+		})
+	}
+	if wanted("certifications") {
+		group.Go(func() error {
+			certResults = searchCollection[Certification](groupCtx, ps.certifications, scope(certificationFilter, "author_id"), nil, failures)
+			return nil
+		})
 	}
+	if wanted("awards") {
+		group.Go(func() error {
+			awardResults = searchCollection[Award](groupCtx, ps.awards, scope(awardFilter, "author_id"), nil, failures)
+			return nil
+		})
+	}
+	if wanted("publications") {
+		group.Go(func() error {
+			publicationResults = searchCollection[Publication](groupCtx, ps.publications, scope(publicationFilter, "author_id"), nil, failures)
+			return nil
+		})
+	}
+	if wanted("talks") {
+		group.Go(func() error {
+			talkResults = searchCollection[Talk](groupCtx, ps.talks, scope(talkFilter, "author_id"), nil, failures)
+			return nil
+		})
+	}
+	if wanted("skills") {
+		group.Go(func() error {
+			skillResults = searchCollection[Skill](groupCtx, ps.skills, scope(skillFilter, "author_id"), nil, failures)
+			return nil
+		})
+	}
+	if wanted("now") {
+		group.Go(func() error {
+			nowResults = searchCollection[Now](groupCtx, ps.now, scope(bson.M{}, "author_id"), nowOpts, failures)
+			return nil
+		})
+	}
+	group.Wait()
 
-	// Search authors
-	authors, err := ps.authors.Find(ctx, authorFilter)
-	if err != nil {
-		log.Printf("Error searching authors: %v", err)
-		authors, _ = ps.authors.Find(ctx, bson.M{}) // Fallback to all
+	// Results feed the public search endpoint and chatbot context, so contact details are masked
+	for i := range authorResults {
+		authorResults[i].redactContact()
 	}
-	var authorResults []Author
-	authors.All(ctx, &authorResults)
+	redactResumes(resumeResults)
+	for i := range experienceResults {
+		experienceResults[i].refreshDuration()
+	}
+
 	results["authors"] = authorResults
-	authors.Close(ctx)
+	results["projects"] = projectResults
+	results["education"] = educationResults
+	results["resumes"] = resumeResults
+	results["experience"] = experienceResults
+	results["posts"] = postResults
+	results["testimonials"] = testimonialResults
+	results["awards"] = awardResults
+	results["certifications"] = certResults
+	results["publications"] = publicationResults
+	results["talks"] = talkResults
+	results["skills"] = skillResults
+	results["now"] = nowResults
+	for name := range results {
+		if !wanted(name) {
+			delete(results, name)
+		}
+	}
 
-	// Search projects
-	projects, err := ps.projects.Find(ctx, projectFilter)
-	if err != nil {
-		log.Printf("Error searching projects: %v", err)
-		projects, _ = ps.projects.Find(ctx, bson.M{}) // Fallback to all
+	// A failed collection is reported rather than replaced with every document, which would bloat the
+	// chatbot context; only a search that failed everywhere is an error
+	sort.Slice(failures.list, func(i, j int) bool { return failures.list[i].Collection < failures.list[j].Collection })
+	if len(failures.list) > 0 && len(failures.list) == len(results) {
+		return nil, nil, fmt.Errorf("search failed in every collection: %s", failures.list[0].Error)
 	}
-	var projectResults []Project
-	projects.All(ctx, &projectResults)
-	results["projects"] = projectResults
-	projects.Close(ctx)
+	return results, failures.list, nil
+}
 
-	// Search education
-	education, err := ps.education.Find(ctx, educationFilter)
+// SearchFailure reports a collection whose search failed, so its results are missing
+type SearchFailure struct {
+	Collection string `json:"collection"`
+	Error      string `json:"error"`
+}
+
+// searchFailures collects the failures of concurrent collection searches
+type searchFailures struct {
+	mutex sync.Mutex
+	list  []SearchFailure
+}
+
+// searchCollection runs one collection's search within SEARCH_COLLECTION_TIMEOUT, recording a failure
+// instead of returning an error so the other collections' results are still used
+func searchCollection[T any](ctx context.Context, collection *mongo.Collection, filter bson.M, opts *options.FindOptions, failures *searchFailures) []T {
+	ctx, cancel := context.WithTimeout(ctx, queryLimitsConfig().searchTimeout)
+	defer cancel()
+
+	results, err := findAll[T](ctx, collection, filter, opts)
 	if err != nil {
-		log.Printf("Error searching education: %v", err)
-		education, _ = ps.education.Find(ctx, bson.M{}) // Fallback to all
+		log.Printf("Error searching %s: %v", collection.Name(), err)
+		failures.mutex.Lock()
+		failures.list = append(failures.list, SearchFailure{Collection: collection.Name(), Error: err.Error()})
+		failures.mutex.Unlock()
 	}
-	var educationResults []Education
-	education.All(ctx, &educationResults)
-	results["education"] = educationResults
-	education.Close(ctx)
+	return results
+}
 
-	// Search resumes
-	resumes, err := ps.resumes.Find(ctx, resumeFilter)
+// findAll runs a bounded search query and decodes every document
+func findAll[T any](ctx context.Context, collection *mongo.Collection, filter bson.M, opts *options.FindOptions) ([]T, error) {
+	cursor, err := collection.Find(ctx, filter, boundedSearch(opts))
 	if err != nil {
-		log.Printf("Error searching resumes: %v", err)
-		resumes, _ = ps.resumes.Find(ctx, bson.M{}) // Fallback to all
+		return nil, err
 	}
-	var resumeResults []Resume
-	resumes.All(ctx, &resumeResults)
-	results["resumes"] = resumeResults
-	resumes.Close(ctx)
+	defer cursor.Close(ctx)
 
+	var results []T
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
 	return results, nil
 }
 
@@ -636,6 +1479,8 @@ type LLMService struct {
 	client           openai.Client
 	portfolioService *PortfolioService
 	model            string
+	summaryModel     string // Cheaper model used to summarize context that does not fit the prompt
+	limiter          *LLMLimiter
 }
 
 // NewLLMService creates a new LLM service instance
@@ -651,6 +1496,11 @@ func NewLLMService(apiKey string, portfolioService *PortfolioService) *LLMServic
 		model = "gpt-3.5-turbo"
 	}
 
+	summaryModel := os.Getenv("OPENAI_SUMMARY_MODEL")
+	if summaryModel == "" {
+		summaryModel = "gpt-4o-mini"
+	}
+
 	log.Printf("Initializing LLM service with model: %s", model)
 
 	client := openai.NewClient(option.WithAPIKey(apiKey))
@@ -658,22 +1508,107 @@ func NewLLMService(apiKey string, portfolioService *PortfolioService) *LLMServic
 		client:           client,
 		portfolioService: portfolioService,
 		model:            model,
+		summaryModel:     summaryModel,
+		limiter:          NewLLMLimiter(),
 	}
 }
 
 // ProcessQuery handles user queries with portfolio context
 func (l *LLMService) ProcessQuery(ctx context.Context, query string) (string, error) {
+	return l.ProcessQueryWithVariant(ctx, query, nil)
+}
+
+// ProcessQueryWithVariant answers a query using an experiment variant's model and extra instructions
+func (l *LLMService) ProcessQueryWithVariant(ctx context.Context, query string, variant *PromptVariant) (string, error) {
+	answer, _, err := l.answerQuery(ctx, query, nil, variant, "", "")
+	return answer.Text, err
+}
+
+// answerQuery answers a query and also returns the portfolio context the answer was based on. With an
+// author, the context and persona are limited to that author's portfolio. A non-empty locale translates the
+// context where translations exist and asks for an answer in that language; a non-empty style presents the
+// answer for that audience.
+func (l *LLMService) answerQuery(ctx context.Context, query string, author *Author, variant *PromptVariant, locale, style string) (chatAnswer, string, error) {
 	if l == nil {
-		return "Chatbot is not available. OpenAI API key not configured.", nil
+		return chatAnswer{Text: "Chatbot is not available. OpenAI API key not configured."}, "", nil
 	}
 
 	log.Printf("Processing chatbot query: %s", query)
+	prompt, err := l.buildChatPrompt(ctx, query, author, variant, locale, style)
+	if err != nil {
+		return chatAnswer{}, "", err
+	}
+
+	log.Printf("Sending request to OpenAI using model: %s", prompt.Model)
+
+	// Send request to OpenAI using the official client (corrected syntax)
+	completion, err := l.createCompletion(ctx, "chat", openai.ChatCompletionNewParams{
+		Messages: []openai.ChatCompletionMessageParamUnion{
+			openai.UserMessage(prompt.Prompt),
+		},
+		Model: prompt.Model, // Use the configurable model, unless the variant overrides it
+	})
+
+	if err != nil {
+		log.Printf("OpenAI API error: %v", err)
+		return chatAnswer{}, "", fmt.Errorf("OpenAI API error: %w", err)
+	}
+
+	if len(completion.Choices) == 0 {
+		log.Printf("No choices returned from OpenAI")
+		return chatAnswer{Text: "I'm sorry, I couldn't generate a response. Please try again."}, prompt.Context, nil
+	}
+
+	response := completion.Choices[0].Message.Content
+	log.Printf("OpenAI response received: %d characters", len(response))
+
+	answer := parseChatAnswer(response)
+	if answer.Confidence == "" {
+		log.Printf("OpenAI response has no confidence line")
+	}
+	return answer, prompt.Context, nil
+}
+
+// chatPrompt is everything sent to the model for one chatbot question
+type chatPrompt struct {
+	Context        string
+	Prompt         string
+	Model          string
+	SearchFailures []SearchFailure
+}
+
+// buildChatPrompt retrieves the portfolio context for a query and assembles the prompt, without calling
+// the chat model
+func (l *LLMService) buildChatPrompt(ctx context.Context, query string, author *Author, variant *PromptVariant, locale, style string) (*chatPrompt, error) {
+	retrievalStart := time.Now()
+	trace := chatTraceFrom(ctx)
 
 	// Get relevant portfolio data as context
-	searchResults, err := l.portfolioService.SearchAll(ctx, query)
+	var authorID primitive.ObjectID
+	if author != nil {
+		authorID = author.ID
+	}
+	searchResults, searchFailures, err := l.portfolioService.SearchAllForAuthor(ctx, query, authorID)
 	if err != nil {
 		log.Printf("Error searching portfolio data: %v", err)
-		return "", fmt.Errorf("failed to search portfolio data: %w", err)
+		if trace != nil {
+			trace.Outcome = ChatTraceSearchError
+		}
+		return nil, fmt.Errorf("failed to search portfolio data: %w", err)
+	}
+	// Recent changes are few and answer "what's new" questions whatever the query's words, so they are
+	// always included
+	recentChanges, err := l.portfolioService.GetRecentChanges(ctx, authorID, time.Now().AddDate(0, 0, -recentChangesDays), recentChangesContextLimit)
+	if err != nil {
+		log.Printf("Error loading recent changes: %v", err)
+		searchFailures = append(searchFailures, SearchFailure{Collection: "recent_changes", Error: err.Error()})
+	} else {
+		searchResults["recent_changes"] = recentChanges
+	}
+	if trace != nil {
+		for _, failure := range searchFailures {
+			trace.SearchErrors = append(trace.SearchErrors, failure.Collection+": "+failure.Error)
+		}
 	}
 
 	// Log what data we found
@@ -693,6 +1628,33 @@ func (l *LLMService) ProcessQuery(ctx context.Context, query string) (string, er
 		} else if dataSlice, ok := data.([]Resume); ok {
 			count = len(dataSlice)
 			log.Printf("  %s: %d resumes", collection, count)
+		} else if dataSlice, ok := data.([]Experience); ok {
+			count = len(dataSlice)
+			log.Printf("  %s: %d positions", collection, count)
+		} else if dataSlice, ok := data.([]Post); ok {
+			count = len(dataSlice)
+			log.Printf("  %s: %d posts", collection, count)
+		} else if dataSlice, ok := data.([]Testimonial); ok {
+			count = len(dataSlice)
+			log.Printf("  %s: %d testimonials", collection, count)
+		} else if dataSlice, ok := data.([]Award); ok {
+			count = len(dataSlice)
+			log.Printf("  %s: %d awards", collection, count)
+		} else if dataSlice, ok := data.([]Publication); ok {
+			count = len(dataSlice)
+			log.Printf("  %s: %d publications", collection, count)
+		} else if dataSlice, ok := data.([]Talk); ok {
+			count = len(dataSlice)
+			log.Printf("  %s: %d talks", collection, count)
+		} else if dataSlice, ok := data.([]Skill); ok {
+			count = len(dataSlice)
+			log.Printf("  %s: %d skills", collection, count)
+		} else if dataSlice, ok := data.([]Now); ok {
+			count = len(dataSlice)
+			log.Printf("  %s: %d now entries", collection, count)
+		} else if dataSlice, ok := data.([]RecentChange); ok {
+			count = len(dataSlice)
+			log.Printf("  %s: %d recent changes", collection, count)
 		} else if dataSlice, ok := data.([]interface{}); ok {
 			count = len(dataSlice)
 			log.Printf("  %s: %d items", collection, count)
@@ -701,41 +1663,102 @@ func (l *LLMService) ProcessQuery(ctx context.Context, query string) (string, er
 	}
 	log.Printf("Total relevant items found: %d", totalItems)
 
-	// Convert search results to JSON for context
-	contextData, err := json.MarshalIndent(searchResults, "", "  ")
+	// Evidence lets the chatbot back up claims about skills with the work showing them
+	if skills, ok := searchResults["skills"].([]Skill); ok {
+		if err := l.portfolioService.AttachSkillEvidence(ctx, skills); err != nil {
+			log.Printf("Error resolving skill evidence: %v", err)
+		}
+	}
+
+	if locale != "" {
+		if projects, ok := searchResults["projects"].([]Project); ok {
+			localizeProjects(projects, locale)
+		}
+		if education, ok := searchResults["education"].([]Education); ok {
+			localizeEducation(education, locale)
+		}
+	}
+
+	// Convert search results to JSON for context, summarizing documents that do not fit the budget
+	contextString, err := l.buildContext(ctx, query, searchResults, chatContextLength)
 	if err != nil {
 		log.Printf("Error marshaling context data: %v", err)
-		return "", fmt.Errorf("failed to marshal context data: %w", err)
+		return nil, fmt.Errorf("failed to marshal context data: %w", err)
 	}
-
-	// Limit context size to prevent token overflow
-	contextString := string(contextData)
-	if len(contextString) > 8000 {
-		contextString = contextString[:8000] + "...[truncated]"
-		log.Printf("Context truncated to 8000 characters")
-	} else if len(contextString) < 500 {
+	if trace != nil {
+		trace.RetrievalMs = time.Since(retrievalStart).Milliseconds()
+		trace.ContextChars = len(contextString)
+	}
+	if len(contextString) < 500 {
 		log.Printf("Context is small (%d characters), sending as-is", len(contextString))
 	}
 
 	log.Printf("Context data being sent to OpenAI: %s", contextString[:min(500, len(contextString))])
 
+	persona := l.personaFor(ctx, author)
+	model := l.model
+	variantInstructions := persona.instructions()
+	if locale != "" {
+		variantInstructions += fmt.Sprintf("\n\t\t- Respond in the language with locale code %s, whatever language the portfolio data is in", locale)
+	}
+	for _, line := range chatStyleInstructions[style] {
+		variantInstructions += "\n\t\t- " + line
+	}
+	if variant != nil {
+		if variant.Model != "" {
+			model = variant.Model
+		}
+		for _, line := range strings.Split(variant.Instructions, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				variantInstructions += "\n\t\t- " + line
+			}
+		}
+	}
+
 	// Include the current date so that the bot doesn't get confused.
 	currentDate := time.Now().Format("2006-01-02 15:04:05")
 	// Create a comprehensive prompt with portfolio context
-	prompt := fmt.Sprintf(`You are BILLIEBOT, a professional portfolio assistant for Billie Mallady, a talented Software Engineer. You have access to Billie's complete portfolio data in the form of MongoDB documents including projects, work experience, education, and skills, resume and hobbies. The following data structures apply:
+	prompt := fmt.Sprintf(persona.apply(`You are {{BOT}}, a professional portfolio assistant for {{NAME}}, a talented {{JOB_TITLE}}. You have access to {{FIRST_NAME}}'s complete portfolio data in the form of MongoDB documents including projects, work experience, education, and skills, resume and hobbies. The following data structures apply:
 
 	CURRENT DATE: %s
 	AUTHORS:
-	Here you will find information about Billie Mallady, including their name, job title, email, LinkedIn URL, GitHub URL, and hobbies.
+	Here you will find information about {{NAME}}, including their name, job title, email, LinkedIn URL, GitHub URL, and hobbies.
 
 	PROJECTS:
-	Here you will find information about Billie's projects, including project names, descriptions, technologies used, and links to live demos or repositories (if availiable). 
+	Here you will find information about {{FIRST_NAME}}'s projects, including project names, descriptions, technologies used, and links to live demos or repositories (if availiable). Projects with "archived": true are past work that is no longer maintained.
 
 	EDUCATION:
-	Here you will find information about Billie's education, including university name, field of study and start and end dates. 
+	Here you will find information about {{FIRST_NAME}}'s education, including university name, major (their degree and field of study), GPA, honors, relevant coursework, thesis title and start and end dates. 
 
 	RESUMES:
-	Here you will find information about Billie's resume, including contact information, work experience, skills, and education.
+	Here you will find information about {{FIRST_NAME}}'s resume, including contact information, skills, and education.
+
+	EXPERIENCE:
+	Here you will find {{FIRST_NAME}}'s work history, one position per document, including job title, company, location, start and end dates (no end date means it is the current position), months in the role, a description and the technologies used.
+
+	POSTS:
+	Here you will find {{FIRST_NAME}}'s blog posts, including titles, tags, publish dates and the markdown body of each post.
+
+	TESTIMONIALS:
+	Here you will find recommendations from people who have worked with {{FIRST_NAME}}, including who wrote them, their relationship to {{FIRST_NAME}} and the quote.
+
+	AWARDS:
+	Here you will find {{FIRST_NAME}}'s awards and honors, such as hackathon wins, including the title, who granted it, when, and the project it was for (if any).
+
+	SKILLS:
+	Here you will find {{FIRST_NAME}}'s skills grouped by category, with a proficiency level (beginner, intermediate, advanced or expert), years of experience and the evidence showing the skill: projects, certifications and repositories, each with a link and an optional note.
+
+	PUBLICATIONS:
+	Here you will find papers and articles {{FIRST_NAME}} has published, including title, venue, year, co-authors and DOI or link.
+
+	TALKS:
+	Here you will find conference and meetup talks {{FIRST_NAME}} has given, including title, event, date and links to slides or video.
+
+	NOW:
+	Here you will find what {{FIRST_NAME}} is working on right now: their current focus, what they are learning and reading, their availability for new work and when this was last updated.
+
+	RECENT_CHANGES:
+	Here you will find the projects and blog posts {{FIRST_NAME}} added or updated recently, newest first, with what changed (added, published, updated, or activity for new commits to the project's repository) and when.
 
 
 
@@ -745,66 +1768,115 @@ func (l *LLMService) ProcessQuery(ctx context.Context, query string) (string, er
 		USER QUESTION: %s
 
 		Instructions:
-		- Answer questions about Billie's professional background, projects, skills, and experience
+		- Answer questions about {{FIRST_NAME}}'s professional background, projects, skills, and experience
 		- Be conversational but professional
-		- Do not assume that Billie knows programming languages or technologies not referenced in their portfolio. 
+		- Do not assume that {{FIRST_NAME}} knows programming languages or technologies not referenced in their portfolio. 
 		- If the question is about specific projects, provide detailed information including technologies used
 		- If asked about skills or experience, reference specific examples from the work history, and present in bullet points if you can
-		- If the question isn't related to Billie's portfolio, politely redirect to professional topics.
-		- Do not lie about Billie or provide false information.
+		- When describing how well {{FIRST_NAME}} knows a skill, use the proficiency and years from SKILLS rather than guessing
+		- When asked whether {{FIRST_NAME}} can really do something, cite the evidence listed for the skill in SKILLS with its links; if a skill lists no evidence, say it is self-reported
+		- If the question isn't related to {{FIRST_NAME}}'s portfolio, politely redirect to professional topics.
+		- Do not lie about {{FIRST_NAME}} or provide false information.
+		- Some documents are given as a short "summary" instead of their full fields; rely on it, but do not invent details it leaves out.
+		- When quoting testimonials, attribute them to their author and do not alter the wording.
+		- When asked what {{FIRST_NAME}} is working on now or whether they are available, answer from NOW and mention when it was last updated.
+		- When asked what {{FIRST_NAME}} has been doing lately or what is new, answer from RECENT_CHANGES and say when each change happened relative to the current date.
+		- Present archived projects as historical work, never as something {{FIRST_NAME}} is currently working on.
 		- Keep responses concise but informative
-		- Use a friendly, confident tone that reflects Billie's professional capabilities
-		- Include relevant examples from the portfolio data to support your answers
+		- Use a friendly, confident tone that reflects {{FIRST_NAME}}'s professional capabilities
+		- Include relevant examples from the portfolio data to support your answers%s
 
 		Please provide a helpful response based on the portfolio data above.
 		Provide your response separated by newline characters where appropriate.
 
-`, currentDate, contextString, query)
+		End your response with a final line of the form "CONFIDENCE: <level>" rating how well the portfolio data supports your answer:
+		- high: the portfolio data states the answer directly
+		- medium: the answer is inferred from related facts in the portfolio data
+		- low: the portfolio data only loosely supports the answer
+		- none: the portfolio data does not contain the answer, or the question is not about {{FIRST_NAME}}. Then say plainly that you don't have that information rather than guessing.
 
-	log.Printf("Sending request to OpenAI using model: %s", l.model)
+`), currentDate, contextString, query, variantInstructions)
 
-	// Send request to OpenAI using the official client (corrected syntax)
-	completion, err := l.client.Chat.Completions.New(ctx, openai.ChatCompletionNewParams{
+	return &chatPrompt{Context: contextString, Prompt: prompt, Model: model, SearchFailures: searchFailures}, nil
+}
+
+// complete sends a single prompt to the model and returns its reply. With jsonResponse the model is
+// constrained to return a JSON object.
+func (l *LLMService) complete(ctx context.Context, prompt string, jsonResponse bool) (string, error) {
+	params := openai.ChatCompletionNewParams{
 		Messages: []openai.ChatCompletionMessageParamUnion{
 			openai.UserMessage(prompt),
 		},
-		Model: l.model, // Use the configurable model
-	})
+		Model: l.model,
+	}
+	if jsonResponse {
+		params.ResponseFormat = openai.ChatCompletionNewParamsResponseFormatUnion{
+			OfJSONObject: &shared.ResponseFormatJSONObjectParam{},
+		}
+	}
 
+	completion, err := l.createCompletion(ctx, "completion", params)
 	if err != nil {
 		log.Printf("OpenAI API error: %v", err)
 		return "", fmt.Errorf("OpenAI API error: %w", err)
 	}
-
 	if len(completion.Choices) == 0 {
-		log.Printf("No choices returned from OpenAI")
-		return "I'm sorry, I couldn't generate a response. Please try again.", nil
+		return "", fmt.Errorf("no choices returned from OpenAI")
 	}
-
-	response := completion.Choices[0].Message.Content
-	log.Printf("OpenAI response received: %d characters", len(response))
-
-	return response, nil
+	return completion.Choices[0].Message.Content, nil
 }
 
 // HTTP Handlers
 
+// cleanup drops expired rate limit counters, cache items and metrics; it runs on the "cleanup" schedule
+func (h *APIHandler) cleanup(ctx context.Context) error {
+	h.metrics.Cleanup()
+	return h.service.state.Purge(ctx)
+}
+
 func NewAPIHandler(service *PortfolioService, llmService *LLMService) *APIHandler {
+	ipRules := NewIPRules(service)
 	return &APIHandler{
-		service:     service,
-		llmService:  llmService,
-		rateLimiter: NewRateLimiter(),
+		service:          service,
+		llmService:       llmService,
+		ipRules:          ipRules,
+		autoBanner:       NewAutoBanner(service, ipRules),
+		rateLimiter:      NewRateLimiter(service.state, ipRules, "chat"),
+		chatIPLimiter:    NewRateLimiterWithLimits(service.state, ipRules, "chat_ip", 10, 30),
+		statsLimiter:     NewRateLimiterWithLimits(service.state, ipRules, "stats", 30, 100),
+		submitLimiter:    NewRateLimiter(service.state, ipRules, "submit"),
+		toolsLimiter:     NewRateLimiterWithLimits(service.state, ipRules, "tools", 2, 5),
+		analyticsLimiter: NewRateLimiterWithLimits(service.state, ipRules, "analytics", 60, 200),
+		ogImageCache:     NewTTLCache[[]byte](service.state, "og_image"),
+		mediaResizeCache: NewTTLCache[*resizedMedia](service.state, "media_resize"),
+		toolsCache:       NewTTLCache[[]InterviewQuestion](service.state, "tools"),
+		githubActivity:   NewGitHubActivityService(service.state),
+		codingStats:      NewWakaTimeService(service.state),
+		profileStats:     NewProfileStatsService(service.state),
+		availability:     NewAvailabilityService(service.state),
+		geoIP:            NewGeoIPDatabase(),
+		metrics:          NewRequestMetrics(),
+		chatJudge:        NewChatJudgeConfig(),
+		domainCache:      NewTTLCache[*Author](service.state, "domain"),
+		locales:          NewLocales(),
+		retention:        NewRetentionPurger(service),
+		jobs:             NewJobQueue(service),
+		scheduler:        NewScheduler(service),
+		snapshots:        NewReadSnapshots(service),
+		events:           NewEventHub(service),
+		visitors:         NewVisitorTokens(service.state),
 	}
 }
 
 // CORS middleware
 func (h *APIHandler) enableCORS(w http.ResponseWriter) {
 	w.Header().Set("Access-Control-Allow-Origin", "*")
-	w.Header().Set("Access-Control-Allow-Methods", "GET,POST,OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+	w.Header().Set("Access-Control-Allow-Methods", "GET,POST,PUT,DELETE,OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Visitor-Token")
+	w.Header().Add("Access-Control-Expose-Headers", "X-Visitor-Token")
 }
 
-// Authors endpoints
+// Authors endpoints: GET lists authors (?name= or ?email= finds one), POST creates one (admin only)
 func (h *APIHandler) handleAuthors(w http.ResponseWriter, r *http.Request) {
 	currentTime := time.Now().Format("2006-01-02 15:04:05")
 	gptModel := "DISABLED"
@@ -817,6 +1889,10 @@ func (h *APIHandler) handleAuthors(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if r.Method == "POST" {
+		h.handleAuthorCreate(w, r)
+		return
+	}
 	if r.Method != "GET" {
 		log.Printf("Date: %s | Route: /api/authors | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -824,6 +1900,10 @@ func (h *APIHandler) handleAuthors(w http.ResponseWriter, r *http.Request) {
 	}
 
 	ctx := context.Background()
+	// Contact details are only returned in full to admins
+	admin := isAdminRequest(r)
+	// Domains mapped to an author only list that author
+	host := h.hostAuthor(r)
 
 	// Check for query parameters
 	name := r.URL.Query().Get("name")
@@ -831,64 +1911,108 @@ func (h *APIHandler) handleAuthors(w http.ResponseWriter, r *http.Request) {
 
 	if name != "" {
 		author, err := h.service.GetAuthorByName(ctx, name)
+		if err == nil && host != nil && author.ID != host.ID {
+			err = mongo.ErrNoDocuments
+		}
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusNotFound)
 			return
 		}
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode([]*Author{author})
+		if !admin {
+			author.redactContact()
+		}
+		writeTimestampedList(w, r, []*Author{author})
 		return
 	}
 
 	if email != "" {
 		author, err := h.service.GetAuthorByEmail(ctx, email)
+		if err == nil && host != nil && author.ID != host.ID {
+			err = mongo.ErrNoDocuments
+		}
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusNotFound)
 			return
 		}
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode([]*Author{author})
+		if !admin {
+			author.redactContact()
+		}
+		writeTimestampedList(w, r, []*Author{author})
 		return
 	}
 
-	// Get all authors
-	authors, err := h.service.GetAllAuthors(ctx)
+	// Get all authors, or just the one the domain is mapped to
+	var authors []Author
+	var err error
+	if host != nil {
+		var author *Author
+		if author, err = h.service.GetAuthorByID(ctx, host.ID); err == nil {
+			authors = []Author{*author}
+		}
+	} else {
+		authors, err = h.service.GetAllAuthors(ctx)
+	}
 	if err != nil {
 		log.Printf("Date: %s | Route: /api/authors | Status: ERROR | GPT Model: %s", currentTime, gptModel)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	if !admin {
+		for i := range authors {
+			authors[i].redactContact()
+		}
+	}
 	log.Printf("Date: %s | Route: /api/authors | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(authors)
+	writeTimestampedList(w, r, authors)
 }
 
-func (h *APIHandler) handleAuthorsCount(w http.ResponseWriter, r *http.Request) {
-	currentTime := time.Now().Format("2006-01-02 15:04:05")
-	gptModel := "DISABLED"
-	if h.llmService != nil {
-		gptModel = h.llmService.model
-	}
-
+// Author sub-resource endpoints: /api/authors/{id or slug}/{resource}
+func (h *APIHandler) handleAuthorRoutes(w http.ResponseWriter, r *http.Request) {
 	h.enableCORS(w)
 	if r.Method == "OPTIONS" {
 		return
 	}
 
-	ctx := context.Background()
-	count, err := h.service.CountAuthors(ctx)
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/authors/"), "/"), "/")
+	if len(parts) == 1 && parts[0] != "" {
+		h.handleAuthorByKey(w, r, parts[0])
+		return
+	}
+	if len(parts) != 2 {
+		http.NotFound(w, r)
+		return
+	}
+
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	author, err := h.service.GetAuthorBySlugOrID(context.Background(), parts[0])
 	if err != nil {
-		log.Printf("Date: %s | Route: /api/authors/count | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		if errors.Is(err, errInvalidIdentifier) {
+			writeInvalidInput(w, err)
+			return
+		}
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Author not found", http.StatusNotFound)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	log.Printf("Date: %s | Route: /api/authors/count | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]int64{"count": count})
+	switch parts[1] {
+	case "vcard":
+		h.handleAuthorVCard(w, r, author.ID)
+	case "qr":
+		h.handleAuthorQR(w, r, author.ID)
+	default:
+		http.NotFound(w, r)
+	}
 }
 
-// Projects endpoints
+// Projects endpoints: GET lists projects, POST creates one (admins and the author's projects:write tokens)
 func (h *APIHandler) handleProjects(w http.ResponseWriter, r *http.Request) {
 	currentTime := time.Now().Format("2006-01-02 15:04:05")
 	gptModel := "DISABLED"
@@ -901,6 +2025,10 @@ func (h *APIHandler) handleProjects(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if r.Method == "POST" {
+		h.handleProjectCreate(w, r)
+		return
+	}
 	if r.Method != "GET" {
 		log.Printf("Date: %s | Route: /api/projects | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -908,43 +2036,76 @@ func (h *APIHandler) handleProjects(w http.ResponseWriter, r *http.Request) {
 	}
 
 	ctx := context.Background()
+	// Admins also see unlisted and private projects
+	admin := isAdminRequest(r)
+	locale := h.locales.Negotiate(r)
+	setLanguageHeaders(w, locale)
 
-	// Check for query parameters
+	// Check for query parameters; ?archived=true|false and ?render=html combine with the others
+	archived, err := archivedQuery(r)
+	if err != nil {
+		writeInvalidInput(w, err)
+		return
+	}
+	render, err := renderQuery(r)
+	if err != nil {
+		writeInvalidInput(w, err)
+		return
+	}
 	name := r.URL.Query().Get("name")
 	category := r.URL.Query().Get("category")
 	technology := r.URL.Query().Get("technology")
 	authorIDStr := r.URL.Query().Get("author_id")
+	// Domains mapped to an author only list that author's projects
+	host := h.hostAuthor(r)
+	if authorIDStr == "" && name == "" && category == "" && technology == "" && host != nil {
+		authorIDStr = host.ID.Hex()
+	}
 
 	if name != "" {
-		project, err := h.service.GetProjectByName(ctx, name)
+		project, err := h.service.GetProjectByName(ctx, name, admin)
+		if err == nil && host != nil && project.AuthorID != host.ID {
+			err = mongo.ErrNoDocuments
+		}
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusNotFound)
 			return
 		}
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode([]*Project{project})
+		if archived != nil && project.Archived != *archived {
+			writeTimestampedList(w, r, []*Project{})
+			return
+		}
+		project.Localize(locale)
+		if render {
+			project.renderHTML()
+		}
+		writeTimestampedList(w, r, []*Project{project})
 		return
 	}
 
 	if category != "" {
-		projects, err := h.service.GetProjectsByCategory(ctx, category)
+		projects, err := h.service.GetProjectsByCategory(ctx, category, admin, host)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(projects)
+		projects = filterArchived(projects, archived)
+		localizeProjects(projects, locale)
+		renderProjectsHTML(projects, render)
+		writeTimestampedList(w, r, projects)
 		return
 	}
 
 	if technology != "" {
-		projects, err := h.service.GetProjectsByTechnology(ctx, technology)
+		projects, err := h.service.GetProjectsByTechnology(ctx, technology, admin, host)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(projects)
+		projects = filterArchived(projects, archived)
+		localizeProjects(projects, locale)
+		renderProjectsHTML(projects, render)
+		writeTimestampedList(w, r, projects)
 		return
 	}
 
@@ -954,54 +2115,101 @@ func (h *APIHandler) handleProjects(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, "Invalid author ID", http.StatusBadRequest)
 			return
 		}
-		projects, err := h.service.GetProjectsByAuthor(ctx, authorID)
+		// The author's own API tokens also see their unlisted and private projects
+		projects, err := h.service.GetProjectsByAuthor(ctx, authorID, admin || h.authorAccess(r, authorID, TokenScopeRead))
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(projects)
+		projects = filterArchived(projects, archived)
+		localizeProjects(projects, locale)
+		renderProjectsHTML(projects, render)
+		writeTimestampedList(w, r, projects)
 		return
 	}
 
-	// Get all projects
-	projects, err := h.service.GetAllProjects(ctx)
+	// Get all projects, streamed as NDJSON for large lists
+	if wantsNDJSON(r) {
+		cursor, err := h.service.FindProjects(ctx, admin, archived, r.URL.Query().Get("sort") == "recent")
+		if err != nil {
+			log.Printf("Date: %s | Route: /api/projects | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		count := streamNDJSON(ctx, w, cursor, func(project *Project) interface{} {
+			project.Localize(locale)
+			if render {
+				project.renderHTML()
+			}
+			return project
+		})
+		log.Printf("Date: %s | Route: /api/projects | Status: SUCCESS (%d streamed) | GPT Model: %s", currentTime, count, gptModel)
+		return
+	}
+	projects, err := h.service.GetAllProjects(ctx, admin, host)
 	if err != nil {
 		log.Printf("Date: %s | Route: /api/projects | Status: ERROR | GPT Model: %s", currentTime, gptModel)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	projects = filterArchived(projects, archived)
+	localizeProjects(projects, locale)
+	renderProjectsHTML(projects, render)
 	log.Printf("Date: %s | Route: /api/projects | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(projects)
+	writeTimestampedList(w, r, projects)
 }
 
-func (h *APIHandler) handleProjectsCount(w http.ResponseWriter, r *http.Request) {
-	currentTime := time.Now().Format("2006-01-02 15:04:05")
-	gptModel := "DISABLED"
-	if h.llmService != nil {
-		gptModel = h.llmService.model
-	}
-
+// Project sub-resource endpoints: /api/projects/{slug}/{resource}
+func (h *APIHandler) handleProjectRoutes(w http.ResponseWriter, r *http.Request) {
 	h.enableCORS(w)
 	if r.Method == "OPTIONS" {
 		return
 	}
 
-	ctx := context.Background()
-	count, err := h.service.CountProjects(ctx)
-	if err != nil {
-		log.Printf("Date: %s | Route: /api/projects/count | Status: ERROR | GPT Model: %s", currentTime, gptModel)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/projects/"), "/"), "/")
+	if len(parts) == 3 && parts[1] == "media" && parts[2] == "presign" {
+		h.handleProjectMediaPresign(w, r, parts[0])
+		return
+	}
+	if len(parts) == 1 && parts[0] != "" {
+		h.handleProjectBySlug(w, r, parts[0])
+		return
+	}
+	if len(parts) != 2 {
+		http.NotFound(w, r)
 		return
 	}
 
-	log.Printf("Date: %s | Route: /api/projects/count | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]int64{"count": count})
+	if parts[1] == "media" {
+		h.handleProjectMedia(w, r, parts[0])
+		return
+	}
+	if parts[1] == "visibility" {
+		h.handleProjectVisibility(w, r, parts[0])
+		return
+	}
+	if parts[1] == "archive" {
+		h.handleProjectArchive(w, r, parts[0])
+		return
+	}
+
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	switch parts[1] {
+	case "og.png":
+		h.handleProjectOGImage(w, r, parts[0], "png")
+	case "og.svg":
+		h.handleProjectOGImage(w, r, parts[0], "svg")
+	default:
+		http.NotFound(w, r)
+	}
 }
 
-// Education endpoints
+// Education endpoints: GET lists education records (?university=, ?major= or ?student_id= filter), POST
+// creates one (admin only)
 func (h *APIHandler) handleEducation(w http.ResponseWriter, r *http.Request) {
 	currentTime := time.Now().Format("2006-01-02 15:04:05")
 	gptModel := "DISABLED"
@@ -1014,38 +2222,89 @@ func (h *APIHandler) handleEducation(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	ctx := context.Background()
+
+	if r.Method == "POST" {
+		if !isAdminRequest(r) {
+			log.Printf("Date: %s | Route: /api/education | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		var education Education
+		if err := json.NewDecoder(r.Body).Decode(&education); err != nil {
+			log.Printf("Date: %s | Route: /api/education | Status: BAD_REQUEST | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, "Invalid JSON request", http.StatusBadRequest)
+			return
+		}
+		if err := validateEducation(&education); err != nil {
+			log.Printf("Date: %s | Route: /api/education | Status: INVALID_INPUT | GPT Model: %s", currentTime, gptModel)
+			writeInvalidInput(w, err)
+			return
+		}
+		student, err := h.service.GetAuthorByID(ctx, education.StudentID)
+		if err == mongo.ErrNoDocuments {
+			writeInvalidInput(w, invalidField("student_id", "exists", "student_id does not match an author"))
+			return
+		} else if err != nil {
+			log.Printf("Date: %s | Route: /api/education | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		education.ID = primitive.NilObjectID
+		education.StudentName = student.Name
+		if err := h.service.InsertEducation(ctx, &education); err != nil {
+			log.Printf("Date: %s | Route: /api/education | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Date: %s | Route: /api/education | Status: CREATED | GPT Model: %s", currentTime, gptModel)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(education)
+		return
+	}
+
 	if r.Method != "GET" {
 		log.Printf("Date: %s | Route: /api/education | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	ctx := context.Background()
+	locale := h.locales.Negotiate(r)
+	setLanguageHeaders(w, locale)
 
 	// Check for query parameters
 	university := r.URL.Query().Get("university")
 	major := r.URL.Query().Get("major")
 	studentIDStr := r.URL.Query().Get("student_id")
+	// Domains mapped to an author only list that author's education
+	host := h.hostAuthor(r)
+	if studentIDStr == "" && university == "" && major == "" && host != nil {
+		studentIDStr = host.ID.Hex()
+	}
 
 	if university != "" {
-		education, err := h.service.GetEducationByUniversity(ctx, university)
+		education, err := h.service.GetEducationByUniversity(ctx, university, host)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(education)
+		localizeEducation(education, locale)
+		writeTimestampedList(w, r, education)
 		return
 	}
 
 	if major != "" {
-		education, err := h.service.GetEducationByMajor(ctx, major)
+		education, err := h.service.GetEducationByMajor(ctx, major, host)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(education)
+		localizeEducation(education, locale)
+		writeTimestampedList(w, r, education)
 		return
 	}
 
@@ -1060,24 +2319,25 @@ func (h *APIHandler) handleEducation(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(education)
+		localizeEducation(education, locale)
+		writeTimestampedList(w, r, education)
 		return
 	}
 
 	// Get all education
-	education, err := h.service.GetAllEducation(ctx)
+	education, err := h.service.GetAllEducation(ctx, host)
 	if err != nil {
 		log.Printf("Date: %s | Route: /api/education | Status: ERROR | GPT Model: %s", currentTime, gptModel)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	localizeEducation(education, locale)
 	log.Printf("Date: %s | Route: /api/education | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(education)
+	writeTimestampedList(w, r, education)
 }
 
-func (h *APIHandler) handleEducationCount(w http.ResponseWriter, r *http.Request) {
+// Resumes endpoints: GET lists resumes (?author_id= or ?skill= filter), POST creates one (admin only)
+func (h *APIHandler) handleResumes(w http.ResponseWriter, r *http.Request) {
 	currentTime := time.Now().Format("2006-01-02 15:04:05")
 	gptModel := "DISABLED"
 	if h.llmService != nil {
@@ -1089,32 +2349,10 @@ func (h *APIHandler) handleEducationCount(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	ctx := context.Background()
-	count, err := h.service.CountEducation(ctx)
-	if err != nil {
-		log.Printf("Date: %s | Route: /api/education/count | Status: ERROR | GPT Model: %s", currentTime, gptModel)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-
-	log.Printf("Date: %s | Route: /api/education/count | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]int64{"count": count})
-}
-
-// Resumes endpoints
-func (h *APIHandler) handleResumes(w http.ResponseWriter, r *http.Request) {
-	currentTime := time.Now().Format("2006-01-02 15:04:05")
-	gptModel := "DISABLED"
-	if h.llmService != nil {
-		gptModel = h.llmService.model
-	}
-
-	h.enableCORS(w)
-	if r.Method == "OPTIONS" {
+	if r.Method == "POST" {
+		h.handleResumeCreate(w, r)
 		return
 	}
-
 	if r.Method != "GET" {
 		log.Printf("Date: %s | Route: /api/resumes | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -1122,10 +2360,17 @@ func (h *APIHandler) handleResumes(w http.ResponseWriter, r *http.Request) {
 	}
 
 	ctx := context.Background()
+	// Contact details are only returned in full to admins
+	admin := isAdminRequest(r)
 
 	// Check for query parameters
 	authorIDStr := r.URL.Query().Get("author_id")
 	skill := r.URL.Query().Get("skill")
+	// Domains mapped to an author only list that author's resume
+	host := h.hostAuthor(r)
+	if authorIDStr == "" && skill == "" && host != nil {
+		authorIDStr = host.ID.Hex()
+	}
 
 	if authorIDStr != "" {
 		authorID, err := primitive.ObjectIDFromHex(authorIDStr)
@@ -1138,19 +2383,23 @@ func (h *APIHandler) handleResumes(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, err.Error(), http.StatusNotFound)
 			return
 		}
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode([]*Resume{resume})
+		if !admin {
+			resume.redactContact()
+		}
+		writeTimestampedList(w, r, []*Resume{resume})
 		return
 	}
 
 	if skill != "" {
-		resumes, err := h.service.GetResumesBySkill(ctx, skill)
+		resumes, err := h.service.GetResumesBySkill(ctx, skill, host)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(resumes)
+		if !admin {
+			redactResumes(resumes)
+		}
+		writeTimestampedList(w, r, resumes)
 		return
 	}
 
@@ -1161,38 +2410,20 @@ func (h *APIHandler) handleResumes(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	log.Printf("Date: %s | Route: /api/resumes | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(resumes)
-}
-
-func (h *APIHandler) handleResumesCount(w http.ResponseWriter, r *http.Request) {
-	currentTime := time.Now().Format("2006-01-02 15:04:05")
-	gptModel := "DISABLED"
-	if h.llmService != nil {
-		gptModel = h.llmService.model
-	}
-
-	h.enableCORS(w)
-	if r.Method == "OPTIONS" {
-		return
-	}
-
-	ctx := context.Background()
-	count, err := h.service.CountResumes(ctx)
-	if err != nil {
-		log.Printf("Date: %s | Route: /api/resumes/count | Status: ERROR | GPT Model: %s", currentTime, gptModel)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+	if !admin {
+		redactResumes(resumes)
 	}
-
-	log.Printf("Date: %s | Route: /api/resumes/count | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]int64{"count": count})
+	log.Printf("Date: %s | Route: /api/resumes | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+	writeTimestampedList(w, r, resumes)
 }
 
 // Search endpoint for LLM integration
 func (h *APIHandler) handleSearch(w http.ResponseWriter, r *http.Request) {
+	h.serveSearch(w, r, h.hostAuthor(r))
+}
+
+// serveSearch searches the portfolio, only one author's documents when author is set
+func (h *APIHandler) serveSearch(w http.ResponseWriter, r *http.Request, author *Author) {
 	currentTime := time.Now().Format("2006-01-02 15:04:05")
 	gptModel := "DISABLED"
 	if h.llmService != nil {
@@ -1211,27 +2442,76 @@ func (h *APIHandler) handleSearch(w http.ResponseWriter, r *http.Request) {
 	}
 
 	query := r.URL.Query().Get("q")
-	if query == "" {
+	var v Validator
+	v.Required("q", query)
+	// ?types=projects,posts searches only those collections
+	var types []string
+	if value := r.URL.Query().Get("types"); value != "" {
+		for _, name := range strings.Split(value, ",") {
+			name = strings.TrimSpace(name)
+			v.OneOf("types", name, searchTypes...)
+			types = append(types, name)
+		}
+	}
+	if err := v.Err(); err != nil {
 		log.Printf("Date: %s | Route: /api/search | Status: BAD_REQUEST | GPT Model: %s", currentTime, gptModel)
-		http.Error(w, "Query parameter 'q' is required", http.StatusBadRequest)
+		writeInvalidInput(w, err)
 		return
 	}
 
 	ctx := context.Background()
-	results, err := h.service.SearchAll(ctx, query)
+	var authorID primitive.ObjectID
+	if author != nil {
+		authorID = author.ID
+	}
+	results, failures, err := h.service.SearchAllForAuthor(ctx, query, authorID, types...)
 	if err != nil {
 		log.Printf("Date: %s | Route: /api/search | Status: ERROR | GPT Model: %s", currentTime, gptModel)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	log.Printf("Date: %s | Route: /api/search | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+	// Partial results keep the collections that were searched and list the failed ones under "errors"
+	if len(failures) > 0 {
+		log.Printf("Date: %s | Route: /api/search | Status: PARTIAL (%d collections failed) | GPT Model: %s", currentTime, len(failures), gptModel)
+		results["errors"] = failures
+		w.Header().Set("X-Partial-Results", "true")
+	} else {
+		log.Printf("Date: %s | Route: /api/search | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+	}
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(results)
 }
 
+// ChatbotRequest is the body of POST /api/chatbot
+type ChatbotRequest struct {
+	Query     string `json:"query"`
+	SessionID string `json:"session_id"`      // Returned by the first answer; keeps the session in one experiment variant
+	Style     string `json:"style,omitempty"` // recruiter, technical or casual; the default suits any visitor
+}
+
+// ChatbotResponse is the answer to POST /api/chatbot; only the response and query are set while the
+// chatbot is disabled
+type ChatbotResponse struct {
+	Response  string `json:"response"`
+	Query     string `json:"query"`
+	SessionID string `json:"session_id,omitempty"`
+	TurnID    string `json:"turn_id,omitempty"`    // For POST /api/chatbot/feedback
+	RequestID string `json:"request_id,omitempty"` // Finds the chat trace
+
+	// How well the portfolio supports the answer: high, medium, low or none; empty if the model did not say.
+	// InsufficientData is set for "none", when the reply says the portfolio does not contain the answer.
+	Confidence       string `json:"confidence,omitempty"`
+	InsufficientData bool   `json:"insufficient_data"`
+}
+
 // Chatbot endpoint
 func (h *APIHandler) handleChatbot(w http.ResponseWriter, r *http.Request) {
+	h.serveChatbot(w, r, h.hostAuthor(r))
+}
+
+// serveChatbot answers a chatbot question, from one author's portfolio when author is set
+func (h *APIHandler) serveChatbot(w http.ResponseWriter, r *http.Request, author *Author) {
 	currentTime := time.Now().Format("2006-01-02 15:04:05")
 	gptModel := "DISABLED"
 	if h.llmService != nil {
@@ -1240,9 +2520,10 @@ func (h *APIHandler) handleChatbot(w http.ResponseWriter, r *http.Request) {
 
 	// Add recovery to prevent server crashes
 	defer func() {
-		if r := recover(); r != nil {
+		if recovered := recover(); recovered != nil {
 			log.Printf("Date: %s | Route: /api/chatbot | Status: PANIC | GPT Model: %s", currentTime, gptModel)
-			log.Printf("Chatbot handler panic: %v", r)
+			log.Printf("Chatbot handler panic: %v", recovered)
+			errorReporter().CapturePanic(recovered, r, map[string]string{"gpt_model": gptModel})
 			http.Error(w, "Internal server error", http.StatusInternalServerError)
 		}
 	}()
@@ -1258,19 +2539,22 @@ func (h *APIHandler) handleChatbot(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get client IP and check rate limiting
-	clientIP := getClientIP(r)
-	if !h.rateLimiter.IsAllowed(clientIP) {
+	// Visitors with a token are limited one by one; the others share their IP's limit. Every IP also has an
+	// overall limit, so collecting tokens does not multiply the chat quota.
+	clientIP := trustedClientIP(r)
+	visitorID := h.visitors.Identify(w, r)
+	limitKey := clientIP
+	if visitorID != "" {
+		limitKey = "visitor:" + visitorID
+	}
+	if !h.rateLimiter.IsAllowedFor(clientIP, limitKey) || (visitorID != "" && !h.chatIPLimiter.IsAllowed(clientIP)) {
 		log.Printf("Date: %s | Route: /api/chatbot | Status: RATE_LIMITED | GPT Model: %s", currentTime, gptModel)
-		log.Printf("Rate limit exceeded for IP: %s", clientIP)
+		log.Printf("Rate limit exceeded for %s", limitKey)
 		http.Error(w, "Rate limit exceeded. Please wait before making another request.", http.StatusTooManyRequests)
 		return
 	}
 
-	var request struct {
-		Query string `json:"query"`
-	}
-
+	var request ChatbotRequest
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
 		log.Printf("Date: %s | Route: /api/chatbot | Status: BAD_REQUEST | GPT Model: %s", currentTime, gptModel)
 		log.Printf("Error decoding chatbot request: %v", err)
@@ -1279,10 +2563,10 @@ func (h *APIHandler) handleChatbot(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Validate input
-	if err := validateChatbotInput(request.Query); err != nil {
+	if err := validateChatbotInput(request.Query, request.Style); err != nil {
 		log.Printf("Date: %s | Route: /api/chatbot | Status: INVALID_INPUT | GPT Model: %s", currentTime, gptModel)
 		log.Printf("Invalid chatbot input from %s: %v", clientIP, err)
-		http.Error(w, fmt.Sprintf("Invalid input: %v", err), http.StatusBadRequest)
+		writeInvalidInput(w, err)
 		return
 	}
 
@@ -1292,15 +2576,65 @@ func (h *APIHandler) handleChatbot(w http.ResponseWriter, r *http.Request) {
 		log.Printf("Date: %s | Route: /api/chatbot | Status: LLM_DISABLED | GPT Model: %s", currentTime, gptModel)
 		log.Printf("LLM service is nil, chatbot disabled")
 		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(map[string]string{
-			"response": "Sorry, the chatbot is currently unavailable. Please ensure OPENAI_API_KEY is configured.",
-			"query":    request.Query,
+		json.NewEncoder(w).Encode(ChatbotResponse{
+			Response: "Sorry, the chatbot is currently unavailable. Please ensure OPENAI_API_KEY is configured.",
+			Query:    request.Query,
 		})
 		return
 	}
 
 	ctx := context.Background()
-	response, err := h.llmService.ProcessQuery(ctx, request.Query)
+	// Without a session of its own, a visitor's questions share one session across IP changes
+	if !chatSessionPattern.MatchString(request.SessionID) {
+		request.SessionID = visitorID
+		if request.SessionID == "" {
+			request.SessionID = newChatSessionID()
+		}
+	}
+
+	// Sessions are split between the variants of the running prompt experiment, if any
+	turn := ChatTurn{SessionID: request.SessionID, Style: request.Style, Model: h.llmService.model}
+	var variant *PromptVariant
+	experiment, err := h.service.GetActiveExperiment(ctx)
+	if err != nil {
+		log.Printf("Error loading active experiment: %v", err)
+	} else if experiment != nil {
+		if variant = assignVariant(experiment, request.SessionID); variant != nil {
+			turn.ExperimentID = &experiment.ID
+			turn.Variant = variant.Name
+			if variant.Model != "" {
+				turn.Model = variant.Model
+			}
+		}
+	}
+
+	// Answers in the default language keep the prompt unchanged
+	locale := h.locales.Negotiate(r)
+	if locale == h.locales.Default() {
+		locale = ""
+	}
+
+	start := time.Now()
+	trace := &ChatTrace{RequestID: requestID(w, r), SessionID: request.SessionID, Question: request.Query, Model: turn.Model, CreatedAt: start}
+	answer, contextString, err := h.llmService.answerQuery(withChatTrace(ctx, trace), request.Query, author, variant, locale, request.Style)
+	trace.LatencyMs = time.Since(start).Milliseconds()
+	if err != nil {
+		if trace.Outcome == "" {
+			trace.Outcome = ChatTraceLLMError
+		}
+		trace.Error = err.Error()
+	} else {
+		trace.Outcome = ChatTraceSuccess
+		trace.Confidence = answer.Confidence
+	}
+	if err := h.service.RecordChatTrace(ctx, trace); err != nil {
+		log.Printf("Error recording chat trace: %v", err)
+	}
+	if errors.Is(err, errLLMBusy) {
+		log.Printf("Date: %s | Route: /api/chatbot | Status: LLM_BUSY | GPT Model: %s", currentTime, gptModel)
+		writeLLMBusy(w)
+		return
+	}
 	if err != nil {
 		log.Printf("Date: %s | Route: /api/chatbot | Status: LLM_ERROR | GPT Model: %s", currentTime, gptModel)
 		log.Printf("Error processing chatbot query: %v", err)
@@ -1311,10 +2645,30 @@ func (h *APIHandler) handleChatbot(w http.ResponseWriter, r *http.Request) {
 	log.Printf("Date: %s | Route: /api/chatbot | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
 	log.Printf("Chatbot response generated successfully")
 
+	turn.LatencyMs = time.Since(start).Milliseconds()
+	turn.CreatedAt = time.Now()
+	turn.Confidence = answer.Confidence
+	judge := h.chatJudge.Sample()
+	if judge {
+		turn.Question = request.Query
+		turn.Answer = answer.Text
+	}
+	if err := h.service.RecordChatTurn(ctx, &turn); err != nil {
+		log.Printf("Error recording chat turn: %v", err)
+	} else if judge {
+		// Scored in the background so the visitor doesn't wait for a second model call
+		go h.judgeChatTurn(turn.ID, request.Query, contextString, answer.Text)
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{
-		"response": response,
-		"query":    request.Query,
+	json.NewEncoder(w).Encode(ChatbotResponse{
+		Response:         answer.Text,
+		Query:            request.Query,
+		SessionID:        request.SessionID,
+		TurnID:           turn.ID.Hex(),
+		RequestID:        trace.RequestID,
+		Confidence:       answer.Confidence,
+		InsufficientData: answer.InsufficientData(),
 	})
 }
 
@@ -1324,6 +2678,18 @@ func main() {
 		log.Println("Warning: Could not load .env file, using system environment variables")
 	}
 
+	// `portfolio --check` runs the self-checks and exits instead of serving
+	if len(os.Args) > 1 && os.Args[1] == "--check" {
+		os.Exit(runCheckCommand())
+	}
+
+	// `portfolio client-ts` prints the generated TypeScript client, e.g. to publish it as a build artifact
+	if len(os.Args) > 1 && os.Args[1] == "client-ts" {
+		source, _ := typeScriptClient()
+		fmt.Print(source)
+		return
+	}
+
 	// Connect to MongoDB
 	client, err := connectToMongoDB()
 	if err != nil {
@@ -1334,34 +2700,233 @@ func main() {
 	// Create portfolio service
 	service := NewPortfolioService(client)
 
+	// Contact details are encrypted at rest when FIELD_ENCRYPTION_KEY is set
+	if _, err := fieldCipher(); err != nil {
+		log.Fatal("Invalid field encryption configuration:", err)
+	}
+	if updated, err := service.EncryptContactFields(context.Background()); err != nil {
+		log.Printf("Failed to encrypt existing contact details: %v", err)
+	} else if updated > 0 {
+		log.Printf("Encrypted contact details of %d existing documents", updated)
+	}
+
 	// Create LLM service (will be nil if API key not provided)
 
 	openaiAPIKey := os.Getenv("OPENAI_API_KEY")
 	llmService := NewLLMService(openaiAPIKey, service)
 
+	// `portfolio eval` runs the golden questions and exits instead of serving
+	if len(os.Args) > 1 && os.Args[1] == "eval" {
+		os.Exit(runEvalCommand(llmService, os.Args[2:]))
+	}
+
+	// Experience used to be embedded in resumes; it now lives in its own collection
+	if migrated, err := service.MigrateResumeExperience(context.Background()); err != nil {
+		log.Printf("Failed to migrate resume experience: %v", err)
+	} else if migrated > 0 {
+		log.Printf("Moved the experience of %d resumes to the experience collection", migrated)
+	}
+
+	// Word counts and reading times are stored on write; older posts and projects get theirs once
+	if backfilled, err := service.BackfillReadingStats(context.Background()); err != nil {
+		log.Printf("Failed to backfill reading stats: %v", err)
+	} else if backfilled > 0 {
+		log.Printf("Computed the reading stats of %d posts and projects", backfilled)
+	}
+
+	// Slugs are stored on write; older projects get theirs once
+	if backfilled, err := service.BackfillProjectSlugs(context.Background()); err != nil {
+		log.Printf("Failed to backfill project slugs: %v", err)
+	} else if backfilled > 0 {
+		log.Printf("Stored the slugs of %d projects", backfilled)
+	}
+
+	// In-memory rate limits survive restarts through snapshots in MongoDB
+	if restored, err := service.RestoreRateLimits(context.Background()); err != nil {
+		log.Printf("Failed to restore rate limits: %v", err)
+	} else if restored > 0 {
+		log.Printf("Restored rate limits of %d clients", restored)
+	}
+
 	// Create API handler
 	handler := NewAPIHandler(service, llmService)
 
-	// Start rate limiter cleanup goroutine
+	// Scheduled tasks; each schedule can be overridden with SCHEDULE_<NAME>, e.g. SCHEDULE_REPO_SYNC="0 */6 * * *"
+	handler.scheduler.RegisterLocal("cleanup", "*/5 * * * *", handler.cleanup)
+	handler.scheduler.RegisterLocal("rate_limit_snapshot", "* * * * *", service.SaveRateLimits)
+	if err := handler.ipRules.Reload(context.Background()); err != nil {
+		log.Printf("Failed to load IP rules: %v", err)
+	}
+	handler.scheduler.RegisterLocal("ip_rules", "* * * * *", handler.ipRules.Reload)
+	// On SIGINT or SIGTERM the server finishes the requests in flight, then the latest counters are saved,
+	// so requests since the last snapshot still count, and main returns to disconnect from MongoDB
+	server := &http.Server{}
+	stopped := make(chan struct{})
 	go func() {
-		ticker := time.NewTicker(5 * time.Minute)
-		defer ticker.Stop()
-		for range ticker.C {
-			handler.rateLimiter.Cleanup()
+		defer close(stopped)
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+		<-signals
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		if err := server.Shutdown(ctx); err != nil {
+			log.Printf("Failed to shut down gracefully: %v", err)
+		}
+		if err := service.SaveRateLimits(ctx); err != nil {
+			log.Printf("Failed to save rate limits: %v", err)
 		}
 	}()
 
+	// Threshold alerts by email or webhook; every replica checks the error rate of the requests it served
+	mailer := NewMailerFromEnv()
+	if alerter := NewAlerter(service, handler.metrics, mailer); alerter != nil {
+		handler.scheduler.RegisterLocal("alerts", "*/5 * * * *", alerter.Check)
+	}
+
+	// Hourly request totals for the usage digest, flushed by every replica; the digest itself is emailed
+	// when DIGEST_EMAIL is set
+	handler.scheduler.RegisterLocal("request_stats", "*/5 * * * *", func(ctx context.Context) error {
+		return handler.metrics.Flush(ctx, service)
+	})
+	if digest := NewUsageDigest(service, mailer); digest != nil {
+		handler.jobs.Register("usage_digest", digest.Send)
+		handler.scheduler.ScheduleJob(handler.jobs, "usage_digest", digest.spec)
+	}
+
+	// Purge chat logs, analytics and contact messages past their retention period
+	if len(handler.retention.policies) > 0 {
+		handler.jobs.Register("retention_purge", handler.retention.RunJob)
+		handler.scheduler.ScheduleJob(handler.jobs, "retention_purge", "@every "+handler.retention.interval.String())
+	}
+
+	// Repository sync (disabled if no GitHub/GitLab/Bitbucket account is configured)
+	if repoSyncer := NewRepoSyncer(service); repoSyncer != nil {
+		handler.jobs.Register("repo_sync", func(ctx context.Context, _ bson.M) error { return repoSyncer.Sync(ctx) })
+		handler.scheduler.ScheduleJob(handler.jobs, "repo_sync", "@every "+repoSyncer.interval.String())
+	}
+
+	// Project and resume summaries for chatbot context (disabled without OPENAI_API_KEY)
+	if summarizer := NewDocumentSummarizer(llmService); summarizer != nil {
+		handler.jobs.Register("document_summaries", func(ctx context.Context, _ bson.M) error {
+			_, err := summarizer.Summarize(ctx)
+			return err
+		})
+		handler.scheduler.ScheduleJob(handler.jobs, "document_summaries", "@every "+summarizer.interval.String())
+	}
+
+	// Credly badge sync (disabled if CREDLY_USERNAME is not set)
+	if credlySyncer := NewCredlySyncer(service); credlySyncer != nil {
+		handler.jobs.Register("credly_sync", func(ctx context.Context, _ bson.M) error { return credlySyncer.Sync(ctx) })
+		handler.scheduler.ScheduleJob(handler.jobs, "credly_sync", "@every "+credlySyncer.interval.String())
+	}
+
+	// Start the background job workers and the scheduler once every job type is registered
+	go handler.jobs.Run(context.Background())
+	go handler.scheduler.Run(context.Background())
+
+	// Data change events for /api/events, from the MongoDB change streams (replica sets only)
+	go handler.events.Watch(context.Background())
+
 	// Setup routes
 	http.HandleFunc("/api/authors", handler.handleAuthors)
-	http.HandleFunc("/api/authors/count", handler.handleAuthorsCount)
+	http.HandleFunc("/api/counts", handler.handleCounts)
+	http.HandleFunc("/api/changelog", handler.handleChangelog)
+	http.HandleFunc("/api/events", handler.handleEvents)
+	http.HandleFunc("/api/client.ts", handler.handleTypeScriptClient)
+	http.HandleFunc("/api/authors/count", handler.handleCollectionCount("authors"))
+	http.HandleFunc("/api/authors/", handler.handleAuthorRoutes)
 	http.HandleFunc("/api/projects", handler.handleProjects)
-	http.HandleFunc("/api/projects/count", handler.handleProjectsCount)
+	http.HandleFunc("/api/projects/count", handler.handleCollectionCount("projects"))
+	http.HandleFunc("/api/projects/", handler.handleProjectRoutes)
 	http.HandleFunc("/api/education", handler.handleEducation)
-	http.HandleFunc("/api/education/count", handler.handleEducationCount)
+	http.HandleFunc("/api/education/count", handler.handleCollectionCount("education"))
+	http.HandleFunc("/api/education/", handler.handleEducationRoutes)
 	http.HandleFunc("/api/resumes", handler.handleResumes)
-	http.HandleFunc("/api/resumes/count", handler.handleResumesCount)
+	http.HandleFunc("/api/experience", handler.handleExperience)
+	http.HandleFunc("/api/experience/", handler.handleExperienceRoutes)
+	http.HandleFunc("/api/resumes/count", handler.handleCollectionCount("resumes"))
+	http.HandleFunc("/api/resumes/", handler.handleResumeRoutes)
+	http.HandleFunc("/api/posts", handler.handlePosts)
+	http.HandleFunc("/api/posts/count", handler.handleCollectionCount("posts"))
+	http.HandleFunc("/api/posts/", handler.handlePostRoutes)
+	http.HandleFunc("/api/testimonials", handler.handleTestimonials)
+	http.HandleFunc("/api/testimonials/", handler.handleTestimonialRoutes)
+	http.HandleFunc("/api/awards", handler.handleAwards)
+	http.HandleFunc("/api/awards/count", handler.handleCollectionCount("awards"))
+	http.HandleFunc("/api/publications", handler.handlePublications)
+	http.HandleFunc("/api/talks", handler.handleTalks)
+	http.HandleFunc("/api/skills", handler.handleSkills)
+	http.HandleFunc("/api/skills/matrix", handler.handleSkillsMatrix)
+	http.HandleFunc("/api/skills/evidence", handler.handleSkillEvidence)
+	http.HandleFunc("/api/now", handler.handleNow)
+	http.HandleFunc("/api/availability", handler.handleAvailability)
+	http.HandleFunc("/api/availability/book", handler.handleAvailabilityBook)
+	http.HandleFunc("/api/analytics/event", handler.handleAnalyticsEvent)
+	http.HandleFunc("/api/analytics/daily", handler.handleAnalyticsDaily)
+	http.HandleFunc("/api/analytics/top", handler.handleAnalyticsTop)
+	http.HandleFunc("/api/analytics/rollups/", handler.handleAnalyticsRollup)
+	http.HandleFunc("/api/media/", handler.handleMedia)
+	http.HandleFunc("/api/timeline", handler.handleTimeline)
+	http.HandleFunc("/api/timeline.ics", handler.handleTimelineCalendar)
+	http.HandleFunc("/api/recent", handler.handleRecentChanges)
 	http.HandleFunc("/api/search", handler.handleSearch)
+	http.HandleFunc("/api/batch", handler.handleBatch)
+	http.HandleFunc("/api/portfolio/markdown", handler.handlePortfolioMarkdown)
+	http.HandleFunc("/api/badges/", handler.handleBadges)
+	http.HandleFunc("/feed.xml", handler.handleFeed)
+	http.HandleFunc("/resume", handler.handleResumePage)
+	http.HandleFunc("/projects/", handler.handleProjectPage)
+
+	// Author-scoped routes such as /api/{author}/chatbot; registered API routes take precedence
+	http.HandleFunc("/api/", handler.handleAuthorScopedRoutes)
+
+	// Serve the built frontend so the whole site runs as a single process
+	if spa := newSPAHandlerFromEnv(); spa != nil {
+		http.Handle("/", spa)
+	}
 	http.HandleFunc("/api/chatbot", handler.handleChatbot)
+	http.HandleFunc("/api/chatbot/feedback", handler.handleChatbotFeedback)
+	http.HandleFunc("/api/widget/config", handler.handleWidgetConfig)
+	http.HandleFunc("/api/chatbot/report", handler.handleChatbotReport)
+	http.HandleFunc("/api/webhooks/github", handler.handleGitHubWebhook)
+	http.HandleFunc("/api/github/activity", handler.handleGitHubActivity)
+	http.HandleFunc("/api/stats/coding", handler.handleCodingStats)
+	http.HandleFunc("/api/stats/profiles", handler.handleProfileStats)
+	http.HandleFunc("/api/certifications", handler.handleCertifications)
+	http.HandleFunc("/api/admin/import/linkedin", handler.handleLinkedInImport)
+	http.HandleFunc("/api/admin/import/jsonresume", handler.handleJSONResumeImport)
+	http.HandleFunc("/api/admin/import/skills", handler.handleSkillsImport)
+	http.HandleFunc("/api/admin/import/resume", handler.handleResumeUpload)
+	http.HandleFunc("/api/tools/cover-letter", handler.handleCoverLetter)
+	http.HandleFunc("/api/tools/match", handler.handleJobMatch)
+	http.HandleFunc("/api/tools/interview-questions", handler.handleInterviewQuestions)
+	http.HandleFunc("/api/admin/metrics", handler.handleMetrics)
+	http.HandleFunc("/api/admin/experiments", handler.handleExperiments)
+	http.HandleFunc("/api/admin/experiments/", handler.handleExperimentRoutes)
+	http.HandleFunc("/api/admin/evals", handler.handleEvals)
+	http.HandleFunc("/api/admin/evals/", handler.handleEvalRoutes)
+	http.HandleFunc("/api/admin/chats", handler.handleChatReview)
+	http.HandleFunc("/api/admin/chat-traces", handler.handleChatTraces)
+	http.HandleFunc("/api/admin/chat-traces/", handler.handleChatTraces)
+	http.HandleFunc("/api/admin/export", handler.handleExport)
+	http.HandleFunc("/api/admin/chat-preview", handler.handleChatPreview)
+	http.HandleFunc("/api/admin/domains", handler.handleDomains)
+	http.HandleFunc("/api/admin/domains/", handler.handleDomainRoutes)
+	http.HandleFunc("/api/admin/retention", handler.handleRetention)
+	http.HandleFunc("/api/admin/jobs", handler.handleJobs)
+	http.HandleFunc("/api/admin/jobs/", handler.handleJobRoutes)
+	http.HandleFunc("/api/admin/diagnostics", handler.handleDiagnostics)
+	http.HandleFunc("/api/admin/rate-limits", handler.handleRateLimits)
+	http.HandleFunc("/api/admin/rate-limits/", handler.handleRateLimits)
+	http.HandleFunc("/api/admin/audit", handler.handleAuditLog)
+	http.HandleFunc("/api/admin/diff", handler.handlePortfolioDiff)
+	http.HandleFunc("/api/admin/order/", handler.handleDisplayOrder)
+	http.HandleFunc("/api/tokens", handler.handleAPITokens)
+	http.HandleFunc("/api/tokens/", handler.handleAPITokens)
+	http.HandleFunc("/healthz", handleHealthz)
+	for _, route := range honeypotRoutes {
+		http.HandleFunc(route, handler.handleHoneypot)
+	}
 
 	// Get port from environment or use default
 	port := os.Getenv("PORT")
@@ -1387,9 +2952,16 @@ func main() {
 		fmt.Println("\n⚠️  Chatbot is DISABLED (set OPENAI_API_KEY environment variable to enable)")
 	}
 
-	fmt.Println("\nNOTE: All endpoints except chatbot are read-only. No create/update/delete operations are available.")
+	fmt.Println("\nNOTE: Public endpoints are read-only apart from chatbot, webhooks and testimonial submissions. Write and moderation endpoints require ADMIN_TOKEN.")
 
-	if err := http.ListenAndServe(":"+port, nil); err != nil {
+	// Requests are recorded per route for /api/admin/metrics; reads fall back to snapshots while MongoDB is down
+	// Panics and 5xx responses are reported when SENTRY_DSN is set; low-priority requests are shed under overload
+	// and banned IPs refused. Deprecated routes and fields are announced in response headers.
+	shedder := NewLoadShedder()
+	server.Addr = ":" + port
+	server.Handler = handler.ipRules.Middleware(handler.autoBanner.Middleware(deprecationMiddleware(shedder.Middleware(handler.snapshots.Middleware(errorReporter().Middleware(handler.metrics.Middleware(http.DefaultServeMux)))))))
+	if err := server.ListenAndServe(); err != http.ErrServerClosed {
 		log.Fatal("Server failed to start:", err)
 	}
+	<-stopped
 }
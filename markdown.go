@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+	"regexp"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/util"
+)
+
+// Raw HTML in markdown is passed through by goldmark and then sanitized, so authors can
+// use safe inline HTML while scripts, event handlers and javascript: links are stripped.
+// Fenced code blocks are highlighted; only the classes of the highlighter survive sanitizing.
+var (
+	markdownRenderer = goldmark.New(
+		goldmark.WithExtensions(extension.GFM),
+		goldmark.WithRendererOptions(
+			html.WithUnsafe(),
+			renderer.WithNodeRenderers(util.Prioritized(&highlightRenderer{}, 100)),
+		),
+	)
+	markdownPolicy = func() *bluemonday.Policy {
+		policy := bluemonday.UGCPolicy()
+		policy.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#.-]+$`)).OnElements("code")
+		policy.AllowAttrs("class").Matching(regexp.MustCompile(`^hl-[a-z]$`)).OnElements("span")
+		return policy
+	}()
+)
+
+// renderMarkdown converts markdown to sanitized HTML
+func renderMarkdown(source string) (string, error) {
+	var buf bytes.Buffer
+	if err := markdownRenderer.Convert([]byte(source), &buf); err != nil {
+		return "", err
+	}
+	return markdownPolicy.Sanitize(buf.String()), nil
+}
+
+// markdownHTML renders markdown for the HTML pages; text that fails to render is shown escaped
+func markdownHTML(source string) template.HTML {
+	rendered, err := renderMarkdown(source)
+	if err != nil {
+		return template.HTML(template.HTMLEscapeString(source))
+	}
+	return template.HTML(rendered)
+}
+
+// renderQuery reads the ?render=html option of endpoints returning markdown fields, which adds their
+// rendered, sanitized HTML to the response
+func renderQuery(r *http.Request) (bool, error) {
+	value := r.URL.Query().Get("render")
+	if value == "" {
+		return false, nil
+	}
+	var v Validator
+	v.OneOf("render", value, "html")
+	return true, v.Err()
+}
+
+// renderHTML sets description_html from the markdown description
+func (project *Project) renderHTML() {
+	project.DescriptionHTML = string(markdownHTML(project.Description))
+}
+
+// renderHTML renders the body again, so posts stored before a renderer change get its current output, and
+// sets summary_html
+func (post *Post) renderHTML() {
+	post.BodyHTML = string(markdownHTML(post.Body))
+	if post.Summary != "" {
+		post.SummaryHTML = string(markdownHTML(post.Summary))
+	}
+}
+
+// renderProjectsHTML renders the descriptions of a list when render is set
+func renderProjectsHTML(projects []Project, render bool) {
+	if !render {
+		return
+	}
+	for i := range projects {
+		projects[i].renderHTML()
+	}
+}
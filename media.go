@@ -0,0 +1,619 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	_ "golang.org/x/image/webp"
+)
+
+// Image types accepted for upload, detected from the file content rather than the client's header.
+// SVG is deliberately excluded since it can carry scripts.
+var allowedMediaTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// maxMediaUploadSize reads the upload limit from MEDIA_MAX_UPLOAD_MB (default 10MB)
+func maxMediaUploadSize() int64 {
+	if value := os.Getenv("MEDIA_MAX_UPLOAD_MB"); value != "" {
+		if mb, err := strconv.Atoi(value); err == nil && mb > 0 {
+			return int64(mb) << 20
+		}
+	}
+	return 10 << 20
+}
+
+// errUnsupportedMediaType is returned by inspectMediaImage for content that is not an allowed image type
+var errUnsupportedMediaType = errors.New("unsupported file type (allowed: PNG, JPEG, GIF, WebP)")
+
+// inspectMediaImage detects an upload's content type and dimensions from its bytes
+func inspectMediaImage(data []byte) (string, image.Config, error) {
+	contentType := http.DetectContentType(data)
+	if !allowedMediaTypes[contentType] {
+		return contentType, image.Config{}, errUnsupportedMediaType
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return contentType, image.Config{}, errors.New("file is not a valid image")
+	}
+	return contentType, config, nil
+}
+
+// mediaURL is the API path a media file is served from
+func mediaURL(id primitive.ObjectID) string {
+	return "/api/media/" + id.Hex()
+}
+
+// mediaCacheControl lets shared caches keep the media of public projects for good; media of unlisted and
+// private projects must not outlive a visibility change in a cache
+func mediaCacheControl(project *Project) string {
+	if project.isPublic() {
+		return "public, max-age=31536000, immutable"
+	}
+	return "private, no-store"
+}
+
+// mediaStoreFor returns the store holding a media file's content
+func (ps *PortfolioService) mediaStoreFor(media *Media) (MediaStore, error) {
+	name := media.Storage
+	if name == "" {
+		name = "gridfs"
+	}
+	store, ok := ps.mediaStores[name]
+	if !ok {
+		return nil, fmt.Errorf("media %s is stored in %s, which is not configured", media.ID.Hex(), name)
+	}
+	return store, nil
+}
+
+// setMediaURL points clients at the store's public URL when it has one, so they can fetch from a CDN
+func (ps *PortfolioService) setMediaURL(media *Media) {
+	media.URL = mediaURL(media.ID)
+	if store, err := ps.mediaStoreFor(media); err == nil {
+		if publicURL := store.PublicURL(media.ID); publicURL != "" {
+			media.URL = publicURL
+		}
+	}
+}
+
+// Media query methods
+func (ps *PortfolioService) GetMediaByID(ctx context.Context, id primitive.ObjectID) (*Media, error) {
+	var media Media
+	if err := ps.media.FindOne(ctx, bson.M{"_id": id}).Decode(&media); err != nil {
+		return nil, err
+	}
+	ps.setMediaURL(&media)
+	return &media, nil
+}
+
+func (ps *PortfolioService) GetMediaByProject(ctx context.Context, projectID primitive.ObjectID) ([]Media, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "uploaded_at", Value: 1}})
+	cursor, err := ps.media.Find(ctx, bson.M{"project_id": projectID}, boundedFind(opts))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	media := []Media{}
+	if err = cursor.All(ctx, &media); err != nil {
+		return nil, err
+	}
+	for i := range media {
+		ps.setMediaURL(&media[i])
+	}
+	return media, nil
+}
+
+// InsertMedia stores the file content in the configured media store and then records its metadata
+func (ps *PortfolioService) InsertMedia(ctx context.Context, media *Media, content io.Reader) error {
+	if media.ID.IsZero() {
+		media.ID = primitive.NewObjectID()
+	}
+
+	store := ps.mediaStore
+	if err := store.Put(ctx, media.ID, media.Filename, media.ContentType, content, media.Size); err != nil {
+		return err
+	}
+
+	if err := ps.RecordUploadedMedia(ctx, media); err != nil {
+		store.Delete(ctx, media.ID)
+		return err
+	}
+	return nil
+}
+
+// RecordUploadedMedia records metadata for content already written to the configured media store,
+// e.g. by a client using a presigned upload URL
+func (ps *PortfolioService) RecordUploadedMedia(ctx context.Context, media *Media) error {
+	media.Storage = ps.mediaStore.Name()
+	if _, err := ps.media.InsertOne(ctx, media); err != nil {
+		return err
+	}
+	ps.setMediaURL(media)
+	return nil
+}
+
+// OpenMediaContent streams a media file's content from its store
+func (ps *PortfolioService) OpenMediaContent(ctx context.Context, media *Media) (io.ReadCloser, error) {
+	store, err := ps.mediaStoreFor(media)
+	if err != nil {
+		return nil, err
+	}
+	return store.Open(ctx, media.ID)
+}
+
+// PresignMediaUpload returns a URL the client can upload content to directly, if the configured store supports it
+func (ps *PortfolioService) PresignMediaUpload(id primitive.ObjectID, contentType string, expires time.Duration) (string, error) {
+	uploader, ok := ps.mediaStore.(PresignedUploader)
+	if !ok {
+		return "", errPresignUnsupported
+	}
+	return uploader.PresignUpload(id, contentType, expires)
+}
+
+// DeleteMedia removes the file, its metadata, and any project thumbnail pointing at it
+func (ps *PortfolioService) DeleteMedia(ctx context.Context, id primitive.ObjectID) error {
+	var media Media
+	if err := ps.media.FindOneAndDelete(ctx, bson.M{"_id": id}).Decode(&media); err != nil {
+		return err
+	}
+
+	store, err := ps.mediaStoreFor(&media)
+	if err != nil {
+		return err
+	}
+	if err := store.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	ps.setMediaURL(&media)
+	thumbnails := []string{mediaURL(id), media.URL}
+	_, err = ps.projects.UpdateMany(ctx, bson.M{"thumbnail": bson.M{"$in": thumbnails}}, withUpdatedAt(bson.M{"$unset": bson.M{"thumbnail": ""}}))
+	return err
+}
+
+func (ps *PortfolioService) SetProjectThumbnail(ctx context.Context, projectID primitive.ObjectID, url string) error {
+	_, err := ps.projects.UpdateOne(ctx, bson.M{"_id": projectID}, withUpdatedAt(bson.M{"$set": bson.M{"thumbnail": url}}))
+	return err
+}
+
+// Project media endpoints: GET /api/projects/{slug}/media lists images, POST uploads one (admins and the
+// author's projects:write tokens).
+// Uploads are multipart with a "file" field, an optional "caption", and "thumbnail=true" to make it the cover image,
+// or JSON completing a presigned upload (see handleProjectMediaPresign).
+func (h *APIHandler) handleProjectMedia(w http.ResponseWriter, r *http.Request, slug string) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	if r.Method != "GET" && r.Method != "POST" {
+		log.Printf("Date: %s | Route: /api/projects/{slug}/media | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if r.Method == "POST" && !mayHoldAuthorAccess(r) {
+		log.Printf("Date: %s | Route: /api/projects/{slug}/media | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ctx := context.Background()
+	project, err := h.service.GetProjectByIDOrSlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, errInvalidIdentifier) {
+			writeInvalidInput(w, err)
+			return
+		}
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Project not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("Date: %s | Route: /api/projects/{slug}/media | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !projectVisibleTo(project, h.authorAccess(r, project.AuthorID, TokenScopeRead)) {
+		http.Error(w, "Project not found", http.StatusNotFound)
+		return
+	}
+	if r.Method == "POST" && !h.authorAccess(r, project.AuthorID, TokenScopeProjectsWrite) {
+		log.Printf("Date: %s | Route: /api/projects/{slug}/media | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if r.Method == "GET" {
+		media, err := h.service.GetMediaByProject(ctx, project.ID)
+		if err != nil {
+			log.Printf("Date: %s | Route: /api/projects/{slug}/media | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Date: %s | Route: /api/projects/{slug}/media | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(media)
+		return
+	}
+
+	// A JSON body completes an upload the client already sent to a presigned URL
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		h.completePresignedMediaUpload(w, r, project)
+		return
+	}
+
+	maxSize := maxMediaUploadSize()
+	// Leave room for the multipart envelope and form fields around the file
+	r.Body = http.MaxBytesReader(w, r.Body, maxSize+(1<<20))
+	if err := r.ParseMultipartForm(maxSize); err != nil {
+		log.Printf("Date: %s | Route: /api/projects/{slug}/media | Status: BAD_REQUEST | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Expected a multipart upload with a 'file' field (max "+strconv.FormatInt(maxSize>>20, 10)+"MB)", http.StatusBadRequest)
+		return
+	}
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "Missing 'file' field", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxSize+1))
+	if err != nil {
+		http.Error(w, "Failed to read upload", http.StatusBadRequest)
+		return
+	}
+	if int64(len(data)) > maxSize {
+		log.Printf("Date: %s | Route: /api/projects/{slug}/media | Status: TOO_LARGE | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "File too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	contentType, config, err := inspectMediaImage(data)
+	if err == errUnsupportedMediaType {
+		log.Printf("Date: %s | Route: /api/projects/{slug}/media | Status: UNSUPPORTED_TYPE | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Unsupported file type "+contentType+" (allowed: PNG, JPEG, GIF, WebP)", http.StatusUnsupportedMediaType)
+		return
+	}
+	if err != nil {
+		http.Error(w, "File is not a valid image", http.StatusBadRequest)
+		return
+	}
+
+	media := Media{
+		ProjectID:   project.ID,
+		Filename:    path.Base(strings.ReplaceAll(header.Filename, "\\", "/")),
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		Width:       config.Width,
+		Height:      config.Height,
+		Caption:     strings.TrimSpace(r.FormValue("caption")),
+		UploadedAt:  time.Now(),
+	}
+	if err := h.service.InsertMedia(ctx, &media, bytes.NewReader(data)); err != nil {
+		log.Printf("Date: %s | Route: /api/projects/{slug}/media | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// The first image becomes the cover unless another is chosen explicitly
+	if r.FormValue("thumbnail") == "true" || project.Thumbnail == "" {
+		if err := h.service.SetProjectThumbnail(ctx, project.ID, media.URL); err != nil {
+			log.Printf("Error setting thumbnail for project %s: %v", project.ID.Hex(), err)
+		}
+	}
+
+	log.Printf("Date: %s | Route: /api/projects/{slug}/media | Status: CREATED | GPT Model: %s", currentTime, gptModel)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(media)
+}
+
+// completePresignedMediaUpload records media whose content the client uploaded directly to the store.
+// The content is read back and checked the same way as a multipart upload before it is recorded.
+func (h *APIHandler) completePresignedMediaUpload(w http.ResponseWriter, r *http.Request, project *Project) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	var request struct {
+		ID        string `json:"id"`
+		Filename  string `json:"filename"`
+		Caption   string `json:"caption"`
+		Thumbnail bool   `json:"thumbnail"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid JSON request", http.StatusBadRequest)
+		return
+	}
+	mediaID, err := primitive.ObjectIDFromHex(request.ID)
+	if err != nil {
+		http.Error(w, "Invalid media ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx := context.Background()
+	if _, err := h.service.GetMediaByID(ctx, mediaID); err == nil {
+		http.Error(w, "Media already recorded", http.StatusConflict)
+		return
+	}
+
+	media := Media{
+		ID:         mediaID,
+		ProjectID:  project.ID,
+		Filename:   path.Base(strings.ReplaceAll(request.Filename, "\\", "/")),
+		Caption:    strings.TrimSpace(request.Caption),
+		Storage:    h.service.mediaStore.Name(),
+		UploadedAt: time.Now(),
+	}
+	content, err := h.service.OpenMediaContent(ctx, &media)
+	if err != nil {
+		log.Printf("Date: %s | Route: /api/projects/{slug}/media | Status: NOT_UPLOADED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Upload not found; PUT the file to the presigned URL first", http.StatusBadRequest)
+		return
+	}
+	maxSize := maxMediaUploadSize()
+	data, err := io.ReadAll(io.LimitReader(content, maxSize+1))
+	content.Close()
+	if err != nil {
+		http.Error(w, "Failed to read upload", http.StatusInternalServerError)
+		return
+	}
+
+	// Anything that fails validation is removed so it cannot be served from the bucket
+	if int64(len(data)) > maxSize {
+		h.service.mediaStore.Delete(ctx, mediaID)
+		log.Printf("Date: %s | Route: /api/projects/{slug}/media | Status: TOO_LARGE | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "File too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	contentType, config, err := inspectMediaImage(data)
+	if err != nil {
+		h.service.mediaStore.Delete(ctx, mediaID)
+		if err == errUnsupportedMediaType {
+			log.Printf("Date: %s | Route: /api/projects/{slug}/media | Status: UNSUPPORTED_TYPE | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, "Unsupported file type "+contentType+" (allowed: PNG, JPEG, GIF, WebP)", http.StatusUnsupportedMediaType)
+			return
+		}
+		http.Error(w, "File is not a valid image", http.StatusBadRequest)
+		return
+	}
+
+	media.ContentType = contentType
+	media.Size = int64(len(data))
+	media.Width = config.Width
+	media.Height = config.Height
+	if media.Filename == "." || media.Filename == "/" {
+		media.Filename = mediaID.Hex()
+	}
+	if err := h.service.RecordUploadedMedia(ctx, &media); err != nil {
+		log.Printf("Date: %s | Route: /api/projects/{slug}/media | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if request.Thumbnail || project.Thumbnail == "" {
+		if err := h.service.SetProjectThumbnail(ctx, project.ID, media.URL); err != nil {
+			log.Printf("Error setting thumbnail for project %s: %v", project.ID.Hex(), err)
+		}
+	}
+
+	log.Printf("Date: %s | Route: /api/projects/{slug}/media | Status: CREATED | GPT Model: %s", currentTime, gptModel)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(media)
+}
+
+// Presigned upload endpoint: POST /api/projects/{slug}/media/presign (admins and the author's projects:write
+// tokens) with {"content_type": "image/png"}
+// returns a URL to PUT the file to, then POST {"id": ..., "filename": ...} as JSON to /api/projects/{slug}/media
+// to record it. Only available when media is stored in S3.
+func (h *APIHandler) handleProjectMediaPresign(w http.ResponseWriter, r *http.Request, slug string) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	if r.Method != "POST" {
+		log.Printf("Date: %s | Route: /api/projects/{slug}/media/presign | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !mayHoldAuthorAccess(r) {
+		log.Printf("Date: %s | Route: /api/projects/{slug}/media/presign | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var request struct {
+		ContentType string `json:"content_type"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid JSON request", http.StatusBadRequest)
+		return
+	}
+	if !allowedMediaTypes[request.ContentType] {
+		http.Error(w, "Unsupported content_type (allowed: image/png, image/jpeg, image/gif, image/webp)", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	ctx := context.Background()
+	project, err := h.service.GetProjectByIDOrSlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, errInvalidIdentifier) {
+			writeInvalidInput(w, err)
+			return
+		}
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Project not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("Date: %s | Route: /api/projects/{slug}/media/presign | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !h.authorAccess(r, project.AuthorID, TokenScopeProjectsWrite) {
+		log.Printf("Date: %s | Route: /api/projects/{slug}/media/presign | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	mediaID := primitive.NewObjectID()
+	expires := 15 * time.Minute
+	uploadURL, err := h.service.PresignMediaUpload(mediaID, request.ContentType, expires)
+	if err == errPresignUnsupported {
+		http.Error(w, "Presigned uploads require MEDIA_STORAGE=s3", http.StatusNotImplemented)
+		return
+	}
+	if err != nil {
+		log.Printf("Date: %s | Route: /api/projects/{slug}/media/presign | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Date: %s | Route: /api/projects/{slug}/media/presign | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"id":         mediaID.Hex(),
+		"upload_url": uploadURL,
+		"method":     "PUT",
+		"headers":    map[string]string{"Content-Type": request.ContentType},
+		"expires_at": time.Now().Add(expires),
+	})
+}
+
+// Media file endpoint: GET /api/media/{id} serves the image (or redirects to its public URL), DELETE removes it (admin only).
+// GET /api/media/{id}?w=400 serves a copy scaled down to about that width.
+func (h *APIHandler) handleMedia(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	mediaID, err := primitive.ObjectIDFromHex(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/media/"), "/"))
+	if err != nil {
+		http.Error(w, "Invalid media ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx := context.Background()
+
+	switch r.Method {
+	case "GET", "HEAD":
+		media, err := h.service.GetMediaByID(ctx, mediaID)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				http.Error(w, "Media not found", http.StatusNotFound)
+				return
+			}
+			log.Printf("Date: %s | Route: /api/media/{id} | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Media is only served to those who may see its project
+		project, err := h.service.GetProjectByIDOrSlug(ctx, media.ProjectID.Hex())
+		if err != nil && err != mongo.ErrNoDocuments {
+			log.Printf("Date: %s | Route: /api/media/{id} | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if err != nil || !projectVisibleTo(project, h.authorAccess(r, project.AuthorID, TokenScopeRead)) {
+			http.Error(w, "Media not found", http.StatusNotFound)
+			return
+		}
+		cacheControl := mediaCacheControl(project)
+
+		if h.serveResizedMedia(w, r, media, cacheControl) {
+			return
+		}
+
+		// Media is never modified in place, so the ID is a stable validator
+		etag := `"` + media.ID.Hex() + `"`
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Cache-Control", cacheControl)
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		// Files on a CDN or public bucket are fetched from there instead of through the API, except those of
+		// projects that are not public, which go through the check above
+		if media.URL != mediaURL(media.ID) && project.isPublic() {
+			http.Redirect(w, r, media.URL, http.StatusFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", media.ContentType)
+		w.Header().Set("Content-Length", strconv.FormatInt(media.Size, 10))
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		if r.Method == "HEAD" {
+			return
+		}
+
+		content, err := h.service.OpenMediaContent(ctx, media)
+		if err != nil {
+			log.Printf("Date: %s | Route: /api/media/{id} | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, "Failed to read media", http.StatusInternalServerError)
+			return
+		}
+		defer content.Close()
+		io.Copy(w, content)
+
+	case "DELETE":
+		if !isAdminRequest(r) {
+			log.Printf("Date: %s | Route: /api/media/{id} | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		if err := h.service.DeleteMedia(ctx, mediaID); err != nil {
+			if err == mongo.ErrNoDocuments {
+				http.Error(w, "Media not found", http.StatusNotFound)
+				return
+			}
+			log.Printf("Date: %s | Route: /api/media/{id} | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Date: %s | Route: /api/media/{id} | Status: DELETED | GPT Model: %s", currentTime, gptModel)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		log.Printf("Date: %s | Route: /api/media/{id} | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"golang.org/x/image/draw"
+)
+
+// Resized widths are snapped up to one of these so the cache holds a handful of variants per image
+// rather than one for every width a client asks for
+var mediaResizeWidths = []int{160, 320, 480, 640, 800, 1024, 1280, 1600, 1920}
+
+// Images larger than this are served at full size rather than decoded for resizing
+const maxResizePixels = 40_000_000
+
+// resizedMedia is a cached resized variant of a media file
+type resizedMedia struct {
+	ContentType string
+	Data        []byte
+}
+
+// snapResizeWidth picks the smallest allowed width that is at least the requested width. It returns
+// 0 when the image should be served as-is because it is already no wider than the result.
+func snapResizeWidth(requested, original int) int {
+	for _, width := range mediaResizeWidths {
+		if width >= requested {
+			if width >= original {
+				return 0
+			}
+			return width
+		}
+	}
+	if mediaResizeWidths[len(mediaResizeWidths)-1] >= original {
+		return 0
+	}
+	return mediaResizeWidths[len(mediaResizeWidths)-1]
+}
+
+// resizeMediaImage scales an image down to the given width, keeping its aspect ratio. JPEGs stay JPEG
+// and PNGs stay PNG; WebP is re-encoded as JPEG when opaque and PNG otherwise, since Go cannot encode WebP.
+func resizeMediaImage(content io.Reader, width int) (*resizedMedia, error) {
+	src, format, err := image.Decode(content)
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := src.Bounds()
+	height := bounds.Dy() * width / bounds.Dx()
+	if height < 1 {
+		height = 1
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Src, nil)
+
+	var buf bytes.Buffer
+	if format == "jpeg" || (format == "webp" && dst.Opaque()) {
+		if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 82}); err != nil {
+			return nil, err
+		}
+		return &resizedMedia{ContentType: "image/jpeg", Data: buf.Bytes()}, nil
+	}
+	encoder := png.Encoder{CompressionLevel: png.BestCompression}
+	if err := encoder.Encode(&buf, dst); err != nil {
+		return nil, err
+	}
+	return &resizedMedia{ContentType: "image/png", Data: buf.Bytes()}, nil
+}
+
+// serveResizedMedia handles GET /api/media/{id}?w=400. It reports false when the original should be
+// served instead: no width was requested, the image is already small enough, it is a GIF (which may be
+// animated), or it is too large to decode safely.
+func (h *APIHandler) serveResizedMedia(w http.ResponseWriter, r *http.Request, media *Media, cacheControl string) bool {
+	requested, err := strconv.Atoi(r.URL.Query().Get("w"))
+	if err != nil || requested <= 0 {
+		return false
+	}
+	if media.ContentType == "image/gif" || media.Width*media.Height > maxResizePixels {
+		return false
+	}
+	width := snapResizeWidth(requested, media.Width)
+	if width == 0 {
+		return false
+	}
+
+	etag := `"` + media.ID.Hex() + "-w" + strconv.Itoa(width) + `"`
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", cacheControl)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return true
+	}
+
+	cacheKey := media.ID.Hex() + "/" + strconv.Itoa(width)
+	var resized *resizedMedia
+	if cached, ok := h.mediaResizeCache.Get(cacheKey); ok {
+		resized = cached
+	} else {
+		content, err := h.service.OpenMediaContent(context.Background(), media)
+		if err != nil {
+			http.Error(w, "Failed to read media", http.StatusInternalServerError)
+			return true
+		}
+		resized, err = resizeMediaImage(content, width)
+		content.Close()
+		if err != nil {
+			http.Error(w, "Failed to resize media", http.StatusInternalServerError)
+			return true
+		}
+		h.mediaResizeCache.Set(cacheKey, resized, 24*time.Hour)
+	}
+
+	w.Header().Set("Content-Type", resized.ContentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(resized.Data)))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if r.Method != "HEAD" {
+		w.Write(resized.Data)
+	}
+	return true
+}
@@ -0,0 +1,331 @@
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// errMediaContentNotFound is returned when a media file's content is missing from its store
+var errMediaContentNotFound = errors.New("media content not found")
+
+// errPresignUnsupported is returned when the configured store cannot issue direct upload URLs
+var errPresignUnsupported = errors.New("media store does not support presigned uploads")
+
+// MediaStore holds the content of uploaded media files; metadata always lives in the media collection
+type MediaStore interface {
+	// Name identifies the backend and is recorded on each Media so older files stay readable after switching
+	Name() string
+	Put(ctx context.Context, id primitive.ObjectID, filename, contentType string, content io.Reader, size int64) error
+	Open(ctx context.Context, id primitive.ObjectID) (io.ReadCloser, error)
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	// PublicURL is where clients can fetch the file directly, or "" if it must be served through the API
+	PublicURL(id primitive.ObjectID) string
+}
+
+// PresignedUploader is implemented by stores that let clients upload directly, bypassing the API server
+type PresignedUploader interface {
+	PresignUpload(id primitive.ObjectID, contentType string, expires time.Duration) (string, error)
+}
+
+// newMediaStores builds the GridFS store plus the backend selected by MEDIA_STORAGE, returning
+// every available store keyed by name and the one new uploads should use
+func newMediaStores(db *mongo.Database) (map[string]MediaStore, MediaStore) {
+	gridFS := &gridFSMediaStore{database: db}
+	stores := map[string]MediaStore{gridFS.Name(): gridFS}
+
+	switch backend := strings.ToLower(os.Getenv("MEDIA_STORAGE")); backend {
+	case "", "gridfs":
+		return stores, gridFS
+	case "s3":
+		s3, err := newS3MediaStoreFromEnv()
+		if err != nil {
+			log.Printf("S3 media storage misconfigured (%v), storing media in GridFS", err)
+			return stores, gridFS
+		}
+		log.Printf("Storing media in S3 bucket %s at %s", s3.bucket, s3.endpoint.Host)
+		stores[s3.Name()] = s3
+		return stores, s3
+	default:
+		log.Printf("Unknown MEDIA_STORAGE %q, storing media in GridFS", backend)
+		return stores, gridFS
+	}
+}
+
+// GridFS store
+type gridFSMediaStore struct {
+	database *mongo.Database
+}
+
+func (s *gridFSMediaStore) Name() string { return "gridfs" }
+
+func (s *gridFSMediaStore) bucket() (*gridfs.Bucket, error) {
+	return gridfs.NewBucket(s.database, options.GridFSBucket().SetName("media"))
+}
+
+func (s *gridFSMediaStore) Put(ctx context.Context, id primitive.ObjectID, filename, contentType string, content io.Reader, size int64) error {
+	bucket, err := s.bucket()
+	if err != nil {
+		return err
+	}
+	uploadOpts := options.GridFSUpload().SetMetadata(bson.M{"content_type": contentType})
+	return bucket.UploadFromStreamWithID(id, filename, content, uploadOpts)
+}
+
+func (s *gridFSMediaStore) Open(ctx context.Context, id primitive.ObjectID) (io.ReadCloser, error) {
+	bucket, err := s.bucket()
+	if err != nil {
+		return nil, err
+	}
+	return bucket.OpenDownloadStream(id)
+}
+
+func (s *gridFSMediaStore) Delete(ctx context.Context, id primitive.ObjectID) error {
+	bucket, err := s.bucket()
+	if err != nil {
+		return err
+	}
+	if err := bucket.DeleteContext(ctx, id); err != nil && err != gridfs.ErrFileNotFound {
+		return err
+	}
+	return nil
+}
+
+func (s *gridFSMediaStore) PublicURL(id primitive.ObjectID) string { return "" }
+
+// S3 store, compatible with AWS S3, MinIO, Cloudflare R2 and other S3 APIs. Requests are signed
+// with AWS Signature Version 4.
+type s3MediaStore struct {
+	httpClient *http.Client
+	endpoint   *url.URL
+	region     string
+	bucket     string
+	accessKey  string
+	secretKey  string
+	pathStyle  bool   // bucket in the path instead of the hostname, as MinIO expects
+	publicURL  string // Base URL (CDN or public bucket) objects are readable from, if any
+}
+
+// newS3MediaStoreFromEnv reads S3_BUCKET, S3_ACCESS_KEY_ID and S3_SECRET_ACCESS_KEY (required), plus
+// S3_REGION, S3_ENDPOINT, S3_FORCE_PATH_STYLE and S3_PUBLIC_URL
+func newS3MediaStoreFromEnv() (*s3MediaStore, error) {
+	bucket := os.Getenv("S3_BUCKET")
+	accessKey := os.Getenv("S3_ACCESS_KEY_ID")
+	secretKey := os.Getenv("S3_SECRET_ACCESS_KEY")
+	if bucket == "" || accessKey == "" || secretKey == "" {
+		return nil, fmt.Errorf("S3_BUCKET, S3_ACCESS_KEY_ID and S3_SECRET_ACCESS_KEY are required")
+	}
+
+	region := os.Getenv("S3_REGION")
+	if region == "" {
+		region = "us-east-1"
+	}
+	rawEndpoint := os.Getenv("S3_ENDPOINT")
+	if rawEndpoint == "" {
+		rawEndpoint = "https://s3." + region + ".amazonaws.com"
+	}
+	endpoint, err := url.Parse(strings.TrimSuffix(rawEndpoint, "/"))
+	if err != nil || endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid S3_ENDPOINT %q", rawEndpoint)
+	}
+
+	return &s3MediaStore{
+		httpClient: &http.Client{Timeout: 60 * time.Second},
+		endpoint:   endpoint,
+		region:     region,
+		bucket:     bucket,
+		accessKey:  accessKey,
+		secretKey:  secretKey,
+		pathStyle:  os.Getenv("S3_FORCE_PATH_STYLE") == "true",
+		publicURL:  strings.TrimSuffix(os.Getenv("S3_PUBLIC_URL"), "/"),
+	}, nil
+}
+
+func (s *s3MediaStore) Name() string { return "s3" }
+
+// objectKey is the S3 key a media file is stored under
+func (s *s3MediaStore) objectKey(id primitive.ObjectID) string {
+	return "media/" + id.Hex()
+}
+
+// objectURL is the S3 API URL for a media file
+func (s *s3MediaStore) objectURL(id primitive.ObjectID) *url.URL {
+	u := *s.endpoint
+	if s.pathStyle {
+		u.Path = s.endpoint.Path + "/" + s.bucket + "/" + s.objectKey(id)
+	} else {
+		u.Host = s.bucket + "." + s.endpoint.Host
+		u.Path = s.endpoint.Path + "/" + s.objectKey(id)
+	}
+	return &u
+}
+
+func (s *s3MediaStore) PublicURL(id primitive.ObjectID) string {
+	if s.publicURL == "" {
+		return ""
+	}
+	return s.publicURL + "/" + s.objectKey(id)
+}
+
+func (s *s3MediaStore) Put(ctx context.Context, id primitive.ObjectID, filename, contentType string, content io.Reader, size int64) error {
+	req, err := http.NewRequestWithContext(ctx, "PUT", s.objectURL(id).String(), content)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+	// Media is never modified in place, matching the immutable caching on /api/media/{id}
+	req.Header.Set("Cache-Control", "public, max-age=31536000, immutable")
+	req.Header.Set("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": filename}))
+
+	resp, err := s.do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("S3 upload returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (s *s3MediaStore) Open(ctx context.Context, id primitive.ObjectID) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", s.objectURL(id).String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, errMediaContentNotFound
+		}
+		return nil, fmt.Errorf("S3 download returned status %d", resp.StatusCode)
+	}
+	return resp.Body, nil
+}
+
+func (s *s3MediaStore) Delete(ctx context.Context, id primitive.ObjectID) error {
+	req, err := http.NewRequestWithContext(ctx, "DELETE", s.objectURL(id).String(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	// S3 reports success for keys that do not exist
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("S3 delete returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// PresignUpload returns a URL the client can PUT the file to. The content type is part of the
+// signature, so the upload must send the same Content-Type header.
+func (s *s3MediaStore) PresignUpload(id primitive.ObjectID, contentType string, expires time.Duration) (string, error) {
+	u := s.objectURL(id)
+	now := time.Now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	scope := now.Format("20060102") + "/" + s.region + "/s3/aws4_request"
+
+	query := url.Values{}
+	query.Set("X-Amz-Algorithm", "AWS4-HMAC-SHA256")
+	query.Set("X-Amz-Credential", s.accessKey+"/"+scope)
+	query.Set("X-Amz-Date", amzDate)
+	query.Set("X-Amz-Expires", fmt.Sprintf("%d", int(expires.Seconds())))
+	query.Set("X-Amz-SignedHeaders", "content-type;host")
+	canonicalQuery := strings.ReplaceAll(query.Encode(), "+", "%20")
+
+	canonicalRequest := strings.Join([]string{
+		"PUT",
+		u.EscapedPath(),
+		canonicalQuery,
+		"content-type:" + contentType + "\nhost:" + u.Host + "\n",
+		"content-type;host",
+		"UNSIGNED-PAYLOAD",
+	}, "\n")
+
+	u.RawQuery = canonicalQuery + "&X-Amz-Signature=" + s.signature(now, scope, amzDate, canonicalRequest)
+	return u.String(), nil
+}
+
+// do signs a request with the Authorization header and sends it. Payloads are left unsigned so
+// uploads can stream without being hashed first.
+func (s *s3MediaStore) do(req *http.Request) (*http.Response, error) {
+	now := time.Now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	scope := now.Format("20060102") + "/" + s.region + "/s3/aws4_request"
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", "UNSIGNED-PAYLOAD")
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		"UNSIGNED-PAYLOAD",
+	}, "\n")
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKey, scope, signedHeaders, s.signature(now, scope, amzDate, canonicalRequest)))
+	return s.httpClient.Do(req)
+}
+
+// signature computes the SigV4 signature of a canonical request
+func (s *s3MediaStore) signature(now time.Time, scope, amzDate, canonicalRequest string) string {
+	hashedRequest := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hashedRequest[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.secretKey), now.Format("20060102"))
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
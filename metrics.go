@@ -0,0 +1,359 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// metricsRetention is how far back request metrics are kept, and so the longest window that can be queried
+const metricsRetention = 24 * time.Hour
+
+// latencyBucketsMs are the upper bounds of the latency histogram; percentiles report the bound of the
+// bucket they fall in
+var latencyBucketsMs = []float64{5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000, 30000, 60000}
+
+// routeBucket holds one minute of request metrics for a route
+type routeBucket struct {
+	requests     int
+	serverErrors int
+	clientErrors int
+	rateLimited  int
+	totalMs      float64
+	latencies    []int // Counts per latencyBucketsMs entry, plus one for slower requests
+}
+
+// RequestMetrics keeps per-minute request counts and latency histograms for each route. Metrics are held
+// in memory, so each instance reports the traffic it served since it started; hourly totals are also
+// flushed to MongoDB for usage digests.
+type RequestMetrics struct {
+	mu      sync.Mutex
+	minutes map[int64]map[string]*routeBucket // Unix minute -> route pattern -> bucket
+	flushed int64                             // Last Unix minute added to the stored hourly totals
+}
+
+func NewRequestMetrics() *RequestMetrics {
+	return &RequestMetrics{minutes: make(map[int64]map[string]*routeBucket)}
+}
+
+// Record adds a completed request to the current minute
+func (m *RequestMetrics) Record(route string, status int, duration time.Duration) {
+	minute := time.Now().Unix() / 60
+	ms := float64(duration) / float64(time.Millisecond)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	routes, ok := m.minutes[minute]
+	if !ok {
+		routes = make(map[string]*routeBucket)
+		m.minutes[minute] = routes
+	}
+	bucket, ok := routes[route]
+	if !ok {
+		bucket = &routeBucket{latencies: make([]int, len(latencyBucketsMs)+1)}
+		routes[route] = bucket
+	}
+
+	bucket.requests++
+	switch {
+	case status == http.StatusTooManyRequests:
+		bucket.rateLimited++
+		bucket.clientErrors++
+	case status >= 500:
+		bucket.serverErrors++
+	case status >= 400:
+		bucket.clientErrors++
+	}
+	bucket.totalMs += ms
+	bucket.latencies[sort.SearchFloat64s(latencyBucketsMs, ms)]++
+}
+
+// Cleanup drops minutes older than the retention period
+func (m *RequestMetrics) Cleanup() {
+	cutoff := time.Now().Add(-metricsRetention).Unix() / 60
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for minute := range m.minutes {
+		if minute < cutoff {
+			delete(m.minutes, minute)
+		}
+	}
+}
+
+// RequestStats counts the requests served by every replica in an hour
+type RequestStats struct {
+	Hour         time.Time `bson:"_id" json:"hour"`
+	Requests     int       `bson:"requests" json:"requests"`
+	ServerErrors int       `bson:"server_errors" json:"server_errors"`
+	ClientErrors int       `bson:"client_errors" json:"client_errors"`
+}
+
+// Flush adds the minutes completed since the last flush to the hourly totals in MongoDB; it runs on the
+// "request_stats" schedule on every replica
+func (m *RequestMetrics) Flush(ctx context.Context, service *PortfolioService) error {
+	current := time.Now().Unix() / 60
+
+	m.mu.Lock()
+	hours := make(map[int64]*RequestStats)
+	for minute, routes := range m.minutes {
+		if minute <= m.flushed || minute >= current {
+			continue
+		}
+		hour := minute / 60
+		stats, ok := hours[hour]
+		if !ok {
+			stats = &RequestStats{Hour: time.Unix(hour*3600, 0).UTC()}
+			hours[hour] = stats
+		}
+		for _, bucket := range routes {
+			stats.Requests += bucket.requests
+			stats.ServerErrors += bucket.serverErrors
+			stats.ClientErrors += bucket.clientErrors
+		}
+	}
+	m.mu.Unlock()
+
+	// Hours are written oldest first, so after a failure the next run resumes with the hour that failed
+	order := make([]int64, 0, len(hours))
+	for hour := range hours {
+		order = append(order, hour)
+	}
+	sort.Slice(order, func(i, j int) bool { return order[i] < order[j] })
+	for _, hour := range order {
+		if err := service.AddRequestStats(ctx, hours[hour]); err != nil {
+			return err
+		}
+		through := hour*60 + 59
+		if through >= current {
+			through = current - 1
+		}
+		m.mu.Lock()
+		m.flushed = through
+		m.mu.Unlock()
+	}
+	m.mu.Lock()
+	m.flushed = current - 1
+	m.mu.Unlock()
+	return nil
+}
+
+// AddRequestStats adds counts to an hour's totals
+func (ps *PortfolioService) AddRequestStats(ctx context.Context, stats *RequestStats) error {
+	_, err := ps.requestStats.UpdateOne(ctx,
+		bson.M{"_id": stats.Hour},
+		bson.M{"$inc": bson.M{"requests": stats.Requests, "server_errors": stats.ServerErrors, "client_errors": stats.ClientErrors}},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+// RouteMetrics summarizes the requests to a route over a window
+type RouteMetrics struct {
+	Route          string  `json:"route"`
+	Requests       int     `json:"requests"`
+	ServerErrors   int     `json:"server_errors"`
+	ClientErrors   int     `json:"client_errors"`
+	ErrorRate      float64 `json:"error_rate"` // Share of requests answered with a 5xx status
+	RateLimited    int     `json:"rate_limited"`
+	AverageMs      float64 `json:"avg_ms"`
+	P50Ms          float64 `json:"p50_ms"`
+	P95Ms          float64 `json:"p95_ms"`
+	latencies      []int
+	totalLatencyMs float64
+}
+
+// Summary merges the minutes within the window into per-route totals, busiest route first
+func (m *RequestMetrics) Summary(window time.Duration) []RouteMetrics {
+	since := time.Now().Add(-window).Unix() / 60
+
+	m.mu.Lock()
+	totals := make(map[string]*RouteMetrics)
+	for minute, routes := range m.minutes {
+		if minute < since {
+			continue
+		}
+		for route, bucket := range routes {
+			total, ok := totals[route]
+			if !ok {
+				total = &RouteMetrics{Route: route, latencies: make([]int, len(latencyBucketsMs)+1)}
+				totals[route] = total
+			}
+			total.Requests += bucket.requests
+			total.ServerErrors += bucket.serverErrors
+			total.ClientErrors += bucket.clientErrors
+			total.RateLimited += bucket.rateLimited
+			total.totalLatencyMs += bucket.totalMs
+			for i, count := range bucket.latencies {
+				total.latencies[i] += count
+			}
+		}
+	}
+	m.mu.Unlock()
+
+	summary := make([]RouteMetrics, 0, len(totals))
+	for _, total := range totals {
+		total.finish()
+		summary = append(summary, *total)
+	}
+	sort.Slice(summary, func(i, j int) bool {
+		if summary[i].Requests != summary[j].Requests {
+			return summary[i].Requests > summary[j].Requests
+		}
+		return summary[i].Route < summary[j].Route
+	})
+	return summary
+}
+
+// finish computes the rates and percentiles from the accumulated counts
+func (t *RouteMetrics) finish() {
+	if t.Requests > 0 {
+		t.ErrorRate = float64(t.ServerErrors) / float64(t.Requests)
+		t.AverageMs = t.totalLatencyMs / float64(t.Requests)
+	}
+	t.P50Ms = latencyPercentile(t.latencies, t.Requests, 0.50)
+	t.P95Ms = latencyPercentile(t.latencies, t.Requests, 0.95)
+}
+
+// latencyPercentile returns the upper bound of the histogram bucket containing the percentile. Requests
+// slower than the last bound report that bound.
+func latencyPercentile(latencies []int, requests int, percentile float64) float64 {
+	if requests == 0 {
+		return 0
+	}
+	target := int(float64(requests)*percentile + 0.5)
+	if target < 1 {
+		target = 1
+	}
+	seen := 0
+	for i, count := range latencies {
+		seen += count
+		if seen >= target {
+			if i < len(latencyBucketsMs) {
+				return latencyBucketsMs[i]
+			}
+			break
+		}
+	}
+	return latencyBucketsMs[len(latencyBucketsMs)-1]
+}
+
+// statusRecorder captures the status code written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(status int) {
+	if s.status == 0 {
+		s.status = status
+	}
+	s.ResponseWriter.WriteHeader(status)
+}
+
+func (s *statusRecorder) Write(b []byte) (int, error) {
+	if s.status == 0 {
+		s.status = http.StatusOK
+	}
+	return s.ResponseWriter.Write(b)
+}
+
+// Flush keeps streaming responses working through the recorder
+func (s *statusRecorder) Flush() {
+	if flusher, ok := s.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (s *statusRecorder) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
+
+// Middleware records every request served by mux under its registered route pattern, so paths with
+// IDs or slugs are grouped together
+func (m *RequestMetrics) Middleware(mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w}
+		mux.ServeHTTP(recorder, r)
+
+		_, route := mux.Handler(r)
+		if route == "" {
+			route = "unmatched"
+		}
+		status := recorder.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		m.Record(route, status, time.Since(start))
+	})
+}
+
+// Metrics endpoint: GET /api/admin/metrics?window= summarizes requests per route over a window such as
+// 15m, 1h or 24h (admin only)
+func (h *APIHandler) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if r.Method != "GET" {
+		log.Printf("Date: %s | Route: /api/admin/metrics | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !isAdminRequest(r) {
+		log.Printf("Date: %s | Route: /api/admin/metrics | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	window := time.Hour
+	if value := r.URL.Query().Get("window"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed < time.Minute || parsed > metricsRetention {
+			writeInvalidInput(w, invalidField("window", "range", fmt.Sprintf("window must be a duration between 1m and %s", metricsRetention)))
+			return
+		}
+		window = parsed
+	}
+
+	routes := h.metrics.Summary(window)
+	totals := RouteMetrics{Route: "all", latencies: make([]int, len(latencyBucketsMs)+1)}
+	for _, route := range routes {
+		totals.Requests += route.Requests
+		totals.ServerErrors += route.ServerErrors
+		totals.ClientErrors += route.ClientErrors
+		totals.RateLimited += route.RateLimited
+		totals.totalLatencyMs += route.totalLatencyMs
+		for i, count := range route.latencies {
+			totals.latencies[i] += count
+		}
+	}
+	totals.finish()
+
+	log.Printf("Date: %s | Route: /api/admin/metrics | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"window": window.String(),
+		"since":  time.Now().Add(-window),
+		"total":  totals,
+		"routes": routes,
+	})
+}
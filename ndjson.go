@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ndjsonFlushEvery is how many documents are written between flushes, so clients receive a steady stream
+// without a syscall per document
+const ndjsonFlushEvery = 100
+
+// wantsNDJSON reports whether a list should be streamed as newline-delimited JSON, requested with
+// Accept: application/x-ndjson or ?format=ndjson
+func wantsNDJSON(r *http.Request) bool {
+	return r.URL.Query().Get("format") == "ndjson" || strings.Contains(r.Header.Get("Accept"), "application/x-ndjson")
+}
+
+// streamNDJSON writes one JSON document per line as the cursor iterates, so a large result is never held
+// in memory. transform, if set, prepares each document and can wrap it. Once streaming has started the
+// status can no longer change, so a failure midway ends the stream with an {"error": ...} line.
+func streamNDJSON[T any](ctx context.Context, w http.ResponseWriter, cursor *mongo.Cursor, transform func(*T) interface{}) int {
+	defer cursor.Close(ctx)
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	flusher, _ := w.(http.Flusher)
+	encoder := json.NewEncoder(w)
+
+	count := 0
+	for cursor.Next(ctx) {
+		var document T
+		if err := cursor.Decode(&document); err != nil {
+			log.Printf("Error decoding streamed document: %v", err)
+			encoder.Encode(map[string]string{"error": "failed to decode document"})
+			return count
+		}
+		var line interface{} = &document
+		if transform != nil {
+			line = transform(&document)
+		}
+		if err := encoder.Encode(line); err != nil {
+			return count // Client went away
+		}
+		count++
+		if flusher != nil && count%ndjsonFlushEvery == 0 {
+			flusher.Flush()
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		log.Printf("Error streaming documents: %v", err)
+		encoder.Encode(map[string]string{"error": "failed to read documents"})
+	}
+	return count
+}
+
+// FindProjects returns a cursor over all projects, or only the archived or current ones, in their curated
+// order, or most recently updated first with sortRecent
+func (ps *PortfolioService) FindProjects(ctx context.Context, includeHidden bool, archived *bool, sortRecent bool) (*mongo.Cursor, error) {
+	filter := archivedFilter(projectVisibilityFilter(bson.M{}, includeHidden), archived)
+	if sortRecent {
+		return ps.projects.Find(ctx, filter, timedFind(options.Find().SetSort(bson.D{{Key: "updated_at", Value: -1}})))
+	}
+	pipeline := append([]bson.M{{"$match": filter}}, pinnedSortStages()...)
+	return ps.projects.Aggregate(ctx, pipeline, timedAggregate())
+}
+
+// exportLine is one document of a full export
+type exportLine struct {
+	Collection string      `json:"collection"`
+	Document   interface{} `json:"document"`
+}
+
+// exportCollection streams every document of a collection as export lines
+func exportCollection[T any](ctx context.Context, w http.ResponseWriter, collection *mongo.Collection) (int, error) {
+	cursor, err := collection.Find(ctx, bson.M{}, timedFind())
+	if err != nil {
+		return 0, err
+	}
+	name := collection.Name()
+	return streamNDJSON(ctx, w, cursor, func(document *T) interface{} {
+		return exportLine{Collection: name, Document: document}
+	}), nil
+}
+
+// Export endpoint: GET /api/admin/export streams the whole portfolio as NDJSON, one
+// {"collection": ..., "document": ...} line per document, with contact details decrypted (admin only)
+func (h *APIHandler) handleExport(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if r.Method != "GET" {
+		log.Printf("Date: %s | Route: /api/admin/export | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !isAdminRequest(r) {
+		log.Printf("Date: %s | Route: /api/admin/export | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ctx := r.Context()
+	ps := h.service
+	w.Header().Set("Content-Disposition", `attachment; filename="portfolio-export.ndjson"`)
+	exports := []func() (int, error){
+		func() (int, error) { return exportCollection[Author](ctx, w, ps.authors) },
+		func() (int, error) { return exportCollection[Project](ctx, w, ps.projects) },
+		func() (int, error) { return exportCollection[Education](ctx, w, ps.education) },
+		func() (int, error) { return exportCollection[Resume](ctx, w, ps.resumes) },
+		func() (int, error) { return exportCollection[Experience](ctx, w, ps.experience) },
+		func() (int, error) { return exportCollection[Certification](ctx, w, ps.certifications) },
+		func() (int, error) { return exportCollection[Post](ctx, w, ps.posts) },
+		func() (int, error) { return exportCollection[Testimonial](ctx, w, ps.testimonials) },
+		func() (int, error) { return exportCollection[Award](ctx, w, ps.awards) },
+		func() (int, error) { return exportCollection[Publication](ctx, w, ps.publications) },
+		func() (int, error) { return exportCollection[Talk](ctx, w, ps.talks) },
+		func() (int, error) { return exportCollection[Skill](ctx, w, ps.skills) },
+		func() (int, error) { return exportCollection[Now](ctx, w, ps.now) },
+	}
+	total := 0
+	for _, export := range exports {
+		count, err := export()
+		total += count
+		if err != nil {
+			log.Printf("Date: %s | Route: /api/admin/export | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			if total == 0 {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			} else {
+				json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			}
+			return
+		}
+	}
+
+	log.Printf("Date: %s | Route: /api/admin/export | Status: SUCCESS (%d documents) | GPT Model: %s", currentTime, total, gptModel)
+}
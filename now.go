@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// nowAvailabilityStatuses are the accepted values of Now.Availability
+var nowAvailabilityStatuses = map[string]bool{
+	"available":   true,
+	"limited":     true,
+	"unavailable": true,
+}
+
+// Now query methods
+
+// GetNow returns the author's now entry, or the most recently updated one when authorID is zero
+func (ps *PortfolioService) GetNow(ctx context.Context, authorID primitive.ObjectID) (*Now, error) {
+	filter := bson.M{}
+	if !authorID.IsZero() {
+		filter["author_id"] = authorID
+	}
+	opts := options.FindOne().SetSort(bson.D{{Key: "updated_at", Value: -1}})
+
+	var now Now
+	if err := ps.now.FindOne(ctx, filter, opts).Decode(&now); err != nil {
+		return nil, err
+	}
+	return &now, nil
+}
+
+// UpsertNow replaces the author's now entry, creating it if it does not exist
+func (ps *PortfolioService) UpsertNow(ctx context.Context, now *Now) error {
+	existing, err := ps.GetNow(ctx, now.AuthorID)
+	if err == nil {
+		now.ID = existing.ID
+	} else if err == mongo.ErrNoDocuments {
+		now.ID = primitive.NewObjectID()
+	} else {
+		return err
+	}
+
+	_, err = ps.now.ReplaceOne(ctx, bson.M{"_id": now.ID}, now, options.Replace().SetUpsert(true))
+	return err
+}
+
+// validateNow checks a now entry submitted through the admin API
+func validateNow(now *Now) error {
+	var v Validator
+	v.Check(!now.AuthorID.IsZero(), "author_id", "required", "author_id is required")
+	v.MaxLength("focus", now.Focus, 500)
+	v.MaxItems("learning", len(now.Learning), 20)
+	v.MaxItems("reading", len(now.Reading), 20)
+	v.Check(nowAvailabilityStatuses[now.Availability], "availability", "one_of", "availability must be one of available, limited, unavailable")
+	v.MaxLength("availability_note", now.AvailabilityNote, 300)
+	return v.Err()
+}
+
+// trimList drops blank entries and surrounding whitespace
+func trimList(values []string) []string {
+	trimmed := []string{}
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			trimmed = append(trimmed, value)
+		}
+	}
+	return trimmed
+}
+
+// Now endpoint: GET returns what the author is currently focused on (?author_id= selects an author),
+// PUT replaces it (admin only)
+func (h *APIHandler) handleNow(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	ctx := context.Background()
+
+	switch r.Method {
+	case "GET":
+		var authorID primitive.ObjectID
+		if authorIDStr := r.URL.Query().Get("author_id"); authorIDStr != "" {
+			parsed, err := primitive.ObjectIDFromHex(authorIDStr)
+			if err != nil {
+				http.Error(w, "Invalid author ID", http.StatusBadRequest)
+				return
+			}
+			authorID = parsed
+		} else if author := h.hostAuthor(r); author != nil {
+			authorID = author.ID
+		}
+
+		now, err := h.service.GetNow(ctx, authorID)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				http.Error(w, "Now entry not found", http.StatusNotFound)
+				return
+			}
+			log.Printf("Date: %s | Route: /api/now | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Date: %s | Route: /api/now | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(now)
+
+	case "PUT":
+		if !isAdminRequest(r) {
+			log.Printf("Date: %s | Route: /api/now | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		var now Now
+		if err := json.NewDecoder(r.Body).Decode(&now); err != nil {
+			log.Printf("Date: %s | Route: /api/now | Status: BAD_REQUEST | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, "Invalid JSON request", http.StatusBadRequest)
+			return
+		}
+		now.Focus = strings.TrimSpace(now.Focus)
+		now.Learning = trimList(now.Learning)
+		now.Reading = trimList(now.Reading)
+		now.Availability = strings.ToLower(strings.TrimSpace(now.Availability))
+		now.AvailabilityNote = strings.TrimSpace(now.AvailabilityNote)
+		if err := validateNow(&now); err != nil {
+			log.Printf("Date: %s | Route: /api/now | Status: INVALID_INPUT | GPT Model: %s", currentTime, gptModel)
+			writeInvalidInput(w, err)
+			return
+		}
+		if _, err := h.service.GetAuthorByID(ctx, now.AuthorID); err != nil {
+			http.Error(w, "Author not found", http.StatusBadRequest)
+			return
+		}
+
+		now.UpdatedAt = time.Now()
+		if err := h.service.UpsertNow(ctx, &now); err != nil {
+			log.Printf("Date: %s | Route: /api/now | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Date: %s | Route: /api/now | Status: UPDATED | GPT Model: %s", currentTime, gptModel)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(now)
+
+	default:
+		log.Printf("Date: %s | Route: /api/now | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,328 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// Open Graph images use the recommended 1.91:1 size
+const (
+	ogImageWidth  = 1200
+	ogImageHeight = 630
+	ogImagePad    = 72
+)
+
+var (
+	ogBackground = color.RGBA{0x1e, 0x1e, 0x1e, 0xff}
+	ogPink       = color.RGBA{0xF5, 0xA9, 0xB8, 0xff}
+	ogBlue       = color.RGBA{0x5B, 0xCE, 0xFA, 0xff}
+	ogText       = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	ogMuted      = color.RGBA{0xaa, 0xaa, 0xaa, 0xff}
+	ogChip       = color.RGBA{0x33, 0x33, 0x33, 0xff}
+)
+
+// Fonts are parsed once at startup; they are bundled with golang.org/x/image
+var (
+	ogBoldFont    = mustParseFont(gobold.TTF)
+	ogRegularFont = mustParseFont(goregular.TTF)
+)
+
+func mustParseFont(data []byte) *opentype.Font {
+	f, err := opentype.Parse(data)
+	if err != nil {
+		log.Fatal("Failed to parse font:", err)
+	}
+	return f
+}
+
+func newOGFace(f *opentype.Font, size float64) font.Face {
+	face, err := opentype.NewFace(f, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		log.Fatal("Failed to create font face:", err)
+	}
+	return face
+}
+
+// ogCard is the layout shared by the PNG and SVG renderers
+type ogCard struct {
+	Title    []string
+	Subtitle string
+	Chips    []string
+	Author   string
+}
+
+// wrapText splits text into at most maxLines lines that fit within width, adding an ellipsis if truncated
+func wrapText(face font.Face, text string, width int, maxLines int) []string {
+	var lines []string
+	current := ""
+	words := strings.Fields(text)
+	for _, word := range words {
+		candidate := strings.TrimSpace(current + " " + word)
+		if font.MeasureString(face, candidate).Ceil() <= width || current == "" {
+			current = candidate
+			continue
+		}
+		lines = append(lines, current)
+		current = word
+		if len(lines) == maxLines {
+			last := []rune(lines[maxLines-1])
+			for font.MeasureString(face, string(last)+"…").Ceil() > width && len(last) > 0 {
+				last = last[:len(last)-1]
+			}
+			lines[maxLines-1] = strings.TrimSpace(string(last)) + "…"
+			return lines
+		}
+	}
+	if current != "" {
+		lines = append(lines, current)
+	}
+	return lines
+}
+
+var (
+	ogTitleFace    = newOGFace(ogBoldFont, 72)
+	ogSubtitleFace = newOGFace(ogRegularFont, 32)
+	ogChipFace     = newOGFace(ogBoldFont, 26)
+	ogAuthorFace   = newOGFace(ogBoldFont, 30)
+)
+
+// buildOGCard lays out the text content for a project card
+func buildOGCard(project *Project, authorName string) ogCard {
+	card := ogCard{
+		Title:  wrapText(ogTitleFace, project.Name, ogImageWidth-2*ogImagePad, 2),
+		Author: authorName,
+	}
+
+	parts := []string{}
+	if project.Category != "" {
+		parts = append(parts, project.Category)
+	}
+	if !project.StartDate.IsZero() {
+		end := "Present"
+		if project.EndDate != nil {
+			end = project.EndDate.Format("2006")
+		}
+		parts = append(parts, fmt.Sprintf("%s – %s", project.StartDate.Format("2006"), end))
+	}
+	card.Subtitle = strings.Join(parts, " · ")
+
+	// Only as many chips as fit on one row
+	x := 0
+	for _, technology := range project.TechnologiesUsed {
+		chipWidth := font.MeasureString(ogChipFace, technology).Ceil() + 40
+		if x+chipWidth > ogImageWidth-2*ogImagePad {
+			break
+		}
+		card.Chips = append(card.Chips, technology)
+		x += chipWidth + 16
+	}
+	return card
+}
+
+// fillRoundedRect fills a rectangle with rounded corners of the given radius
+func fillRoundedRect(img *image.RGBA, rect image.Rectangle, radius int, c color.Color) {
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			dx, dy := 0, 0
+			if x < rect.Min.X+radius {
+				dx = rect.Min.X + radius - x
+			} else if x >= rect.Max.X-radius {
+				dx = x - (rect.Max.X - radius - 1)
+			}
+			if y < rect.Min.Y+radius {
+				dy = rect.Min.Y + radius - y
+			} else if y >= rect.Max.Y-radius {
+				dy = y - (rect.Max.Y - radius - 1)
+			}
+			if dx*dx+dy*dy <= radius*radius {
+				img.Set(x, y, c)
+			}
+		}
+	}
+}
+
+func drawText(img *image.RGBA, face font.Face, c color.Color, x, y int, text string) {
+	d := &font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(c),
+		Face: face,
+		Dot:  fixed.P(x, y),
+	}
+	d.DrawString(text)
+}
+
+// renderOGImagePNG draws the card as a PNG
+func renderOGImagePNG(card ogCard) ([]byte, error) {
+	img := image.NewRGBA(image.Rect(0, 0, ogImageWidth, ogImageHeight))
+	draw.Draw(img, img.Bounds(), image.NewUniform(ogBackground), image.Point{}, draw.Src)
+
+	// Accent bar in the site's pink and blue
+	draw.Draw(img, image.Rect(0, 0, ogImageWidth/2, 12), image.NewUniform(ogPink), image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(ogImageWidth/2, 0, ogImageWidth, 12), image.NewUniform(ogBlue), image.Point{}, draw.Src)
+
+	y := ogImagePad + 80
+	for _, line := range card.Title {
+		drawText(img, ogTitleFace, ogText, ogImagePad, y, line)
+		y += 84
+	}
+
+	if card.Subtitle != "" {
+		drawText(img, ogSubtitleFace, ogMuted, ogImagePad, y+8, card.Subtitle)
+	}
+
+	x := ogImagePad
+	chipTop := ogImageHeight - ogImagePad - 130
+	for _, chip := range card.Chips {
+		chipWidth := font.MeasureString(ogChipFace, chip).Ceil() + 40
+		fillRoundedRect(img, image.Rect(x, chipTop, x+chipWidth, chipTop+48), 24, ogChip)
+		drawText(img, ogChipFace, ogBlue, x+20, chipTop+33, chip)
+		x += chipWidth + 16
+	}
+
+	if card.Author != "" {
+		drawText(img, ogAuthorFace, ogPink, ogImagePad, ogImageHeight-ogImagePad, card.Author)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// renderOGImageSVG draws the same card as an SVG
+func renderOGImageSVG(card ogCard) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`, ogImageWidth, ogImageHeight, ogImageWidth, ogImageHeight)
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="#1e1e1e"/>`, ogImageWidth, ogImageHeight)
+	fmt.Fprintf(&b, `<rect width="%d" height="12" fill="#F5A9B8"/><rect x="%d" width="%d" height="12" fill="#5BCEFA"/>`, ogImageWidth/2, ogImageWidth/2, ogImageWidth/2)
+	b.WriteString(`<g font-family="Go, Helvetica, Arial, sans-serif">`)
+
+	y := ogImagePad + 80
+	for _, line := range card.Title {
+		fmt.Fprintf(&b, `<text x="%d" y="%d" font-size="72" font-weight="bold" fill="#ffffff">%s</text>`, ogImagePad, y, html.EscapeString(line))
+		y += 84
+	}
+	if card.Subtitle != "" {
+		fmt.Fprintf(&b, `<text x="%d" y="%d" font-size="32" fill="#aaaaaa">%s</text>`, ogImagePad, y+8, html.EscapeString(card.Subtitle))
+	}
+
+	x := ogImagePad
+	chipTop := ogImageHeight - ogImagePad - 130
+	for _, chip := range card.Chips {
+		chipWidth := font.MeasureString(ogChipFace, chip).Ceil() + 40
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="48" rx="24" fill="#333333"/>`, x, chipTop, chipWidth)
+		fmt.Fprintf(&b, `<text x="%d" y="%d" font-size="26" font-weight="bold" fill="#5BCEFA">%s</text>`, x+20, chipTop+33, html.EscapeString(chip))
+		x += chipWidth + 16
+	}
+
+	if card.Author != "" {
+		fmt.Fprintf(&b, `<text x="%d" y="%d" font-size="30" font-weight="bold" fill="#F5A9B8">%s</text>`, ogImagePad, ogImageHeight-ogImagePad, html.EscapeString(card.Author))
+	}
+	b.WriteString(`</g></svg>`)
+	return []byte(b.String())
+}
+
+// Open Graph image endpoint: /api/projects/{slug}/og.png or og.svg
+func (h *APIHandler) handleProjectOGImage(w http.ResponseWriter, r *http.Request, slug string, format string) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	cacheKey := slug + "." + format
+	if cached, ok := h.ogImageCache.Get(cacheKey); ok {
+		h.writeOGImage(w, format, cached, "public, max-age=3600")
+		return
+	}
+
+	ctx := context.Background()
+	project, err := h.service.GetProjectByIDOrSlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, errInvalidIdentifier) {
+			writeInvalidInput(w, err)
+			return
+		}
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Project not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("Date: %s | Route: /api/projects/{slug}/og | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !projectVisibleTo(project, isAdminRequest(r)) {
+		http.Error(w, "Project not found", http.StatusNotFound)
+		return
+	}
+
+	authorName := ""
+	if author, err := h.service.GetAuthorByID(ctx, project.AuthorID); err == nil {
+		authorName = author.Name
+	}
+
+	card := buildOGCard(project, authorName)
+	var output []byte
+	if format == "png" {
+		output, err = renderOGImagePNG(card)
+		if err != nil {
+			log.Printf("Date: %s | Route: /api/projects/{slug}/og | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	} else {
+		output = renderOGImageSVG(card)
+	}
+
+	// Cached images are served without a visibility check, so only public projects are cached, here and
+	// in shared caches; purgeOGImages drops them when the project changes
+	cacheControl := "private, no-store"
+	if project.isPublic() {
+		h.ogImageCache.Set(cacheKey, output, time.Hour)
+		cacheControl = "public, max-age=3600"
+	}
+
+	log.Printf("Date: %s | Route: /api/projects/{slug}/og | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+	h.writeOGImage(w, format, output, cacheControl)
+}
+
+func (h *APIHandler) writeOGImage(w http.ResponseWriter, format string, output []byte, cacheControl string) {
+	if format == "png" {
+		w.Header().Set("Content-Type", "image/png")
+	} else {
+		w.Header().Set("Content-Type", "image/svg+xml")
+	}
+	w.Header().Set("Cache-Control", cacheControl)
+	w.Write(output)
+}
+
+// purgeOGImages drops the cached images of a project under every key it can be requested by
+func (h *APIHandler) purgeOGImages(project *Project) {
+	for _, key := range []string{project.ID.Hex(), project.Slug, slugify(project.Name)} {
+		if key == "" {
+			continue
+		}
+		for _, format := range []string{"png", "svg"} {
+			h.ogImageCache.Delete(key + "." + format)
+		}
+	}
+}
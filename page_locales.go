@@ -0,0 +1,208 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"time"
+)
+
+// pageFallbackLocale is the language of the page templates as written; its strings are used for any
+// locale or message without a translation
+const pageFallbackLocale = "en"
+
+// pageMessages are the user interface strings of the server-rendered pages per locale. Portfolio content
+// is translated separately, through the *_i18n fields.
+var pageMessages = map[string]map[string]string{
+	"en": {
+		"back":                     "← Portfolio",
+		"print":                    "Print",
+		"experience":               "Experience",
+		"education":                "Education",
+		"publications":             "Publications",
+		"talks":                    "Talks",
+		"skills":                   "Skills",
+		"thesis":                   "Thesis",
+		"gpa":                      "GPA",
+		"slides":                   "Slides",
+		"video":                    "Video",
+		"projects":                 "Projects",
+		"all_projects":             "All projects",
+		"all_projects_description": "All portfolio projects",
+		"by":                       "By",
+		"technologies":             "Technologies",
+		"source":                   "Source",
+		"latest_release":           "Latest release",
+		"archived":                 "Archived",
+		"archived_notice":          "This project is archived and no longer maintained.",
+		"present":                  "Present",
+		"resume":                   "Resume",
+		"resume_of":                "Resume of",
+		"months":                   "mo",
+		"years":                    "yr",
+	},
+	"fr": {
+		"back":                     "← Portfolio",
+		"print":                    "Imprimer",
+		"experience":               "Expérience",
+		"education":                "Formation",
+		"publications":             "Publications",
+		"talks":                    "Conférences",
+		"skills":                   "Compétences",
+		"thesis":                   "Mémoire",
+		"gpa":                      "Moyenne",
+		"slides":                   "Diapositives",
+		"video":                    "Vidéo",
+		"projects":                 "Projets",
+		"all_projects":             "Tous les projets",
+		"all_projects_description": "Tous les projets du portfolio",
+		"by":                       "Par",
+		"technologies":             "Technologies",
+		"source":                   "Code source",
+		"latest_release":           "Dernière version",
+		"archived":                 "Archivé",
+		"archived_notice":          "Ce projet est archivé et n'est plus maintenu.",
+		"present":                  "Aujourd'hui",
+		"resume":                   "CV",
+		"resume_of":                "CV de",
+		"months":                   "mois",
+		"years":                    "an",
+	},
+	"es": {
+		"back":                     "← Portafolio",
+		"print":                    "Imprimir",
+		"experience":               "Experiencia",
+		"education":                "Educación",
+		"publications":             "Publicaciones",
+		"talks":                    "Charlas",
+		"skills":                   "Habilidades",
+		"thesis":                   "Tesis",
+		"gpa":                      "Promedio",
+		"slides":                   "Diapositivas",
+		"video":                    "Vídeo",
+		"projects":                 "Proyectos",
+		"all_projects":             "Todos los proyectos",
+		"all_projects_description": "Todos los proyectos del portafolio",
+		"by":                       "Por",
+		"technologies":             "Tecnologías",
+		"source":                   "Código fuente",
+		"latest_release":           "Última versión",
+		"archived":                 "Archivado",
+		"archived_notice":          "Este proyecto está archivado y ya no se mantiene.",
+		"present":                  "Actualidad",
+		"resume":                   "Currículum",
+		"resume_of":                "Currículum de",
+		"months":                   "m",
+		"years":                    "a",
+	},
+	"de": {
+		"back":                     "← Portfolio",
+		"print":                    "Drucken",
+		"experience":               "Berufserfahrung",
+		"education":                "Ausbildung",
+		"publications":             "Veröffentlichungen",
+		"talks":                    "Vorträge",
+		"skills":                   "Kenntnisse",
+		"thesis":                   "Abschlussarbeit",
+		"gpa":                      "Note",
+		"slides":                   "Folien",
+		"video":                    "Video",
+		"projects":                 "Projekte",
+		"all_projects":             "Alle Projekte",
+		"all_projects_description": "Alle Projekte des Portfolios",
+		"by":                       "Von",
+		"technologies":             "Technologien",
+		"source":                   "Quellcode",
+		"latest_release":           "Neueste Version",
+		"archived":                 "Archiviert",
+		"archived_notice":          "Dieses Projekt ist archiviert und wird nicht mehr gepflegt.",
+		"present":                  "Heute",
+		"resume":                   "Lebenslauf",
+		"resume_of":                "Lebenslauf von",
+		"months":                   "Mon.",
+		"years":                    "J.",
+	},
+}
+
+// pageMonths are the abbreviated month names per locale, January first
+var pageMonths = map[string][12]string{
+	"en": {"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+	"fr": {"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+	"es": {"ene.", "feb.", "mar.", "abr.", "may.", "jun.", "jul.", "ago.", "sept.", "oct.", "nov.", "dic."},
+	"de": {"Jan.", "Feb.", "März", "Apr.", "Mai", "Juni", "Juli", "Aug.", "Sept.", "Okt.", "Nov.", "Dez."},
+}
+
+// pageText returns a page message in a locale, falling back to English
+func pageText(locale, key string) string {
+	if text, ok := pageMessages[locale][key]; ok {
+		return text
+	}
+	return pageMessages[pageFallbackLocale][key]
+}
+
+// pageTemplateFuncs overrides the text-producing template helpers for a locale: t looks up a message, and
+// dates and durations use the locale's month names and units
+func pageTemplateFuncs(locale string) template.FuncMap {
+	months, ok := pageMonths[locale]
+	if !ok {
+		months = pageMonths[pageFallbackLocale]
+	}
+	month := func(t time.Time) string {
+		return fmt.Sprintf("%s %d", months[t.Month()-1], t.Year())
+	}
+	return template.FuncMap{
+		"t": func(key string) string { return pageText(locale, key) },
+		"date": func(t time.Time) string {
+			if t.IsZero() {
+				return ""
+			}
+			return month(t)
+		},
+		"enddate": func(t *time.Time) string {
+			if t == nil {
+				return pageText(locale, "present")
+			}
+			return month(*t)
+		},
+		"duration": func(months int) string {
+			mo, yr := pageText(locale, "months"), pageText(locale, "years")
+			if months < 12 {
+				return fmt.Sprintf("%d %s", months, mo)
+			}
+			if months%12 == 0 {
+				return fmt.Sprintf("%d %s", months/12, yr)
+			}
+			return fmt.Sprintf("%d %s %d %s", months/12, yr, months%12, mo)
+		},
+	}
+}
+
+// localizedPages are the page templates of one locale
+type localizedPages struct {
+	resume       *template.Template
+	project      *template.Template
+	projectIndex *template.Template
+}
+
+// pageTemplates holds a clone of every page template per locale with messages, parsed once at startup
+var pageTemplates = func() map[string]*localizedPages {
+	templates := make(map[string]*localizedPages, len(pageMessages))
+	for locale := range pageMessages {
+		localize := func(page *template.Template) *template.Template {
+			return template.Must(page.Clone()).Funcs(pageTemplateFuncs(locale))
+		}
+		templates[locale] = &localizedPages{
+			resume:       localize(resumePage),
+			project:      localize(projectPage),
+			projectIndex: localize(projectIndexPage),
+		}
+	}
+	return templates
+}()
+
+// pagesFor returns the page templates of a locale, or the English ones when it has no messages
+func pagesFor(locale string) *localizedPages {
+	if pages, ok := pageTemplates[locale]; ok {
+		return pages
+	}
+	return pageTemplates[pageFallbackLocale]
+}
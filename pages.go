@@ -0,0 +1,513 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"html/template"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// pageMeta holds the <head> metadata for server-rendered pages
+type pageMeta struct {
+	Title        string
+	Description  string
+	CanonicalURL string
+	ImageURL     string
+	Type         string
+	Lang         string // Language of the page, for <html lang>
+}
+
+const pageLayoutTemplate = `{{ define "layout" }}<!DOCTYPE html>
+<html lang="{{ .Meta.Lang }}">
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <title>{{ .Meta.Title }}</title>
+  <meta name="description" content="{{ .Meta.Description }}">
+  <link rel="canonical" href="{{ .Meta.CanonicalURL }}">
+  <meta property="og:type" content="{{ .Meta.Type }}">
+  <meta property="og:title" content="{{ .Meta.Title }}">
+  <meta property="og:description" content="{{ .Meta.Description }}">
+  <meta property="og:url" content="{{ .Meta.CanonicalURL }}">
+  {{- if .Meta.ImageURL }}
+  <meta property="og:image" content="{{ .Meta.ImageURL }}">
+  <meta name="twitter:card" content="summary_large_image">
+  <meta name="twitter:image" content="{{ .Meta.ImageURL }}">
+  {{- else }}
+  <meta name="twitter:card" content="summary">
+  {{- end }}
+  <link rel="alternate" type="application/atom+xml" title="Portfolio updates" href="/feed.xml">
+  <link rel="icon" href="/robot-icon.ico">
+  <style>
+    body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; color: #222; max-width: 820px; margin: 2rem auto; padding: 0 1.25rem; line-height: 1.5; }
+    header { border-bottom: 4px solid; border-image: linear-gradient(90deg, #F5A9B8 50%, #5BCEFA 50%) 1; padding-bottom: 1rem; margin-bottom: 1.5rem; }
+    h1 { margin: 0; font-size: 2rem; }
+    h2 { font-size: 1.2rem; text-transform: uppercase; letter-spacing: .05em; color: #555; border-bottom: 1px solid #ddd; padding-bottom: .25rem; margin-top: 2rem; }
+    h3 { margin: 1rem 0 .25rem; font-size: 1.05rem; }
+    .subtitle { color: #555; margin: .25rem 0; }
+    .meta, time { color: #666; font-size: .9rem; }
+    .contact { list-style: none; padding: 0; display: flex; flex-wrap: wrap; gap: .25rem 1.25rem; margin: .5rem 0 0; }
+    .tags { list-style: none; padding: 0; display: flex; flex-wrap: wrap; gap: .4rem; }
+    .tags li { background: #eef8fd; color: #1a6f91; border-radius: 999px; padding: .1rem .7rem; font-size: .85rem; }
+    a { color: #1a6f91; }
+    pre { background: #f6f8fa; border-radius: 6px; padding: .75rem 1rem; overflow-x: auto; font-size: .85rem; }
+    .hl-k { color: #a626a4; } .hl-s { color: #50a14f; } .hl-c { color: #a0a1a7; font-style: italic; } .hl-n { color: #986801; }
+    nav { margin-bottom: 1rem; font-size: .9rem; }
+    @media print {
+      body { margin: 0; max-width: none; font-size: 10.5pt; }
+      nav, .no-print { display: none; }
+      header { border-image: none; border-bottom: 1px solid #000; }
+      a { color: inherit; text-decoration: none; }
+      h2 { color: #000; margin-top: 1rem; }
+      article, section > div { break-inside: avoid; }
+      .tags li { background: none; color: #000; padding: 0; }
+      .tags li + li::before { content: "· "; }
+    }
+  </style>
+</head>
+<body>
+{{ template "content" . }}
+</body>
+</html>
+{{ end }}`
+
+const resumePageTemplate = `{{ define "content" }}
+<nav class="no-print"><a href="/">{{ t "back" }}</a> · <a href="#" onclick="window.print();return false;">{{ t "print" }}</a></nav>
+<header>
+  <h1>{{ .Resume.AuthorName }}</h1>
+  {{- if .Author }}{{ if .Author.JobTitle }}
+  <p class="subtitle">{{ .Author.JobTitle }}</p>
+  {{- end }}{{ end }}
+  <ul class="contact">
+    {{- if .Resume.Contact.Email }}
+    <li>{{ if .Redacted }}{{ .Resume.Contact.Email }}{{ else }}<a href="mailto:{{ .Resume.Contact.Email }}">{{ .Resume.Contact.Email }}</a>{{ end }}</li>
+    {{- end }}
+    {{- if .Resume.Contact.Phone }}
+    <li>{{ .Resume.Contact.Phone }}</li>
+    {{- end }}
+    {{- if .Author }}{{ if .Author.LinkedinURL }}
+    <li><a href="{{ .Author.LinkedinURL }}">LinkedIn</a></li>
+    {{- end }}{{ if .Author.GithubURL }}
+    <li><a href="{{ .Author.GithubURL }}">GitHub</a></li>
+    {{- end }}{{ end }}
+  </ul>
+</header>
+<main>
+  {{- if .Resume.Experience }}
+  <section>
+    <h2>{{ t "experience" }}</h2>
+    {{- range .Resume.Experience }}
+    <article>
+      <h3>{{ .JobTitle }} — {{ .Company }}</h3>
+      <p class="meta">{{ duration .TimePresent }}</p>
+      {{- if .Projects }}
+      <ul>
+        {{- range .Projects }}
+        <li><strong>{{ .Name }}</strong>: {{ .Description }}{{ if .TechnologiesUsed }} <span class="meta">({{ join .TechnologiesUsed ", " }})</span>{{ end }}</li>
+        {{- end }}
+      </ul>
+      {{- end }}
+    </article>
+    {{- end }}
+  </section>
+  {{- end }}
+  {{- if .Resume.Education }}
+  <section>
+    <h2>{{ t "education" }}</h2>
+    {{- range .Resume.Education }}
+    <div>
+      <h3>{{ .UniversityName }}</h3>
+      <p class="subtitle">{{ .Major }} · <time datetime="{{ .StartDate.Format "2006-01" }}">{{ date .StartDate }}</time> – {{ enddate .EndDate }}</p>
+      {{- if or .Honors .GPA }}
+      <p>{{ .Honors }}{{ if and .Honors .GPA }} · {{ end }}{{ if .GPA }}{{ t "gpa" }} {{ .GPA }}{{ end }}</p>
+      {{- end }}
+      {{- if .Thesis }}
+      <p>{{ t "thesis" }}: {{ .Thesis }}</p>
+      {{- end }}
+      {{- if .Description }}
+      <p>{{ .Description }}</p>
+      {{- end }}
+    </div>
+    {{- end }}
+  </section>
+  {{- end }}
+  {{- if .Publications }}
+  <section>
+    <h2>{{ t "publications" }}</h2>
+    <ul>
+      {{- range .Publications }}
+      <li>{{ with publink . }}<a href="{{ . }}">{{ end }}{{ .Title }}{{ if publink . }}</a>{{ end }}. <em>{{ .Venue }}</em>, {{ .Year }}.</li>
+      {{- end }}
+    </ul>
+  </section>
+  {{- end }}
+  {{- if .Talks }}
+  <section>
+    <h2>{{ t "talks" }}</h2>
+    <ul>
+      {{- range .Talks }}
+      <li>{{ .Title }}, <em>{{ .Event }}</em>{{ if .Location }}, {{ .Location }}{{ end }} · <time datetime="{{ .Date.Format "2006-01-02" }}">{{ date .Date }}</time>
+        {{- if .SlidesURL }} · <a href="{{ .SlidesURL }}">{{ t "slides" }}</a>{{ end }}
+        {{- if .VideoURL }} · <a href="{{ .VideoURL }}">{{ t "video" }}</a>{{ end }}</li>
+      {{- end }}
+    </ul>
+  </section>
+  {{- end }}
+  {{- if .Resume.Skills }}
+  <section>
+    <h2>{{ t "skills" }}</h2>
+    <ul class="tags">
+      {{- range .Resume.Skills }}
+      <li>{{ . }}</li>
+      {{- end }}
+    </ul>
+  </section>
+  {{- end }}
+</main>
+{{ end }}`
+
+const projectPageTemplate = `{{ define "content" }}
+<nav class="no-print"><a href="/">{{ t "back" }}</a> · <a href="/projects/">{{ t "all_projects" }}</a></nav>
+<header>
+  <h1>{{ .Project.Name }}</h1>
+  <p class="subtitle">
+    {{- if .Project.Category }}{{ .Project.Category }} · {{ end -}}
+    <time datetime="{{ .Project.StartDate.Format "2006-01" }}">{{ date .Project.StartDate }}</time> – {{ enddate .Project.EndDate }}
+  </p>
+  {{- if .Author }}
+  <p class="meta">{{ t "by" }} {{ .Author.Name }}</p>
+  {{- end }}
+  {{- if .Project.Archived }}
+  <p class="meta">{{ t "archived_notice" }}</p>
+  {{- end }}
+</header>
+<main>
+  <section>
+    {{ markdown .Project.Description }}
+  </section>
+  {{- if .Project.TechnologiesUsed }}
+  <section>
+    <h2>{{ t "technologies" }}</h2>
+    <ul class="tags">
+      {{- range .Project.TechnologiesUsed }}
+      <li>{{ . }}</li>
+      {{- end }}
+    </ul>
+  </section>
+  {{- end }}
+  {{- if .Project.RepoURL }}
+  <section>
+    <h2>{{ t "source" }}</h2>
+    <p><a href="{{ .Project.RepoURL }}">{{ if .Project.Repo }}{{ .Project.Repo.FullName }}{{ else }}{{ .Project.RepoURL }}{{ end }}</a>
+    {{- if .Project.Repo }}{{ if .Project.Repo.LatestRelease }} <span class="meta">{{ t "latest_release" }} {{ .Project.Repo.LatestRelease }}</span>{{ end }}{{ end }}</p>
+  </section>
+  {{- end }}
+</main>
+{{ end }}`
+
+const projectIndexPageTemplate = `{{ define "content" }}
+<nav class="no-print"><a href="/">{{ t "back" }}</a></nav>
+<header>
+  <h1>{{ t "projects" }}</h1>
+</header>
+<main>
+  {{- range .Projects }}
+  <article>
+    <h3><a href="/projects/{{ .Slug }}">{{ .Name }}</a></h3>
+    <p class="meta">{{ if .Category }}{{ .Category }} · {{ end }}{{ date .StartDate }} – {{ enddate .EndDate }}{{ if .Archived }} · {{ t "archived" }}{{ end }}</p>
+    <p>{{ .Description }}</p>
+  </article>
+  {{- end }}
+</main>
+{{ end }}`
+
+// Descriptions are markdown, rendered by the same renderer as the API's ?render=html
+var pageLayout = template.Must(template.New("layout").Funcs(template.FuncMap(formatTemplateFuncs())).Funcs(pageTemplateFuncs(pageFallbackLocale)).
+	Funcs(template.FuncMap{"markdown": markdownHTML}).Parse(pageLayoutTemplate))
+
+// newPageTemplate combines the shared layout with a page's content block; pageTemplates holds its clones
+// for every locale
+func newPageTemplate(content string) *template.Template {
+	return template.Must(template.Must(pageLayout.Clone()).Parse(content))
+}
+
+var (
+	resumePage       = newPageTemplate(resumePageTemplate)
+	projectPage      = newPageTemplate(projectPageTemplate)
+	projectIndexPage = newPageTemplate(projectIndexPageTemplate)
+)
+
+type resumePageData struct {
+	Meta         pageMeta
+	Resume       *Resume
+	Author       *Author
+	Publications []Publication
+	Talks        []Talk
+	Redacted     bool // Contact details are masked, so the email is not linked
+}
+
+type projectPageData struct {
+	Meta    pageMeta
+	Project *Project
+	Author  *Author
+}
+
+type projectIndexPageData struct {
+	Meta     pageMeta
+	Projects []Project
+}
+
+// pageDescription trims text to a length suitable for a meta description
+func pageDescription(text string) string {
+	const maxLength = 160
+	text = strings.Join(strings.Fields(text), " ")
+	runes := []rune(text)
+	if len(runes) <= maxLength {
+		return text
+	}
+	cut := string(runes[:maxLength-1])
+	if i := strings.LastIndex(cut, " "); i > 0 {
+		cut = cut[:i]
+	}
+	return cut + "…"
+}
+
+// renderPage executes a page template into a buffer so errors never produce half-written HTML
+func renderPage(w http.ResponseWriter, page *template.Template, data interface{}) error {
+	var buf bytes.Buffer
+	if err := page.ExecuteTemplate(&buf, "layout", data); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(buf.Bytes())
+	return nil
+}
+
+// resolveResume loads the resume for an author, or the first resume when no author is given
+func (ps *PortfolioService) resolveResume(ctx context.Context, authorIDStr string) (*Resume, error) {
+	if authorIDStr != "" {
+		authorID, err := primitive.ObjectIDFromHex(authorIDStr)
+		if err != nil {
+			return nil, mongo.ErrNoDocuments
+		}
+		return ps.GetResumeByAuthor(ctx, authorID)
+	}
+
+	resumes, err := ps.GetAllResumes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(resumes) == 0 {
+		return nil, mongo.ErrNoDocuments
+	}
+	return &resumes[0], nil
+}
+
+// Server-rendered resume page
+func (h *APIHandler) handleResumePage(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	if r.Method != "GET" && r.Method != "HEAD" {
+		log.Printf("Date: %s | Route: /resume | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx := context.Background()
+	// Domains mapped to an author show that author's resume
+	authorIDStr := r.URL.Query().Get("author_id")
+	if host := h.hostAuthor(r); authorIDStr == "" && host != nil {
+		authorIDStr = host.ID.Hex()
+	}
+	resume, err := h.service.resolveResume(ctx, authorIDStr)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Resume not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("Date: %s | Route: /resume | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	author, err := h.service.GetAuthorByID(ctx, resume.AuthorID)
+	if err != nil {
+		author = nil
+	}
+	redacted := !isAdminRequest(r)
+	if redacted {
+		resume.redactContact()
+		if author != nil {
+			author.redactContact()
+		}
+	}
+
+	publications, _ := h.service.GetPublicationsByAuthor(ctx, resume.AuthorID)
+	talks, _ := h.service.GetTalksByAuthor(ctx, resume.AuthorID)
+
+	locale := h.locales.Negotiate(r)
+	setLanguageHeaders(w, locale)
+	localizeEducation(resume.Education, locale)
+
+	description := pageText(locale, "resume_of") + " " + resume.AuthorName
+	if author != nil && author.JobTitle != "" {
+		description += ", " + author.JobTitle
+	}
+
+	data := resumePageData{
+		Meta: pageMeta{
+			Title:        resume.AuthorName + " – " + pageText(locale, "resume"),
+			Description:  description,
+			CanonicalURL: publicBaseURL(r) + "/resume",
+			Type:         "profile",
+			Lang:         locale,
+		},
+		Resume:       resume,
+		Author:       author,
+		Publications: publications,
+		Talks:        talks,
+		Redacted:     redacted,
+	}
+	if err := renderPage(w, pagesFor(locale).resume, data); err != nil {
+		log.Printf("Date: %s | Route: /resume | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		log.Printf("Error rendering resume page: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Date: %s | Route: /resume | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+}
+
+// Server-rendered project pages: /projects/ lists every project, /projects/{slug} shows one
+func (h *APIHandler) handleProjectPage(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	if r.Method != "GET" && r.Method != "HEAD" {
+		log.Printf("Date: %s | Route: /projects | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	slug := strings.Trim(strings.TrimPrefix(r.URL.Path, "/projects"), "/")
+	if strings.Contains(slug, "/") {
+		http.NotFound(w, r)
+		return
+	}
+
+	ctx := context.Background()
+	baseURL := publicBaseURL(r)
+	locale := h.locales.Negotiate(r)
+	setLanguageHeaders(w, locale)
+
+	// Domains mapped to an author only show that author's projects
+	host := h.hostAuthor(r)
+
+	if slug == "" {
+		var projects []Project
+		var err error
+		if host != nil {
+			projects, err = h.service.GetProjectsByAuthor(ctx, host.ID, false)
+		} else {
+			projects, err = h.service.GetAllProjects(ctx, false, nil)
+		}
+		if err != nil {
+			log.Printf("Date: %s | Route: /projects | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		for i := range projects {
+			if projects[i].Slug == "" {
+				projects[i].Slug = slugify(projects[i].Name)
+			}
+		}
+		localizeProjects(projects, locale)
+
+		data := projectIndexPageData{
+			Meta: pageMeta{
+				Title:        pageText(locale, "projects"),
+				Description:  pageText(locale, "all_projects_description"),
+				CanonicalURL: baseURL + "/projects/",
+				Type:         "website",
+				Lang:         locale,
+			},
+			Projects: projects,
+		}
+		if err := renderPage(w, pagesFor(locale).projectIndex, data); err != nil {
+			log.Printf("Date: %s | Route: /projects | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			log.Printf("Error rendering project index page: %v", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		log.Printf("Date: %s | Route: /projects | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+		return
+	}
+
+	project, err := h.service.GetProjectByIDOrSlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, errInvalidIdentifier) {
+			writeInvalidInput(w, err)
+			return
+		}
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Project not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("Date: %s | Route: /projects/{slug} | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if !projectVisibleTo(project, isAdminRequest(r)) || (host != nil && project.AuthorID != host.ID) {
+		http.Error(w, "Project not found", http.StatusNotFound)
+		return
+	}
+	if project.Slug == "" {
+		project.Slug = slugify(project.Name)
+	}
+	project.Localize(locale)
+
+	author, err := h.service.GetAuthorByID(ctx, project.AuthorID)
+	if err != nil {
+		author = nil
+	}
+
+	title := project.Name
+	if author != nil {
+		title += " – " + author.Name
+	}
+
+	data := projectPageData{
+		Meta: pageMeta{
+			Title:        title,
+			Description:  pageDescription(project.Description),
+			CanonicalURL: baseURL + "/projects/" + project.Slug,
+			ImageURL:     baseURL + "/api/projects/" + project.Slug + "/og.png",
+			Type:         "article",
+			Lang:         locale,
+		},
+		Project: project,
+		Author:  author,
+	}
+	if err := renderPage(w, pagesFor(locale).project, data); err != nil {
+		log.Printf("Date: %s | Route: /projects/{slug} | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		log.Printf("Error rendering project page: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Date: %s | Route: /projects/{slug} | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+}
@@ -0,0 +1,840 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// errAuthorInUse is returned when deleting an author whose portfolio still has documents
+var errAuthorInUse = errors.New("author still has portfolio documents; delete them first")
+
+// UpdateAuthor replaces an author; created_at must already be carried over from the stored document. The
+// name copied into the author's education and resume follows a rename.
+func (ps *PortfolioService) UpdateAuthor(ctx context.Context, author *Author) error {
+	author.stampUpdated()
+	result, err := ps.authors.ReplaceOne(ctx, bson.M{"_id": author.ID}, author)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	if _, err := ps.education.UpdateMany(ctx, bson.M{"student_id": author.ID, "student_name": bson.M{"$ne": author.Name}},
+		withUpdatedAt(bson.M{"$set": bson.M{"student_name": author.Name}})); err != nil {
+		return err
+	}
+	_, err = ps.resumes.UpdateMany(ctx, bson.M{"author_id": author.ID, "author_name": bson.M{"$ne": author.Name}},
+		withUpdatedAt(bson.M{"$set": bson.M{"author_name": author.Name}}))
+	return err
+}
+
+// DeleteAuthor deletes an author with an empty portfolio, or returns errAuthorInUse. Documents are not
+// deleted along with their author, so a mistyped ID cannot wipe out a portfolio.
+func (ps *PortfolioService) DeleteAuthor(ctx context.Context, id primitive.ObjectID) error {
+	owned := []struct {
+		collection *mongo.Collection
+		filter     bson.M
+	}{
+		{ps.projects, bson.M{"author_id": id}},
+		{ps.education, bson.M{"student_id": id}},
+		{ps.resumes, bson.M{"author_id": id}},
+		{ps.experience, bson.M{"author_id": id}},
+		{ps.posts, bson.M{"author_id": id}},
+		{ps.certifications, bson.M{"author_id": id}},
+	}
+	for _, documents := range owned {
+		count, err := documents.collection.CountDocuments(ctx, documents.filter)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return errAuthorInUse
+		}
+	}
+
+	result, err := ps.authors.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	ps.recordDeletions(ctx, "authors", id)
+	return nil
+}
+
+// UpdateProject replaces a project; created_at must already be carried over from the stored document
+func (ps *PortfolioService) UpdateProject(ctx context.Context, project *Project) error {
+	if project.Slug == "" {
+		project.Slug = slugify(project.Name)
+	}
+	project.refreshReadingStats()
+	project.stampUpdated()
+	result, err := ps.projects.ReplaceOne(ctx, bson.M{"_id": project.ID}, project)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// DeleteProject deletes a project and its media
+func (ps *PortfolioService) DeleteProject(ctx context.Context, id primitive.ObjectID) error {
+	result, err := ps.projects.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	ps.recordDeletions(ctx, "projects", id)
+
+	media, err := ps.GetMediaByProject(ctx, id)
+	if err != nil {
+		return err
+	}
+	for _, item := range media {
+		if err := ps.DeleteMedia(ctx, item.ID); err != nil && err != mongo.ErrNoDocuments {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ps *PortfolioService) GetEducationByID(ctx context.Context, id primitive.ObjectID) (*Education, error) {
+	var education Education
+	if err := ps.education.FindOne(ctx, bson.M{"_id": id}).Decode(&education); err != nil {
+		return nil, err
+	}
+	return &education, nil
+}
+
+// UpdateEducation replaces an education record; created_at must already be carried over from the stored
+// document
+func (ps *PortfolioService) UpdateEducation(ctx context.Context, education *Education) error {
+	education.stampUpdated()
+	result, err := ps.education.ReplaceOne(ctx, bson.M{"_id": education.ID}, education)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (ps *PortfolioService) DeleteEducation(ctx context.Context, id primitive.ObjectID) error {
+	result, err := ps.education.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	ps.recordDeletions(ctx, "education", id)
+	return nil
+}
+
+// DeleteResume deletes a resume. Its positions stay in the experience collection, where they are managed
+// on their own.
+func (ps *PortfolioService) DeleteResume(ctx context.Context, id primitive.ObjectID) error {
+	result, err := ps.resumes.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	ps.recordDeletions(ctx, "resumes", id)
+	return nil
+}
+
+// validateStrings checks the entries of a list field: at most maxItems, none blank or longer than maxLength
+func validateStrings(v *Validator, field string, values []string, maxItems, maxLength int) {
+	if !v.MaxItems(field, len(values), maxItems) {
+		return
+	}
+	for _, value := range values {
+		if !v.Check(strings.TrimSpace(value) != "", field, "required", field+" entries must not be empty") ||
+			!v.MaxLength(field, value, maxLength) {
+			return
+		}
+	}
+}
+
+// validateAuthor checks an author submitted through the API
+func validateAuthor(author *Author) error {
+	var v Validator
+	if v.Required("name", author.Name) {
+		v.MaxLength("name", author.Name, 200)
+	}
+	v.MaxLength("job_title", author.JobTitle, 200)
+	email := string(author.Email)
+	v.Check(email == "" || strings.Count(email, "@") == 1 && !strings.ContainsAny(email, " \t\r\n"), "email", "format", "email must be an email address")
+	v.MaxLength("email", email, 254)
+	v.HTTPURL("linkedin_url", author.LinkedinURL)
+	v.HTTPURL("github_url", author.GithubURL)
+	validateStrings(&v, "hobbies", author.Hobbies, 50, 100)
+	v.MaxLength("pronouns", author.Pronouns, 50)
+	v.MaxLength("bot_name", author.BotName, 50)
+	v.MaxLength("chat_tone", author.ChatTone, 200)
+	for _, field := range author.PublicContact {
+		v.OneOf("public_contact", field, "email", "phone")
+	}
+	return v.Err()
+}
+
+// validateProject checks a project submitted through the API
+func validateProject(project *Project) error {
+	var v Validator
+	if v.Required("name", project.Name) {
+		v.MaxLength("name", project.Name, 200)
+	}
+	v.Check(project.Slug == "" || slugify(project.Slug) == project.Slug, "slug", "format", "slug may only contain lowercase letters, numbers and hyphens")
+	v.MaxLength("category", project.Category, 100)
+	v.Check(!project.AuthorID.IsZero(), "author_id", "required", "author_id is required")
+	v.RequiredTime("start_date", project.StartDate)
+	if project.EndDate != nil {
+		v.Check(!project.EndDate.Before(project.StartDate), "end_date", "range", "end_date must not be before start_date")
+	}
+	v.MaxLength("description", project.Description, 20000)
+	validateStrings(&v, "technologies_used", project.TechnologiesUsed, 50, 100)
+	if project.RepoURL != nil {
+		v.HTTPURL("repo_url", *project.RepoURL)
+	}
+	if !strings.HasPrefix(project.Thumbnail, "/api/media/") {
+		v.HTTPURL("thumbnail", project.Thumbnail)
+	}
+	v.Check(project.Visibility == "" || projectVisibilities[project.Visibility], "visibility", "one_of", "visibility must be one of public, unlisted, private")
+	return v.Err()
+}
+
+// validateResume checks a resume submitted through the API; its positions are checked like manually
+// entered ones
+func validateResume(resume *Resume) error {
+	var v Validator
+	v.Check(!resume.AuthorID.IsZero(), "author_id", "required", "author_id is required")
+	validateStrings(&v, "skills", resume.Skills, 200, 100)
+	v.MaxItems("education", len(resume.Education), 20)
+	v.MaxItems("experience", len(resume.Experience), 100)
+	if err := v.Err(); err != nil {
+		return err
+	}
+	for i := range resume.Experience {
+		resume.Experience[i].AuthorID = resume.AuthorID
+		if err := validateExperience(&resume.Experience[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Author creation: POST /api/authors (admin only)
+func (h *APIHandler) handleAuthorCreate(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	if !isAdminRequest(r) {
+		log.Printf("Date: %s | Route: /api/authors | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var author Author
+	if err := json.NewDecoder(r.Body).Decode(&author); err != nil {
+		log.Printf("Date: %s | Route: /api/authors | Status: BAD_REQUEST | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Invalid JSON request", http.StatusBadRequest)
+		return
+	}
+	if err := validateAuthor(&author); err != nil {
+		log.Printf("Date: %s | Route: /api/authors | Status: INVALID_INPUT | GPT Model: %s", currentTime, gptModel)
+		writeInvalidInput(w, err)
+		return
+	}
+
+	author.ID = primitive.NilObjectID
+	if err := h.service.InsertAuthor(context.Background(), &author); err != nil {
+		log.Printf("Date: %s | Route: /api/authors | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Date: %s | Route: /api/authors | Status: CREATED | GPT Model: %s", currentTime, gptModel)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(author)
+}
+
+// Author detail endpoints: GET /api/authors/{id or slug}; PUT replaces the author and DELETE removes an
+// author whose portfolio is empty (admin only)
+func (h *APIHandler) handleAuthorByKey(w http.ResponseWriter, r *http.Request, key string) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	admin := isAdminRequest(r)
+	if r.Method != "GET" && !admin {
+		log.Printf("Date: %s | Route: /api/authors/{id} | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ctx := context.Background()
+	author, err := h.service.GetAuthorBySlugOrID(ctx, key)
+	if err != nil {
+		if errors.Is(err, errInvalidIdentifier) {
+			writeInvalidInput(w, err)
+			return
+		}
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Author not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("Date: %s | Route: /api/authors/{id} | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		// Contact details are only returned in full to admins
+		if !admin {
+			author.redactContact()
+		}
+		log.Printf("Date: %s | Route: /api/authors/{id} | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(author)
+
+	case "PUT":
+		var updated Author
+		if err := json.NewDecoder(r.Body).Decode(&updated); err != nil {
+			log.Printf("Date: %s | Route: /api/authors/{id} | Status: BAD_REQUEST | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, "Invalid JSON request", http.StatusBadRequest)
+			return
+		}
+		updated.ID = author.ID
+		updated.CreatedAt = author.CreatedAt
+		if err := validateAuthor(&updated); err != nil {
+			log.Printf("Date: %s | Route: /api/authors/{id} | Status: INVALID_INPUT | GPT Model: %s", currentTime, gptModel)
+			writeInvalidInput(w, err)
+			return
+		}
+
+		if err := h.service.UpdateAuthor(ctx, &updated); err != nil {
+			log.Printf("Date: %s | Route: /api/authors/{id} | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Date: %s | Route: /api/authors/{id} | Status: UPDATED | GPT Model: %s", currentTime, gptModel)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(updated)
+
+	case "DELETE":
+		if err := h.service.DeleteAuthor(ctx, author.ID); err != nil {
+			if err == errAuthorInUse {
+				http.Error(w, err.Error(), http.StatusConflict)
+				return
+			}
+			log.Printf("Date: %s | Route: /api/authors/{id} | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Date: %s | Route: /api/authors/{id} | Status: DELETED | GPT Model: %s", currentTime, gptModel)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		log.Printf("Date: %s | Route: /api/authors/{id} | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// projectSlugTaken reports whether another project than id already uses a slug
+func (ps *PortfolioService) projectSlugTaken(ctx context.Context, slug string, id primitive.ObjectID) (bool, error) {
+	existing, err := ps.GetProjectBySlug(ctx, slug)
+	if err == mongo.ErrNoDocuments {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return existing.ID != id, nil
+}
+
+// Project creation: POST /api/projects (admins and the author's projects:write tokens)
+func (h *APIHandler) handleProjectCreate(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	if !mayHoldAuthorAccess(r) {
+		log.Printf("Date: %s | Route: /api/projects | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var project Project
+	if err := json.NewDecoder(r.Body).Decode(&project); err != nil {
+		log.Printf("Date: %s | Route: /api/projects | Status: BAD_REQUEST | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Invalid JSON request", http.StatusBadRequest)
+		return
+	}
+	if err := validateProject(&project); err != nil {
+		log.Printf("Date: %s | Route: /api/projects | Status: INVALID_INPUT | GPT Model: %s", currentTime, gptModel)
+		writeInvalidInput(w, err)
+		return
+	}
+	if !h.authorAccess(r, project.AuthorID, TokenScopeProjectsWrite) {
+		log.Printf("Date: %s | Route: /api/projects | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ctx := context.Background()
+	if _, err := h.service.GetAuthorByID(ctx, project.AuthorID); err == mongo.ErrNoDocuments {
+		writeInvalidInput(w, invalidField("author_id", "exists", "author_id does not match an author"))
+		return
+	} else if err != nil {
+		log.Printf("Date: %s | Route: /api/projects | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Archiving, the curated order and repository metadata have endpoints and jobs of their own
+	project.ID = primitive.NewObjectID()
+	project.Archived, project.ArchivedAt = false, nil
+	project.Pinning = Pinning{}
+	project.Repo = nil
+	if project.Slug == "" {
+		project.Slug = slugify(project.Name)
+	}
+	if taken, err := h.service.projectSlugTaken(ctx, project.Slug, project.ID); err != nil {
+		log.Printf("Date: %s | Route: /api/projects | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	} else if taken {
+		http.Error(w, "A project with this slug already exists", http.StatusConflict)
+		return
+	}
+
+	if err := h.service.InsertProject(ctx, &project); err != nil {
+		log.Printf("Date: %s | Route: /api/projects | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Date: %s | Route: /api/projects | Status: CREATED | GPT Model: %s", currentTime, gptModel)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(project)
+}
+
+// Project writes: PUT /api/projects/{slug} replaces a project, keeping the stored value of any optional field
+// left out, and DELETE removes it with its media (admins and the author's projects:write tokens)
+func (h *APIHandler) handleProjectWrite(w http.ResponseWriter, r *http.Request, slug string) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	if !mayHoldAuthorAccess(r) {
+		log.Printf("Date: %s | Route: /api/projects/{slug} | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ctx := context.Background()
+	project, err := h.service.GetProjectByIDOrSlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, errInvalidIdentifier) {
+			writeInvalidInput(w, err)
+			return
+		}
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Project not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("Date: %s | Route: /api/projects/{slug} | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !h.authorAccess(r, project.AuthorID, TokenScopeProjectsWrite) {
+		log.Printf("Date: %s | Route: /api/projects/{slug} | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case "PUT":
+		var updated Project
+		var sent map[string]json.RawMessage
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 1<<20))
+		if err == nil {
+			err = json.Unmarshal(body, &updated)
+		}
+		if err == nil {
+			err = json.Unmarshal(body, &sent)
+		}
+		if err != nil {
+			log.Printf("Date: %s | Route: /api/projects/{slug} | Status: BAD_REQUEST | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, "Invalid JSON request", http.StatusBadRequest)
+			return
+		}
+		// Optional fields left out keep their stored values (null clears them), so an edit that omits the
+		// visibility cannot publish a private project
+		for field, keep := range map[string]func(){
+			"category":          func() { updated.Category = project.Category },
+			"end_date":          func() { updated.EndDate = project.EndDate },
+			"description":       func() { updated.Description = project.Description },
+			"technologies_used": func() { updated.TechnologiesUsed = project.TechnologiesUsed },
+			"repo_url":          func() { updated.RepoURL = project.RepoURL },
+			"thumbnail":         func() { updated.Thumbnail = project.Thumbnail },
+			"visibility":        func() { updated.Visibility = project.Visibility },
+			"name_i18n":         func() { updated.NameI18n = project.NameI18n },
+			"description_i18n":  func() { updated.DescriptionI18n = project.DescriptionI18n },
+		} {
+			if _, ok := sent[field]; !ok {
+				keep()
+			}
+		}
+		// A project stays with its author; archiving, the curated order and repository metadata are kept
+		updated.ID = project.ID
+		updated.AuthorID = project.AuthorID
+		updated.CreatedAt = project.CreatedAt
+		updated.Archived, updated.ArchivedAt = project.Archived, project.ArchivedAt
+		updated.Pinning = project.Pinning
+		updated.Repo = project.Repo
+		if updated.Slug == "" {
+			updated.Slug = project.Slug
+		}
+		if err := validateProject(&updated); err != nil {
+			log.Printf("Date: %s | Route: /api/projects/{slug} | Status: INVALID_INPUT | GPT Model: %s", currentTime, gptModel)
+			writeInvalidInput(w, err)
+			return
+		}
+		if updated.Slug != "" {
+			if taken, err := h.service.projectSlugTaken(ctx, updated.Slug, updated.ID); err != nil {
+				log.Printf("Date: %s | Route: /api/projects/{slug} | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			} else if taken {
+				http.Error(w, "A project with this slug already exists", http.StatusConflict)
+				return
+			}
+		}
+
+		if err := h.service.UpdateProject(ctx, &updated); err != nil {
+			log.Printf("Date: %s | Route: /api/projects/{slug} | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		// The visibility, name or slug may have changed
+		h.purgeOGImages(project)
+		h.purgeOGImages(&updated)
+
+		log.Printf("Date: %s | Route: /api/projects/{slug} | Status: UPDATED | GPT Model: %s", currentTime, gptModel)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(updated)
+
+	case "DELETE":
+		if err := h.service.DeleteProject(ctx, project.ID); err != nil {
+			log.Printf("Date: %s | Route: /api/projects/{slug} | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		h.purgeOGImages(project)
+
+		log.Printf("Date: %s | Route: /api/projects/{slug} | Status: DELETED | GPT Model: %s", currentTime, gptModel)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		log.Printf("Date: %s | Route: /api/projects/{slug} | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// Education detail endpoints: GET /api/education/{id}; PUT replaces the record and DELETE removes it
+// (admin only)
+func (h *APIHandler) handleEducationRoutes(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	key := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/education/"), "/")
+	if key == "" || strings.Contains(key, "/") {
+		http.NotFound(w, r)
+		return
+	}
+	id, err := primitive.ObjectIDFromHex(key)
+	if err != nil {
+		http.Error(w, "Invalid education ID", http.StatusBadRequest)
+		return
+	}
+
+	if r.Method != "GET" && !isAdminRequest(r) {
+		log.Printf("Date: %s | Route: /api/education/{id} | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ctx := context.Background()
+	education, err := h.service.GetEducationByID(ctx, id)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Education not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("Date: %s | Route: /api/education/{id} | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		locale := h.locales.Negotiate(r)
+		localized := []Education{*education}
+		localizeEducation(localized, locale)
+		setLanguageHeaders(w, locale)
+		log.Printf("Date: %s | Route: /api/education/{id} | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(localized[0])
+
+	case "PUT":
+		var updated Education
+		if err := json.NewDecoder(r.Body).Decode(&updated); err != nil {
+			log.Printf("Date: %s | Route: /api/education/{id} | Status: BAD_REQUEST | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, "Invalid JSON request", http.StatusBadRequest)
+			return
+		}
+		// A record stays with its student, whose name is copied from the author
+		updated.ID = education.ID
+		updated.StudentID = education.StudentID
+		updated.StudentName = education.StudentName
+		updated.CreatedAt = education.CreatedAt
+		if err := validateEducation(&updated); err != nil {
+			log.Printf("Date: %s | Route: /api/education/{id} | Status: INVALID_INPUT | GPT Model: %s", currentTime, gptModel)
+			writeInvalidInput(w, err)
+			return
+		}
+
+		if err := h.service.UpdateEducation(ctx, &updated); err != nil {
+			log.Printf("Date: %s | Route: /api/education/{id} | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Date: %s | Route: /api/education/{id} | Status: UPDATED | GPT Model: %s", currentTime, gptModel)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(updated)
+
+	case "DELETE":
+		if err := h.service.DeleteEducation(ctx, education.ID); err != nil {
+			log.Printf("Date: %s | Route: /api/education/{id} | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Date: %s | Route: /api/education/{id} | Status: DELETED | GPT Model: %s", currentTime, gptModel)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		log.Printf("Date: %s | Route: /api/education/{id} | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// Resume creation: POST /api/resumes creates the resume of an author who has none; its experience is
+// stored in the experience collection (admin only)
+func (h *APIHandler) handleResumeCreate(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	if !isAdminRequest(r) {
+		log.Printf("Date: %s | Route: /api/resumes | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var resume Resume
+	if err := json.NewDecoder(r.Body).Decode(&resume); err != nil {
+		log.Printf("Date: %s | Route: /api/resumes | Status: BAD_REQUEST | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Invalid JSON request", http.StatusBadRequest)
+		return
+	}
+	if err := validateResume(&resume); err != nil {
+		log.Printf("Date: %s | Route: /api/resumes | Status: INVALID_INPUT | GPT Model: %s", currentTime, gptModel)
+		writeInvalidInput(w, err)
+		return
+	}
+
+	ctx := context.Background()
+	author, err := h.service.GetAuthorByID(ctx, resume.AuthorID)
+	if err == mongo.ErrNoDocuments {
+		writeInvalidInput(w, invalidField("author_id", "exists", "author_id does not match an author"))
+		return
+	} else if err != nil {
+		log.Printf("Date: %s | Route: /api/resumes | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if _, err := h.service.GetResumeByAuthor(ctx, resume.AuthorID); err == nil {
+		http.Error(w, "The author already has a resume; update it with PUT /api/resumes/{id}", http.StatusConflict)
+		return
+	} else if err != mongo.ErrNoDocuments {
+		log.Printf("Date: %s | Route: /api/resumes | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	resume.AuthorName = author.Name
+	resume.ExperienceIDs = nil
+	if resume.Experience == nil {
+		resume.Experience = []Experience{}
+	}
+	if err := h.service.UpsertResumeByAuthor(ctx, &resume); err != nil {
+		log.Printf("Date: %s | Route: /api/resumes | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Date: %s | Route: /api/resumes | Status: CREATED | GPT Model: %s", currentTime, gptModel)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(resume)
+}
+
+// Resume sub-resource endpoints: /api/resumes/{id} and /api/resumes/{id}/export, where {id} is the resume
+// ID or the author's ID or slug
+func (h *APIHandler) handleResumeRoutes(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/resumes/"), "/"), "/")
+	if len(parts) == 1 && parts[0] != "" {
+		h.handleResumeByKey(w, r, parts[0])
+		return
+	}
+	h.handleResumeExport(w, r)
+}
+
+// Resume detail endpoints: GET /api/resumes/{id}; PUT replaces the resume, keeping its author, and DELETE
+// removes it (admin only). Positions left out of a replaced resume are deleted; those of a deleted resume
+// are kept.
+func (h *APIHandler) handleResumeByKey(w http.ResponseWriter, r *http.Request, key string) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	admin := isAdminRequest(r)
+	if r.Method != "GET" && !admin {
+		log.Printf("Date: %s | Route: /api/resumes/{id} | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ctx := context.Background()
+	resume, err := h.service.GetResumeByIDOrAuthor(ctx, key)
+	if err != nil {
+		if errors.Is(err, errInvalidIdentifier) {
+			writeInvalidInput(w, err)
+			return
+		}
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Resume not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("Date: %s | Route: /api/resumes/{id} | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		// Contact details are only returned in full to admins
+		if !admin {
+			resume.redactContact()
+		}
+		log.Printf("Date: %s | Route: /api/resumes/{id} | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resume)
+
+	case "PUT":
+		var updated Resume
+		if err := json.NewDecoder(r.Body).Decode(&updated); err != nil {
+			log.Printf("Date: %s | Route: /api/resumes/{id} | Status: BAD_REQUEST | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, "Invalid JSON request", http.StatusBadRequest)
+			return
+		}
+		updated.AuthorID = resume.AuthorID
+		updated.AuthorName = resume.AuthorName
+		if err := validateResume(&updated); err != nil {
+			log.Printf("Date: %s | Route: /api/resumes/{id} | Status: INVALID_INPUT | GPT Model: %s", currentTime, gptModel)
+			writeInvalidInput(w, err)
+			return
+		}
+		// Positions are sent in full; a resume without experience lists none
+		updated.ExperienceIDs = nil
+		if updated.Experience == nil {
+			updated.Experience = []Experience{}
+		}
+
+		if err := h.service.UpsertResumeByAuthor(ctx, &updated); err != nil {
+			log.Printf("Date: %s | Route: /api/resumes/{id} | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Date: %s | Route: /api/resumes/{id} | Status: UPDATED | GPT Model: %s", currentTime, gptModel)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(updated)
+
+	case "DELETE":
+		if err := h.service.DeleteResume(ctx, resume.ID); err != nil {
+			log.Printf("Date: %s | Route: /api/resumes/{id} | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Date: %s | Route: /api/resumes/{id} | Status: DELETED | GPT Model: %s", currentTime, gptModel)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		log.Printf("Date: %s | Route: /api/resumes/{id} | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
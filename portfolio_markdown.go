@@ -0,0 +1,208 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	`*`, `\*`,
+	`_`, `\_`,
+	`[`, `\[`,
+	`]`, `\]`,
+	`<`, `&lt;`,
+	`>`, `&gt;`,
+	`|`, `\|`,
+)
+
+// markdownURLEscaper percent-encodes what would end a link destination early
+var markdownURLEscaper = strings.NewReplacer(
+	` `, `%20`,
+	`(`, `%28`,
+	`)`, `%29`,
+	`<`, `%3C`,
+	`>`, `%3E`,
+)
+
+// portfolioSection is everything the exports know about one author
+type portfolioSection struct {
+	Author    Author
+	Projects  []Project
+	Education []Education
+	Resume    *Resume
+}
+
+const portfolioMarkdownTemplate = `{{- range $i, $section := . }}{{ if $i }}
+
+---
+
+{{ end }}# {{ esc .Author.Name }}
+{{- if .Author.JobTitle }}
+
+**{{ esc .Author.JobTitle }}**
+{{- end }}
+{{ if or .Author.Email .Author.LinkedinURL .Author.GithubURL }}
+{{ if .Author.Email }}- Email: {{ esc .Author.Email }}
+{{ end }}{{ if .Author.LinkedinURL }}- LinkedIn: {{ .Author.LinkedinURL }}
+{{ end }}{{ if .Author.GithubURL }}- GitHub: {{ .Author.GithubURL }}
+{{ end }}{{ end }}
+{{- if .Author.Hobbies }}
+## About
+
+Outside of work I enjoy {{ esc (join .Author.Hobbies ", ") }}.
+{{ end }}
+{{- if .Projects }}
+## Projects
+{{ range .Projects }}
+### {{ if .RepoURL }}[{{ esc .Name }}]({{ urlesc .RepoURL }}){{ else }}{{ esc .Name }}{{ end }}
+
+*{{ esc .Category }} · {{ date .StartDate }} – {{ enddate .EndDate }}*
+
+{{ esc .Description }}
+{{ if .TechnologiesUsed }}
+**Technologies:** {{ esc (join .TechnologiesUsed ", ") }}
+{{ end }}{{ end }}{{ end }}
+{{- if .Resume }}{{ if .Resume.Experience }}
+## Experience
+{{ range .Resume.Experience }}
+- **{{ esc .JobTitle }}**, {{ esc .Company }} ({{ duration .TimePresent }})
+{{- end }}
+{{ end }}{{ end }}
+{{- if .Education }}
+## Education
+{{ range .Education }}
+- **{{ esc .UniversityName }}**, {{ esc .Major }} ({{ date .StartDate }} – {{ enddate .EndDate }})
+{{- if .Honors }}, {{ esc .Honors }}{{ end }}{{ if .GPA }}, GPA {{ esc .GPA }}{{ end }}
+{{- if .Thesis }}
+  - Thesis: {{ esc .Thesis }}
+{{- end }}
+{{- if .Coursework }}
+  - Coursework: {{ range $i, $course := .Coursework }}{{ if $i }}, {{ end }}{{ esc $course }}{{ end }}
+{{- end }}
+{{- end }}
+{{ end }}
+{{- if .Resume }}{{ if .Resume.Skills }}
+## Skills
+
+{{ esc (join .Resume.Skills ", ") }}
+{{ end }}{{ end }}
+{{- end }}`
+
+var portfolioMarkdown = template.Must(template.New("markdown").Funcs(exportTemplateFuncs(markdownEscaper, markdownURLEscaper)).Parse(portfolioMarkdownTemplate))
+
+// GetPortfolioSections loads every author (or a single one) together with their related documents. Projects
+// come in their curated order (see Pinning), so the export lists featured and ordered projects first.
+func (ps *PortfolioService) GetPortfolioSections(ctx context.Context, authorID *primitive.ObjectID) ([]portfolioSection, error) {
+	var authors []Author
+	if authorID != nil {
+		author, err := ps.GetAuthorByID(ctx, *authorID)
+		if err != nil {
+			return nil, err
+		}
+		authors = []Author{*author}
+	} else {
+		var err error
+		authors, err = ps.GetAllAuthors(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sections := make([]portfolioSection, 0, len(authors))
+	for _, author := range authors {
+		projects, err := ps.GetProjectsByAuthor(ctx, author.ID, false)
+		if err != nil {
+			return nil, err
+		}
+		education, err := ps.GetEducationByStudent(ctx, author.ID)
+		if err != nil {
+			return nil, err
+		}
+		resume, err := ps.GetResumeByAuthor(ctx, author.ID)
+		if err != nil && err != mongo.ErrNoDocuments {
+			return nil, err
+		}
+
+		sections = append(sections, portfolioSection{
+			Author:    author,
+			Projects:  projects,
+			Education: education,
+			Resume:    resume,
+		})
+	}
+	return sections, nil
+}
+
+// Markdown export endpoint
+func (h *APIHandler) handlePortfolioMarkdown(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if r.Method != "GET" {
+		log.Printf("Date: %s | Route: /api/portfolio/markdown | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var authorID *primitive.ObjectID
+	if authorIDStr := r.URL.Query().Get("author_id"); authorIDStr != "" {
+		id, err := primitive.ObjectIDFromHex(authorIDStr)
+		if err != nil {
+			http.Error(w, "Invalid author ID", http.StatusBadRequest)
+			return
+		}
+		authorID = &id
+	} else if host := h.hostAuthor(r); host != nil {
+		authorID = &host.ID
+	}
+
+	ctx := context.Background()
+	sections, err := h.service.GetPortfolioSections(ctx, authorID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Author not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("Date: %s | Route: /api/portfolio/markdown | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if !isAdminRequest(r) {
+		for i := range sections {
+			sections[i].Author.redactContact()
+			if sections[i].Resume != nil {
+				sections[i].Resume.redactContact()
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := portfolioMarkdown.Execute(&buf, sections); err != nil {
+		log.Printf("Date: %s | Route: /api/portfolio/markdown | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Date: %s | Route: /api/portfolio/markdown | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+	w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+	w.Write(buf.Bytes())
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// SetProjectArchived archives a project, marking it as past work that is no longer maintained, or brings it
+// back as current work
+func (ps *PortfolioService) SetProjectArchived(ctx context.Context, projectID primitive.ObjectID, archived bool) (*time.Time, error) {
+	update := bson.M{"$unset": bson.M{"archived": "", "archived_at": ""}}
+	var archivedAt *time.Time
+	if archived {
+		now := time.Now()
+		archivedAt = &now
+		update = bson.M{"$set": bson.M{"archived": true, "archived_at": now}}
+	}
+	result, err := ps.projects.UpdateOne(ctx, bson.M{"_id": projectID}, withUpdatedAt(update))
+	if err != nil {
+		return nil, err
+	}
+	if result.MatchedCount == 0 {
+		return nil, mongo.ErrNoDocuments
+	}
+	return archivedAt, nil
+}
+
+// archivedQuery reads the ?archived=true|false filter of project lists; nil lists archived and current
+// projects alike
+func archivedQuery(r *http.Request) (*bool, error) {
+	value := r.URL.Query().Get("archived")
+	if value == "" {
+		return nil, nil
+	}
+	archived, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, invalidField("archived", "format", "archived must be true or false")
+	}
+	return &archived, nil
+}
+
+// archivedFilter restricts a project filter to archived or current projects unless archived is nil
+func archivedFilter(filter bson.M, archived *bool) bson.M {
+	if archived == nil {
+		return filter
+	}
+	if *archived {
+		return bson.M{"$and": []bson.M{filter, {"archived": true}}}
+	}
+	return bson.M{"$and": []bson.M{filter, {"archived": bson.M{"$ne": true}}}}
+}
+
+// filterArchived keeps the archived or the current projects of a list unless archived is nil
+func filterArchived(projects []Project, archived *bool) []Project {
+	if archived == nil {
+		return projects
+	}
+	kept := []Project{}
+	for _, project := range projects {
+		if project.Archived == *archived {
+			kept = append(kept, project)
+		}
+	}
+	return kept
+}
+
+// Project archive endpoint: PUT /api/projects/{slug}/archive with {"archived": true|false} archives or
+// unarchives a project (admins and the author's projects:write tokens). Archived projects stay listed, but
+// are presented as historical.
+func (h *APIHandler) handleProjectArchive(w http.ResponseWriter, r *http.Request, slug string) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	if r.Method != "PUT" {
+		log.Printf("Date: %s | Route: /api/projects/{slug}/archive | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !mayHoldAuthorAccess(r) {
+		log.Printf("Date: %s | Route: /api/projects/{slug}/archive | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		Archived *bool `json:"archived"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("Date: %s | Route: /api/projects/{slug}/archive | Status: BAD_REQUEST | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Invalid JSON request", http.StatusBadRequest)
+		return
+	}
+	if req.Archived == nil {
+		writeInvalidInput(w, invalidField("archived", "required", "archived is required"))
+		return
+	}
+
+	ctx := context.Background()
+	project, err := h.service.GetProjectByIDOrSlug(ctx, slug)
+	if err == nil && !h.authorAccess(r, project.AuthorID, TokenScopeProjectsWrite) {
+		log.Printf("Date: %s | Route: /api/projects/{slug}/archive | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	var archivedAt *time.Time
+	if err == nil {
+		archivedAt, err = h.service.SetProjectArchived(ctx, project.ID, *req.Archived)
+	}
+	if err != nil {
+		if errors.Is(err, errInvalidIdentifier) {
+			writeInvalidInput(w, err)
+			return
+		}
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Project not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("Date: %s | Route: /api/projects/{slug}/archive | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	project.Archived, project.ArchivedAt = *req.Archived, archivedAt
+
+	log.Printf("Date: %s | Route: /api/projects/{slug}/archive | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(project)
+}
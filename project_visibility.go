@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// SetProjectVisibility changes whether a project is public, unlisted or private
+func (ps *PortfolioService) SetProjectVisibility(ctx context.Context, projectID primitive.ObjectID, visibility string) error {
+	result, err := ps.projects.UpdateOne(ctx, bson.M{"_id": projectID}, withUpdatedAt(bson.M{"$set": bson.M{"visibility": visibility}}))
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// Project detail endpoint: GET /api/projects/{slug}?render=html. Unlisted projects are served to anyone with the slug;
+// private projects only to admins and the author's API tokens.
+func (h *APIHandler) handleProjectBySlug(w http.ResponseWriter, r *http.Request, slug string) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	if r.Method == "PUT" || r.Method == "DELETE" {
+		h.handleProjectWrite(w, r, slug)
+		return
+	}
+	if r.Method != "GET" {
+		log.Printf("Date: %s | Route: /api/projects/{slug} | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	project, err := h.service.GetProjectByIDOrSlug(context.Background(), slug)
+	if err != nil {
+		if errors.Is(err, errInvalidIdentifier) {
+			writeInvalidInput(w, err)
+			return
+		}
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Project not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("Date: %s | Route: /api/projects/{slug} | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !projectVisibleTo(project, h.authorAccess(r, project.AuthorID, TokenScopeRead)) {
+		http.Error(w, "Project not found", http.StatusNotFound)
+		return
+	}
+
+	render, err := renderQuery(r)
+	if err != nil {
+		writeInvalidInput(w, err)
+		return
+	}
+	locale := h.locales.Negotiate(r)
+	project.Localize(locale)
+	if render {
+		project.renderHTML()
+	}
+	setLanguageHeaders(w, locale)
+
+	log.Printf("Date: %s | Route: /api/projects/{slug} | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(project)
+}
+
+// Project visibility endpoint: PUT /api/projects/{slug}/visibility with {"visibility": "public|unlisted|private"}
+// (admins and the author's projects:write tokens)
+func (h *APIHandler) handleProjectVisibility(w http.ResponseWriter, r *http.Request, slug string) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	if r.Method != "PUT" {
+		log.Printf("Date: %s | Route: /api/projects/{slug}/visibility | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !mayHoldAuthorAccess(r) {
+		log.Printf("Date: %s | Route: /api/projects/{slug}/visibility | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		Visibility string `json:"visibility"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("Date: %s | Route: /api/projects/{slug}/visibility | Status: BAD_REQUEST | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Invalid JSON request", http.StatusBadRequest)
+		return
+	}
+	if !projectVisibilities[req.Visibility] {
+		writeInvalidInput(w, invalidField("visibility", "one_of", "visibility must be public, unlisted or private"))
+		return
+	}
+
+	ctx := context.Background()
+	project, err := h.service.GetProjectByIDOrSlug(ctx, slug)
+	if err == nil && !h.authorAccess(r, project.AuthorID, TokenScopeProjectsWrite) {
+		log.Printf("Date: %s | Route: /api/projects/{slug}/visibility | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if err == nil {
+		err = h.service.SetProjectVisibility(ctx, project.ID, req.Visibility)
+	}
+	if err != nil {
+		if errors.Is(err, errInvalidIdentifier) {
+			writeInvalidInput(w, err)
+			return
+		}
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Project not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("Date: %s | Route: /api/projects/{slug}/visibility | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	project.Visibility = req.Visibility
+	h.purgeOGImages(project)
+
+	log.Printf("Date: %s | Route: /api/projects/{slug}/visibility | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(project)
+}
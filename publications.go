@@ -0,0 +1,239 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Publication query methods
+func (ps *PortfolioService) GetAllPublications(ctx context.Context, author *Author) ([]Publication, error) {
+	return ps.findPublications(ctx, hostScope(bson.M{}, "author_id", author))
+}
+
+func (ps *PortfolioService) GetPublicationsByAuthor(ctx context.Context, authorID primitive.ObjectID) ([]Publication, error) {
+	return ps.findPublications(ctx, bson.M{"author_id": authorID})
+}
+
+func (ps *PortfolioService) findPublications(ctx context.Context, filter bson.M) ([]Publication, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "year", Value: -1}, {Key: "title", Value: 1}})
+	cursor, err := ps.publications.Find(ctx, filter, boundedFind(opts))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var publications []Publication
+	if err = cursor.All(ctx, &publications); err != nil {
+		return nil, err
+	}
+	return publications, nil
+}
+
+func (ps *PortfolioService) InsertPublication(ctx context.Context, publication *Publication) error {
+	if publication.ID.IsZero() {
+		publication.ID = primitive.NewObjectID()
+	}
+	publication.stampCreated()
+	_, err := ps.publications.InsertOne(ctx, publication)
+	return err
+}
+
+// Talk query methods
+func (ps *PortfolioService) GetAllTalks(ctx context.Context, author *Author) ([]Talk, error) {
+	return ps.findTalks(ctx, hostScope(bson.M{}, "author_id", author))
+}
+
+func (ps *PortfolioService) GetTalksByAuthor(ctx context.Context, authorID primitive.ObjectID) ([]Talk, error) {
+	return ps.findTalks(ctx, bson.M{"author_id": authorID})
+}
+
+func (ps *PortfolioService) findTalks(ctx context.Context, filter bson.M) ([]Talk, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "date", Value: -1}})
+	cursor, err := ps.talks.Find(ctx, filter, boundedFind(opts))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var talks []Talk
+	if err = cursor.All(ctx, &talks); err != nil {
+		return nil, err
+	}
+	return talks, nil
+}
+
+func (ps *PortfolioService) InsertTalk(ctx context.Context, talk *Talk) error {
+	if talk.ID.IsZero() {
+		talk.ID = primitive.NewObjectID()
+	}
+	talk.stampCreated()
+	_, err := ps.talks.InsertOne(ctx, talk)
+	return err
+}
+
+// publicationURL links to the DOI resolver when a DOI is set, otherwise to the stored URL
+func publicationURL(publication *Publication) string {
+	if publication.DOI != "" {
+		return "https://doi.org/" + publication.DOI
+	}
+	return publication.URL
+}
+
+// validatePublication checks a manually entered publication
+func validatePublication(publication *Publication) error {
+	var v Validator
+	v.Required("title", publication.Title)
+	v.Required("venue", publication.Venue)
+	v.Check(publication.Year >= 1900 && publication.Year <= time.Now().Year()+1, "year", "range", "year is out of range")
+	v.Check(publication.DOI == "" || strings.HasPrefix(publication.DOI, "10."), "doi", "format", "doi must start with \"10.\" (without the https://doi.org/ prefix)")
+	v.HTTPURL("url", publication.URL)
+	return v.Err()
+}
+
+// validateTalk checks a manually entered talk
+func validateTalk(talk *Talk) error {
+	var v Validator
+	v.Required("title", talk.Title)
+	v.Required("event", talk.Event)
+	v.RequiredTime("date", talk.Date)
+	v.HTTPURL("slides_url", talk.SlidesURL)
+	v.HTTPURL("video_url", talk.VideoURL)
+	return v.Err()
+}
+
+// Publications endpoints: GET lists publications, POST creates one (admin only)
+func (h *APIHandler) handlePublications(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	ctx := context.Background()
+
+	switch r.Method {
+	case "GET":
+		publications, err := h.service.GetAllPublications(ctx, h.hostAuthor(r))
+		if err != nil {
+			log.Printf("Date: %s | Route: /api/publications | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Date: %s | Route: /api/publications | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+		writeTimestampedList(w, r, publications)
+
+	case "POST":
+		if !isAdminRequest(r) {
+			log.Printf("Date: %s | Route: /api/publications | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		var publication Publication
+		if err := json.NewDecoder(r.Body).Decode(&publication); err != nil {
+			log.Printf("Date: %s | Route: /api/publications | Status: BAD_REQUEST | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, "Invalid JSON request", http.StatusBadRequest)
+			return
+		}
+		publication.DOI = strings.TrimPrefix(strings.TrimSpace(publication.DOI), "https://doi.org/")
+		if err := validatePublication(&publication); err != nil {
+			log.Printf("Date: %s | Route: /api/publications | Status: INVALID_INPUT | GPT Model: %s", currentTime, gptModel)
+			writeInvalidInput(w, err)
+			return
+		}
+
+		publication.ID = primitive.NilObjectID
+		if err := h.service.InsertPublication(ctx, &publication); err != nil {
+			log.Printf("Date: %s | Route: /api/publications | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Date: %s | Route: /api/publications | Status: CREATED | GPT Model: %s", currentTime, gptModel)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(publication)
+
+	default:
+		log.Printf("Date: %s | Route: /api/publications | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// Talks endpoints: GET lists talks, POST creates one (admin only)
+func (h *APIHandler) handleTalks(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	ctx := context.Background()
+
+	switch r.Method {
+	case "GET":
+		talks, err := h.service.GetAllTalks(ctx, h.hostAuthor(r))
+		if err != nil {
+			log.Printf("Date: %s | Route: /api/talks | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Date: %s | Route: /api/talks | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+		writeTimestampedList(w, r, talks)
+
+	case "POST":
+		if !isAdminRequest(r) {
+			log.Printf("Date: %s | Route: /api/talks | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		var talk Talk
+		if err := json.NewDecoder(r.Body).Decode(&talk); err != nil {
+			log.Printf("Date: %s | Route: /api/talks | Status: BAD_REQUEST | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, "Invalid JSON request", http.StatusBadRequest)
+			return
+		}
+		if err := validateTalk(&talk); err != nil {
+			log.Printf("Date: %s | Route: /api/talks | Status: INVALID_INPUT | GPT Model: %s", currentTime, gptModel)
+			writeInvalidInput(w, err)
+			return
+		}
+
+		talk.ID = primitive.NilObjectID
+		if err := h.service.InsertTalk(ctx, &talk); err != nil {
+			log.Printf("Date: %s | Route: /api/talks | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Date: %s | Route: /api/talks | Status: CREATED | GPT Model: %s", currentTime, gptModel)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(talk)
+
+	default:
+		log.Printf("Date: %s | Route: /api/talks | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
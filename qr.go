@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	qrcode "github.com/skip2/go-qrcode"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// renderQRCodeSVG draws the QR bitmap (including its quiet zone) as an SVG path,
+// one horizontal run of dark modules per segment
+func renderQRCodeSVG(qr *qrcode.QRCode) string {
+	bitmap := qr.Bitmap()
+	size := len(bitmap)
+
+	var path strings.Builder
+	for y, row := range bitmap {
+		for x := 0; x < len(row); x++ {
+			if !row[x] {
+				continue
+			}
+			start := x
+			for x < len(row) && row[x] {
+				x++
+			}
+			fmt.Fprintf(&path, "M%d %dh%dv1h-%dz", start, y, x-start, x-start)
+		}
+	}
+
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" shape-rendering="crispEdges">`+
+		`<rect width="%d" height="%d" fill="#ffffff"/><path fill="#000000" d="%s"/></svg>`,
+		size, size, size, size, path.String())
+}
+
+// QR code endpoint: /api/authors/{id}/qr?content=vcard|url&format=svg|png&size=256
+func (h *APIHandler) handleAuthorQR(w http.ResponseWriter, r *http.Request, authorID primitive.ObjectID) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	portfolioURL := os.Getenv("PORTFOLIO_URL")
+
+	// Default to the portfolio URL when one is configured; it produces a much smaller code
+	content := r.URL.Query().Get("content")
+	if content == "" {
+		content = "vcard"
+		if portfolioURL != "" {
+			content = "url"
+		}
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "svg"
+	}
+
+	var v Validator
+	v.OneOf("content", content, "vcard", "url")
+	v.OneOf("format", format, "svg", "png")
+	size := v.QueryInt(r, "size", 256, 64, 1024)
+	if err := v.Err(); err != nil {
+		writeInvalidInput(w, err)
+		return
+	}
+
+	ctx := context.Background()
+
+	var payload string
+	switch content {
+	case "url":
+		if portfolioURL == "" {
+			http.Error(w, "PORTFOLIO_URL is not configured", http.StatusBadRequest)
+			return
+		}
+		if _, err := h.service.GetAuthorByID(ctx, authorID); err != nil {
+			if err == mongo.ErrNoDocuments {
+				http.Error(w, "Author not found", http.StatusNotFound)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		payload = portfolioURL
+	case "vcard":
+		_, vcard, err := h.loadAuthorVCard(ctx, authorID, isAdminRequest(r))
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				http.Error(w, "Author not found", http.StatusNotFound)
+				return
+			}
+			log.Printf("Date: %s | Route: /api/authors/{id}/qr | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		payload = vcard
+	}
+
+	qr, err := qrcode.New(payload, qrcode.Medium)
+	if err != nil {
+		log.Printf("Date: %s | Route: /api/authors/{id}/qr | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, fmt.Sprintf("Failed to generate QR code: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	// QR codes only change when the author's contact data does; admins' carry unpublished details, which
+	// must not land in a shared cache
+	if isAdminRequest(r) {
+		w.Header().Set("Cache-Control", "private, no-store")
+	} else {
+		w.Header().Set("Cache-Control", "public, max-age=3600")
+	}
+	w.Header().Set("Vary", "Authorization")
+
+	if format == "png" {
+		png, err := qr.PNG(size)
+		if err != nil {
+			log.Printf("Date: %s | Route: /api/authors/{id}/qr | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		log.Printf("Date: %s | Route: /api/authors/{id}/qr | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(png)
+		return
+	}
+
+	log.Printf("Date: %s | Route: /api/authors/{id}/qr | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+	w.Header().Set("Content-Type", "image/svg+xml")
+	w.Write([]byte(renderQRCodeSVG(qr)))
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// maxSearchTerms is how many words of a search query are matched
+const maxSearchTerms = 10
+
+// queryLimits bound every list and search query, so a pathological filter can neither pin MongoDB nor
+// produce a huge response. NDJSON streams are exempt from the document cap since they never buffer.
+type queryLimits struct {
+	maxDocuments     int64         // Per list query
+	maxSearchResults int64         // Per collection in SearchAll, which feeds the chatbot context
+	maxTime          time.Duration // Server-side maxTimeMS
+	searchTimeout    time.Duration // Per collection in SearchAll, which searches them concurrently
+}
+
+// queryLimitsConfig loads QUERY_MAX_DOCUMENTS (default 1000), SEARCH_MAX_RESULTS (100) and
+// QUERY_MAX_TIME (5s) and SEARCH_COLLECTION_TIMEOUT (3s) once
+var queryLimitsConfig = sync.OnceValue(func() queryLimits {
+	limits := queryLimits{maxDocuments: 1000, maxSearchResults: 100, maxTime: 5 * time.Second, searchTimeout: 3 * time.Second}
+	for key, target := range map[string]*int64{
+		"QUERY_MAX_DOCUMENTS": &limits.maxDocuments,
+		"SEARCH_MAX_RESULTS":  &limits.maxSearchResults,
+	} {
+		if value := os.Getenv(key); value != "" {
+			parsed, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || parsed <= 0 {
+				log.Printf("Invalid %s %q, using %d", key, value, *target)
+			} else {
+				*target = parsed
+			}
+		}
+	}
+	for key, target := range map[string]*time.Duration{
+		"QUERY_MAX_TIME":            &limits.maxTime,
+		"SEARCH_COLLECTION_TIMEOUT": &limits.searchTimeout,
+	} {
+		if value := os.Getenv(key); value != "" {
+			parsed, err := time.ParseDuration(value)
+			if err != nil || parsed <= 0 {
+				log.Printf("Invalid %s %q, using %s", key, value, *target)
+			} else {
+				*target = parsed
+			}
+		}
+	}
+	return limits
+})
+
+// capFind merges find options and applies a document cap and the query time limit. A smaller limit set
+// by the caller is kept.
+func capFind(maxDocuments int64, opts []*options.FindOptions) *options.FindOptions {
+	merged := options.MergeFindOptions(opts...)
+	if merged.Limit == nil || *merged.Limit <= 0 || *merged.Limit > maxDocuments {
+		merged.SetLimit(maxDocuments)
+	}
+	if merged.MaxTime == nil {
+		merged.SetMaxTime(queryLimitsConfig().maxTime)
+	}
+	return merged
+}
+
+// boundedFind caps a list query at QUERY_MAX_DOCUMENTS documents and QUERY_MAX_TIME
+func boundedFind(opts ...*options.FindOptions) *options.FindOptions {
+	return capFind(queryLimitsConfig().maxDocuments, opts)
+}
+
+// timedFind applies only QUERY_MAX_TIME, for exports, migrations and reports that must see every document
+func timedFind(opts ...*options.FindOptions) *options.FindOptions {
+	merged := options.MergeFindOptions(opts...)
+	if merged.MaxTime == nil {
+		merged.SetMaxTime(queryLimitsConfig().maxTime)
+	}
+	return merged
+}
+
+// timedAggregate applies QUERY_MAX_TIME to an aggregation
+func timedAggregate() *options.AggregateOptions {
+	return options.Aggregate().SetMaxTime(queryLimitsConfig().maxTime)
+}
+
+// boundedSearch caps one collection of a search at SEARCH_MAX_RESULTS documents and QUERY_MAX_TIME
+func boundedSearch(opts ...*options.FindOptions) *options.FindOptions {
+	return capFind(queryLimitsConfig().maxSearchResults, opts)
+}
@@ -0,0 +1,354 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// rateLimitSnapshot is one replica's in-memory rate limit state, saved to MongoDB so a restart or deploy
+// does not reset every client's quota. The MongoDB state store needs no snapshots.
+type rateLimitSnapshot struct {
+	Instance string              `bson:"_id"`
+	Clients  []rateLimitRequests `bson:"clients"`
+	SavedAt  time.Time           `bson:"saved_at"`
+}
+
+// rateLimitRequests are one rate limit key's recent request times; keys contain IP addresses, whose dots
+// cannot be used as field names
+type rateLimitRequests struct {
+	Key      string      `bson:"key"`
+	Requests []time.Time `bson:"requests"`
+}
+
+// SaveRateLimits snapshots the in-memory rate limit counters; it runs on the "rate_limit_snapshot"
+// schedule and at shutdown
+func (ps *PortfolioService) SaveRateLimits(ctx context.Context) error {
+	store, ok := ps.state.(*memoryStateStore)
+	if !ok {
+		return nil
+	}
+	snapshot := rateLimitSnapshot{Instance: instanceID, SavedAt: time.Now()}
+	for key, requests := range store.requestLog() {
+		snapshot.Clients = append(snapshot.Clients, rateLimitRequests{Key: key, Requests: requests})
+	}
+	if _, err := ps.rateLimits.ReplaceOne(ctx, bson.M{"_id": instanceID}, snapshot, options.Replace().SetUpsert(true)); err != nil {
+		return err
+	}
+	// Snapshots older than the longest window no longer hold a request that counts
+	_, err := ps.rateLimits.DeleteMany(ctx, bson.M{"saved_at": bson.M{"$lt": time.Now().Add(-time.Hour)}})
+	return err
+}
+
+// RestoreRateLimits loads the recent snapshots of every replica into the in-memory counters, so a
+// restarted replica keeps enforcing the quotas clients had used up. It returns how many clients it restored.
+func (ps *PortfolioService) RestoreRateLimits(ctx context.Context) (int, error) {
+	store, ok := ps.state.(*memoryStateStore)
+	if !ok {
+		return 0, nil
+	}
+	cursor, err := ps.rateLimits.Find(ctx, bson.M{"saved_at": bson.M{"$gte": time.Now().Add(-time.Hour)}}, boundedFind())
+	if err != nil {
+		return 0, err
+	}
+	var snapshots []rateLimitSnapshot
+	if err := cursor.All(ctx, &snapshots); err != nil {
+		return 0, err
+	}
+
+	saved := make(map[string][]time.Time)
+	// Runs at startup, while memory is empty; this replica's own snapshot counts too, as a restarted
+	// container keeps its hostname and PID and so its instance ID. Snapshots overlap, since each replica
+	// saves what it restored from the others; restoreRequests counts every request time once.
+	for _, snapshot := range snapshots {
+		for _, client := range snapshot.Clients {
+			saved[client.Key] = append(saved[client.Key], client.Requests...)
+		}
+	}
+	store.restoreRequests(saved)
+	return len(saved), nil
+}
+
+// IP rule actions
+const (
+	IPRuleBan   = "ban"   // Every request is refused
+	IPRuleAllow = "allow" // Rate limits are not applied
+)
+
+// IPRule bans or whitelists a client IP, until ExpiresAt or for good
+type IPRule struct {
+	IP        string     `bson:"_id" json:"ip"`
+	Action    string     `bson:"action" json:"action"`
+	Reason    string     `bson:"reason,omitempty" json:"reason,omitempty"`
+	ExpiresAt *time.Time `bson:"expires_at,omitempty" json:"expires_at,omitempty"`
+	CreatedAt time.Time  `bson:"created_at" json:"created_at"`
+}
+
+func (rule IPRule) active(now time.Time) bool {
+	return rule.ExpiresAt == nil || rule.ExpiresAt.After(now)
+}
+
+// IPRules keeps the IP rules in memory, reloaded from MongoDB every minute and after every change made on
+// this replica, so checking a request never queries the database
+type IPRules struct {
+	service *PortfolioService
+	mutex   sync.RWMutex
+	rules   map[string]IPRule
+}
+
+func NewIPRules(service *PortfolioService) *IPRules {
+	return &IPRules{service: service, rules: make(map[string]IPRule)}
+}
+
+// Reload replaces the rules with the active ones in MongoDB and deletes the expired ones; it runs on the
+// "ip_rules" schedule
+func (r *IPRules) Reload(ctx context.Context) error {
+	if _, err := r.service.ipRules.DeleteMany(ctx, bson.M{"expires_at": bson.M{"$lt": time.Now()}}); err != nil {
+		return err
+	}
+	rules, err := r.service.ListIPRules(ctx)
+	if err != nil {
+		return err
+	}
+	byIP := make(map[string]IPRule, len(rules))
+	for _, rule := range rules {
+		byIP[rule.IP] = rule
+	}
+	r.mutex.Lock()
+	r.rules = byIP
+	r.mutex.Unlock()
+	return nil
+}
+
+// set adds a rule made on this replica without waiting for the next reload
+func (r *IPRules) set(rule IPRule) {
+	r.mutex.Lock()
+	r.rules[rule.IP] = rule
+	r.mutex.Unlock()
+}
+
+// rule returns the active rule for an IP
+func (r *IPRules) rule(ip string) (IPRule, bool) {
+	r.mutex.RLock()
+	rule, found := r.rules[ip]
+	r.mutex.RUnlock()
+	if !found || !rule.active(time.Now()) {
+		return IPRule{}, false
+	}
+	return rule, true
+}
+
+// Action returns the action of the active rule for an IP, or ""
+func (r *IPRules) Action(ip string) string {
+	rule, _ := r.rule(ip)
+	return rule.Action
+}
+
+// Middleware refuses every request from a banned IP; a temporary ban tells the client when it ends
+func (r *IPRules) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if rule, found := r.rule(trustedClientIP(req)); found && rule.Action == IPRuleBan {
+			if rule.ExpiresAt != nil {
+				w.Header().Set("Retry-After", strconv.Itoa(int(time.Until(*rule.ExpiresAt).Seconds())+1))
+			}
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, req)
+	})
+}
+
+func (ps *PortfolioService) ListIPRules(ctx context.Context) ([]IPRule, error) {
+	cursor, err := ps.ipRules.Find(ctx, bson.M{}, boundedFind(options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})))
+	if err != nil {
+		return nil, err
+	}
+	rules := []IPRule{}
+	if err := cursor.All(ctx, &rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// SetIPRule creates or replaces the rule for an IP
+func (ps *PortfolioService) SetIPRule(ctx context.Context, rule *IPRule) error {
+	_, err := ps.ipRules.ReplaceOne(ctx, bson.M{"_id": rule.IP}, rule, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (ps *PortfolioService) DeleteIPRule(ctx context.Context, ip string) error {
+	result, err := ps.ipRules.DeleteOne(ctx, bson.M{"_id": ip})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// Rate limit endpoints (admin only):
+//   - GET /api/admin/rate-limits?client=&limit= lists the busiest clients' usage per limiter and the IP rules
+//   - POST /api/admin/rate-limits/rules {"ip", "action": "ban"|"allow", "duration": "24h", "reason"} bans or
+//     whitelists an IP; without a duration the rule is permanent
+//   - DELETE /api/admin/rate-limits/rules/{ip} lifts a rule
+//   - DELETE /api/admin/rate-limits/usage/{ip} resets a client's counters under every limiter
+func (h *APIHandler) handleRateLimits(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if !isAdminRequest(r) {
+		log.Printf("Date: %s | Route: /api/admin/rate-limits | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ctx := r.Context()
+	resource, ip, _ := strings.Cut(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/admin/rate-limits"), "/"), "/")
+	switch {
+	case resource == "" && r.Method == "GET":
+		var v Validator
+		limit := v.QueryInt(r, "limit", 100, 1, 1000)
+		if err := v.Err(); err != nil {
+			writeInvalidInput(w, err)
+			return
+		}
+		usage, err := h.service.state.RateLimitUsage(ctx, []time.Duration{time.Minute, 5 * time.Minute})
+		if err != nil {
+			log.Printf("Date: %s | Route: /api/admin/rate-limits | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if client := r.URL.Query().Get("client"); client != "" {
+			filtered := usage[:0]
+			for _, entry := range usage {
+				if entry.Client == client {
+					filtered = append(filtered, entry)
+				}
+			}
+			usage = filtered
+		}
+		sort.Slice(usage, func(i, j int) bool {
+			if usage[i].Count != usage[j].Count {
+				return usage[i].Count > usage[j].Count
+			}
+			return usage[i].Client < usage[j].Client
+		})
+		if len(usage) > limit {
+			usage = usage[:limit]
+		}
+		rules, err := h.service.ListIPRules(ctx)
+		if err != nil {
+			log.Printf("Date: %s | Route: /api/admin/rate-limits | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Date: %s | Route: /api/admin/rate-limits | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"store": h.service.state.Name(),
+			"usage": usage,
+			"rules": rules,
+		})
+
+	case resource == "rules" && ip == "" && r.Method == "POST":
+		var request struct {
+			IP       string `json:"ip"`
+			Action   string `json:"action"`
+			Duration string `json:"duration"`
+			Reason   string `json:"reason"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, "Invalid JSON request", http.StatusBadRequest)
+			return
+		}
+		var v Validator
+		v.Check(net.ParseIP(request.IP) != nil, "ip", "ip", "must be an IP address")
+		v.OneOf("action", request.Action, IPRuleBan, IPRuleAllow)
+		v.MaxLength("reason", request.Reason, 200)
+		rule := IPRule{IP: request.IP, Action: request.Action, Reason: request.Reason, CreatedAt: time.Now()}
+		if request.Duration != "" {
+			duration, err := time.ParseDuration(request.Duration)
+			if v.Check(err == nil && duration > 0, "duration", "duration", "must be a positive duration such as 24h") {
+				expiresAt := rule.CreatedAt.Add(duration)
+				rule.ExpiresAt = &expiresAt
+			}
+		}
+		if err := v.Err(); err != nil {
+			writeInvalidInput(w, err)
+			return
+		}
+		if err := h.service.SetIPRule(ctx, &rule); err != nil {
+			log.Printf("Date: %s | Route: /api/admin/rate-limits/rules | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if err := h.service.RecordAudit(ctx, &AuditEntry{Action: AuditIPRuleSet, Actor: "admin", IP: rule.IP, Reason: rule.Action + ": " + rule.Reason, ExpiresAt: rule.ExpiresAt}); err != nil {
+			log.Printf("Error recording audit entry: %v", err)
+		}
+		if err := h.ipRules.Reload(ctx); err != nil {
+			log.Printf("Error reloading IP rules: %v", err)
+		}
+
+		log.Printf("Date: %s | Route: /api/admin/rate-limits/rules | Status: SUCCESS (%s %s) | GPT Model: %s", currentTime, rule.Action, rule.IP, gptModel)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(rule)
+
+	case resource == "rules" && ip != "" && r.Method == "DELETE":
+		err := h.service.DeleteIPRule(ctx, ip)
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "IP rule not found", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			log.Printf("Date: %s | Route: /api/admin/rate-limits/rules/{ip} | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if err := h.service.RecordAudit(ctx, &AuditEntry{Action: AuditIPRuleDeleted, Actor: "admin", IP: ip}); err != nil {
+			log.Printf("Error recording audit entry: %v", err)
+		}
+		if err := h.ipRules.Reload(ctx); err != nil {
+			log.Printf("Error reloading IP rules: %v", err)
+		}
+		log.Printf("Date: %s | Route: /api/admin/rate-limits/rules/{ip} | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+		w.WriteHeader(http.StatusNoContent)
+
+	case resource == "usage" && ip != "" && r.Method == "DELETE":
+		if err := h.service.state.ResetRateLimits(ctx, ip); err != nil {
+			log.Printf("Date: %s | Route: /api/admin/rate-limits/usage/{ip} | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		log.Printf("Date: %s | Route: /api/admin/rate-limits/usage/{ip} | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+		w.WriteHeader(http.StatusNoContent)
+
+	case resource == "" || resource == "rules" || resource == "usage":
+		log.Printf("Date: %s | Route: /api/admin/rate-limits | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+
+	default:
+		http.Error(w, "Not found", http.StatusNotFound)
+	}
+}
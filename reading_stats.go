@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"html"
+	"strings"
+	"unicode"
+
+	"github.com/microcosm-cc/bluemonday"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	// readingWordsPerMinute is a typical silent reading speed for technical prose
+	readingWordsPerMinute = 230
+	// longDescriptionWords is the length from which a project description gets a reading time; shorter
+	// ones are read at a glance
+	longDescriptionWords = 200
+)
+
+// plainTextPolicy strips every tag from rendered HTML, leaving the text a reader sees
+var plainTextPolicy = bluemonday.StrictPolicy()
+
+// ReadingStats are computed from a post's body or a project's description on write, so clients do not
+// count words on every render
+type ReadingStats struct {
+	WordCount      int `bson:"word_count" json:"word_count,omitempty"`
+	ReadingMinutes int `bson:"reading_minutes,omitempty" json:"reading_minutes,omitempty"` // Rounded up
+}
+
+// countWords counts the words of a text: runs of letters and digits, with inner apostrophes and hyphens
+func countWords(text string) int {
+	words := 0
+	inWord := false
+	for _, r := range text {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if !inWord {
+				words++
+			}
+			inWord = true
+		case inWord && (r == '\'' || r == '’' || r == '-'):
+			// Part of the word if a letter follows; a trailing one starts no new word either way
+		default:
+			inWord = false
+		}
+	}
+	return words
+}
+
+// readingStatsOf computes the stats of a text, with a reading time from minWords words
+func readingStatsOf(text string, minWords int) ReadingStats {
+	stats := ReadingStats{WordCount: countWords(text)}
+	if stats.WordCount > 0 && stats.WordCount >= minWords {
+		stats.ReadingMinutes = (stats.WordCount + readingWordsPerMinute - 1) / readingWordsPerMinute
+	}
+	return stats
+}
+
+// refreshReadingStats counts the words of the post as rendered, so markdown syntax and link targets are
+// not counted; BodyHTML must be up to date
+func (post *Post) refreshReadingStats() {
+	post.ReadingStats = readingStatsOf(html.UnescapeString(plainTextPolicy.Sanitize(post.BodyHTML)), 1)
+}
+
+// refreshReadingStats counts the words of the project's description; only long ones get a reading time
+func (project *Project) refreshReadingStats() {
+	project.ReadingStats = readingStatsOf(project.Description, longDescriptionWords)
+}
+
+// BackfillReadingStats computes the reading stats of posts and projects written before they were stored.
+// updated_at is left alone: the content did not change. Returns the number of documents updated.
+func (ps *PortfolioService) BackfillReadingStats(ctx context.Context) (int64, error) {
+	missing := bson.M{"word_count": bson.M{"$exists": false}}
+	var updated int64
+	save := func(collection *mongo.Collection, id interface{}, stats ReadingStats) error {
+		set := bson.M{"word_count": stats.WordCount}
+		if stats.ReadingMinutes > 0 {
+			set["reading_minutes"] = stats.ReadingMinutes
+		}
+		result, err := collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": set})
+		if err != nil {
+			return err
+		}
+		updated += result.ModifiedCount
+		return nil
+	}
+
+	var posts []Post
+	cursor, err := ps.posts.Find(ctx, missing, timedFind())
+	if err != nil {
+		return updated, err
+	}
+	if err = cursor.All(ctx, &posts); err != nil {
+		return updated, err
+	}
+	for i := range posts {
+		post := &posts[i]
+		if strings.TrimSpace(post.BodyHTML) == "" && post.Body != "" {
+			if post.BodyHTML, err = renderMarkdown(post.Body); err != nil {
+				return updated, err
+			}
+		}
+		post.refreshReadingStats()
+		if err := save(ps.posts, post.ID, post.ReadingStats); err != nil {
+			return updated, err
+		}
+	}
+
+	var projects []Project
+	if cursor, err = ps.projects.Find(ctx, missing, timedFind()); err != nil {
+		return updated, err
+	}
+	if err = cursor.All(ctx, &projects); err != nil {
+		return updated, err
+	}
+	for i := range projects {
+		projects[i].refreshReadingStats()
+		if err := save(ps.projects, projects[i].ID, projects[i].ReadingStats); err != nil {
+			return updated, err
+		}
+	}
+	return updated, nil
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// recentChangesDays is the default period of the recent changes feed
+	recentChangesDays = 30
+	// recentChangesContextLimit caps the changes given to the chatbot, so a busy month does not crowd out
+	// the rest of the context
+	recentChangesContextLimit = 10
+)
+
+// Kinds of recent change
+const (
+	RecentChangeAdded     = "added"     // A project was created
+	RecentChangePublished = "published" // A post was published
+	RecentChangeUpdated   = "updated"   // The document was edited
+	RecentChangeActivity  = "activity"  // New commits or pushes in the project's repository
+)
+
+// RecentChange is a project or post added or updated within the period of the feed
+type RecentChange struct {
+	Type    string    `json:"type"` // project or post
+	ID      string    `json:"id"`
+	Title   string    `json:"title"`
+	Summary string    `json:"summary,omitempty"`
+	Change  string    `json:"change"`
+	Date    time.Time `json:"date"` // When the change happened
+	URL     string    `json:"url,omitempty"`
+}
+
+// projectChange returns the latest change to a project since a time, if any
+func projectChange(project *Project, since time.Time) (string, time.Time) {
+	change, date := "", time.Time{}
+	consider := func(kind string, at *time.Time) {
+		if at != nil && !at.Before(since) && at.After(date) {
+			change, date = kind, *at
+		}
+	}
+	created := project.ID.Timestamp()
+	if project.CreatedAt != nil {
+		created = *project.CreatedAt
+	}
+	consider(RecentChangeAdded, &created)
+	if project.Repo != nil {
+		consider(RecentChangeActivity, project.Repo.LastPushedAt)
+		consider(RecentChangeActivity, project.Repo.LastCommitAt)
+	}
+	// An edit right after creation is part of adding the project
+	if project.UpdatedAt != nil && project.UpdatedAt.Sub(created) > time.Minute {
+		consider(RecentChangeUpdated, project.UpdatedAt)
+	}
+	return change, date
+}
+
+// postChange returns the latest change to a published post since a time, if any
+func postChange(post *Post, since time.Time) (string, time.Time) {
+	change, date := "", time.Time{}
+	if post.PublishedAt != nil && !post.PublishedAt.Before(since) {
+		change, date = RecentChangePublished, *post.PublishedAt
+	}
+	if post.UpdatedAt != nil && !post.UpdatedAt.Before(since) && post.PublishedAt != nil && post.UpdatedAt.Sub(*post.PublishedAt) > time.Minute {
+		change, date = RecentChangeUpdated, *post.UpdatedAt
+	}
+	return change, date
+}
+
+// GetRecentChanges lists the listed, current projects and published posts added or updated since a time, newest
+// first, optionally of one author; limit 0 lists them all
+func (ps *PortfolioService) GetRecentChanges(ctx context.Context, authorID primitive.ObjectID, since time.Time, limit int) ([]RecentChange, error) {
+	scope := func(filter bson.M) bson.M {
+		if authorID.IsZero() {
+			return filter
+		}
+		return bson.M{"$and": []bson.M{filter, {"author_id": authorID}}}
+	}
+
+	// Documents written before timestamps were introduced are found by the creation time in their ID
+	// Archived projects are left out: they are not news, and neither is archiving them
+	projects, err := findAll[Project](ctx, ps.projects, scope(bson.M{"$and": []bson.M{listedProjectsFilter(), {"archived": bson.M{"$ne": true}}, {"$or": []bson.M{
+		{"_id": bson.M{"$gte": primitive.NewObjectIDFromTimestamp(since)}},
+		{"created_at": bson.M{"$gte": since}},
+		{"updated_at": bson.M{"$gte": since}},
+		{"repo.last_pushed_at": bson.M{"$gte": since}},
+		{"repo.last_commit_at": bson.M{"$gte": since}},
+	}}}}), nil)
+	if err != nil {
+		return nil, err
+	}
+	posts, err := findAll[Post](ctx, ps.posts, scope(bson.M{"$and": []bson.M{publishedPostsFilter(), {"$or": []bson.M{
+		{"published_at": bson.M{"$gte": since}},
+		{"updated_at": bson.M{"$gte": since}},
+	}}}}), options.Find().SetProjection(bson.M{"body": 0, "body_html": 0}))
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []RecentChange{}
+	for i := range projects {
+		project := &projects[i]
+		change, date := projectChange(project, since)
+		if change == "" {
+			continue
+		}
+		slug := project.Slug
+		if slug == "" {
+			slug = slugify(project.Name)
+		}
+		changes = append(changes, RecentChange{
+			Type:    "project",
+			ID:      project.ID.Hex(),
+			Title:   project.Name,
+			Summary: project.Description,
+			Change:  change,
+			Date:    date,
+			URL:     "/projects/" + slug,
+		})
+	}
+	for i := range posts {
+		post := &posts[i]
+		change, date := postChange(post, since)
+		if change == "" {
+			continue
+		}
+		changes = append(changes, RecentChange{
+			Type:    "post",
+			ID:      post.ID.Hex(),
+			Title:   post.Title,
+			Summary: post.Summary,
+			Change:  change,
+			Date:    date,
+			URL:     "/api/posts/" + post.Slug,
+		})
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].Date.After(changes[j].Date) })
+	if limit > 0 && len(changes) > limit {
+		changes = changes[:limit]
+	}
+	return changes, nil
+}
+
+// Recent changes endpoint: GET /api/recent?days=30&author_id=&limit= lists the projects and posts added or
+// updated in the last days, newest first
+func (h *APIHandler) handleRecentChanges(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if r.Method != "GET" {
+		log.Printf("Date: %s | Route: /api/recent | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var v Validator
+	days := v.QueryInt(r, "days", recentChangesDays, 1, 365)
+	limit := v.QueryInt(r, "limit", 50, 1, 200)
+	var authorID primitive.ObjectID
+	if value := r.URL.Query().Get("author_id"); value != "" {
+		parsed, err := primitive.ObjectIDFromHex(value)
+		v.Check(err == nil, "author_id", "format", "author_id must be an ID")
+		authorID = parsed
+	} else if author := h.hostAuthor(r); author != nil {
+		authorID = author.ID
+	}
+	if err := v.Err(); err != nil {
+		writeInvalidInput(w, err)
+		return
+	}
+
+	since := time.Now().AddDate(0, 0, -days)
+	changes, err := h.service.GetRecentChanges(r.Context(), authorID, since, limit)
+	if err != nil {
+		log.Printf("Date: %s | Route: /api/recent | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Date: %s | Route: /api/recent | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(changes)
+}
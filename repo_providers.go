@@ -0,0 +1,287 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// RepoInfo is the provider-independent view of a hosted repository
+type RepoInfo struct {
+	Name          string
+	FullName      string
+	URL           string
+	Description   string
+	Language      string
+	Topics        []string
+	Stars         int
+	DefaultBranch string
+	Fork          bool
+	CreatedAt     time.Time
+	PushedAt      time.Time
+}
+
+// RepoProvider lists repositories for an account on a single code host
+type RepoProvider interface {
+	// Name is the display name of the host, also used as the stub project category
+	Name() string
+	// Account is the configured user or workspace whose repositories are synced
+	Account() string
+	ListRepos(ctx context.Context) ([]RepoInfo, error)
+}
+
+// newRepoProviders builds every provider that has an account configured in the environment
+func newRepoProviders() []RepoProvider {
+	var providers []RepoProvider
+	if username := os.Getenv("GITHUB_USERNAME"); username != "" {
+		providers = append(providers, &githubProvider{
+			client:   NewGitHubClient(os.Getenv("GITHUB_TOKEN")),
+			username: username,
+		})
+	}
+	if username := os.Getenv("GITLAB_USERNAME"); username != "" {
+		baseURL := os.Getenv("GITLAB_BASE_URL")
+		if baseURL == "" {
+			baseURL = "https://gitlab.com"
+		}
+		providers = append(providers, &gitlabProvider{
+			httpClient: &http.Client{Timeout: 30 * time.Second},
+			baseURL:    strings.TrimSuffix(baseURL, "/"),
+			token:      os.Getenv("GITLAB_TOKEN"),
+			username:   username,
+		})
+	}
+	if workspace := os.Getenv("BITBUCKET_WORKSPACE"); workspace != "" {
+		providers = append(providers, &bitbucketProvider{
+			httpClient:  &http.Client{Timeout: 30 * time.Second},
+			workspace:   workspace,
+			username:    os.Getenv("BITBUCKET_USERNAME"),
+			appPassword: os.Getenv("BITBUCKET_APP_PASSWORD"),
+		})
+	}
+	return providers
+}
+
+// getJSON performs a GET request and decodes a JSON response
+func getJSON(ctx context.Context, client *http.Client, req *http.Request, out interface{}) error {
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned status %d", req.URL.Host, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// GitHub provider
+type githubProvider struct {
+	client   *GitHubClient
+	username string
+}
+
+func (p *githubProvider) Name() string    { return "GitHub" }
+func (p *githubProvider) Account() string { return p.username }
+
+func (p *githubProvider) ListRepos(ctx context.Context) ([]RepoInfo, error) {
+	repos, err := p.client.ListUserRepos(ctx, p.username)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]RepoInfo, 0, len(repos))
+	for _, repo := range repos {
+		info := RepoInfo{
+			Name:          repo.Name,
+			FullName:      repo.FullName,
+			URL:           repo.HTMLURL,
+			Topics:        repo.Topics,
+			Stars:         repo.StargazersCount,
+			DefaultBranch: repo.DefaultBranch,
+			Fork:          repo.Fork,
+			CreatedAt:     repo.CreatedAt,
+			PushedAt:      repo.PushedAt,
+		}
+		if repo.Description != nil {
+			info.Description = *repo.Description
+		}
+		if repo.Language != nil {
+			info.Language = *repo.Language
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// GitLab provider (gitlab.com or self-hosted)
+type gitlabProvider struct {
+	httpClient *http.Client
+	baseURL    string
+	token      string
+	username   string
+}
+
+type gitlabProject struct {
+	ID                int       `json:"id"`
+	Name              string    `json:"name"`
+	PathWithNamespace string    `json:"path_with_namespace"`
+	WebURL            string    `json:"web_url"`
+	Description       *string   `json:"description"`
+	Topics            []string  `json:"topics"`
+	StarCount         int       `json:"star_count"`
+	DefaultBranch     string    `json:"default_branch"`
+	ForkedFromProject *struct{} `json:"forked_from_project"`
+	CreatedAt         time.Time `json:"created_at"`
+	LastActivityAt    time.Time `json:"last_activity_at"`
+}
+
+func (p *gitlabProvider) Name() string    { return "GitLab" }
+func (p *gitlabProvider) Account() string { return p.username }
+
+func (p *gitlabProvider) newRequest(path string) (*http.Request, error) {
+	req, err := http.NewRequest("GET", p.baseURL+"/api/v4"+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	if p.token != "" {
+		req.Header.Set("PRIVATE-TOKEN", p.token)
+	}
+	return req, nil
+}
+
+func (p *gitlabProvider) ListRepos(ctx context.Context) ([]RepoInfo, error) {
+	var infos []RepoInfo
+	for page := 1; ; page++ {
+		req, err := p.newRequest(fmt.Sprintf("/users/%s/projects?visibility=public&per_page=100&page=%d", url.PathEscape(p.username), page))
+		if err != nil {
+			return nil, err
+		}
+
+		var batch []gitlabProject
+		if err := getJSON(ctx, p.httpClient, req, &batch); err != nil {
+			return nil, err
+		}
+
+		for _, project := range batch {
+			info := RepoInfo{
+				Name:          project.Name,
+				FullName:      project.PathWithNamespace,
+				URL:           project.WebURL,
+				Topics:        project.Topics,
+				Stars:         project.StarCount,
+				DefaultBranch: project.DefaultBranch,
+				Fork:          project.ForkedFromProject != nil,
+				CreatedAt:     project.CreatedAt,
+				PushedAt:      project.LastActivityAt,
+			}
+			if project.Description != nil {
+				info.Description = *project.Description
+			}
+			info.Language = p.primaryLanguage(ctx, project.ID)
+			infos = append(infos, info)
+		}
+
+		if len(batch) < 100 {
+			break
+		}
+	}
+	return infos, nil
+}
+
+// primaryLanguage returns the language with the largest share, or "" if it cannot be determined
+func (p *gitlabProvider) primaryLanguage(ctx context.Context, projectID int) string {
+	req, err := p.newRequest(fmt.Sprintf("/projects/%d/languages", projectID))
+	if err != nil {
+		return ""
+	}
+
+	var languages map[string]float64
+	if err := getJSON(ctx, p.httpClient, req, &languages); err != nil {
+		return ""
+	}
+
+	primary, share := "", 0.0
+	for name, percent := range languages {
+		if percent > share {
+			primary, share = name, percent
+		}
+	}
+	return primary
+}
+
+// Bitbucket Cloud provider
+type bitbucketProvider struct {
+	httpClient  *http.Client
+	workspace   string
+	username    string
+	appPassword string
+}
+
+type bitbucketRepoPage struct {
+	Next   string `json:"next"`
+	Values []struct {
+		Name        string `json:"name"`
+		FullName    string `json:"full_name"`
+		Description string `json:"description"`
+		Language    string `json:"language"`
+		Links       struct {
+			HTML struct {
+				Href string `json:"href"`
+			} `json:"html"`
+		} `json:"links"`
+		MainBranch *struct {
+			Name string `json:"name"`
+		} `json:"mainbranch"`
+		Parent    *struct{} `json:"parent"`
+		CreatedOn time.Time `json:"created_on"`
+		UpdatedOn time.Time `json:"updated_on"`
+	} `json:"values"`
+}
+
+func (p *bitbucketProvider) Name() string    { return "Bitbucket" }
+func (p *bitbucketProvider) Account() string { return p.workspace }
+
+func (p *bitbucketProvider) ListRepos(ctx context.Context) ([]RepoInfo, error) {
+	var infos []RepoInfo
+	next := fmt.Sprintf("https://api.bitbucket.org/2.0/repositories/%s?pagelen=100", url.PathEscape(p.workspace))
+	for next != "" {
+		req, err := http.NewRequest("GET", next, nil)
+		if err != nil {
+			return nil, err
+		}
+		if p.username != "" && p.appPassword != "" {
+			req.SetBasicAuth(p.username, p.appPassword)
+		}
+
+		var page bitbucketRepoPage
+		if err := getJSON(ctx, p.httpClient, req, &page); err != nil {
+			return nil, err
+		}
+
+		for _, repo := range page.Values {
+			info := RepoInfo{
+				Name:        repo.Name,
+				FullName:    repo.FullName,
+				URL:         repo.Links.HTML.Href,
+				Description: repo.Description,
+				Language:    repo.Language,
+				Fork:        repo.Parent != nil,
+				CreatedAt:   repo.CreatedOn,
+				PushedAt:    repo.UpdatedOn,
+			}
+			if repo.MainBranch != nil {
+				info.DefaultBranch = repo.MainBranch.Name
+			}
+			infos = append(infos, info)
+		}
+		next = page.Next
+	}
+	return infos, nil
+}
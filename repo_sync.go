@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RepoSyncer periodically enriches projects with data from the owner's hosted repositories
+type RepoSyncer struct {
+	service   *PortfolioService
+	providers []RepoProvider
+	interval  time.Duration
+}
+
+// NewRepoSyncer creates a syncer from environment configuration, or nil if no provider is configured
+func NewRepoSyncer(service *PortfolioService) *RepoSyncer {
+	providers := newRepoProviders()
+	if len(providers) == 0 {
+		log.Println("No repository accounts configured (GITHUB_USERNAME, GITLAB_USERNAME, BITBUCKET_WORKSPACE), repository sync disabled")
+		return nil
+	}
+
+	interval := 6 * time.Hour
+	if value := os.Getenv("REPO_SYNC_INTERVAL"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			log.Printf("Invalid REPO_SYNC_INTERVAL %q, using %s", value, interval)
+		} else {
+			interval = parsed
+		}
+	}
+
+	return &RepoSyncer{
+		service:   service,
+		providers: providers,
+		interval:  interval,
+	}
+}
+
+// Sync runs every configured provider; one failing host does not stop the others
+func (s *RepoSyncer) Sync(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	authorID := s.resolveStubAuthor(ctx)
+
+	var failed []string
+	for _, provider := range s.providers {
+		if err := s.syncProvider(ctx, provider, authorID); err != nil {
+			log.Printf("%s sync failed for %s: %v", provider.Name(), provider.Account(), err)
+			failed = append(failed, provider.Name())
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("sync failed for %v", failed)
+	}
+	return nil
+}
+
+// resolveStubAuthor picks the author new project stubs are attributed to
+func (s *RepoSyncer) resolveStubAuthor(ctx context.Context) primitive.ObjectID {
+	if username := os.Getenv("GITHUB_USERNAME"); username != "" {
+		if author, err := s.service.GetAuthorByGithubUsername(ctx, username); err == nil {
+			return author.ID
+		}
+	}
+
+	// A single-author portfolio can attribute stubs unambiguously
+	authors, err := s.service.GetAllAuthors(ctx)
+	if err == nil && len(authors) == 1 {
+		return authors[0].ID
+	}
+	return primitive.NilObjectID
+}
+
+func (s *RepoSyncer) syncProvider(ctx context.Context, provider RepoProvider, authorID primitive.ObjectID) error {
+	repos, err := provider.ListRepos(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list repositories: %w", err)
+	}
+
+	updated, created := 0, 0
+	for _, repo := range repos {
+		if repo.Fork || repo.URL == "" {
+			continue
+		}
+
+		pushedAt := repo.PushedAt
+		now := time.Now()
+
+		matched, err := s.service.UpdateProjectsByRepo(ctx, repo.URL, bson.M{"$set": bson.M{
+			"repo.provider":       provider.Name(),
+			"repo.full_name":      repo.FullName,
+			"repo.default_branch": repo.DefaultBranch,
+			"repo.stars":          repo.Stars,
+			"repo.language":       repo.Language,
+			"repo.topics":         repo.Topics,
+			"repo.last_commit_at": pushedAt,
+			"repo.synced_at":      now,
+		}})
+		if err != nil {
+			log.Printf("%s sync: failed to update projects for %s: %v", provider.Name(), repo.FullName, err)
+			continue
+		}
+		if matched > 0 {
+			updated++
+			continue
+		}
+
+		// Unmatched repositories become project stubs for later curation
+		repoURL := repo.URL
+		technologies := append([]string{}, repo.Topics...)
+		if repo.Language != "" {
+			technologies = append([]string{repo.Language}, technologies...)
+		}
+		stub := &Project{
+			Name:             repo.Name,
+			Category:         provider.Name(),
+			StartDate:        repo.CreatedAt,
+			Description:      repo.Description,
+			AuthorID:         authorID,
+			TechnologiesUsed: technologies,
+			RepoURL:          &repoURL,
+			Repo: &RepoMetadata{
+				Provider:      provider.Name(),
+				FullName:      repo.FullName,
+				DefaultBranch: repo.DefaultBranch,
+				Stars:         repo.Stars,
+				Language:      repo.Language,
+				Topics:        repo.Topics,
+				LastCommitAt:  &pushedAt,
+				SyncedAt:      now,
+			},
+		}
+		if err := s.service.InsertProject(ctx, stub); err != nil {
+			log.Printf("%s sync: failed to create project stub for %s: %v", provider.Name(), repo.FullName, err)
+			continue
+		}
+		created++
+	}
+
+	log.Printf("%s sync complete for %s: %d repositories, %d projects updated, %d stubs created",
+		provider.Name(), provider.Account(), len(repos), updated, created)
+	return nil
+}
@@ -0,0 +1,334 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// resumeExportData is the view model passed to resume export templates
+type resumeExportData struct {
+	Resume       *Resume
+	Author       *Author
+	Publications []Publication
+	Talks        []Talk
+}
+
+// resumeExportFormat describes a supported resume export format
+type resumeExportFormat struct {
+	template    *template.Template
+	contentType string
+	extension   string
+}
+
+var latexEscaper = strings.NewReplacer(
+	`\`, `\textbackslash{}`,
+	`&`, `\&`,
+	`%`, `\%`,
+	`$`, `\$`,
+	`#`, `\#`,
+	`_`, `\_`,
+	`{`, `\{`,
+	`}`, `\}`,
+	`~`, `\textasciitilde{}`,
+	`^`, `\textasciicircum{}`,
+)
+
+var typstEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`#`, `\#`,
+	`$`, `\$`,
+	`*`, `\*`,
+	`_`, `\_`,
+	"`", "\\`",
+	`<`, `\<`,
+	`>`, `\>`,
+	`@`, `\@`,
+	`[`, `\[`,
+	`]`, `\]`,
+)
+
+// latexURLEscaper prepares a URL for \url{}: \% and \# are understood there, the rest is percent-encoded
+var latexURLEscaper = strings.NewReplacer(
+	`\`, `\%5C`,
+	`%`, `\%`,
+	`#`, `\#`,
+	`_`, `\%5F`,
+	`{`, `\%7B`,
+	`}`, `\%7D`,
+)
+
+// typstURLEscaper prepares a URL for a #link("...") string literal
+var typstURLEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`"`, `\"`,
+)
+
+// formatTemplateFuncs are the date and list helpers shared by every template
+func formatTemplateFuncs() map[string]interface{} {
+	return map[string]interface{}{
+		"date": func(t time.Time) string {
+			if t.IsZero() {
+				return ""
+			}
+			return t.Format("Jan 2006")
+		},
+		"enddate": func(t *time.Time) string {
+			if t == nil {
+				return "Present"
+			}
+			return t.Format("Jan 2006")
+		},
+		"duration": func(months int) string {
+			if months < 12 {
+				return fmt.Sprintf("%d mo", months)
+			}
+			if months%12 == 0 {
+				return fmt.Sprintf("%d yr", months/12)
+			}
+			return fmt.Sprintf("%d yr %d mo", months/12, months%12)
+		},
+		"join": strings.Join,
+		"publink": func(publication Publication) string {
+			return publicationURL(&publication)
+		},
+	}
+}
+
+// exportTemplateFuncs adds the format-specific esc and urlesc functions to the shared helpers
+func exportTemplateFuncs(escaper, urlEscaper *strings.Replacer) template.FuncMap {
+	funcs := template.FuncMap(formatTemplateFuncs())
+	funcs["esc"] = escaper.Replace
+	funcs["urlesc"] = urlEscaper.Replace
+	return funcs
+}
+
+// LaTeX uses braces heavily, so templates use << >> delimiters
+const latexResumeTemplate = `\documentclass[11pt]{article}
+\usepackage[margin=0.75in]{geometry}
+\usepackage[hidelinks]{hyperref}
+\usepackage{enumitem}
+\setlist[itemize]{leftmargin=*,nosep}
+\pagestyle{empty}
+
+\begin{document}
+
+\begin{center}
+  {\LARGE\bfseries << esc .Resume.AuthorName >>}\\[2pt]
+<<- if .Author >><< if .Author.JobTitle >>
+  << esc .Author.JobTitle >>\\
+<<- end >><< end >>
+  << esc .Resume.Contact.Email >><< if .Resume.Contact.Phone >> \textbar{} << esc .Resume.Contact.Phone >><< end >>
+<<- if .Author >><< if .Author.LinkedinURL >> \textbar{} \url{<< urlesc .Author.LinkedinURL >>}<< end >><< if .Author.GithubURL >> \textbar{} \url{<< urlesc .Author.GithubURL >>}<< end >><< end >>
+\end{center}
+<< if .Resume.Experience >>
+\section*{Experience}
+<<- range .Resume.Experience >>
+\noindent\textbf{<< esc .JobTitle >>}, << esc .Company >> \hfill << duration .TimePresent >>
+<<- if .Projects >>
+\begin{itemize}
+<<- range .Projects >>
+  \item \textbf{<< esc .Name >>}: << esc .Description >><< if .TechnologiesUsed >> \emph{(<< esc (join .TechnologiesUsed ", ") >>)}<< end >>
+<<- end >>
+\end{itemize}
+<<- end >>
+\medskip
+<<- end >>
+<< end >>
+<<- if .Resume.Education >>
+\section*{Education}
+<<- range .Resume.Education >>
+\noindent\textbf{<< esc .UniversityName >>}, << esc .Major >> \hfill << date .StartDate >> -- << enddate .EndDate >>
+<<- if .Description >>\\
+<< esc .Description >>
+<<- end >>
+\medskip
+<<- end >>
+<< end >>
+<<- if .Publications >>
+\section*{Publications}
+\begin{itemize}
+<<- range .Publications >>
+  \item << esc .Title >>. \emph{<< esc .Venue >>}, << .Year >>.<< with publink . >> \url{<< urlesc . >>}<< end >>
+<<- end >>
+\end{itemize}
+<< end >>
+<<- if .Talks >>
+\section*{Talks}
+\begin{itemize}
+<<- range .Talks >>
+  \item << esc .Title >>, \emph{<< esc .Event >>}<< if .Location >>, << esc .Location >><< end >> \hfill << date .Date >>
+<<- end >>
+\end{itemize}
+<< end >>
+<<- if .Resume.Skills >>
+\section*{Skills}
+<< esc (join .Resume.Skills ", ") >>
+<< end >>
+\end{document}
+`
+
+const typstResumeTemplate = `#set page(margin: 0.75in)
+#set text(size: 11pt)
+
+#align(center)[
+  #text(size: 18pt, weight: "bold")[<< esc .Resume.AuthorName >>] \
+<<- if .Author >><< if .Author.JobTitle >>
+  << esc .Author.JobTitle >> \
+<<- end >><< end >>
+  << esc .Resume.Contact.Email >><< if .Resume.Contact.Phone >> | << esc .Resume.Contact.Phone >><< end >>
+<<- if .Author >><< if .Author.LinkedinURL >> | #link("<< urlesc .Author.LinkedinURL >>")<< end >><< if .Author.GithubURL >> | #link("<< urlesc .Author.GithubURL >>")<< end >><< end >>
+]
+<< if .Resume.Experience >>
+== Experience
+<<- range .Resume.Experience >>
+
+*<< esc .JobTitle >>*, << esc .Company >> #h(1fr) << duration .TimePresent >>
+<<- range .Projects >>
+- *<< esc .Name >>*: << esc .Description >><< if .TechnologiesUsed >> _(<< esc (join .TechnologiesUsed ", ") >>)_<< end >>
+<<- end >>
+<<- end >>
+<< end >>
+<<- if .Resume.Education >>
+== Education
+<<- range .Resume.Education >>
+
+*<< esc .UniversityName >>*, << esc .Major >> #h(1fr) << date .StartDate >> -- << enddate .EndDate >>
+<<- if .Description >> \
+<< esc .Description >>
+<<- end >>
+<<- end >>
+<< end >>
+<<- if .Publications >>
+== Publications
+<<- range .Publications >>
+- << esc .Title >>. _<< esc .Venue >>_, << .Year >>.<< with publink . >> #link("<< urlesc . >>")<< end >>
+<<- end >>
+<< end >>
+<<- if .Talks >>
+== Talks
+<<- range .Talks >>
+- << esc .Title >>, _<< esc .Event >>_<< if .Location >>, << esc .Location >><< end >> #h(1fr) << date .Date >>
+<<- end >>
+<< end >>
+<<- if .Resume.Skills >>
+== Skills
+<< esc (join .Resume.Skills ", ") >>
+<< end >>
+`
+
+var resumeExportFormats = map[string]resumeExportFormat{
+	"latex": {
+		template:    template.Must(template.New("latex").Delims("<<", ">>").Funcs(exportTemplateFuncs(latexEscaper, latexURLEscaper)).Parse(latexResumeTemplate)),
+		contentType: "application/x-tex; charset=utf-8",
+		extension:   "tex",
+	},
+	"typst": {
+		template:    template.Must(template.New("typst").Delims("<<", ">>").Funcs(exportTemplateFuncs(typstEscaper, typstURLEscaper)).Parse(typstResumeTemplate)),
+		contentType: "text/plain; charset=utf-8",
+		extension:   "typ",
+	},
+}
+
+// renderResumeExport renders a resume in the given export format
+func renderResumeExport(format string, data resumeExportData) ([]byte, *resumeExportFormat, error) {
+	exportFormat, ok := resumeExportFormats[format]
+	if !ok {
+		return nil, nil, fmt.Errorf("unsupported format %q (supported: latex, typst)", format)
+	}
+
+	var buf bytes.Buffer
+	if err := exportFormat.template.Execute(&buf, data); err != nil {
+		return nil, nil, err
+	}
+	return buf.Bytes(), &exportFormat, nil
+}
+
+// Resume export endpoint: /api/resumes/{id}/export?format=latex|typst, where {id} is the resume ID or the
+// author's ID or slug
+func (h *APIHandler) handleResumeExport(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/resumes/"), "/"), "/")
+	if len(parts) != 2 || parts[1] != "export" {
+		http.NotFound(w, r)
+		return
+	}
+
+	if r.Method != "GET" {
+		log.Printf("Date: %s | Route: /api/resumes/{id}/export | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "latex"
+	}
+
+	ctx := context.Background()
+	resume, err := h.service.GetResumeByIDOrAuthor(ctx, parts[0])
+	if err != nil {
+		if errors.Is(err, errInvalidIdentifier) {
+			writeInvalidInput(w, err)
+			return
+		}
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Resume not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("Date: %s | Route: /api/resumes/{id}/export | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// The author document adds job title and profile links; the export still works without it
+	author, err := h.service.GetAuthorByID(ctx, resume.AuthorID)
+	if err != nil {
+		author = nil
+	}
+	if !isAdminRequest(r) {
+		resume.redactContact()
+		if author != nil {
+			author.redactContact()
+		}
+	}
+
+	// Publications and talks are optional sections; a lookup failure just leaves them out
+	publications, _ := h.service.GetPublicationsByAuthor(ctx, resume.AuthorID)
+	talks, _ := h.service.GetTalksByAuthor(ctx, resume.AuthorID)
+
+	output, exportFormat, err := renderResumeExport(format, resumeExportData{
+		Resume:       resume,
+		Author:       author,
+		Publications: publications,
+		Talks:        talks,
+	})
+	if err != nil {
+		log.Printf("Date: %s | Route: /api/resumes/{id}/export | Status: BAD_REQUEST | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	log.Printf("Date: %s | Route: /api/resumes/{id}/export | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+	w.Header().Set("Content-Type", exportFormat.contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="resume.%s"`, exportFormat.extension))
+	w.Write(output)
+}
@@ -0,0 +1,387 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Maximum resume document we accept, and how much of its text is sent to the model
+const (
+	maxResumeUploadSize = 10 << 20
+	maxResumeTextLength = 20000
+)
+
+// ResumeUpload is a resume parsed from an uploaded document. It is returned for review first and
+// saved when posted back, possibly edited, as JSON.
+type ResumeUpload struct {
+	Saved     bool             `json:"saved"`
+	Resume    Resume           `json:"resume"`
+	Education []Education      `json:"education"`
+	Projects  []Project        `json:"projects"`
+	Diff      ResumeUploadDiff `json:"diff"`
+	Warnings  []string         `json:"warnings"`
+}
+
+// ResumeUploadDiff summarizes how a parsed resume differs from what is already stored for the author
+type ResumeUploadDiff struct {
+	SkillsAdded       []string `json:"skills_added"`
+	SkillsRemoved     []string `json:"skills_removed"`
+	ExperienceAdded   []string `json:"experience_added"`
+	ExperienceRemoved []string `json:"experience_removed"`
+	EducationAdded    []string `json:"education_added"`
+	EducationExisting []string `json:"education_existing"` // Matched to stored records, which are kept as they are
+	ProjectsAdded     []string `json:"projects_added"`
+	ProjectsExisting  []string `json:"projects_existing"` // Matched to stored projects, which are kept as they are
+}
+
+// ParseResumeText asks the model to structure resume text as a JSON Resume document
+func (l *LLMService) ParseResumeText(ctx context.Context, text string) (*JSONResume, error) {
+	if len(text) > maxResumeTextLength {
+		text = text[:maxResumeTextLength]
+	}
+
+	prompt := fmt.Sprintf(`Extract the resume below into a JSON object using this structure (a subset of the JSON Resume schema):
+
+{
+  "basics": {"name": "", "label": "job title", "email": "", "phone": "", "url": "", "summary": "", "profiles": [{"network": "LinkedIn or GitHub", "username": "", "url": ""}]},
+  "work": [{"name": "company", "position": "", "startDate": "YYYY-MM", "endDate": "YYYY-MM or empty if current", "summary": "", "highlights": [""]}],
+  "education": [{"institution": "", "area": "field of study", "studyType": "degree", "startDate": "YYYY-MM", "endDate": "YYYY-MM", "score": "GPA if given", "courses": [""]}],
+  "skills": [{"name": "", "keywords": [""]}],
+  "projects": [{"name": "", "description": "", "highlights": [""], "keywords": ["technologies used"], "startDate": "YYYY-MM", "endDate": "YYYY-MM", "url": "", "type": "category"}],
+  "interests": [{"name": ""}]
+}
+
+Instructions:
+- Only include information present in the resume text. Do not invent details.
+- Use empty strings or empty arrays for anything not stated.
+- Use the YYYY-MM date format, or YYYY if only the year is given.
+- Respond with the JSON object only.
+
+RESUME TEXT:
+%s`, text)
+
+	response, err := l.complete(ctx, prompt, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc JSONResume
+	if err := json.Unmarshal([]byte(response), &doc); err != nil {
+		return nil, fmt.Errorf("model returned invalid JSON: %w", err)
+	}
+	return &doc, nil
+}
+
+// newResumeUpload maps a parsed JSON Resume document onto the author's resume, education and projects
+func newResumeUpload(doc *JSONResume, author *Author) (*ResumeUpload, error) {
+	if strings.TrimSpace(doc.Basics.Name) == "" {
+		doc.Basics.Name = author.Name
+	}
+	mapped, err := mapJSONResume(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	upload := &ResumeUpload{
+		Resume:    mapped.Resume,
+		Education: mapped.Education,
+		Projects:  mapped.Projects,
+		Warnings:  mapped.Warnings,
+	}
+	upload.Resume.AuthorID = author.ID
+	upload.Resume.AuthorName = author.Name
+	for i := range upload.Education {
+		upload.Education[i].StudentName = author.Name
+		upload.Education[i].StudentID = author.ID
+	}
+	for i := range upload.Projects {
+		upload.Projects[i].AuthorID = author.ID
+	}
+	return upload, nil
+}
+
+// experienceLabel describes a position for the review diff
+func experienceLabel(experience Experience) string {
+	return joinNonEmpty(" at ", experience.JobTitle, experience.Company)
+}
+
+// diffResumeUpload compares the upload against the author's stored data, matching education and projects
+// to existing records so only new ones are inserted on save
+func (ps *PortfolioService) diffResumeUpload(ctx context.Context, author *Author, upload *ResumeUpload) error {
+	diff := ResumeUploadDiff{
+		SkillsAdded:       []string{},
+		SkillsRemoved:     []string{},
+		ExperienceAdded:   []string{},
+		ExperienceRemoved: []string{},
+		EducationAdded:    []string{},
+		EducationExisting: []string{},
+		ProjectsAdded:     []string{},
+		ProjectsExisting:  []string{},
+	}
+
+	existing, err := ps.GetResumeByAuthor(ctx, author.ID)
+	if err == mongo.ErrNoDocuments {
+		existing = &Resume{}
+	} else if err != nil {
+		return err
+	}
+
+	// Keep stored contact details the document did not mention
+	if upload.Resume.Contact.Phone == "" {
+		upload.Resume.Contact.Phone = existing.Contact.Phone
+	}
+	if upload.Resume.Contact.Email == "" {
+		upload.Resume.Contact.Email = existing.Contact.Email
+	}
+
+	oldSkills := make(map[string]bool)
+	for _, skill := range existing.Skills {
+		oldSkills[strings.ToLower(skill)] = true
+	}
+	newSkills := make(map[string]bool)
+	for _, skill := range upload.Resume.Skills {
+		newSkills[strings.ToLower(skill)] = true
+		if !oldSkills[strings.ToLower(skill)] {
+			diff.SkillsAdded = append(diff.SkillsAdded, skill)
+		}
+	}
+	for _, skill := range existing.Skills {
+		if !newSkills[strings.ToLower(skill)] {
+			diff.SkillsRemoved = append(diff.SkillsRemoved, skill)
+		}
+	}
+
+	oldExperience := make(map[string]bool)
+	for _, experience := range existing.Experience {
+		oldExperience[strings.ToLower(experienceLabel(experience))] = true
+	}
+	newExperience := make(map[string]bool)
+	for _, experience := range upload.Resume.Experience {
+		label := experienceLabel(experience)
+		newExperience[strings.ToLower(label)] = true
+		if !oldExperience[strings.ToLower(label)] {
+			diff.ExperienceAdded = append(diff.ExperienceAdded, label)
+		}
+	}
+	for _, experience := range existing.Experience {
+		if label := experienceLabel(experience); !newExperience[strings.ToLower(label)] {
+			diff.ExperienceRemoved = append(diff.ExperienceRemoved, label)
+		}
+	}
+
+	storedEducation, err := ps.GetEducationByStudent(ctx, author.ID)
+	if err != nil {
+		return err
+	}
+	for i := range upload.Education {
+		entry := &upload.Education[i]
+		label := joinNonEmpty(", ", entry.Major, entry.UniversityName)
+		entry.ID = primitive.NilObjectID
+		for _, stored := range storedEducation {
+			if strings.EqualFold(stored.UniversityName, entry.UniversityName) && strings.EqualFold(stored.Major, entry.Major) {
+				*entry = stored
+				break
+			}
+		}
+		if entry.ID.IsZero() {
+			diff.EducationAdded = append(diff.EducationAdded, label)
+		} else {
+			diff.EducationExisting = append(diff.EducationExisting, label)
+		}
+	}
+
+	storedProjects, err := ps.GetProjectsByAuthor(ctx, author.ID, true)
+	if err != nil {
+		return err
+	}
+	for i := range upload.Projects {
+		project := &upload.Projects[i]
+		project.ID = primitive.NilObjectID
+		for _, stored := range storedProjects {
+			if slugify(stored.Name) == slugify(project.Name) {
+				*project = stored
+				break
+			}
+		}
+		if project.ID.IsZero() {
+			diff.ProjectsAdded = append(diff.ProjectsAdded, project.Name)
+		} else {
+			diff.ProjectsExisting = append(diff.ProjectsExisting, project.Name)
+		}
+	}
+
+	upload.Diff = diff
+	return nil
+}
+
+// saveResumeUpload inserts new education and projects and replaces the author's resume
+func (ps *PortfolioService) saveResumeUpload(ctx context.Context, upload *ResumeUpload) error {
+	for i := range upload.Education {
+		if upload.Education[i].ID.IsZero() {
+			if err := ps.InsertEducation(ctx, &upload.Education[i]); err != nil {
+				return err
+			}
+		}
+	}
+	for i := range upload.Projects {
+		if upload.Projects[i].ID.IsZero() {
+			if err := ps.InsertProject(ctx, &upload.Projects[i]); err != nil {
+				return err
+			}
+		}
+	}
+
+	upload.Resume.Education = upload.Education
+	return ps.UpsertResumeByAuthor(ctx, &upload.Resume)
+}
+
+// Resume upload endpoint (admin only): POST /api/admin/import/resume?author_id=...
+// A multipart upload with a PDF or DOCX "file" is parsed by the LLM and returned for review with a diff
+// against the stored data; nothing is saved. Posting the reviewed result back as JSON saves it.
+func (h *APIHandler) handleResumeUpload(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if r.Method != "POST" {
+		log.Printf("Date: %s | Route: /api/admin/import/resume | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !isAdminRequest(r) {
+		log.Printf("Date: %s | Route: /api/admin/import/resume | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	authorID, err := primitive.ObjectIDFromHex(r.URL.Query().Get("author_id"))
+	if err != nil {
+		http.Error(w, "Invalid author ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx := context.Background()
+	author, err := h.service.GetAuthorByID(ctx, authorID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxResumeUploadSize)
+
+	// Saving a reviewed upload
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		var upload ResumeUpload
+		if err := json.NewDecoder(r.Body).Decode(&upload); err != nil {
+			log.Printf("Date: %s | Route: /api/admin/import/resume | Status: BAD_REQUEST | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, "Invalid JSON request", http.StatusBadRequest)
+			return
+		}
+		upload.Resume.AuthorID = author.ID
+		upload.Resume.AuthorName = author.Name
+		for i := range upload.Education {
+			upload.Education[i].StudentID = author.ID
+		}
+		for i := range upload.Projects {
+			upload.Projects[i].AuthorID = author.ID
+		}
+
+		// The diff is recomputed so records added since the review are not duplicated
+		if err := h.service.diffResumeUpload(ctx, author, &upload); err != nil {
+			log.Printf("Date: %s | Route: /api/admin/import/resume | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if err := h.service.saveResumeUpload(ctx, &upload); err != nil {
+			log.Printf("Date: %s | Route: /api/admin/import/resume | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		upload.Saved = true
+
+		log.Printf("Date: %s | Route: /api/admin/import/resume | Status: SAVED | GPT Model: %s", currentTime, gptModel)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(upload)
+		return
+	}
+
+	if h.llmService == nil {
+		log.Printf("Date: %s | Route: /api/admin/import/resume | Status: LLM_DISABLED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Resume parsing requires OPENAI_API_KEY", http.StatusServiceUnavailable)
+		return
+	}
+
+	if err := r.ParseMultipartForm(maxResumeUploadSize); err != nil {
+		log.Printf("Date: %s | Route: /api/admin/import/resume | Status: BAD_REQUEST | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Expected a multipart upload with a PDF or DOCX 'file' (max 10MB)", http.StatusBadRequest)
+		return
+	}
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "Missing 'file' field", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		http.Error(w, "Failed to read upload", http.StatusBadRequest)
+		return
+	}
+	text, err := extractDocumentText(data)
+	if err != nil {
+		log.Printf("Date: %s | Route: /api/admin/import/resume | Status: INVALID_INPUT | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, fmt.Sprintf("Invalid document: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	doc, err := h.llmService.ParseResumeText(ctx, text)
+	if errors.Is(err, errLLMBusy) {
+		log.Printf("Date: %s | Route: /api/admin/import/resume | Status: LLM_BUSY | GPT Model: %s", currentTime, gptModel)
+		writeLLMBusy(w)
+		return
+	}
+	if err != nil {
+		log.Printf("Date: %s | Route: /api/admin/import/resume | Status: LLM_ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, fmt.Sprintf("Failed to parse resume: %v", err), http.StatusBadGateway)
+		return
+	}
+
+	upload, err := newResumeUpload(doc, author)
+	if err != nil {
+		log.Printf("Date: %s | Route: /api/admin/import/resume | Status: INVALID_INPUT | GPT Model: %s", currentTime, gptModel)
+		writeInvalidInput(w, err)
+		return
+	}
+	if len(text) > maxResumeTextLength {
+		upload.Warnings = append(upload.Warnings, fmt.Sprintf("document text was truncated to %d characters", maxResumeTextLength))
+	}
+	if err := h.service.diffResumeUpload(ctx, author, upload); err != nil {
+		log.Printf("Date: %s | Route: /api/admin/import/resume | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Date: %s | Route: /api/admin/import/resume | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(upload)
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// RetentionPolicy deletes documents in a collection once their timestamp field is older than MaxAge
+type RetentionPolicy struct {
+	Name       string
+	Collection string
+	Field      string
+	MaxAge     time.Duration
+}
+
+// RetentionReport describes what a policy removed, or would remove in a dry run
+type RetentionReport struct {
+	Policy        string     `json:"policy"`
+	Collection    string     `json:"collection"`
+	RetentionDays int        `json:"retention_days"`
+	Cutoff        time.Time  `json:"cutoff"`
+	Expired       int64      `json:"expired"`             // Documents older than the cutoff
+	Deleted       int64      `json:"deleted"`             // Always 0 in a dry run
+	OldestAt      *time.Time `json:"oldest_at,omitempty"` // Oldest expired document
+	Error         string     `json:"error,omitempty"`
+}
+
+// RetentionPurger enforces the retention policies on a schedule
+type RetentionPurger struct {
+	service  *PortfolioService
+	policies []RetentionPolicy
+	interval time.Duration
+}
+
+// retentionDays reads a retention period in days from the environment; 0 disables the policy
+func retentionDays(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	days, err := strconv.Atoi(value)
+	if err != nil || days < 0 {
+		log.Printf("Invalid %s %q, keeping %d days", key, value, fallback)
+		return fallback
+	}
+	return days
+}
+
+// NewRetentionPurger builds the retention policies from the environment. Defaults: chat logs 90 days,
+// analytics 13 months, contact messages 2 years, unused context summaries 30 days, finished jobs 14 days,
+// schedule claims 7 days, LLM calls and chat traces 90 days, hourly request totals, the audit log and deletion
+// tombstones 13 months.
+func NewRetentionPurger(service *PortfolioService) *RetentionPurger {
+	defaults := []struct {
+		name, collection, field, env string
+		days                         int
+	}{
+		{"chat_logs", "chat_turns", "created_at", "CHAT_RETENTION_DAYS", 90},
+		{"analytics", "analytics", "created_at", "ANALYTICS_RETENTION_DAYS", 395},
+		{"contact_messages", "contact_messages", "created_at", "CONTACT_RETENTION_DAYS", 730}, // Only takes effect once contact messages are stored
+		{"context_summaries", "context_summaries", "used_at", "CONTEXT_SUMMARY_RETENTION_DAYS", 30},
+		{"finished_jobs", "jobs", "finished_at", "JOB_RETENTION_DAYS", 14}, // Queued and running jobs have no finished_at
+		{"schedule_claims", "schedule_claims", "claimed_at", "SCHEDULE_CLAIM_RETENTION_DAYS", 7},
+		{"llm_calls", "llm_calls", "created_at", "LLM_CALL_RETENTION_DAYS", 90},
+		{"chat_traces", "chat_traces", "created_at", "CHAT_TRACE_RETENTION_DAYS", 90},
+		{"request_stats", "request_stats", "_id", "REQUEST_STATS_RETENTION_DAYS", 395}, // Keyed by hour
+		{"audit_log", "audit_log", "created_at", "AUDIT_RETENTION_DAYS", 395},
+		{"deletions", "deletions", "deleted_at", "DELETION_RETENTION_DAYS", 395}, // Diffs over a longer period miss deletions
+	}
+
+	var policies []RetentionPolicy
+	for _, d := range defaults {
+		days := retentionDays(d.env, d.days)
+		if days == 0 {
+			log.Printf("%s is 0, %s are kept indefinitely", d.env, d.name)
+			continue
+		}
+		policies = append(policies, RetentionPolicy{
+			Name:       d.name,
+			Collection: d.collection,
+			Field:      d.field,
+			MaxAge:     time.Duration(days) * 24 * time.Hour,
+		})
+	}
+
+	interval := 24 * time.Hour
+	if value := os.Getenv("RETENTION_INTERVAL"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			log.Printf("Invalid RETENTION_INTERVAL %q, using %s", value, interval)
+		} else {
+			interval = parsed
+		}
+	}
+
+	return &RetentionPurger{
+		service:  service,
+		policies: policies,
+		interval: interval,
+	}
+}
+
+// RunJob purges as a background job, failing if any policy failed so the queue retries it
+func (p *RetentionPurger) RunJob(ctx context.Context, _ bson.M) error {
+	var failed []string
+	for _, report := range p.Purge(ctx, false) {
+		if report.Error != "" {
+			failed = append(failed, report.Policy+": "+report.Error)
+		} else if report.Deleted > 0 {
+			log.Printf("Retention purge removed %d %s older than %s", report.Deleted, report.Policy, report.Cutoff.Format("2006-01-02"))
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("retention purge failed for %s", strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+// Purge applies every policy; with dryRun it only counts what would be deleted. A failing policy does
+// not stop the others.
+func (p *RetentionPurger) Purge(ctx context.Context, dryRun bool) []RetentionReport {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	now := time.Now()
+	reports := []RetentionReport{}
+	for _, policy := range p.policies {
+		report := RetentionReport{
+			Policy:        policy.Name,
+			Collection:    policy.Collection,
+			RetentionDays: int(policy.MaxAge / (24 * time.Hour)),
+			Cutoff:        now.Add(-policy.MaxAge),
+		}
+		if err := p.service.applyRetentionPolicy(ctx, policy, &report, dryRun); err != nil {
+			report.Error = err.Error()
+		}
+		reports = append(reports, report)
+	}
+	return reports
+}
+
+// applyRetentionPolicy counts, and unless dryRun deletes, the documents older than the report's cutoff
+func (ps *PortfolioService) applyRetentionPolicy(ctx context.Context, policy RetentionPolicy, report *RetentionReport, dryRun bool) error {
+	collection := ps.database.Collection(policy.Collection)
+	filter := bson.M{policy.Field: bson.M{"$lt": report.Cutoff}}
+
+	expired, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("failed to count expired documents: %w", err)
+	}
+	report.Expired = expired
+	if expired == 0 {
+		return nil
+	}
+
+	var oldest bson.M
+	err = collection.FindOne(ctx, filter, options.FindOne().SetSort(bson.D{{Key: policy.Field, Value: 1}})).Decode(&oldest)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return fmt.Errorf("failed to find oldest document: %w", err)
+	}
+	if value, ok := oldest[policy.Field].(primitive.DateTime); ok {
+		t := value.Time()
+		report.OldestAt = &t
+	}
+
+	if dryRun {
+		return nil
+	}
+	result, err := collection.DeleteMany(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("failed to delete expired documents: %w", err)
+	}
+	report.Deleted = result.DeletedCount
+	return nil
+}
+
+// Retention endpoint: GET /api/admin/retention reports what each policy would delete without deleting;
+// POST runs the purge now (admin only)
+func (h *APIHandler) handleRetention(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if r.Method != "GET" && r.Method != "POST" {
+		log.Printf("Date: %s | Route: /api/admin/retention | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !isAdminRequest(r) {
+		log.Printf("Date: %s | Route: /api/admin/retention | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	dryRun := r.Method == "GET"
+	reports := h.retention.Purge(r.Context(), dryRun)
+
+	log.Printf("Date: %s | Route: /api/admin/retention | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"dry_run":  dryRun,
+		"policies": reports,
+	})
+}
@@ -0,0 +1,370 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Schedule computes when a scheduled task runs next
+type Schedule interface {
+	Next(after time.Time) time.Time
+}
+
+// everySchedule runs at a fixed interval. Runs are aligned to multiples of the interval since the zero time,
+// so every replica computes the same run times.
+type everySchedule struct {
+	interval time.Duration
+}
+
+func (s everySchedule) Next(after time.Time) time.Time {
+	return after.Truncate(s.interval).Add(s.interval)
+}
+
+// cronSchedule is a standard five-field cron expression: minute, hour, day of month, month, day of week.
+// Each field holds a bitset of the values it matches.
+type cronSchedule struct {
+	minutes, hours, days, months, weekdays uint64
+	anyDay, anyWeekday                     bool
+}
+
+// cronDescriptors are the supported shorthands besides "@every <duration>"
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// ParseSchedule parses a cron expression such as "*/5 * * * *" or "0 3 * * 1-5" (lists, ranges and steps are
+// supported; names such as "mon" are not), a descriptor such as "@daily", or "@every 6h"
+func ParseSchedule(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if value, found := strings.CutPrefix(spec, "@every "); found {
+		interval, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil || interval < time.Minute {
+			return nil, fmt.Errorf("invalid interval in %q: must be a duration of at least 1m", spec)
+		}
+		return everySchedule{interval: interval}, nil
+	}
+	if expanded, ok := cronDescriptors[spec]; ok {
+		spec = expanded
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid schedule %q: expected 5 fields (minute hour day month weekday)", spec)
+	}
+	bounds := [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+	var sets [5]uint64
+	for i, field := range fields {
+		set, err := parseCronField(field, bounds[i][0], bounds[i][1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule %q: %w", spec, err)
+		}
+		sets[i] = set
+	}
+	// 7 is an alias for Sunday
+	if sets[4]&(1<<7) != 0 {
+		sets[4] = sets[4]&^(1<<7) | 1
+	}
+	return &cronSchedule{
+		minutes:    sets[0],
+		hours:      sets[1],
+		days:       sets[2],
+		months:     sets[3],
+		weekdays:   sets[4],
+		anyDay:     fields[2] == "*",
+		anyWeekday: fields[4] == "*",
+	}, nil
+}
+
+// parseCronField parses a comma-separated list of *, n, a-b, */step and a-b/step into a bitset
+func parseCronField(field string, min, max int) (uint64, error) {
+	var set uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			parsed, err := strconv.Atoi(stepPart)
+			if err != nil || parsed < 1 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+			step = parsed
+		}
+
+		low, high := min, max
+		if rangePart != "*" {
+			first, last, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if low, err = strconv.Atoi(first); err != nil {
+				return 0, fmt.Errorf("invalid value in %q", part)
+			}
+			high = low
+			if isRange {
+				if high, err = strconv.Atoi(last); err != nil {
+					return 0, fmt.Errorf("invalid range in %q", part)
+				}
+			} else if hasStep {
+				high = max
+			}
+		}
+		if low < min || high > max || low > high {
+			return 0, fmt.Errorf("%q is out of range %d-%d", part, min, max)
+		}
+		for value := low; value <= high; value += step {
+			set |= 1 << value
+		}
+	}
+	return set, nil
+}
+
+// matchesDay applies the cron rule that a day matches either restricted day field when both are restricted
+func (s *cronSchedule) matchesDay(t time.Time) bool {
+	day := s.days&(1<<t.Day()) != 0
+	weekday := s.weekdays&(1<<int(t.Weekday())) != 0
+	switch {
+	case s.anyDay && s.anyWeekday:
+		return true
+	case s.anyDay:
+		return weekday
+	case s.anyWeekday:
+		return day
+	default:
+		return day || weekday
+	}
+}
+
+// Next returns the first matching minute after the given time, or the zero time if there is none within
+// five years (e.g. "0 0 30 2 *")
+func (s *cronSchedule) Next(after time.Time) time.Time {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case s.months&(1<<int(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !s.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case s.hours&(1<<t.Hour()) == 0:
+			t = t.Truncate(time.Hour).Add(time.Hour)
+		case s.minutes&(1<<t.Minute()) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// ScheduledTaskStatus describes a scheduled task for the admin API
+type ScheduledTaskStatus struct {
+	Name      string     `json:"name"`
+	Schedule  string     `json:"schedule"`
+	NextRun   *time.Time `json:"next_run,omitempty"`
+	LastRun   *time.Time `json:"last_run,omitempty"`
+	LastError string     `json:"last_error,omitempty"`
+	Running   bool       `json:"running"`
+}
+
+type scheduledTask struct {
+	ScheduledTaskStatus
+	schedule Schedule
+	local    bool // Runs on every replica instead of once per schedule
+	run      func(ctx context.Context) error
+}
+
+// Scheduler runs registered tasks on cron schedules. A task that is still running when it is due again is
+// skipped rather than run twice. With several replicas, each run of a task is claimed in MongoDB so it
+// happens on exactly one of them, unless the task is registered with RegisterLocal.
+type Scheduler struct {
+	service *PortfolioService
+	mutex   sync.Mutex
+	tasks   []*scheduledTask
+	wake    chan struct{}
+}
+
+func NewScheduler(service *PortfolioService) *Scheduler {
+	return &Scheduler{service: service, wake: make(chan struct{}, 1)}
+}
+
+// scheduleEnvKey is the variable overriding a task's schedule, e.g. SCHEDULE_REPO_SYNC for repo_sync
+func scheduleEnvKey(name string) string {
+	return "SCHEDULE_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// Register adds a task that runs once per schedule across all replicas. Its schedule is defaultSpec unless
+// SCHEDULE_<NAME> is set; "off" disables the task. An "@every" task whose current interval has not run yet,
+// e.g. on first start, runs right away.
+func (s *Scheduler) Register(name, defaultSpec string, run func(ctx context.Context) error) {
+	s.register(name, defaultSpec, false, run)
+}
+
+// RegisterLocal adds a task that runs on every replica, for work on in-memory state such as caches
+func (s *Scheduler) RegisterLocal(name, defaultSpec string, run func(ctx context.Context) error) {
+	s.register(name, defaultSpec, true, run)
+}
+
+func (s *Scheduler) register(name, defaultSpec string, local bool, run func(ctx context.Context) error) {
+	key := scheduleEnvKey(name)
+	spec := defaultSpec
+	if value := strings.TrimSpace(os.Getenv(key)); value != "" {
+		spec = value
+	}
+	if spec == "off" {
+		log.Printf("%s is off, %s is not scheduled", key, name)
+		return
+	}
+	schedule, err := ParseSchedule(spec)
+	if err != nil && spec != defaultSpec {
+		log.Printf("Invalid %s: %v, using %q", key, err, defaultSpec)
+		spec = defaultSpec
+		schedule, err = ParseSchedule(spec)
+	}
+	if err != nil {
+		log.Printf("Not scheduling %s: %v", name, err)
+		return
+	}
+
+	now := time.Now()
+	next := schedule.Next(now)
+	if every, ok := schedule.(everySchedule); ok {
+		next = now.Truncate(every.interval)
+	}
+	if next.IsZero() {
+		log.Printf("Not scheduling %s: %q never matches", name, spec)
+		return
+	}
+	task := &scheduledTask{
+		ScheduledTaskStatus: ScheduledTaskStatus{Name: name, Schedule: spec, NextRun: &next},
+		schedule:            schedule,
+		local:               local,
+		run:                 run,
+	}
+
+	s.mutex.Lock()
+	s.tasks = append(s.tasks, task)
+	s.mutex.Unlock()
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Status lists the registered tasks
+func (s *Scheduler) Status() []ScheduledTaskStatus {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	statuses := make([]ScheduledTaskStatus, len(s.tasks))
+	for i, task := range s.tasks {
+		statuses[i] = task.ScheduledTaskStatus
+	}
+	return statuses
+}
+
+// Run starts due tasks until the context is cancelled
+func (s *Scheduler) Run(ctx context.Context) {
+	for {
+		wait := s.startDueTasks(ctx)
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-s.wake:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+// startDueTasks starts every task whose time has come and returns how long to sleep until the next one
+func (s *Scheduler) startDueTasks(ctx context.Context) time.Duration {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	wait := time.Hour
+	for _, task := range s.tasks {
+		if task.NextRun == nil {
+			continue // No future match for the schedule
+		}
+		if !task.NextRun.After(now) {
+			if task.Running {
+				log.Printf("Scheduled task %s is still running, skipping this run", task.Name)
+			} else {
+				task.Running = true
+				go s.execute(ctx, task, *task.NextRun)
+			}
+			next := task.schedule.Next(now)
+			task.NextRun = &next
+			if next.IsZero() {
+				task.NextRun = nil
+				continue
+			}
+		}
+		if until := task.NextRun.Sub(now); until < wait {
+			wait = until
+		}
+	}
+	return wait
+}
+
+// execute runs one occurrence of a task, unless another replica has already claimed it
+func (s *Scheduler) execute(ctx context.Context, task *scheduledTask, occurrence time.Time) {
+	if !task.local {
+		claimed, err := s.service.ClaimScheduledRun(ctx, task.Name, occurrence)
+		if err != nil {
+			log.Printf("Error claiming scheduled task %s: %v", task.Name, err)
+		}
+		if !claimed {
+			s.mutex.Lock()
+			task.Running = false
+			if err != nil {
+				task.LastError = err.Error()
+			}
+			s.mutex.Unlock()
+			return
+		}
+	}
+
+	started := time.Now()
+	err := func() (err error) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				errorReporter().CapturePanic(recovered, nil, map[string]string{"scheduled_task": task.Name})
+				err = fmt.Errorf("panic: %v", recovered)
+			}
+		}()
+		return task.run(ctx)
+	}()
+	if err != nil {
+		log.Printf("Scheduled task %s failed: %v", task.Name, err)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	task.Running = false
+	task.LastRun = &started
+	task.LastError = ""
+	if err != nil {
+		task.LastError = err.Error()
+	}
+}
+
+// ScheduleJob registers a task that enqueues a background job of the same name, unless one is already
+// pending, so scheduled work gets the queue's retries and shows up in /api/admin/jobs
+func (s *Scheduler) ScheduleJob(queue *JobQueue, jobType, defaultSpec string) {
+	s.Register(jobType, defaultSpec, func(ctx context.Context) error {
+		_, err := queue.EnqueueUnlessPending(ctx, jobType)
+		return err
+	})
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Kinds of evidence a skill verification can link to
+const (
+	SkillVerificationProject       = "project"
+	SkillVerificationCertification = "certification"
+	SkillVerificationRepo          = "repo"
+)
+
+const (
+	maxSkillVerifications    = 20
+	maxSkillVerificationNote = 300
+)
+
+// SkillVerification links a skill to evidence of it: a project or certification by ID, or a repository URL
+type SkillVerification struct {
+	Type string              `bson:"type" json:"type"`                     // project, certification or repo
+	ID   *primitive.ObjectID `bson:"id,omitempty" json:"id,omitempty"`     // Project or certification
+	URL  string              `bson:"url,omitempty" json:"url,omitempty"`   // Repository
+	Note string              `bson:"note,omitempty" json:"note,omitempty"` // What it shows, e.g. "wrote the Go backend"
+}
+
+// SkillEvidence is a verification resolved for display and for the chatbot to cite
+type SkillEvidence struct {
+	Type  string `json:"type"`
+	ID    string `json:"id,omitempty"`
+	Title string `json:"title"`
+	URL   string `json:"url,omitempty"`
+	Note  string `json:"note,omitempty"`
+}
+
+// validateSkillVerifications checks the verifications submitted with a skill
+func validateSkillVerifications(v *Validator, verifications []SkillVerification) {
+	v.MaxItems("verifications", len(verifications), maxSkillVerifications)
+	for i, verification := range verifications {
+		field := fmt.Sprintf("verifications[%d]", i)
+		if !v.OneOf(field+".type", verification.Type, SkillVerificationProject, SkillVerificationCertification, SkillVerificationRepo) {
+			continue
+		}
+		if verification.Type == SkillVerificationRepo {
+			if v.Required(field+".url", verification.URL) {
+				v.HTTPURL(field+".url", verification.URL)
+			}
+		} else {
+			v.Check(verification.ID != nil && !verification.ID.IsZero(), field+".id", "required", field+".id is required for "+verification.Type+" evidence")
+		}
+		v.MaxLength(field+".note", verification.Note, maxSkillVerificationNote)
+	}
+}
+
+// resolveSkillEvidence fills in the evidence of each skill: its verifications, then the projects it is linked
+// to and the certifications listing it that they do not already cite. Only the skill author's listed projects
+// count, and verifications of deleted or hidden documents are dropped.
+func resolveSkillEvidence(skills []Skill, projects []Project, certifications []Certification) {
+	projectsByID := make(map[primitive.ObjectID]*Project, len(projects))
+	for i := range projects {
+		projectsByID[projects[i].ID] = &projects[i]
+	}
+	certificationsByID := make(map[primitive.ObjectID]*Certification, len(certifications))
+	for i := range certifications {
+		certificationsByID[certifications[i].ID] = &certifications[i]
+	}
+
+	for i := range skills {
+		skill := &skills[i]
+		skill.Evidence = []SkillEvidence{}
+		cited := make(map[primitive.ObjectID]bool)
+
+		addProject := func(id primitive.ObjectID, note string) {
+			project, ok := projectsByID[id]
+			if !ok || cited[id] || project.AuthorID != skill.AuthorID {
+				return
+			}
+			cited[id] = true
+			slug := project.Slug
+			if slug == "" {
+				slug = slugify(project.Name)
+			}
+			skill.Evidence = append(skill.Evidence, SkillEvidence{Type: SkillVerificationProject, ID: id.Hex(), Title: project.Name, URL: "/projects/" + slug, Note: note})
+		}
+		addCertification := func(id primitive.ObjectID, note string) {
+			certification, ok := certificationsByID[id]
+			if !ok || cited[id] || certification.AuthorID != skill.AuthorID {
+				return
+			}
+			cited[id] = true
+			title := certification.Name
+			if certification.Issuer != "" {
+				title += " (" + certification.Issuer + ")"
+			}
+			skill.Evidence = append(skill.Evidence, SkillEvidence{Type: SkillVerificationCertification, ID: id.Hex(), Title: title, URL: certification.VerificationURL, Note: note})
+		}
+
+		for _, verification := range skill.Verifications {
+			switch verification.Type {
+			case SkillVerificationProject:
+				if verification.ID != nil {
+					addProject(*verification.ID, verification.Note)
+				}
+			case SkillVerificationCertification:
+				if verification.ID != nil {
+					addCertification(*verification.ID, verification.Note)
+				}
+			case SkillVerificationRepo:
+				title := strings.TrimPrefix(strings.TrimPrefix(verification.URL, "https://"), "http://")
+				skill.Evidence = append(skill.Evidence, SkillEvidence{Type: SkillVerificationRepo, Title: title, URL: verification.URL, Note: verification.Note})
+			}
+		}
+		for _, projectID := range skill.ProjectIDs {
+			addProject(projectID, "")
+		}
+		name := normalizeSkillName(skill.Name)
+		for _, certification := range certifications {
+			for _, certified := range certification.Skills {
+				if normalizeSkillName(certified) == name {
+					addCertification(certification.ID, "")
+					break
+				}
+			}
+		}
+	}
+}
+
+// AttachSkillEvidence resolves the evidence of skills in place
+func (ps *PortfolioService) AttachSkillEvidence(ctx context.Context, skills []Skill) error {
+	if len(skills) == 0 {
+		return nil
+	}
+	projects, err := ps.GetAllProjects(ctx, false, nil)
+	if err != nil {
+		return err
+	}
+	certifications, err := ps.GetAllCertifications(ctx, nil)
+	if err != nil {
+		return err
+	}
+	resolveSkillEvidence(skills, projects, certifications)
+	return nil
+}
+
+// Skill evidence endpoint: GET /api/skills/evidence?category=Languages lists skills with the projects,
+// certifications and repositories showing them
+func (h *APIHandler) handleSkillEvidence(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if r.Method != "GET" {
+		log.Printf("Date: %s | Route: /api/skills/evidence | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx := context.Background()
+	skills, err := h.service.GetSkills(ctx, r.URL.Query().Get("category"), h.hostAuthor(r))
+	if err == nil {
+		err = h.service.AttachSkillEvidence(ctx, skills)
+	}
+	if err != nil {
+		log.Printf("Date: %s | Route: /api/skills/evidence | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if skills == nil {
+		skills = []Skill{}
+	}
+
+	log.Printf("Date: %s | Route: /api/skills/evidence | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(skills)
+}
@@ -0,0 +1,391 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// skillProficiencyLevels maps proficiency names to a numeric level for sorting and display
+var skillProficiencyLevels = map[string]int{
+	"beginner":     1,
+	"intermediate": 2,
+	"advanced":     3,
+	"expert":       4,
+}
+
+// Skills imported from plain resume strings have no category until one is assigned
+const uncategorizedSkill = "Uncategorized"
+
+// Skill query methods
+func (ps *PortfolioService) GetSkills(ctx context.Context, category string, author *Author) ([]Skill, error) {
+	filter := hostScope(bson.M{}, "author_id", author)
+	if category != "" {
+		filter["category"] = bson.M{"$regex": "^" + regexp.QuoteMeta(category) + "$", "$options": "i"}
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "category", Value: 1}, {Key: "name", Value: 1}})
+	cursor, err := ps.skills.Find(ctx, filter, boundedFind(opts))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var skills []Skill
+	if err = cursor.All(ctx, &skills); err != nil {
+		return nil, err
+	}
+	return skills, nil
+}
+
+// UpsertSkill inserts a skill or replaces the author's existing skill with the same name
+func (ps *PortfolioService) UpsertSkill(ctx context.Context, skill *Skill) error {
+	filter := bson.M{"author_id": skill.AuthorID, "name": skill.Name}
+
+	var existing Skill
+	if err := ps.skills.FindOne(ctx, filter).Decode(&existing); err == nil {
+		skill.ID = existing.ID
+		skill.CreatedAt = existing.CreatedAt
+		skill.stampUpdated()
+	} else {
+		if skill.ID.IsZero() {
+			skill.ID = primitive.NewObjectID()
+		}
+		skill.stampCreated()
+	}
+
+	_, err := ps.skills.ReplaceOne(ctx, bson.M{"_id": skill.ID}, skill, options.Replace().SetUpsert(true))
+	return err
+}
+
+// validateSkill checks a skill submitted through the API
+func validateSkill(skill *Skill) error {
+	var v Validator
+	if v.Required("name", skill.Name) {
+		v.MaxLength("name", skill.Name, 100)
+	}
+	v.Required("category", skill.Category)
+	_, ok := skillProficiencyLevels[skill.Proficiency]
+	v.Check(ok, "proficiency", "one_of", "proficiency must be one of beginner, intermediate, advanced, expert")
+	v.Range("years", skill.Years, 0, 60)
+	validateSkillVerifications(&v, skill.Verifications)
+	return v.Err()
+}
+
+// SkillMatrixCategory groups skills under one category for the frontend skills matrix
+type SkillMatrixCategory struct {
+	Category string             `json:"category"`
+	Skills   []SkillMatrixEntry `json:"skills"`
+}
+
+type SkillMatrixEntry struct {
+	ID          primitive.ObjectID `json:"id"`
+	Name        string             `json:"name"`
+	Proficiency string             `json:"proficiency"`
+	Level       int                `json:"level"` // 1-4, 0 when not rated
+	Years       float64            `json:"years"`
+	Projects    []SkillProject     `json:"projects"`
+}
+
+// SkillProject is a project reference resolved from Skill.ProjectIDs
+type SkillProject struct {
+	ID   primitive.ObjectID `json:"id"`
+	Name string             `json:"name"`
+	Slug string             `json:"slug"`
+}
+
+// buildSkillsMatrix groups skills by category, strongest skills first, with related projects resolved
+func buildSkillsMatrix(skills []Skill, projects []Project) []SkillMatrixCategory {
+	projectsByID := make(map[primitive.ObjectID]*Project, len(projects))
+	for i := range projects {
+		projectsByID[projects[i].ID] = &projects[i]
+	}
+
+	matrix := []SkillMatrixCategory{}
+	categoryIndex := make(map[string]int)
+	for _, skill := range skills {
+		category := skill.Category
+		if category == "" {
+			category = uncategorizedSkill
+		}
+		index, ok := categoryIndex[category]
+		if !ok {
+			index = len(matrix)
+			categoryIndex[category] = index
+			matrix = append(matrix, SkillMatrixCategory{Category: category, Skills: []SkillMatrixEntry{}})
+		}
+
+		entry := SkillMatrixEntry{
+			ID:          skill.ID,
+			Name:        skill.Name,
+			Proficiency: skill.Proficiency,
+			Level:       skillProficiencyLevels[skill.Proficiency],
+			Years:       skill.Years,
+			Projects:    []SkillProject{},
+		}
+		for _, projectID := range skill.ProjectIDs {
+			project, ok := projectsByID[projectID]
+			if !ok {
+				continue
+			}
+			slug := project.Slug
+			if slug == "" {
+				slug = slugify(project.Name)
+			}
+			entry.Projects = append(entry.Projects, SkillProject{ID: project.ID, Name: project.Name, Slug: slug})
+		}
+		matrix[index].Skills = append(matrix[index].Skills, entry)
+	}
+
+	sort.Slice(matrix, func(i, j int) bool {
+		// Unrated imports sort last so curated categories lead
+		if (matrix[i].Category == uncategorizedSkill) != (matrix[j].Category == uncategorizedSkill) {
+			return matrix[j].Category == uncategorizedSkill
+		}
+		return matrix[i].Category < matrix[j].Category
+	})
+	for _, category := range matrix {
+		sort.SliceStable(category.Skills, func(i, j int) bool {
+			if category.Skills[i].Level != category.Skills[j].Level {
+				return category.Skills[i].Level > category.Skills[j].Level
+			}
+			return category.Skills[i].Years > category.Skills[j].Years
+		})
+	}
+	return matrix
+}
+
+// SkillsImport is the result of converting resume skill strings into skill documents
+type SkillsImport struct {
+	Skills []Skill `json:"skills"`
+	DryRun bool    `json:"dry_run"`
+}
+
+// mapResumeSkills creates skill documents for resume skills that don't exist yet, linking the
+// author's projects that list the skill among their technologies
+func mapResumeSkills(resumes []Resume, existing []Skill, projects []Project) *SkillsImport {
+	result := &SkillsImport{Skills: []Skill{}}
+
+	seen := make(map[string]bool)
+	for _, skill := range existing {
+		seen[skill.AuthorID.Hex()+"|"+strings.ToLower(skill.Name)] = true
+	}
+
+	for _, resume := range resumes {
+		for _, name := range resume.Skills {
+			name = strings.TrimSpace(name)
+			key := resume.AuthorID.Hex() + "|" + strings.ToLower(name)
+			if name == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			skill := Skill{
+				Name:       name,
+				Category:   uncategorizedSkill,
+				ProjectIDs: []primitive.ObjectID{},
+				AuthorID:   resume.AuthorID,
+			}
+			for _, project := range projects {
+				if project.AuthorID != resume.AuthorID {
+					continue
+				}
+				for _, technology := range project.TechnologiesUsed {
+					if strings.EqualFold(technology, name) {
+						skill.ProjectIDs = append(skill.ProjectIDs, project.ID)
+						break
+					}
+				}
+			}
+			result.Skills = append(result.Skills, skill)
+		}
+	}
+	return result
+}
+
+// ImportResumeSkills converts resume skill strings that have no skill document yet; a dry run only reports them
+func (ps *PortfolioService) ImportResumeSkills(ctx context.Context, dryRun bool) (*SkillsImport, error) {
+	resumes, err := ps.GetAllResumes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := ps.GetSkills(ctx, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	projects, err := ps.GetAllProjects(ctx, true, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := mapResumeSkills(resumes, existing, projects)
+	result.DryRun = dryRun
+	if dryRun {
+		return result, nil
+	}
+	for i := range result.Skills {
+		if err := ps.UpsertSkill(ctx, &result.Skills[i]); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// Skills endpoints: GET lists skills (?category= filters), POST creates or updates one by name (admin only)
+func (h *APIHandler) handleSkills(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	ctx := context.Background()
+
+	switch r.Method {
+	case "GET":
+		skills, err := h.service.GetSkills(ctx, r.URL.Query().Get("category"), h.hostAuthor(r))
+		if err != nil {
+			log.Printf("Date: %s | Route: /api/skills | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Date: %s | Route: /api/skills | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+		writeTimestampedList(w, r, skills)
+
+	case "POST":
+		if !isAdminRequest(r) {
+			log.Printf("Date: %s | Route: /api/skills | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		var skill Skill
+		if err := json.NewDecoder(r.Body).Decode(&skill); err != nil {
+			log.Printf("Date: %s | Route: /api/skills | Status: BAD_REQUEST | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, "Invalid JSON request", http.StatusBadRequest)
+			return
+		}
+		skill.Name = strings.TrimSpace(skill.Name)
+		skill.Category = strings.TrimSpace(skill.Category)
+		skill.Proficiency = strings.ToLower(strings.TrimSpace(skill.Proficiency))
+		if err := validateSkill(&skill); err != nil {
+			log.Printf("Date: %s | Route: /api/skills | Status: INVALID_INPUT | GPT Model: %s", currentTime, gptModel)
+			writeInvalidInput(w, err)
+			return
+		}
+		if skill.ProjectIDs == nil {
+			skill.ProjectIDs = []primitive.ObjectID{}
+		}
+
+		skill.ID = primitive.NilObjectID
+		if err := h.service.UpsertSkill(ctx, &skill); err != nil {
+			log.Printf("Date: %s | Route: /api/skills | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Date: %s | Route: /api/skills | Status: SAVED | GPT Model: %s", currentTime, gptModel)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(skill)
+
+	default:
+		log.Printf("Date: %s | Route: /api/skills | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// Skills matrix endpoint: skills grouped by category with related projects resolved
+func (h *APIHandler) handleSkillsMatrix(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if r.Method != "GET" {
+		log.Printf("Date: %s | Route: /api/skills/matrix | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx := context.Background()
+	skills, err := h.service.GetSkills(ctx, "", h.hostAuthor(r))
+	if err != nil {
+		log.Printf("Date: %s | Route: /api/skills/matrix | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	projects, err := h.service.GetAllProjects(ctx, false, nil)
+	if err != nil {
+		log.Printf("Date: %s | Route: /api/skills/matrix | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Date: %s | Route: /api/skills/matrix | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(buildSkillsMatrix(skills, projects))
+}
+
+// Skills import endpoint (admin only): converts the flat skill strings on resumes into skill documents
+func (h *APIHandler) handleSkillsImport(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if r.Method != "POST" {
+		log.Printf("Date: %s | Route: /api/admin/import/skills | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !isAdminRequest(r) {
+		log.Printf("Date: %s | Route: /api/admin/import/skills | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+
+	ctx := context.Background()
+	result, err := h.service.ImportResumeSkills(ctx, dryRun)
+	if err != nil {
+		log.Printf("Date: %s | Route: /api/admin/import/skills | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Date: %s | Route: /api/admin/import/skills | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+	w.Header().Set("Content-Type", "application/json")
+	if !dryRun {
+		w.WriteHeader(http.StatusCreated)
+	}
+	json.NewEncoder(w).Encode(result)
+}
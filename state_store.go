@@ -0,0 +1,368 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// RateLimit allows at most Max requests per Window
+type RateLimit struct {
+	Window time.Duration
+	Max    int
+}
+
+// StateStore holds the short-lived state that must be consistent across replicas: cache entries and rate
+// limit counters. Durable data lives in its own collections.
+type StateStore interface {
+	// Name identifies the backend in logs
+	Name() string
+	// Get returns a value and when it expires; expired values are returned until the next Purge
+	Get(ctx context.Context, key string) (value []byte, expiresAt time.Time, found bool, err error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+	// Allow records a request under key unless it would exceed one of the limits
+	Allow(ctx context.Context, key string, limits []RateLimit) (bool, error)
+	// Purge drops expired values and rate limit counters
+	Purge(ctx context.Context) error
+	// RateLimitUsage lists the requests counted per key; the memory store counts them over the given
+	// windows, the MongoDB store reports its current fixed windows
+	RateLimitUsage(ctx context.Context, windows []time.Duration) ([]RateLimitUsage, error)
+	// ResetRateLimits drops the counters of a client under every limiter
+	ResetRateLimits(ctx context.Context, client string) error
+}
+
+// RateLimitUsage is how many requests a client made under one limiter within a window
+type RateLimitUsage struct {
+	Limiter       string `json:"limiter"`
+	Client        string `json:"client"`
+	WindowSeconds int64  `json:"window_seconds"`
+	Count         int    `json:"count"`
+}
+
+// newStateStore builds the backend selected by STATE_STORE: "memory" (the default) keeps state per replica,
+// "mongo" shares it between replicas through the state collection
+func newStateStore(db *mongo.Database) StateStore {
+	switch backend := strings.ToLower(os.Getenv("STATE_STORE")); backend {
+	case "", "memory":
+		return newMemoryStateStore()
+	case "mongo", "mongodb":
+		log.Println("Keeping caches and rate limits in MongoDB")
+		return &mongoStateStore{collection: db.Collection("state")}
+	default:
+		log.Printf("Unknown STATE_STORE %q, keeping caches and rate limits in memory", backend)
+		return newMemoryStateStore()
+	}
+}
+
+// In-memory store, for a single replica
+type memoryStateStore struct {
+	mutex    sync.Mutex
+	entries  map[string]memoryStateEntry
+	requests map[string][]time.Time // Request times per rate limit key, newest last
+}
+
+type memoryStateEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+func newMemoryStateStore() *memoryStateStore {
+	return &memoryStateStore{
+		entries:  make(map[string]memoryStateEntry),
+		requests: make(map[string][]time.Time),
+	}
+}
+
+func (s *memoryStateStore) Name() string { return "memory" }
+
+func (s *memoryStateStore) Get(_ context.Context, key string) ([]byte, time.Time, bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	entry, exists := s.entries[key]
+	return entry.value, entry.expiresAt, exists, nil
+}
+
+func (s *memoryStateStore) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.entries[key] = memoryStateEntry{value: value, expiresAt: time.Now().Add(ttl)}
+	return nil
+}
+
+func (s *memoryStateStore) Delete(_ context.Context, key string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.entries, key)
+	return nil
+}
+
+// Allow keeps each client's request times, so limits apply over a sliding window
+func (s *memoryStateStore) Allow(_ context.Context, key string, limits []RateLimit) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	requests := pruneRequests(s.requests[key], now, longestWindow(limits))
+	for _, limit := range limits {
+		recent := 0
+		for _, at := range requests {
+			if at.After(now.Add(-limit.Window)) {
+				recent++
+			}
+		}
+		if recent >= limit.Max {
+			s.requests[key] = requests
+			return false, nil
+		}
+	}
+	s.requests[key] = append(requests, now)
+	return true, nil
+}
+
+func (s *memoryStateStore) Purge(_ context.Context) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	for key, entry := range s.entries {
+		if now.After(entry.expiresAt) {
+			delete(s.entries, key)
+		}
+	}
+	// No limiter has a window over an hour, so older request times never count
+	for key, requests := range s.requests {
+		if requests = pruneRequests(requests, now, time.Hour); len(requests) == 0 {
+			delete(s.requests, key)
+		} else {
+			s.requests[key] = requests
+		}
+	}
+	return nil
+}
+
+func (s *memoryStateStore) RateLimitUsage(_ context.Context, windows []time.Duration) ([]RateLimitUsage, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	var usage []RateLimitUsage
+	for key, requests := range s.requests {
+		limiter, client, _ := strings.Cut(key, ":")
+		for _, window := range windows {
+			count := 0
+			for _, at := range requests {
+				if at.After(now.Add(-window)) {
+					count++
+				}
+			}
+			if count > 0 {
+				usage = append(usage, RateLimitUsage{Limiter: limiter, Client: client, WindowSeconds: int64(window.Seconds()), Count: count})
+			}
+		}
+	}
+	return usage, nil
+}
+
+func (s *memoryStateStore) ResetRateLimits(_ context.Context, client string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for key := range s.requests {
+		if _, keyClient, _ := strings.Cut(key, ":"); keyClient == client {
+			delete(s.requests, key)
+		}
+	}
+	return nil
+}
+
+// requestLog copies the request times of the last hour, the longest window any limiter uses
+func (s *memoryStateStore) requestLog() map[string][]time.Time {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	saved := make(map[string][]time.Time, len(s.requests))
+	for key, requests := range s.requests {
+		if requests = pruneRequests(requests, now, time.Hour); len(requests) > 0 {
+			saved[key] = append([]time.Time(nil), requests...)
+		}
+	}
+	return saved
+}
+
+// restoreRequests merges saved request times into the current ones, keeping them in order
+func (s *memoryStateStore) restoreRequests(saved map[string][]time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	for key, requests := range saved {
+		merged := append(s.requests[key], requests...)
+		sort.Slice(merged, func(i, j int) bool { return merged[i].Before(merged[j]) })
+		// A request restored from another replica's snapshot is saved again in this replica's, so the same
+		// time can appear in several snapshots; it only counts once
+		merged = slices.CompactFunc(merged, func(a, b time.Time) bool { return a.Equal(b) })
+		if merged = pruneRequests(merged, now, time.Hour); len(merged) > 0 {
+			s.requests[key] = merged
+		}
+	}
+}
+
+// pruneRequests drops the request times older than window
+func pruneRequests(requests []time.Time, now time.Time, window time.Duration) []time.Time {
+	cutoff := now.Add(-window)
+	for len(requests) > 0 && !requests[0].After(cutoff) {
+		requests = requests[1:]
+	}
+	return requests
+}
+
+func longestWindow(limits []RateLimit) time.Duration {
+	var longest time.Duration
+	for _, limit := range limits {
+		if limit.Window > longest {
+			longest = limit.Window
+		}
+	}
+	return longest
+}
+
+// MongoDB store, shared by every replica
+type mongoStateStore struct {
+	collection *mongo.Collection
+}
+
+func (s *mongoStateStore) Name() string { return "mongo" }
+
+type stateDocument struct {
+	Key       string    `bson:"_id"`
+	Value     []byte    `bson:"value,omitempty"`
+	Count     int       `bson:"count,omitempty"`
+	ExpiresAt time.Time `bson:"expires_at"`
+}
+
+func (s *mongoStateStore) Get(ctx context.Context, key string) ([]byte, time.Time, bool, error) {
+	var document stateDocument
+	err := s.collection.FindOne(ctx, bson.M{"_id": "cache:" + key}).Decode(&document)
+	if err == mongo.ErrNoDocuments {
+		return nil, time.Time{}, false, nil
+	}
+	if err != nil {
+		return nil, time.Time{}, false, err
+	}
+	return document.Value, document.ExpiresAt, true, nil
+}
+
+func (s *mongoStateStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	_, err := s.collection.ReplaceOne(ctx, bson.M{"_id": "cache:" + key}, stateDocument{
+		Key:       "cache:" + key,
+		Value:     value,
+		ExpiresAt: time.Now().Add(ttl),
+	}, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (s *mongoStateStore) Delete(ctx context.Context, key string) error {
+	_, err := s.collection.DeleteOne(ctx, bson.M{"_id": "cache:" + key})
+	return err
+}
+
+// Allow counts requests in fixed windows aligned to the window length, so every replica increments the
+// same counter. A rejected request is taken back out of the counters it was added to.
+func (s *mongoStateStore) Allow(ctx context.Context, key string, limits []RateLimit) (bool, error) {
+	now := time.Now()
+	var counted []string
+	allowed := true
+	for _, limit := range limits {
+		start := now.Truncate(limit.Window)
+		id := fmt.Sprintf("limit:%s:%d@%d", key, int64(limit.Window.Seconds()), start.Unix())
+		var document stateDocument
+		err := s.collection.FindOneAndUpdate(ctx,
+			bson.M{"_id": id},
+			bson.M{
+				"$inc":         bson.M{"count": 1},
+				"$setOnInsert": bson.M{"expires_at": start.Add(limit.Window)},
+			},
+			options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+		).Decode(&document)
+		if err != nil {
+			s.release(ctx, counted)
+			return false, err
+		}
+		counted = append(counted, id)
+		if document.Count > limit.Max {
+			allowed = false
+			break
+		}
+	}
+	if !allowed {
+		s.release(ctx, counted)
+	}
+	return allowed, nil
+}
+
+// release takes a request back out of the given counters
+func (s *mongoStateStore) release(ctx context.Context, ids []string) {
+	if len(ids) == 0 {
+		return
+	}
+	if _, err := s.collection.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": ids}}, bson.M{"$inc": bson.M{"count": -1}}); err != nil {
+		log.Printf("Error releasing rate limit counters: %v", err)
+	}
+}
+
+func (s *mongoStateStore) Purge(ctx context.Context) error {
+	_, err := s.collection.DeleteMany(ctx, bson.M{"expires_at": bson.M{"$lt": time.Now()}})
+	return err
+}
+
+// rateLimitIDPattern parses counter IDs: "limit:" limiter ":" client ":" window seconds "@" window start.
+// Clients may contain colons (IPv6), so the limiter is the first field and the window the last.
+var rateLimitIDPattern = regexp.MustCompile(`^limit:([^:]+):(.+):(\d+)@\d+$`)
+
+func (s *mongoStateStore) RateLimitUsage(ctx context.Context, _ []time.Duration) ([]RateLimitUsage, error) {
+	cursor, err := s.collection.Find(ctx, bson.M{
+		"_id":        bson.M{"$regex": "^limit:"},
+		"expires_at": bson.M{"$gt": time.Now()},
+		"count":      bson.M{"$gt": 0},
+	}, boundedFind())
+	if err != nil {
+		return nil, err
+	}
+	var documents []stateDocument
+	if err := cursor.All(ctx, &documents); err != nil {
+		return nil, err
+	}
+
+	usage := make([]RateLimitUsage, 0, len(documents))
+	for _, document := range documents {
+		match := rateLimitIDPattern.FindStringSubmatch(document.Key)
+		if match == nil {
+			continue
+		}
+		window, _ := strconv.ParseInt(match[3], 10, 64)
+		usage = append(usage, RateLimitUsage{Limiter: match[1], Client: match[2], WindowSeconds: window, Count: document.Count})
+	}
+	return usage, nil
+}
+
+func (s *mongoStateStore) ResetRateLimits(ctx context.Context, client string) error {
+	_, err := s.collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$regex": "^limit:[^:]+:" + regexp.QuoteMeta(client) + `:\d+@\d+$`}})
+	return err
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+// spaHandler serves the built frontend, falling back to index.html for client-side routes
+type spaHandler struct {
+	files      fs.FS
+	fileServer http.Handler
+	index      []byte
+	loadedAt   time.Time
+}
+
+// NewSPAHandler creates a handler for a frontend build; it returns nil if the build has no index.html
+func NewSPAHandler(files fs.FS) *spaHandler {
+	index, err := fs.ReadFile(files, "index.html")
+	if err != nil {
+		return nil
+	}
+	return &spaHandler{
+		files:      files,
+		fileServer: http.FileServer(http.FS(files)),
+		index:      index,
+		loadedAt:   time.Now(),
+	}
+}
+
+// newSPAHandlerFromEnv serves the frontend build directory from FRONTEND_DIR (default frontend/build).
+// Binaries built with -tags embedfrontend serve their embedded copy unless FRONTEND_DIR overrides it.
+func newSPAHandlerFromEnv() *spaHandler {
+	dir := os.Getenv("FRONTEND_DIR")
+	if dir == "" {
+		if embedded := embeddedFrontendFS(); embedded != nil {
+			if handler := NewSPAHandler(embedded); handler != nil {
+				log.Println("Serving embedded frontend build")
+				return handler
+			}
+		}
+		dir = "frontend/build"
+	}
+
+	handler := NewSPAHandler(os.DirFS(dir))
+	if handler == nil {
+		log.Printf("No frontend build found in %s, static frontend serving disabled", dir)
+		return nil
+	}
+	log.Printf("Serving frontend from %s", dir)
+	return handler
+}
+
+func (h *spaHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" && r.Method != "HEAD" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Unknown API routes must 404 rather than return the app shell
+	if strings.HasPrefix(r.URL.Path, "/api/") {
+		http.NotFound(w, r)
+		return
+	}
+
+	name := strings.TrimPrefix(path.Clean(r.URL.Path), "/")
+	if name == "" || name == "index.html" {
+		h.serveIndex(w, r)
+		return
+	}
+
+	info, err := fs.Stat(h.files, name)
+	if err != nil || info.IsDir() {
+		// Paths that look like files are real 404s; everything else is a client-side route
+		if path.Ext(name) != "" {
+			http.NotFound(w, r)
+			return
+		}
+		h.serveIndex(w, r)
+		return
+	}
+
+	// The build puts content-hashed assets under static/, so they never change
+	if strings.HasPrefix(name, "static/") {
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	} else {
+		w.Header().Set("Cache-Control", "public, max-age=3600")
+	}
+	h.fileServer.ServeHTTP(w, r)
+}
+
+// serveIndex serves the app shell, which must always be revalidated so new deploys are picked up
+func (h *spaHandler) serveIndex(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	http.ServeContent(w, r, "index.html", h.loadedAt, bytes.NewReader(h.index))
+}
@@ -0,0 +1,23 @@
+//go:build embedfrontend
+
+package main
+
+import (
+	"embed"
+	"io/fs"
+)
+
+// The frontend build is compiled into the binary when built with -tags embedfrontend.
+// Run `yarn build` in frontend/ first so frontend/build exists.
+//
+//go:embed all:frontend/build
+var embeddedFrontend embed.FS
+
+// embeddedFrontendFS returns the embedded frontend build rooted at its index.html
+func embeddedFrontendFS() fs.FS {
+	files, err := fs.Sub(embeddedFrontend, "frontend/build")
+	if err != nil {
+		return nil
+	}
+	return files
+}
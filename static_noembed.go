@@ -0,0 +1,10 @@
+//go:build !embedfrontend
+
+package main
+
+import "io/fs"
+
+// embeddedFrontendFS returns nil when the binary is built without -tags embedfrontend
+func embeddedFrontendFS() fs.FS {
+	return nil
+}
@@ -0,0 +1,453 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// CodingStats summarizes weekly coding activity from WakaTime
+type CodingStats struct {
+	Range               string          `json:"range"`
+	TotalSeconds        float64         `json:"total_seconds"`
+	HumanReadableTotal  string          `json:"human_readable_total"`
+	DailyAverageSeconds float64         `json:"daily_average_seconds"`
+	Languages           []CodingSummary `json:"languages"`
+	Editors             []CodingSummary `json:"editors"`
+	FetchedAt           time.Time       `json:"fetched_at"`
+}
+
+type CodingSummary struct {
+	Name         string  `json:"name"`
+	TotalSeconds float64 `json:"total_seconds"`
+	Percent      float64 `json:"percent"`
+	Text         string  `json:"text"`
+}
+
+type wakatimeStatsResponse struct {
+	Data struct {
+		Range              string          `json:"range"`
+		TotalSeconds       float64         `json:"total_seconds"`
+		HumanReadableTotal string          `json:"human_readable_total"`
+		DailyAverage       float64         `json:"daily_average"`
+		Languages          []CodingSummary `json:"languages"`
+		Editors            []CodingSummary `json:"editors"`
+	} `json:"data"`
+}
+
+// WakaTimeService fetches coding stats server-side so the API key never reaches the browser
+type WakaTimeService struct {
+	httpClient *http.Client
+	apiKey     string
+	cache      *TTLCache[*CodingStats]
+	ttl        time.Duration
+}
+
+// NewWakaTimeService creates the service from environment configuration, or nil if disabled
+func NewWakaTimeService(store StateStore) *WakaTimeService {
+	apiKey := os.Getenv("WAKATIME_API_KEY")
+	if apiKey == "" {
+		log.Println("WAKATIME_API_KEY not set, coding stats endpoint disabled")
+		return nil
+	}
+
+	ttl := time.Hour
+	if value := os.Getenv("WAKATIME_CACHE_TTL"); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil && parsed > 0 {
+			ttl = parsed
+		}
+	}
+
+	return &WakaTimeService{
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		apiKey:     apiKey,
+		cache:      NewTTLCache[*CodingStats](store, "coding_stats"),
+		ttl:        ttl,
+	}
+}
+
+// fetchStats retrieves the last 7 days of stats from the WakaTime API
+func (s *WakaTimeService) fetchStats(ctx context.Context) (*CodingStats, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://wakatime.com/api/v1/users/current/stats/last_7_days", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(s.apiKey)))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// WakaTime returns 202 while stats are still being computed
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("WakaTime API returned status %d", resp.StatusCode)
+	}
+
+	var payload wakatimeStatsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, err
+	}
+
+	stats := &CodingStats{
+		Range:               payload.Data.Range,
+		TotalSeconds:        payload.Data.TotalSeconds,
+		HumanReadableTotal:  payload.Data.HumanReadableTotal,
+		DailyAverageSeconds: payload.Data.DailyAverage,
+		Languages:           payload.Data.Languages,
+		Editors:             payload.Data.Editors,
+		FetchedAt:           time.Now(),
+	}
+	if stats.Languages == nil {
+		stats.Languages = []CodingSummary{}
+	}
+	if stats.Editors == nil {
+		stats.Editors = []CodingSummary{}
+	}
+	return stats, nil
+}
+
+// GetStats returns cached stats, refreshing them from WakaTime when expired
+func (s *WakaTimeService) GetStats(ctx context.Context) (*CodingStats, error) {
+	if cached, ok := s.cache.Get("coding"); ok {
+		return cached, nil
+	}
+
+	stats, err := s.fetchStats(ctx)
+	if err != nil {
+		if stale, ok := s.cache.GetStale("coding"); ok {
+			log.Printf("WakaTime refresh failed, serving stale data: %v", err)
+			return stale, nil
+		}
+		return nil, err
+	}
+
+	s.cache.Set("coding", stats, s.ttl)
+	return stats, nil
+}
+
+// Coding stats endpoint
+func (h *APIHandler) handleCodingStats(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if r.Method != "GET" {
+		log.Printf("Date: %s | Route: /api/stats/coding | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	clientIP := trustedClientIP(r)
+	if !h.statsLimiter.IsAllowed(clientIP) {
+		log.Printf("Date: %s | Route: /api/stats/coding | Status: RATE_LIMITED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Rate limit exceeded. Please wait before making another request.", http.StatusTooManyRequests)
+		return
+	}
+
+	if h.codingStats == nil {
+		log.Printf("Date: %s | Route: /api/stats/coding | Status: DISABLED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Coding stats are not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	stats, err := h.codingStats.GetStats(ctx)
+	if err != nil {
+		log.Printf("Date: %s | Route: /api/stats/coding | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		log.Printf("Error fetching WakaTime stats: %v", err)
+		http.Error(w, "Failed to fetch coding stats", http.StatusBadGateway)
+		return
+	}
+
+	log.Printf("Date: %s | Route: /api/stats/coding | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stats)
+}
+
+// ProfileStats groups third-party profile stats; a nil entry means the profile is not configured or unavailable
+type ProfileStats struct {
+	StackOverflow *StackOverflowStats `json:"stackoverflow"`
+	LeetCode      *LeetCodeStats      `json:"leetcode"`
+	FetchedAt     time.Time           `json:"fetched_at"`
+}
+
+type StackOverflowStats struct {
+	DisplayName string                `json:"display_name"`
+	ProfileURL  string                `json:"profile_url"`
+	Reputation  int                   `json:"reputation"`
+	Badges      map[string]int        `json:"badges"`
+	TopTags     []StackOverflowTopTag `json:"top_tags"`
+}
+
+type StackOverflowTopTag struct {
+	Name          string `json:"name"`
+	AnswerCount   int    `json:"answer_count"`
+	AnswerScore   int    `json:"answer_score"`
+	QuestionCount int    `json:"question_count"`
+}
+
+type LeetCodeStats struct {
+	Username   string         `json:"username"`
+	ProfileURL string         `json:"profile_url"`
+	Ranking    int            `json:"ranking"`
+	Solved     map[string]int `json:"solved"`
+}
+
+// profileStatsRetryTTL is how long stats are cached after a profile failed to refresh, so the failure is
+// retried soon instead of blanking the profile for the whole cache TTL
+const profileStatsRetryTTL = 5 * time.Minute
+
+// ProfileStatsService fetches Stack Overflow and LeetCode stats with caching
+type ProfileStatsService struct {
+	httpClient        *http.Client
+	stackOverflowUser string
+	leetCodeUser      string
+	cache             *TTLCache[*ProfileStats]
+	ttl               time.Duration
+}
+
+// NewProfileStatsService creates the service from environment configuration, or nil if no profile is configured
+func NewProfileStatsService(store StateStore) *ProfileStatsService {
+	stackOverflowUser := os.Getenv("STACKOVERFLOW_USER_ID")
+	leetCodeUser := os.Getenv("LEETCODE_USERNAME")
+	if stackOverflowUser == "" && leetCodeUser == "" {
+		log.Println("STACKOVERFLOW_USER_ID and LEETCODE_USERNAME not set, profile stats endpoint disabled")
+		return nil
+	}
+
+	ttl := 6 * time.Hour
+	if value := os.Getenv("PROFILE_STATS_CACHE_TTL"); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil && parsed > 0 {
+			ttl = parsed
+		}
+	}
+
+	return &ProfileStatsService{
+		httpClient:        &http.Client{Timeout: 30 * time.Second},
+		stackOverflowUser: stackOverflowUser,
+		leetCodeUser:      leetCodeUser,
+		cache:             NewTTLCache[*ProfileStats](store, "profile_stats"),
+		ttl:               ttl,
+	}
+}
+
+// fetchStackOverflow retrieves reputation, badges and top tags from the Stack Exchange API
+func (s *ProfileStatsService) fetchStackOverflow(ctx context.Context) (*StackOverflowStats, error) {
+	var user struct {
+		Items []struct {
+			DisplayName string         `json:"display_name"`
+			Link        string         `json:"link"`
+			Reputation  int            `json:"reputation"`
+			BadgeCounts map[string]int `json:"badge_counts"`
+		} `json:"items"`
+	}
+	userURL := fmt.Sprintf("https://api.stackexchange.com/2.3/users/%s?site=stackoverflow", url.PathEscape(s.stackOverflowUser))
+	req, err := http.NewRequest("GET", userURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := getJSON(ctx, s.httpClient, req, &user); err != nil {
+		return nil, err
+	}
+	if len(user.Items) == 0 {
+		return nil, fmt.Errorf("Stack Overflow user %s not found", s.stackOverflowUser)
+	}
+
+	var tags struct {
+		Items []struct {
+			TagName       string `json:"tag_name"`
+			AnswerCount   int    `json:"answer_count"`
+			AnswerScore   int    `json:"answer_score"`
+			QuestionCount int    `json:"question_count"`
+		} `json:"items"`
+	}
+	tagsURL := fmt.Sprintf("https://api.stackexchange.com/2.3/users/%s/top-tags?site=stackoverflow&pagesize=5", url.PathEscape(s.stackOverflowUser))
+	req, err = http.NewRequest("GET", tagsURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := getJSON(ctx, s.httpClient, req, &tags); err != nil {
+		return nil, err
+	}
+
+	profile := user.Items[0]
+	stats := &StackOverflowStats{
+		DisplayName: profile.DisplayName,
+		ProfileURL:  profile.Link,
+		Reputation:  profile.Reputation,
+		Badges:      profile.BadgeCounts,
+		TopTags:     []StackOverflowTopTag{},
+	}
+	for _, tag := range tags.Items {
+		stats.TopTags = append(stats.TopTags, StackOverflowTopTag{
+			Name:          tag.TagName,
+			AnswerCount:   tag.AnswerCount,
+			AnswerScore:   tag.AnswerScore,
+			QuestionCount: tag.QuestionCount,
+		})
+	}
+	return stats, nil
+}
+
+const leetCodeStatsQuery = `query($username: String!) {
+  matchedUser(username: $username) {
+    profile { ranking }
+    submitStats { acSubmissionNum { difficulty count } }
+  }
+}`
+
+// fetchLeetCode retrieves solved problem counts from the LeetCode GraphQL API
+func (s *ProfileStatsService) fetchLeetCode(ctx context.Context) (*LeetCodeStats, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"query":     leetCodeStatsQuery,
+		"variables": map[string]string{"username": s.leetCodeUser},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", "https://leetcode.com/graphql", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Referer", "https://leetcode.com")
+
+	var payload struct {
+		Data struct {
+			MatchedUser *struct {
+				Profile struct {
+					Ranking int `json:"ranking"`
+				} `json:"profile"`
+				SubmitStats struct {
+					AcSubmissionNum []struct {
+						Difficulty string `json:"difficulty"`
+						Count      int    `json:"count"`
+					} `json:"acSubmissionNum"`
+				} `json:"submitStats"`
+			} `json:"matchedUser"`
+		} `json:"data"`
+	}
+	if err := getJSON(ctx, s.httpClient, req, &payload); err != nil {
+		return nil, err
+	}
+	if payload.Data.MatchedUser == nil {
+		return nil, fmt.Errorf("LeetCode user %s not found", s.leetCodeUser)
+	}
+
+	stats := &LeetCodeStats{
+		Username:   s.leetCodeUser,
+		ProfileURL: "https://leetcode.com/u/" + url.PathEscape(s.leetCodeUser),
+		Ranking:    payload.Data.MatchedUser.Profile.Ranking,
+		Solved:     make(map[string]int),
+	}
+	for _, entry := range payload.Data.MatchedUser.SubmitStats.AcSubmissionNum {
+		stats.Solved[strings.ToLower(entry.Difficulty)] = entry.Count
+	}
+	return stats, nil
+}
+
+// GetStats returns cached profile stats; each profile is refreshed independently
+func (s *ProfileStatsService) GetStats(ctx context.Context) *ProfileStats {
+	if cached, ok := s.cache.Get("profiles"); ok {
+		return cached
+	}
+
+	var previous *ProfileStats
+	if stale, ok := s.cache.GetStale("profiles"); ok {
+		previous = stale
+	}
+
+	stats := &ProfileStats{FetchedAt: time.Now()}
+	failed := false
+	if s.stackOverflowUser != "" {
+		stackOverflow, err := s.fetchStackOverflow(ctx)
+		if err != nil {
+			log.Printf("Error fetching Stack Overflow stats: %v", err)
+			failed = true
+			if previous != nil {
+				stackOverflow = previous.StackOverflow
+			}
+		}
+		stats.StackOverflow = stackOverflow
+	}
+	if s.leetCodeUser != "" {
+		leetCode, err := s.fetchLeetCode(ctx)
+		if err != nil {
+			log.Printf("Error fetching LeetCode stats: %v", err)
+			failed = true
+			if previous != nil {
+				leetCode = previous.LeetCode
+			}
+		}
+		stats.LeetCode = leetCode
+	}
+
+	ttl := s.ttl
+	if failed && ttl > profileStatsRetryTTL {
+		ttl = profileStatsRetryTTL
+	}
+	s.cache.Set("profiles", stats, ttl)
+	return stats
+}
+
+// Profile stats endpoint
+func (h *APIHandler) handleProfileStats(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if r.Method != "GET" {
+		log.Printf("Date: %s | Route: /api/stats/profiles | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	clientIP := trustedClientIP(r)
+	if !h.statsLimiter.IsAllowed(clientIP) {
+		log.Printf("Date: %s | Route: /api/stats/profiles | Status: RATE_LIMITED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Rate limit exceeded. Please wait before making another request.", http.StatusTooManyRequests)
+		return
+	}
+
+	if h.profileStats == nil {
+		log.Printf("Date: %s | Route: /api/stats/profiles | Status: DISABLED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Profile stats are not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	stats := h.profileStats.GetStats(ctx)
+
+	log.Printf("Date: %s | Route: /api/stats/profiles | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stats)
+}
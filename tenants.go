@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// chatPersona is who the chatbot speaks for
+type chatPersona struct {
+	BotName   string
+	Name      string
+	FirstName string
+	JobTitle  string
+	Pronouns  string // e.g. "she/her"; the prompt uses "they" when unset
+	Tone      string
+}
+
+// genericPersona is used when no author is stored yet
+var genericPersona = chatPersona{
+	BotName:   "PORTFOLIOBOT",
+	Name:      "the portfolio owner",
+	FirstName: "the portfolio owner",
+	JobTitle:  "professional",
+}
+
+// personaForAuthor derives the chatbot persona from an author, e.g. "Alex Smith" gets ALEXBOT unless the
+// author sets bot_name
+func personaForAuthor(author *Author) chatPersona {
+	if author == nil || strings.TrimSpace(author.Name) == "" {
+		return genericPersona
+	}
+
+	name := strings.TrimSpace(author.Name)
+	firstName := strings.Fields(name)[0]
+	jobTitle := author.JobTitle
+	if jobTitle == "" {
+		jobTitle = "professional"
+	}
+	botName := strings.TrimSpace(author.BotName)
+	if botName == "" {
+		botName = strings.ToUpper(firstName) + "BOT"
+	}
+	return chatPersona{
+		BotName:   botName,
+		Name:      name,
+		FirstName: firstName,
+		JobTitle:  jobTitle,
+		Pronouns:  strings.TrimSpace(author.Pronouns),
+		Tone:      strings.TrimSpace(author.ChatTone),
+	}
+}
+
+// personaFor returns the persona of an author, or for the unscoped chatbot routes that of the site's
+// primary author, loaded at request time so edits to the author apply to the next question
+func (l *LLMService) personaFor(ctx context.Context, author *Author) chatPersona {
+	if author != nil {
+		return personaForAuthor(author)
+	}
+	primary, err := l.portfolioService.GetPrimaryAuthor(ctx)
+	if err != nil && err != mongo.ErrNoDocuments {
+		log.Printf("Error loading primary author for chatbot persona: %v", err)
+	}
+	return personaForAuthor(primary)
+}
+
+// instructions are the prompt lines describing how to refer to the person and which tone to use
+func (p chatPersona) instructions() string {
+	var lines string
+	if p.Pronouns != "" {
+		lines += "\n\t\t- Refer to " + p.FirstName + " with the pronouns " + p.Pronouns
+	}
+	if p.Tone != "" {
+		lines += "\n\t\t- Tone: " + p.Tone
+	}
+	return lines
+}
+
+// GetPrimaryAuthor returns the author created first, whose portfolio the unscoped routes present
+func (ps *PortfolioService) GetPrimaryAuthor(ctx context.Context) (*Author, error) {
+	var author Author
+	opts := options.FindOne().SetSort(bson.D{{Key: "_id", Value: 1}})
+	if err := ps.authors.FindOne(ctx, bson.M{}, opts).Decode(&author); err != nil {
+		return nil, err
+	}
+	return &author, nil
+}
+
+// apply fills the persona placeholders of a prompt template. Percent signs are escaped because the
+// result is used as a format string.
+func (p chatPersona) apply(template string) string {
+	escape := func(value string) string { return strings.ReplaceAll(value, "%", "%%") }
+	return strings.NewReplacer(
+		"{{BOT}}", escape(p.BotName),
+		"{{NAME}}", escape(p.Name),
+		"{{FIRST_NAME}}", escape(p.FirstName),
+		"{{JOB_TITLE}}", escape(p.JobTitle),
+	).Replace(template)
+}
+
+// GetAuthorBySlugOrID finds an author by ObjectID or by their name or its slug (e.g. billie-mallady)
+func (ps *PortfolioService) GetAuthorBySlugOrID(ctx context.Context, key string) (*Author, error) {
+	id, slug, err := parseIdentifier(key)
+	if err != nil {
+		return nil, err
+	}
+	if !id.IsZero() {
+		author, err := ps.GetAuthorByID(ctx, id)
+		if err != mongo.ErrNoDocuments {
+			return author, err
+		}
+	}
+
+	// Authors have no stored slug and there are only a handful, so names are slugified here
+	cursor, err := ps.authors.Find(ctx, bson.M{}, boundedFind())
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var authors []Author
+	if err = cursor.All(ctx, &authors); err != nil {
+		return nil, err
+	}
+	for i := range authors {
+		if slugify(authors[i].Name) == slug {
+			return &authors[i], nil
+		}
+	}
+	return nil, mongo.ErrNoDocuments
+}
+
+// Author-scoped routes: /api/{author}/chatbot and /api/{author}/search, where {author} is an author ID or
+// name slug. They behave like /api/chatbot and /api/search with context limited to that author's portfolio.
+func (h *APIHandler) handleAuthorScopedRoutes(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/"), "/"), "/")
+	if len(parts) != 2 || (parts[1] != "chatbot" && parts[1] != "search") {
+		http.NotFound(w, r)
+		return
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	author, err := h.service.GetAuthorBySlugOrID(context.Background(), parts[0])
+	if err != nil {
+		if errors.Is(err, errInvalidIdentifier) {
+			writeInvalidInput(w, err)
+			return
+		}
+		if err == mongo.ErrNoDocuments {
+			http.NotFound(w, r)
+			return
+		}
+		log.Printf("Date: %s | Route: /api/{author}/%s | Status: ERROR | GPT Model: %s", currentTime, parts[1], gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	switch parts[1] {
+	case "chatbot":
+		h.serveChatbot(w, r, author)
+	case "search":
+		h.serveSearch(w, r, author)
+	}
+}
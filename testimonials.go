@@ -0,0 +1,257 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Testimonial query methods
+func (ps *PortfolioService) GetTestimonials(ctx context.Context, status string, author *Author) ([]Testimonial, error) {
+	filter := hostScope(bson.M{}, "author_id", author)
+	switch status {
+	case "approved":
+		filter["approved"] = true
+	case "pending":
+		filter["approved"] = false
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "submitted_at", Value: -1}})
+	cursor, err := ps.testimonials.Find(ctx, filter, boundedFind(opts))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var testimonials []Testimonial
+	if err = cursor.All(ctx, &testimonials); err != nil {
+		return nil, err
+	}
+	return testimonials, nil
+}
+
+func (ps *PortfolioService) InsertTestimonial(ctx context.Context, testimonial *Testimonial) error {
+	if testimonial.ID.IsZero() {
+		testimonial.ID = primitive.NewObjectID()
+	}
+	testimonial.stampCreated()
+	_, err := ps.testimonials.InsertOne(ctx, testimonial)
+	return err
+}
+
+// ApproveTestimonial publishes a pending testimonial
+func (ps *PortfolioService) ApproveTestimonial(ctx context.Context, id primitive.ObjectID) (*Testimonial, error) {
+	now := time.Now()
+	update := bson.M{"$set": bson.M{"approved": true, "approved_at": now, "updated_at": now.UTC()}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var testimonial Testimonial
+	if err := ps.testimonials.FindOneAndUpdate(ctx, bson.M{"_id": id}, update, opts).Decode(&testimonial); err != nil {
+		return nil, err
+	}
+	return &testimonial, nil
+}
+
+func (ps *PortfolioService) DeleteTestimonial(ctx context.Context, id primitive.ObjectID) error {
+	result, err := ps.testimonials.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	ps.recordDeletions(ctx, "testimonials", id)
+	return nil
+}
+
+// validateTestimonial checks a submitted testimonial
+func validateTestimonial(testimonial *Testimonial) error {
+	var v Validator
+	if v.Required("author", testimonial.Author) {
+		v.MaxLength("author", testimonial.Author, 100)
+	}
+	v.MaxLength("relationship", testimonial.Relationship, 200)
+	if v.Required("quote", testimonial.Quote) {
+		v.MaxLength("quote", testimonial.Quote, 2000)
+	}
+	v.HTTPURL("link", testimonial.Link)
+	return v.Err()
+}
+
+// Testimonials endpoints: GET lists approved testimonials (admins may pass ?status=pending|all),
+// POST submits one for moderation
+func (h *APIHandler) handleTestimonials(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	ctx := context.Background()
+
+	switch r.Method {
+	case "GET":
+		status := r.URL.Query().Get("status")
+		if status == "" {
+			status = "approved"
+		}
+		var v Validator
+		if !v.OneOf("status", status, "approved", "pending", "all") {
+			writeInvalidInput(w, v.Err())
+			return
+		}
+		if status != "approved" && !isAdminRequest(r) {
+			log.Printf("Date: %s | Route: /api/testimonials | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		testimonials, err := h.service.GetTestimonials(ctx, status, h.hostAuthor(r))
+		if err != nil {
+			log.Printf("Date: %s | Route: /api/testimonials | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Date: %s | Route: /api/testimonials | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+		writeTimestampedList(w, r, testimonials)
+
+	case "POST":
+		admin := isAdminRequest(r)
+		if !admin {
+			clientIP := trustedClientIP(r)
+			if !h.submitLimiter.IsAllowed(clientIP) {
+				log.Printf("Date: %s | Route: /api/testimonials | Status: RATE_LIMITED | GPT Model: %s", currentTime, gptModel)
+				http.Error(w, "Rate limit exceeded. Please wait before making another request.", http.StatusTooManyRequests)
+				return
+			}
+		}
+
+		var testimonial Testimonial
+		if err := json.NewDecoder(r.Body).Decode(&testimonial); err != nil {
+			log.Printf("Date: %s | Route: /api/testimonials | Status: BAD_REQUEST | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, "Invalid JSON request", http.StatusBadRequest)
+			return
+		}
+		if err := validateTestimonial(&testimonial); err != nil {
+			log.Printf("Date: %s | Route: /api/testimonials | Status: INVALID_INPUT | GPT Model: %s", currentTime, gptModel)
+			writeInvalidInput(w, err)
+			return
+		}
+
+		if testimonial.AuthorID != nil {
+			if _, err := h.service.GetAuthorByID(ctx, *testimonial.AuthorID); err != nil {
+				http.Error(w, "Author not found", http.StatusBadRequest)
+				return
+			}
+		}
+
+		// Public submissions always start pending; admins can publish directly
+		testimonial.ID = primitive.NilObjectID
+		testimonial.SubmittedAt = time.Now()
+		testimonial.ApprovedAt = nil
+		if !admin {
+			testimonial.Approved = false
+		} else if testimonial.Approved {
+			testimonial.ApprovedAt = &testimonial.SubmittedAt
+		}
+
+		if err := h.service.InsertTestimonial(ctx, &testimonial); err != nil {
+			log.Printf("Date: %s | Route: /api/testimonials | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Date: %s | Route: /api/testimonials | Status: CREATED | GPT Model: %s", currentTime, gptModel)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(testimonial)
+
+	default:
+		log.Printf("Date: %s | Route: /api/testimonials | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// Testimonial moderation: POST /api/testimonials/{id}/approve publishes, DELETE /api/testimonials/{id} rejects or removes
+func (h *APIHandler) handleTestimonialRoutes(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/testimonials/"), "/"), "/")
+	if len(parts) > 2 || (len(parts) == 2 && parts[1] != "approve") {
+		http.NotFound(w, r)
+		return
+	}
+
+	if !isAdminRequest(r) {
+		log.Printf("Date: %s | Route: /api/testimonials/{id} | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	testimonialID, err := primitive.ObjectIDFromHex(parts[0])
+	if err != nil {
+		http.Error(w, "Invalid testimonial ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx := context.Background()
+
+	switch {
+	case len(parts) == 2 && r.Method == "POST":
+		testimonial, err := h.service.ApproveTestimonial(ctx, testimonialID)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				http.Error(w, "Testimonial not found", http.StatusNotFound)
+				return
+			}
+			log.Printf("Date: %s | Route: /api/testimonials/{id}/approve | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Date: %s | Route: /api/testimonials/{id}/approve | Status: APPROVED | GPT Model: %s", currentTime, gptModel)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(testimonial)
+
+	case len(parts) == 1 && r.Method == "DELETE":
+		if err := h.service.DeleteTestimonial(ctx, testimonialID); err != nil {
+			if err == mongo.ErrNoDocuments {
+				http.Error(w, "Testimonial not found", http.StatusNotFound)
+				return
+			}
+			log.Printf("Date: %s | Route: /api/testimonials/{id} | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Date: %s | Route: /api/testimonials/{id} | Status: DELETED | GPT Model: %s", currentTime, gptModel)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		log.Printf("Date: %s | Route: /api/testimonials/{id} | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,280 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TimelineEntry is a dated item from any collection, normalized for a single chronological view
+type TimelineEntry struct {
+	Type     string     `json:"type"`
+	ID       string     `json:"id"`
+	Title    string     `json:"title"`
+	Subtitle string     `json:"subtitle,omitempty"`
+	Date     time.Time  `json:"date"`
+	EndDate  *time.Time `json:"end_date,omitempty"`
+	URL      string     `json:"url,omitempty"`
+}
+
+// timelineSource loads the timeline entries for one collection, scoped to author when not nil (see hostScope)
+type timelineSource func(ctx context.Context, ps *PortfolioService, author *Author) ([]TimelineEntry, error)
+
+// timelineSources lists every collection that contributes to the timeline, keyed by entry type
+var timelineSources = map[string]timelineSource{
+	"project": func(ctx context.Context, ps *PortfolioService, author *Author) ([]TimelineEntry, error) {
+		projects, err := ps.GetAllProjects(ctx, false, author)
+		if err != nil {
+			return nil, err
+		}
+		entries := make([]TimelineEntry, 0, len(projects))
+		for _, project := range projects {
+			slug := project.Slug
+			if slug == "" {
+				slug = slugify(project.Name)
+			}
+			entries = append(entries, TimelineEntry{
+				Type:     "project",
+				ID:       project.ID.Hex(),
+				Title:    project.Name,
+				Subtitle: project.Category,
+				Date:     project.StartDate,
+				EndDate:  project.EndDate,
+				URL:      "/projects/" + slug,
+			})
+		}
+		return entries, nil
+	},
+	"education": func(ctx context.Context, ps *PortfolioService, author *Author) ([]TimelineEntry, error) {
+		education, err := ps.GetAllEducation(ctx, author)
+		if err != nil {
+			return nil, err
+		}
+		entries := make([]TimelineEntry, 0, len(education))
+		for _, e := range education {
+			entries = append(entries, TimelineEntry{
+				Type:     "education",
+				ID:       e.ID.Hex(),
+				Title:    e.UniversityName,
+				Subtitle: e.Major,
+				Date:     e.StartDate,
+				EndDate:  e.EndDate,
+			})
+		}
+		return entries, nil
+	},
+	"experience": func(ctx context.Context, ps *PortfolioService, author *Author) ([]TimelineEntry, error) {
+		var authorID primitive.ObjectID
+		if author != nil {
+			authorID = author.ID
+		}
+		experience, err := ps.GetExperience(ctx, authorID, "", "")
+		if err != nil {
+			return nil, err
+		}
+		entries := make([]TimelineEntry, 0, len(experience))
+		for _, position := range experience {
+			// Positions imported without dates cannot be placed on the timeline
+			if position.StartDate == nil {
+				continue
+			}
+			entries = append(entries, TimelineEntry{
+				Type:     "experience",
+				ID:       position.ID.Hex(),
+				Title:    position.JobTitle,
+				Subtitle: position.Company,
+				Date:     *position.StartDate,
+				EndDate:  position.EndDate,
+			})
+		}
+		return entries, nil
+	},
+	"certification": func(ctx context.Context, ps *PortfolioService, author *Author) ([]TimelineEntry, error) {
+		certifications, err := ps.GetAllCertifications(ctx, author)
+		if err != nil {
+			return nil, err
+		}
+		entries := make([]TimelineEntry, 0, len(certifications))
+		for _, certification := range certifications {
+			entries = append(entries, TimelineEntry{
+				Type:     "certification",
+				ID:       certification.ID.Hex(),
+				Title:    certification.Name,
+				Subtitle: certification.Issuer,
+				Date:     certification.IssuedAt,
+				URL:      certification.VerificationURL,
+			})
+		}
+		return entries, nil
+	},
+	"award": func(ctx context.Context, ps *PortfolioService, author *Author) ([]TimelineEntry, error) {
+		awards, err := ps.GetAwards(ctx, "", author)
+		if err != nil {
+			return nil, err
+		}
+		entries := make([]TimelineEntry, 0, len(awards))
+		for _, award := range awards {
+			entries = append(entries, TimelineEntry{
+				Type:     "award",
+				ID:       award.ID.Hex(),
+				Title:    award.Title,
+				Subtitle: award.Issuer,
+				Date:     award.AwardedAt,
+				URL:      award.URL,
+			})
+		}
+		return entries, nil
+	},
+	"publication": func(ctx context.Context, ps *PortfolioService, author *Author) ([]TimelineEntry, error) {
+		publications, err := ps.GetAllPublications(ctx, author)
+		if err != nil {
+			return nil, err
+		}
+		entries := make([]TimelineEntry, 0, len(publications))
+		for i := range publications {
+			publication := &publications[i]
+			entries = append(entries, TimelineEntry{
+				Type:     "publication",
+				ID:       publication.ID.Hex(),
+				Title:    publication.Title,
+				Subtitle: publication.Venue,
+				Date:     time.Date(publication.Year, time.January, 1, 0, 0, 0, 0, time.UTC), // Only the year is known
+				URL:      publicationURL(publication),
+			})
+		}
+		return entries, nil
+	},
+	"talk": func(ctx context.Context, ps *PortfolioService, author *Author) ([]TimelineEntry, error) {
+		talks, err := ps.GetAllTalks(ctx, author)
+		if err != nil {
+			return nil, err
+		}
+		entries := make([]TimelineEntry, 0, len(talks))
+		for _, talk := range talks {
+			url := talk.VideoURL
+			if url == "" {
+				url = talk.SlidesURL
+			}
+			entries = append(entries, TimelineEntry{
+				Type:     "talk",
+				ID:       talk.ID.Hex(),
+				Title:    talk.Title,
+				Subtitle: talk.Event,
+				Date:     talk.Date,
+				URL:      url,
+			})
+		}
+		return entries, nil
+	},
+	"post": func(ctx context.Context, ps *PortfolioService, author *Author) ([]TimelineEntry, error) {
+		posts, err := ps.GetPosts(ctx, "", false, author)
+		if err != nil {
+			return nil, err
+		}
+		entries := make([]TimelineEntry, 0, len(posts))
+		for _, post := range posts {
+			entries = append(entries, TimelineEntry{
+				Type:     "post",
+				ID:       post.ID.Hex(),
+				Title:    post.Title,
+				Subtitle: post.Summary,
+				Date:     *post.PublishedAt,
+				URL:      "/api/posts/" + post.Slug,
+			})
+		}
+		return entries, nil
+	},
+}
+
+// GetTimeline returns entries of the given types (all types when empty), newest first; only the author's
+// and the shared entries when author is not nil
+func (ps *PortfolioService) GetTimeline(ctx context.Context, types []string, author *Author) ([]TimelineEntry, error) {
+	if len(types) == 0 {
+		for entryType := range timelineSources {
+			types = append(types, entryType)
+		}
+	}
+
+	entries := []TimelineEntry{}
+	for _, entryType := range types {
+		source, ok := timelineSources[entryType]
+		if !ok {
+			continue
+		}
+		sourceEntries, err := source(ctx, ps, author)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range sourceEntries {
+			if !entry.Date.IsZero() {
+				entries = append(entries, entry)
+			}
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Date.Equal(entries[j].Date) {
+			return entries[i].Type < entries[j].Type
+		}
+		return entries[i].Date.After(entries[j].Date)
+	})
+	return entries, nil
+}
+
+// Timeline endpoint: /api/timeline?type=project,award&limit=20
+func (h *APIHandler) handleTimeline(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if r.Method != "GET" {
+		log.Printf("Date: %s | Route: /api/timeline | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var v Validator
+	var types []string
+	if typeParam := r.URL.Query().Get("type"); typeParam != "" {
+		for _, entryType := range strings.Split(typeParam, ",") {
+			entryType = strings.TrimSpace(entryType)
+			_, ok := timelineSources[entryType]
+			v.Check(ok, "type", "one_of", "Unknown timeline type: "+entryType)
+			types = append(types, entryType)
+		}
+	}
+	limit := v.QueryInt(r, "limit", 0, 1, math.MaxInt32)
+	if err := v.Err(); err != nil {
+		writeInvalidInput(w, err)
+		return
+	}
+
+	ctx := context.Background()
+	entries, err := h.service.GetTimeline(ctx, types, h.hostAuthor(r))
+	if err != nil {
+		log.Printf("Date: %s | Route: /api/timeline | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+
+	log.Printf("Date: %s | Route: /api/timeline | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entries)
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// timelineCalendarTypes are the timeline types exported to calendars by default: the ones spanning a period
+var timelineCalendarTypes = []string{"education", "experience", "project"}
+
+// buildTimelineCalendar renders timeline entries as an iCalendar (RFC 5545) of all-day events. Periods
+// still running end today; entries without an end date among the other types are single-day events.
+func buildTimelineCalendar(baseURL string, entries []TimelineEntry) string {
+	host := baseURL
+	if parsed, err := url.Parse(baseURL); err == nil && parsed.Host != "" {
+		host = parsed.Host
+	}
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	var b strings.Builder
+	b.WriteString("BEGIN:VCALENDAR\r\n")
+	b.WriteString("VERSION:2.0\r\n")
+	b.WriteString(foldVCardLine("PRODID:-//" + host + "//Portfolio timeline//EN"))
+	b.WriteString("CALSCALE:GREGORIAN\r\n")
+	b.WriteString("X-WR-CALNAME:Career timeline\r\n")
+	for _, entry := range entries {
+		start := entry.Date.UTC()
+		start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+		end := start
+		ongoing := false
+		if entry.EndDate != nil {
+			end = entry.EndDate.UTC()
+		} else if slices.Contains(timelineCalendarTypes, entry.Type) {
+			end, ongoing = today, true
+		}
+		end = time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, time.UTC)
+		if end.Before(start) {
+			end = start
+		}
+
+		b.WriteString("BEGIN:VEVENT\r\n")
+		b.WriteString(foldVCardLine("UID:" + entry.Type + "-" + entry.ID + "@" + host))
+		b.WriteString("DTSTAMP:" + now.Format("20060102T150405Z") + "\r\n")
+		b.WriteString("DTSTART;VALUE=DATE:" + start.Format("20060102") + "\r\n")
+		// All-day events end on the day after their last day
+		b.WriteString("DTEND;VALUE=DATE:" + end.AddDate(0, 0, 1).Format("20060102") + "\r\n")
+		summary := entry.Title
+		if entry.Subtitle != "" {
+			summary += " - " + entry.Subtitle
+		}
+		b.WriteString(foldVCardLine("SUMMARY:" + vcardEscaper.Replace(summary)))
+		description := entry.Type
+		if ongoing {
+			description = "ongoing " + entry.Type
+		}
+		b.WriteString(foldVCardLine("DESCRIPTION:" + vcardEscaper.Replace(description)))
+		b.WriteString(foldVCardLine("CATEGORIES:" + vcardEscaper.Replace(entry.Type)))
+		if entry.URL != "" {
+			link := entry.URL
+			if strings.HasPrefix(link, "/") {
+				link = baseURL + link
+			}
+			b.WriteString(foldVCardLine("URL:" + link))
+		}
+		b.WriteString("TRANSP:TRANSPARENT\r\n") // Past roles should not show as busy time
+		b.WriteString("END:VEVENT\r\n")
+	}
+	b.WriteString("END:VCALENDAR\r\n")
+	return b.String()
+}
+
+// Timeline calendar endpoint: /api/timeline.ics?type=education,experience renders the education periods,
+// positions and projects (or the given timeline types) as calendar events for import
+func (h *APIHandler) handleTimelineCalendar(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if r.Method != "GET" {
+		log.Printf("Date: %s | Route: /api/timeline.ics | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var v Validator
+	types := timelineCalendarTypes
+	if typeParam := r.URL.Query().Get("type"); typeParam != "" {
+		types = nil
+		for _, entryType := range strings.Split(typeParam, ",") {
+			entryType = strings.TrimSpace(entryType)
+			_, ok := timelineSources[entryType]
+			v.Check(ok, "type", "one_of", "Unknown timeline type: "+entryType)
+			types = append(types, entryType)
+		}
+	}
+	if err := v.Err(); err != nil {
+		writeInvalidInput(w, err)
+		return
+	}
+
+	entries, err := h.service.GetTimeline(context.Background(), types, h.hostAuthor(r))
+	if err != nil {
+		log.Printf("Date: %s | Route: /api/timeline.ics | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Date: %s | Route: /api/timeline.ics | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="timeline.ics"`)
+	w.Write([]byte(buildTimelineCalendar(publicBaseURL(r), entries)))
+}
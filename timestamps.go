@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Timestamps are the audit fields embedded in every portfolio document. The service layer sets them on
+// writes; documents written before they were introduced have neither.
+type Timestamps struct {
+	CreatedAt *time.Time `bson:"created_at,omitempty" json:"created_at,omitempty"`
+	UpdatedAt *time.Time `bson:"updated_at,omitempty" json:"updated_at,omitempty"`
+}
+
+// stampCreated sets both timestamps of a new document, ignoring any sent by the client
+func (t *Timestamps) stampCreated() {
+	now := time.Now().UTC()
+	t.CreatedAt = &now
+	t.UpdatedAt = &now
+}
+
+// stampUpdated moves updated_at to now; created_at must already be carried over from the stored document
+func (t *Timestamps) stampUpdated() {
+	now := time.Now().UTC()
+	t.UpdatedAt = &now
+}
+
+// modifiedAt returns when the document last changed, or the zero time if unknown
+func (t Timestamps) modifiedAt() time.Time {
+	if t.UpdatedAt != nil {
+		return *t.UpdatedAt
+	}
+	if t.CreatedAt != nil {
+		return *t.CreatedAt
+	}
+	return time.Time{}
+}
+
+// withUpdatedAt adds updated_at to the $set of an update document
+func withUpdatedAt(update bson.M) bson.M {
+	set, ok := update["$set"].(bson.M)
+	if !ok {
+		set = bson.M{}
+		update["$set"] = set
+	}
+	set["updated_at"] = time.Now().UTC()
+	return update
+}
+
+// timestamped is implemented by every model embedding Timestamps
+type timestamped interface {
+	modifiedAt() time.Time
+}
+
+// latestModification returns the most recent modification time in a list
+func latestModification[T timestamped](items []T) time.Time {
+	var latest time.Time
+	for _, item := range items {
+		if modified := item.modifiedAt(); modified.After(latest) {
+			latest = modified
+		}
+	}
+	return latest
+}
+
+// writeTimestampedList encodes a list response. ?sort=recent orders it by most recently updated first.
+// Last-Modified is the latest update in the list, and a request whose If-Modified-Since is not older gets
+// 304 Not Modified. Deletions do not move Last-Modified, so clients should not cache indefinitely.
+func writeTimestampedList[T timestamped](w http.ResponseWriter, r *http.Request, items []T) {
+	if r.URL.Query().Get("sort") == "recent" {
+		sort.SliceStable(items, func(i, j int) bool {
+			return items[i].modifiedAt().After(items[j].modifiedAt())
+		})
+	}
+
+	if latest := latestModification(items); !latest.IsZero() {
+		w.Header().Set("Last-Modified", latest.UTC().Format(http.TimeFormat))
+		if since, err := http.ParseTime(r.Header.Get("If-Modified-Since")); err == nil && !latest.Truncate(time.Second).After(since) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(items)
+}
@@ -0,0 +1,531 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Limits on the job descriptions accepted by the LLM tools
+const (
+	maxJobDescriptionLength = 8000
+	toolsContextLength      = 12000
+)
+
+// portfolioContext serializes the whole portfolio for tools that reason over all of it, summarizing what
+// does not fit in maxLength
+func (l *LLMService) portfolioContext(ctx context.Context, maxLength int) (string, error) {
+	results, _, err := l.portfolioService.SearchAll(ctx, "")
+	if err != nil {
+		return "", err
+	}
+	return l.buildContext(ctx, "", results, maxLength)
+}
+
+// validateJobDescription checks the job description submitted to a tool
+func validateJobDescription(description string) error {
+	var v Validator
+	if v.Required("job_description", description) {
+		v.MaxLength("job_description", description, maxJobDescriptionLength)
+	}
+	return v.Err()
+}
+
+// GenerateCoverLetter writes a cover letter for the job using only facts from the portfolio
+func (l *LLMService) GenerateCoverLetter(ctx context.Context, jobDescription, company, role string) (string, error) {
+	portfolio, err := l.portfolioContext(ctx, toolsContextLength)
+	if err != nil {
+		return "", fmt.Errorf("failed to load portfolio data: %w", err)
+	}
+
+	prompt := fmt.Sprintf(`Write a cover letter for the person described in the portfolio data below, applying for the job described after it.
+
+PORTFOLIO DATA:
+%s
+
+COMPANY: %s
+ROLE: %s
+JOB DESCRIPTION:
+%s
+
+Instructions:
+- Ground every claim in the portfolio data. Do not invent employers, projects, skills, dates or results.
+- Pick the two or three projects or positions most relevant to the job and explain why they are relevant.
+- Where the job asks for something the portfolio does not show, do not claim it; focus on related strengths instead.
+- Keep it under 400 words, in a confident and professional tone, addressed to the hiring team.
+- Sign it with the person's name from the portfolio data.
+- Return only the letter text.
+`, portfolio, company, role, jobDescription)
+
+	return l.complete(ctx, prompt, false)
+}
+
+// Cover letter endpoint (admin only, rate limited): POST /api/tools/cover-letter
+// with {"job_description": "...", "company": "...", "role": "..."}
+func (h *APIHandler) handleCoverLetter(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if r.Method != "POST" {
+		log.Printf("Date: %s | Route: /api/tools/cover-letter | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !isAdminRequest(r) {
+		log.Printf("Date: %s | Route: /api/tools/cover-letter | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	clientIP := trustedClientIP(r)
+	if !h.toolsLimiter.IsAllowed(clientIP) {
+		log.Printf("Date: %s | Route: /api/tools/cover-letter | Status: RATE_LIMITED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Rate limit exceeded. Please wait before making another request.", http.StatusTooManyRequests)
+		return
+	}
+
+	var request struct {
+		JobDescription string `json:"job_description"`
+		Company        string `json:"company"`
+		Role           string `json:"role"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 64<<10)).Decode(&request); err != nil {
+		log.Printf("Date: %s | Route: /api/tools/cover-letter | Status: BAD_REQUEST | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Invalid JSON request", http.StatusBadRequest)
+		return
+	}
+	if err := validateJobDescription(request.JobDescription); err != nil {
+		log.Printf("Date: %s | Route: /api/tools/cover-letter | Status: INVALID_INPUT | GPT Model: %s", currentTime, gptModel)
+		writeInvalidInput(w, err)
+		return
+	}
+
+	if h.llmService == nil {
+		log.Printf("Date: %s | Route: /api/tools/cover-letter | Status: LLM_DISABLED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Cover letters require OPENAI_API_KEY", http.StatusServiceUnavailable)
+		return
+	}
+
+	ctx := context.Background()
+	letter, err := h.llmService.GenerateCoverLetter(ctx, request.JobDescription, strings.TrimSpace(request.Company), strings.TrimSpace(request.Role))
+	if errors.Is(err, errLLMBusy) {
+		log.Printf("Date: %s | Route: /api/tools/cover-letter | Status: LLM_BUSY | GPT Model: %s", currentTime, gptModel)
+		writeLLMBusy(w)
+		return
+	}
+	if err != nil {
+		log.Printf("Date: %s | Route: /api/tools/cover-letter | Status: LLM_ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, fmt.Sprintf("Cover letter error: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Date: %s | Route: /api/tools/cover-letter | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"cover_letter": letter,
+	})
+}
+
+// JobRequirement is a requirement the LLM extracted from a job description
+type JobRequirement struct {
+	Requirement string   `json:"requirement"`
+	Importance  string   `json:"importance"` // "required" or "preferred"
+	Skills      []string `json:"skills"`
+}
+
+// MatchReport compares a job description against the stored skills and projects
+type MatchReport struct {
+	Score            int                `json:"score"` // Percentage of requirements covered, required ones counting double
+	Covered          []MatchRequirement `json:"covered"`
+	Gaps             []MatchRequirement `json:"gaps"`
+	RelevantProjects []MatchProject     `json:"relevant_projects"`
+}
+
+type MatchRequirement struct {
+	JobRequirement
+	Evidence []MatchEvidence `json:"evidence"`
+}
+
+// MatchEvidence is a stored skill or project technology satisfying a requirement
+type MatchEvidence struct {
+	Skill       string  `json:"skill"`
+	Proficiency string  `json:"proficiency,omitempty"` // Empty when only a project lists the technology
+	Years       float64 `json:"years,omitempty"`
+}
+
+type MatchProject struct {
+	SkillProject
+	MatchedSkills []string `json:"matched_skills"`
+}
+
+// Relevant projects returned in a match report
+const maxMatchProjects = 5
+
+// ExtractJobRequirements asks the model to list the requirements of a job description
+func (l *LLMService) ExtractJobRequirements(ctx context.Context, jobDescription string) ([]JobRequirement, error) {
+	prompt := fmt.Sprintf(`List the requirements of the job description below as a JSON object:
+
+{"requirements": [{"requirement": "short description", "importance": "required or preferred", "skills": ["specific technologies, languages, tools or practices"]}]}
+
+Instructions:
+- Include at most 25 requirements, most important first.
+- Use "preferred" for anything described as a plus, nice to have or bonus.
+- Name skills the way they are usually written (e.g. "Go", "PostgreSQL", "Kubernetes"), one per entry.
+- Respond with the JSON object only.
+
+JOB DESCRIPTION:
+%s`, jobDescription)
+
+	response, err := l.complete(ctx, prompt, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed struct {
+		Requirements []JobRequirement `json:"requirements"`
+	}
+	if err := json.Unmarshal([]byte(response), &parsed); err != nil {
+		return nil, fmt.Errorf("model returned invalid JSON: %w", err)
+	}
+	if len(parsed.Requirements) > 25 {
+		parsed.Requirements = parsed.Requirements[:25]
+	}
+	return parsed.Requirements, nil
+}
+
+// normalizeSkillName lets "Golang"/"go" or "Node.js"/"NodeJS" compare equal
+func normalizeSkillName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = strings.NewReplacer(".", "", "-", "", " ", "", "_", "").Replace(name)
+	if name == "golang" {
+		return "go"
+	}
+	return name
+}
+
+// buildMatchReport checks each requirement against the skills matrix and the projects' technologies
+func buildMatchReport(requirements []JobRequirement, skills []Skill, projects []Project) *MatchReport {
+	report := &MatchReport{
+		Covered:          []MatchRequirement{},
+		Gaps:             []MatchRequirement{},
+		RelevantProjects: []MatchProject{},
+	}
+
+	skillsByName := make(map[string]Skill, len(skills))
+	for _, skill := range skills {
+		skillsByName[normalizeSkillName(skill.Name)] = skill
+	}
+	technologies := make(map[string]string)
+	for _, project := range projects {
+		for _, technology := range project.TechnologiesUsed {
+			technologies[normalizeSkillName(technology)] = technology
+		}
+	}
+
+	matchedSkills := make(map[string]bool)
+	weight, coveredWeight := 0, 0
+	for _, requirement := range requirements {
+		match := MatchRequirement{JobRequirement: requirement, Evidence: []MatchEvidence{}}
+		if match.Skills == nil {
+			match.Skills = []string{}
+		}
+		for _, name := range requirement.Skills {
+			key := normalizeSkillName(name)
+			if skill, ok := skillsByName[key]; ok {
+				match.Evidence = append(match.Evidence, MatchEvidence{Skill: skill.Name, Proficiency: skill.Proficiency, Years: skill.Years})
+				matchedSkills[key] = true
+			} else if technology, ok := technologies[key]; ok {
+				match.Evidence = append(match.Evidence, MatchEvidence{Skill: technology})
+				matchedSkills[key] = true
+			}
+		}
+
+		requirementWeight := 1
+		if requirement.Importance != "preferred" {
+			requirementWeight = 2
+		}
+		weight += requirementWeight
+		if len(match.Evidence) > 0 {
+			coveredWeight += requirementWeight
+			report.Covered = append(report.Covered, match)
+		} else {
+			report.Gaps = append(report.Gaps, match)
+		}
+	}
+	if weight > 0 {
+		report.Score = coveredWeight * 100 / weight
+	}
+
+	// Projects are relevant when they use a matched skill, either directly or through a skill's project links
+	linkedSkills := make(map[string][]string)
+	for _, skill := range skills {
+		if !matchedSkills[normalizeSkillName(skill.Name)] {
+			continue
+		}
+		for _, projectID := range skill.ProjectIDs {
+			linkedSkills[projectID.Hex()] = append(linkedSkills[projectID.Hex()], skill.Name)
+		}
+	}
+	for _, project := range projects {
+		seen := make(map[string]bool)
+		var matched []string
+		candidates := append(append([]string{}, project.TechnologiesUsed...), linkedSkills[project.ID.Hex()]...)
+		for _, name := range candidates {
+			key := normalizeSkillName(name)
+			if matchedSkills[key] && !seen[key] {
+				seen[key] = true
+				matched = append(matched, name)
+			}
+		}
+		if len(matched) == 0 {
+			continue
+		}
+		slug := project.Slug
+		if slug == "" {
+			slug = slugify(project.Name)
+		}
+		report.RelevantProjects = append(report.RelevantProjects, MatchProject{
+			SkillProject:  SkillProject{ID: project.ID, Name: project.Name, Slug: slug},
+			MatchedSkills: matched,
+		})
+	}
+	sort.SliceStable(report.RelevantProjects, func(i, j int) bool {
+		return len(report.RelevantProjects[i].MatchedSkills) > len(report.RelevantProjects[j].MatchedSkills)
+	})
+	if len(report.RelevantProjects) > maxMatchProjects {
+		report.RelevantProjects = report.RelevantProjects[:maxMatchProjects]
+	}
+	return report
+}
+
+// Job match endpoint (rate limited): POST /api/tools/match with {"job_description": "..."}
+func (h *APIHandler) handleJobMatch(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if r.Method != "POST" {
+		log.Printf("Date: %s | Route: /api/tools/match | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	clientIP := trustedClientIP(r)
+	if !h.toolsLimiter.IsAllowed(clientIP) {
+		log.Printf("Date: %s | Route: /api/tools/match | Status: RATE_LIMITED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Rate limit exceeded. Please wait before making another request.", http.StatusTooManyRequests)
+		return
+	}
+
+	var request struct {
+		JobDescription string `json:"job_description"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 64<<10)).Decode(&request); err != nil {
+		log.Printf("Date: %s | Route: /api/tools/match | Status: BAD_REQUEST | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Invalid JSON request", http.StatusBadRequest)
+		return
+	}
+	if err := validateJobDescription(request.JobDescription); err != nil {
+		log.Printf("Date: %s | Route: /api/tools/match | Status: INVALID_INPUT | GPT Model: %s", currentTime, gptModel)
+		writeInvalidInput(w, err)
+		return
+	}
+
+	if h.llmService == nil {
+		log.Printf("Date: %s | Route: /api/tools/match | Status: LLM_DISABLED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Job matching requires OPENAI_API_KEY", http.StatusServiceUnavailable)
+		return
+	}
+
+	ctx := context.Background()
+	requirements, err := h.llmService.ExtractJobRequirements(ctx, request.JobDescription)
+	if errors.Is(err, errLLMBusy) {
+		log.Printf("Date: %s | Route: /api/tools/match | Status: LLM_BUSY | GPT Model: %s", currentTime, gptModel)
+		writeLLMBusy(w)
+		return
+	}
+	if err != nil {
+		log.Printf("Date: %s | Route: /api/tools/match | Status: LLM_ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, fmt.Sprintf("Job match error: %v", err), http.StatusInternalServerError)
+		return
+	}
+	skills, err := h.service.GetSkills(ctx, "", nil)
+	if err != nil {
+		log.Printf("Date: %s | Route: /api/tools/match | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	projects, err := h.service.GetAllProjects(ctx, false, nil)
+	if err != nil {
+		log.Printf("Date: %s | Route: /api/tools/match | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Date: %s | Route: /api/tools/match | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(buildMatchReport(requirements, skills, projects))
+}
+
+// InterviewQuestion is a likely interview question with an answer drawn from the portfolio
+type InterviewQuestion struct {
+	Question        string   `json:"question"`
+	Category        string   `json:"category"` // e.g. "technical", "behavioral", "project deep-dive"
+	SuggestedAnswer string   `json:"suggested_answer"`
+	Projects        []string `json:"projects"` // Names of the projects the answer draws on
+}
+
+// GenerateInterviewQuestions asks the model for questions an interviewer for the role would likely ask,
+// with answers referencing the portfolio's actual projects
+func (l *LLMService) GenerateInterviewQuestions(ctx context.Context, role string, count int) ([]InterviewQuestion, error) {
+	portfolio, err := l.portfolioContext(ctx, toolsContextLength)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load portfolio data: %w", err)
+	}
+
+	prompt := fmt.Sprintf(`Generate %d interview questions that an interviewer hiring for the role below would likely ask the person described in the portfolio data, with a suggested answer for each.
+
+ROLE: %s
+
+PORTFOLIO DATA:
+%s
+
+Respond with a JSON object:
+{"questions": [{"question": "", "category": "technical, behavioral or project deep-dive", "suggested_answer": "", "projects": ["names of projects the answer refers to"]}]}
+
+Instructions:
+- Mix technical, behavioral and project deep-dive questions relevant to the role.
+- Suggested answers must be in the first person and reference the person's actual projects, skills and experience from the portfolio data. Do not invent anything.
+- Where the portfolio has nothing relevant, say how the person could honestly address the gap.
+- Keep each answer under 120 words.
+- Respond with the JSON object only.
+`, count, role, portfolio)
+
+	response, err := l.complete(ctx, prompt, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed struct {
+		Questions []InterviewQuestion `json:"questions"`
+	}
+	if err := json.Unmarshal([]byte(response), &parsed); err != nil {
+		return nil, fmt.Errorf("model returned invalid JSON: %w", err)
+	}
+	for i := range parsed.Questions {
+		if parsed.Questions[i].Projects == nil {
+			parsed.Questions[i].Projects = []string{}
+		}
+	}
+	if len(parsed.Questions) > count {
+		parsed.Questions = parsed.Questions[:count]
+	}
+	return parsed.Questions, nil
+}
+
+// Interview questions endpoint (rate limited): POST /api/tools/interview-questions with {"role": "...", "count": 5}.
+// Results are cached per role so repeat visitors don't each pay for a generation.
+func (h *APIHandler) handleInterviewQuestions(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if r.Method != "POST" {
+		log.Printf("Date: %s | Route: /api/tools/interview-questions | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var request struct {
+		Role  string `json:"role"`
+		Count int    `json:"count"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 16<<10)).Decode(&request); err != nil {
+		log.Printf("Date: %s | Route: /api/tools/interview-questions | Status: BAD_REQUEST | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Invalid JSON request", http.StatusBadRequest)
+		return
+	}
+	role := strings.TrimSpace(request.Role)
+	var v Validator
+	v.ChatInput("role", role, 200)
+	if err := v.Err(); err != nil {
+		log.Printf("Date: %s | Route: /api/tools/interview-questions | Status: INVALID_INPUT | GPT Model: %s", currentTime, gptModel)
+		writeInvalidInput(w, err)
+		return
+	}
+	count := request.Count
+	if count <= 0 {
+		count = 5
+	}
+	if count > 10 {
+		count = 10
+	}
+
+	cacheKey := fmt.Sprintf("interview|%s|%d", strings.ToLower(role), count)
+	if cached, ok := h.toolsCache.Get(cacheKey); ok {
+		log.Printf("Date: %s | Route: /api/tools/interview-questions | Status: CACHED | GPT Model: %s", currentTime, gptModel)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"role": role, "questions": cached})
+		return
+	}
+
+	clientIP := trustedClientIP(r)
+	if !h.toolsLimiter.IsAllowed(clientIP) {
+		log.Printf("Date: %s | Route: /api/tools/interview-questions | Status: RATE_LIMITED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Rate limit exceeded. Please wait before making another request.", http.StatusTooManyRequests)
+		return
+	}
+
+	if h.llmService == nil {
+		log.Printf("Date: %s | Route: /api/tools/interview-questions | Status: LLM_DISABLED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Interview questions require OPENAI_API_KEY", http.StatusServiceUnavailable)
+		return
+	}
+
+	ctx := context.Background()
+	questions, err := h.llmService.GenerateInterviewQuestions(ctx, role, count)
+	if errors.Is(err, errLLMBusy) {
+		log.Printf("Date: %s | Route: /api/tools/interview-questions | Status: LLM_BUSY | GPT Model: %s", currentTime, gptModel)
+		writeLLMBusy(w)
+		return
+	}
+	if err != nil {
+		log.Printf("Date: %s | Route: /api/tools/interview-questions | Status: LLM_ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, fmt.Sprintf("Interview questions error: %v", err), http.StatusInternalServerError)
+		return
+	}
+	h.toolsCache.Set(cacheKey, questions, 6*time.Hour)
+
+	log.Printf("Date: %s | Route: /api/tools/interview-questions | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"role": role, "questions": questions})
+}
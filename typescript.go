@@ -0,0 +1,316 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// tsModels are the Go types exported to the TypeScript client, in output order. Struct types they reference
+// are exported too, after them.
+var tsModels = []interface{}{
+	Author{}, Project{}, Education{}, Experience{}, Resume{}, Certification{}, Post{}, Testimonial{}, Award{},
+	Publication{}, Talk{}, Skill{}, Now{}, TimelineEntry{}, RecentChange{}, SearchFailure{}, FieldError{},
+	ChatbotRequest{}, ChatbotResponse{}, ChatFeedbackRequest{}, ChatReportRequest{}, WidgetConfig{}, changelogEntry{},
+}
+
+// tsEndpoint describes one public route for the generated client. {name} path segments become method
+// arguments, query parameters an optional options object.
+type tsEndpoint struct {
+	name     string
+	method   string
+	path     string
+	query    []string
+	body     string // TypeScript type of the JSON body; empty for none
+	response string // TypeScript type of the JSON response
+	doc      string
+}
+
+// tsEndpoints lists the routes of the generated client: the public reads the frontend uses and the chatbot.
+// Admin routes are left out.
+var tsEndpoints = []tsEndpoint{
+	{name: "getAuthors", method: "GET", path: "/api/authors", response: "Author[]", doc: "Lists authors"},
+	{name: "getAuthor", method: "GET", path: "/api/authors/{key}", response: "Author", doc: "Gets an author by ID or slug"},
+	{name: "getProjects", method: "GET", path: "/api/projects", query: []string{"name", "category", "technology", "author_id", "archived", "render", "sort"}, response: "Project[]", doc: "Lists listed projects"},
+	{name: "getProject", method: "GET", path: "/api/projects/{key}", query: []string{"render"}, response: "Project", doc: "Gets a project by ID or slug"},
+	{name: "getEducation", method: "GET", path: "/api/education", query: []string{"university", "major", "student_id", "sort"}, response: "Education[]", doc: "Lists education records"},
+	{name: "getEducationRecord", method: "GET", path: "/api/education/{id}", response: "Education", doc: "Gets an education record"},
+	{name: "getResumes", method: "GET", path: "/api/resumes", response: "Resume[]", doc: "Lists resumes with their experience"},
+	{name: "getResume", method: "GET", path: "/api/resumes/{key}", response: "Resume", doc: "Gets a resume by its ID or its author's ID or slug"},
+	{name: "getExperience", method: "GET", path: "/api/experience", query: []string{"author_id", "company", "technology", "sort"}, response: "Experience[]", doc: "Lists positions, most recent first"},
+	{name: "getPosition", method: "GET", path: "/api/experience/{id}", response: "Experience", doc: "Gets a position"},
+	{name: "getPosts", method: "GET", path: "/api/posts", query: []string{"tag", "render", "sort"}, response: "Post[]", doc: "Lists published posts"},
+	{name: "getPost", method: "GET", path: "/api/posts/{slug}", query: []string{"render"}, response: "Post", doc: "Gets a post by ID or slug"},
+	{name: "getTestimonials", method: "GET", path: "/api/testimonials", response: "Testimonial[]", doc: "Lists approved testimonials"},
+	{name: "getAwards", method: "GET", path: "/api/awards", query: []string{"category", "sort"}, response: "Award[]", doc: "Lists awards"},
+	{name: "getCertifications", method: "GET", path: "/api/certifications", response: "Certification[]", doc: "Lists certifications"},
+	{name: "getPublications", method: "GET", path: "/api/publications", response: "Publication[]", doc: "Lists publications"},
+	{name: "getTalks", method: "GET", path: "/api/talks", response: "Talk[]", doc: "Lists talks"},
+	{name: "getSkills", method: "GET", path: "/api/skills", query: []string{"category", "sort"}, response: "Skill[]", doc: "Lists skills"},
+	{name: "getSkillEvidence", method: "GET", path: "/api/skills/evidence", query: []string{"category"}, response: "Skill[]", doc: "Lists skills with the projects, certifications and repositories showing them"},
+	{name: "getNow", method: "GET", path: "/api/now", query: []string{"author_id"}, response: "Now", doc: "Gets what the author is working on now"},
+	{name: "getTimeline", method: "GET", path: "/api/timeline", query: []string{"type", "limit"}, response: "TimelineEntry[]", doc: "Lists dated items of every collection, newest first"},
+	{name: "getRecentChanges", method: "GET", path: "/api/recent", query: []string{"days", "author_id", "limit"}, response: "RecentChange[]", doc: "Lists projects and posts added or updated in the last days, newest first"},
+	{name: "getCounts", method: "GET", path: "/api/counts", query: []string{"collections"}, response: "Record<string, number>", doc: "Counts the documents of every public collection"},
+	{name: "search", method: "GET", path: "/api/search", query: []string{"q", "types"}, response: "Record<string, unknown> & { errors?: SearchFailure[] }", doc: "Searches every collection, or the comma-separated types; failed collections are listed in errors"},
+	{name: "chat", method: "POST", path: "/api/chatbot", body: "ChatbotRequest", response: "ChatbotResponse", doc: "Asks the chatbot a question"},
+	{name: "sendChatFeedback", method: "POST", path: "/api/chatbot/feedback", body: "ChatFeedbackRequest", response: "unknown", doc: "Rates a chatbot answer"},
+	{name: "reportChatAnswer", method: "POST", path: "/api/chatbot/report", body: "ChatReportRequest", response: "unknown", doc: "Reports an inaccurate or inappropriate chatbot answer for review"},
+	{name: "getWidgetConfig", method: "GET", path: "/api/widget/config", response: "WidgetConfig", doc: "Gets the chat widget's theme, greeting, suggested questions and enabled features"},
+	{name: "getChangelog", method: "GET", path: "/api/changelog", response: "{ deprecations: ChangelogEntry[] }", doc: "Lists deprecated routes and fields"},
+}
+
+var (
+	tsTimeType     = reflect.TypeOf(time.Time{})
+	tsObjectIDType = reflect.TypeOf(primitive.ObjectID{})
+)
+
+// tsGenerator converts Go types to TypeScript interfaces, naming each struct type once
+type tsGenerator struct {
+	names   map[reflect.Type]string
+	pending []reflect.Type
+	out     strings.Builder
+}
+
+// tsTypeName exports a Go type name, e.g. changelogEntry becomes ChangelogEntry
+func tsTypeName(t reflect.Type) string {
+	name := []rune(t.Name())
+	name[0] = unicode.ToUpper(name[0])
+	return string(name)
+}
+
+// typeOf returns the TypeScript type of a Go type, queueing named structs for their own interface
+func (g *tsGenerator) typeOf(t reflect.Type) string {
+	switch t {
+	case tsTimeType:
+		return "string" // RFC 3339
+	case tsObjectIDType:
+		return "string" // Hex ObjectID
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return g.typeOf(t.Elem())
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "string" // Base64
+		}
+		element := g.typeOf(t.Elem())
+		if strings.ContainsAny(element, " |&") {
+			element = "(" + element + ")"
+		}
+		return element + "[]"
+	case reflect.Map:
+		return "Record<string, " + g.typeOf(t.Elem()) + ">"
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.objectOf(t, "")
+		}
+		if name, ok := g.names[t]; ok {
+			return name
+		}
+		name := tsTypeName(t)
+		g.names[t] = name
+		g.pending = append(g.pending, t)
+		return name
+	default:
+		return "unknown"
+	}
+}
+
+// fieldsOf writes a struct's JSON fields, flattening embedded structs the way encoding/json does
+func (g *tsGenerator) fieldsOf(t reflect.Type, indent string, out *strings.Builder) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				g.fieldsOf(embedded, indent, out)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		optional := strings.Contains(","+options+",", ",omitempty,")
+		fieldType := g.typeOf(field.Type)
+		if strings.Contains(","+options+",", ",string,") {
+			fieldType = "string"
+		}
+		if field.Type.Kind() == reflect.Pointer && !optional {
+			fieldType += " | null"
+		}
+		marker := ""
+		if optional {
+			marker = "?"
+		}
+		fmt.Fprintf(out, "%s%s%s: %s;\n", indent, name, marker, fieldType)
+	}
+}
+
+// objectOf returns an inline object type for an anonymous struct
+func (g *tsGenerator) objectOf(t reflect.Type, indent string) string {
+	var out strings.Builder
+	out.WriteString("{\n")
+	g.fieldsOf(t, indent+"    ", &out)
+	out.WriteString(indent + "  }")
+	return out.String()
+}
+
+// generateTypeScript renders the TypeScript definitions of tsModels and a fetch-based client for tsEndpoints
+func generateTypeScript() string {
+	g := &tsGenerator{names: make(map[reflect.Type]string)}
+	for _, model := range tsModels {
+		g.typeOf(reflect.TypeOf(model))
+	}
+
+	g.out.WriteString(`// Code generated by the portfolio API from its Go types; DO NOT EDIT.
+// Download the current version from /api/client.ts or run ` + "`portfolio client-ts > client.ts`" + `.
+
+`)
+	for len(g.pending) > 0 {
+		t := g.pending[0]
+		g.pending = g.pending[1:]
+		fmt.Fprintf(&g.out, "export interface %s {\n", g.names[t])
+		g.fieldsOf(t, "  ", &g.out)
+		g.out.WriteString("}\n\n")
+	}
+
+	g.out.WriteString(`export type Query = Record<string, string | number | boolean | undefined>;
+
+/** PortfolioAPIError is thrown for every non-2xx response */
+export class PortfolioAPIError extends Error {
+  constructor(
+    public readonly status: number,
+    public readonly body: unknown,
+  ) {
+    super(typeof body === "object" && body !== null && "error" in body ? String((body as { error: unknown }).error) : ` + "`Request failed with status ${status}`" + `);
+    this.name = "PortfolioAPIError";
+  }
+
+  /** Field errors of a 400 response */
+  get fieldErrors(): FieldError[] {
+    const body = this.body as { errors?: FieldError[] } | null;
+    return body?.errors ?? [];
+  }
+}
+
+export class PortfolioClient {
+  constructor(
+    private readonly baseURL = "",
+    private readonly fetcher: typeof fetch = (input, init) => fetch(input, init),
+  ) {}
+
+  private async request<T>(method: string, path: string, query?: Query, body?: unknown): Promise<T> {
+    const params = new URLSearchParams();
+    for (const [key, value] of Object.entries(query ?? {})) {
+      if (value !== undefined) params.set(key, String(value));
+    }
+    const search = params.toString();
+    const response = await this.fetcher(this.baseURL + path + (search ? "?" + search : ""), {
+      method,
+      headers: body === undefined ? undefined : { "Content-Type": "application/json" },
+      body: body === undefined ? undefined : JSON.stringify(body),
+    });
+    const text = await response.text();
+    let parsed: unknown = text;
+    try {
+      parsed = text ? JSON.parse(text) : undefined;
+    } catch {
+      // Plain-text error bodies are kept as they are
+    }
+    if (!response.ok) throw new PortfolioAPIError(response.status, parsed);
+    return parsed as T;
+  }
+`)
+	for _, endpoint := range tsEndpoints {
+		var params []string
+		path := endpoint.path
+		for _, segment := range strings.Split(endpoint.path, "/") {
+			if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+				name := strings.Trim(segment, "{}")
+				params = append(params, name+": string")
+				path = strings.Replace(path, segment, "${encodeURIComponent("+name+")}", 1)
+			}
+		}
+		if endpoint.body != "" {
+			params = append(params, "body: "+endpoint.body)
+		}
+		queryArg := "undefined"
+		if len(endpoint.query) > 0 {
+			fields := make([]string, len(endpoint.query))
+			for i, name := range endpoint.query {
+				fields[i] = name + "?: string | number"
+			}
+			params = append(params, "query: { "+strings.Join(fields, "; ")+" } = {}")
+			queryArg = "query"
+		}
+		bodyArg := ""
+		if endpoint.body != "" {
+			bodyArg = ", body"
+		}
+		fmt.Fprintf(&g.out, "\n  /** %s: %s %s */\n  %s(%s): Promise<%s> {\n    return this.request(%q, `%s`, %s%s);\n  }\n",
+			endpoint.doc, endpoint.method, endpoint.path, endpoint.name, strings.Join(params, ", "), endpoint.response,
+			endpoint.method, path, queryArg, bodyArg)
+	}
+	g.out.WriteString("}\n")
+	return g.out.String()
+}
+
+// typeScriptClient is generated once; the types only change with a new build
+var typeScriptClient = sync.OnceValues(func() (string, string) {
+	source := generateTypeScript()
+	sum := sha256.Sum256([]byte(source))
+	return source, `"` + hex.EncodeToString(sum[:8]) + `"`
+})
+
+// TypeScript client endpoint: GET /api/client.ts serves the type definitions and client generated from the
+// Go types, so the frontend can import them instead of maintaining its own interfaces
+func (h *APIHandler) handleTypeScriptClient(w http.ResponseWriter, r *http.Request) {
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	source, etag := typeScriptClient()
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "application/typescript; charset=utf-8")
+	w.Write([]byte(source))
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// UsageDigest emails a summary of the site's usage over the last day or week
+type UsageDigest struct {
+	service *PortfolioService
+	mailer  *Mailer
+	to      []string
+	period  time.Duration
+	spec    string // Default schedule, 08:00 daily or on Mondays
+}
+
+// NewUsageDigest creates the digest from DIGEST_EMAIL and DIGEST_PERIOD ("daily" or "weekly", the
+// default), or returns nil if DIGEST_EMAIL or SMTP is not configured
+func NewUsageDigest(service *PortfolioService, mailer *Mailer) *UsageDigest {
+	to := emailList(os.Getenv("DIGEST_EMAIL"))
+	if len(to) == 0 {
+		log.Println("DIGEST_EMAIL not set, usage digest disabled")
+		return nil
+	}
+	if mailer == nil {
+		log.Println("DIGEST_EMAIL is set but SMTP_HOST is not, usage digest disabled")
+		return nil
+	}
+
+	digest := &UsageDigest{service: service, mailer: mailer, to: to, period: 7 * 24 * time.Hour, spec: "0 8 * * 1"}
+	switch value := strings.ToLower(os.Getenv("DIGEST_PERIOD")); value {
+	case "", "weekly":
+	case "daily":
+		digest.period, digest.spec = 24*time.Hour, "0 8 * * *"
+	default:
+		log.Printf("Invalid DIGEST_PERIOD %q, using weekly", value)
+	}
+	return digest
+}
+
+// UsageReport is what the digest reports on
+type UsageReport struct {
+	From          time.Time
+	To            time.Time
+	Requests      RequestStats
+	ChatRequests  int64
+	ChatFailures  int64
+	Conversations int64
+	TopQuestions  []QuestionCount
+	LLM           LLMUsage
+	FailedJobs    int64
+}
+
+// QuestionCount is how often a chatbot question was asked
+type QuestionCount struct {
+	Question string `bson:"_id"`
+	Count    int64  `bson:"count"`
+}
+
+// Send builds the report for the period just ended and emails it; it runs as the "usage_digest" job
+func (d *UsageDigest) Send(ctx context.Context, _ bson.M) error {
+	now := time.Now()
+	report, err := d.service.GetUsageReport(ctx, now.Add(-d.period), now)
+	if err != nil {
+		return err
+	}
+	name := "Weekly"
+	if d.period == 24*time.Hour {
+		name = "Daily"
+	}
+	return d.mailer.Send(d.to, fmt.Sprintf("[portfolio] %s usage digest", name), report.Text())
+}
+
+// GetUsageReport gathers the stored metrics between two times
+func (ps *PortfolioService) GetUsageReport(ctx context.Context, from, to time.Time) (*UsageReport, error) {
+	report := &UsageReport{From: from, To: to}
+	period := bson.M{"$gte": from, "$lt": to}
+
+	cursor, err := ps.requestStats.Find(ctx, bson.M{"_id": period}, timedFind())
+	if err != nil {
+		return nil, fmt.Errorf("loading request stats: %w", err)
+	}
+	var hours []RequestStats
+	if err := cursor.All(ctx, &hours); err != nil {
+		return nil, fmt.Errorf("loading request stats: %w", err)
+	}
+	for _, hour := range hours {
+		report.Requests.Requests += hour.Requests
+		report.Requests.ServerErrors += hour.ServerErrors
+		report.Requests.ClientErrors += hour.ClientErrors
+	}
+
+	traces := bson.M{"created_at": period}
+	if report.ChatRequests, err = ps.chatTraces.CountDocuments(ctx, traces); err != nil {
+		return nil, fmt.Errorf("counting chat requests: %w", err)
+	}
+	if report.ChatFailures, err = ps.chatTraces.CountDocuments(ctx, bson.M{"created_at": period, "outcome": bson.M{"$ne": ChatTraceSuccess}}); err != nil {
+		return nil, fmt.Errorf("counting chat failures: %w", err)
+	}
+	sessions, err := ps.chatTraces.Distinct(ctx, "session_id", traces)
+	if err != nil {
+		return nil, fmt.Errorf("counting conversations: %w", err)
+	}
+	report.Conversations = int64(len(sessions))
+
+	cursor, err = ps.chatTraces.Aggregate(ctx, []bson.M{
+		{"$match": bson.M{"created_at": period, "question": bson.M{"$gt": ""}}},
+		{"$group": bson.M{"_id": bson.M{"$toLower": bson.M{"$trim": bson.M{"input": "$question"}}}, "count": bson.M{"$sum": 1}}},
+		{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}},
+		{"$limit": 10},
+	}, timedAggregate())
+	if err != nil {
+		return nil, fmt.Errorf("loading top questions: %w", err)
+	}
+	if err := cursor.All(ctx, &report.TopQuestions); err != nil {
+		return nil, fmt.Errorf("loading top questions: %w", err)
+	}
+
+	if report.LLM, err = ps.GetLLMUsage(ctx, from); err != nil {
+		return nil, fmt.Errorf("loading LLM usage: %w", err)
+	}
+	if report.FailedJobs, err = ps.jobs.CountDocuments(ctx, bson.M{"status": JobFailed, "finished_at": period}); err != nil {
+		return nil, fmt.Errorf("counting failed jobs: %w", err)
+	}
+	return report, nil
+}
+
+// Text renders the report as a plain-text email
+func (r *UsageReport) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Portfolio usage from %s to %s\n\n", r.From.Format("Mon Jan 2 15:04"), r.To.Format("Mon Jan 2 15:04 MST"))
+
+	errorRate := 0.0
+	if r.Requests.Requests > 0 {
+		errorRate = float64(r.Requests.ServerErrors) / float64(r.Requests.Requests) * 100
+	}
+	fmt.Fprintf(&b, "Requests served: %d (%d server errors, %.1f%%; %d client errors)\n",
+		r.Requests.Requests, r.Requests.ServerErrors, errorRate, r.Requests.ClientErrors)
+	fmt.Fprintf(&b, "Chatbot: %d questions in %d conversations, %d failed\n", r.ChatRequests, r.Conversations, r.ChatFailures)
+	fmt.Fprintf(&b, "OpenAI: %d calls, %d failed, %d prompt and %d completion tokens, estimated cost $%.2f\n",
+		r.LLM.Calls, r.LLM.Failures, r.LLM.PromptTokens, r.LLM.CompletionTokens, r.LLM.CostUSD)
+	fmt.Fprintf(&b, "Failed background jobs: %d\n", r.FailedJobs)
+
+	if len(r.TopQuestions) > 0 {
+		b.WriteString("\nTop questions:\n")
+		for _, question := range r.TopQuestions {
+			fmt.Fprintf(&b, "  %3d  %s\n", question.Count, question.Question)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FieldError describes one invalid field of a request body or query string
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"` // e.g. required, max_length, one_of, range, url, format
+	Message string `json:"message"`
+}
+
+// ValidationErrors lists every invalid field of a request so clients can report them all at once
+type ValidationErrors []FieldError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, fieldError := range e {
+		messages[i] = fieldError.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// invalidField returns a ValidationErrors with a single field error
+func invalidField(field, rule, message string) error {
+	return ValidationErrors{{Field: field, Rule: rule, Message: message}}
+}
+
+// Validator collects field errors from a sequence of rules. Once a field has failed, its later rules are
+// skipped, so a missing title is not also reported as too short.
+type Validator struct {
+	errors ValidationErrors
+	failed map[string]bool
+}
+
+// Check records a field error unless ok holds. It returns ok, and false for a field that already failed.
+func (v *Validator) Check(ok bool, field, rule, message string) bool {
+	if v.failed[field] {
+		return false
+	}
+	if !ok {
+		if v.failed == nil {
+			v.failed = make(map[string]bool)
+		}
+		v.failed[field] = true
+		v.errors = append(v.errors, FieldError{Field: field, Rule: rule, Message: message})
+	}
+	return ok
+}
+
+// Required fails for an empty or blank value
+func (v *Validator) Required(field, value string) bool {
+	return v.Check(strings.TrimSpace(value) != "", field, "required", field+" is required")
+}
+
+// RequiredTime fails for the zero time
+func (v *Validator) RequiredTime(field string, value time.Time) bool {
+	return v.Check(!value.IsZero(), field, "required", field+" is required")
+}
+
+// MaxLength fails for a value longer than max bytes
+func (v *Validator) MaxLength(field, value string, max int) bool {
+	return v.Check(len(value) <= max, field, "max_length", fmt.Sprintf("%s too long (max %d characters)", field, max))
+}
+
+// MaxItems fails for a list with more than max entries
+func (v *Validator) MaxItems(field string, count, max int) bool {
+	return v.Check(count <= max, field, "max_items", fmt.Sprintf("%s is limited to %d entries", field, max))
+}
+
+// OneOf fails unless the value is one of the allowed values
+func (v *Validator) OneOf(field, value string, allowed ...string) bool {
+	for _, candidate := range allowed {
+		if value == candidate {
+			return true
+		}
+	}
+	return v.Check(false, field, "one_of", fmt.Sprintf("%s must be one of %s", field, strings.Join(allowed, ", ")))
+}
+
+// Range fails for a number outside [min, max]
+func (v *Validator) Range(field string, value, min, max float64) bool {
+	return v.Check(value >= min && value <= max, field, "range",
+		fmt.Sprintf("%s must be between %s and %s", field, strconv.FormatFloat(min, 'f', -1, 64), strconv.FormatFloat(max, 'f', -1, 64)))
+}
+
+// HTTPURL fails unless a non-empty value is an absolute http(s) URL; empty values pass
+func (v *Validator) HTTPURL(field, value string) bool {
+	if value == "" {
+		return true
+	}
+	parsed, err := url.Parse(value)
+	ok := err == nil && parsed.Host != "" && (parsed.Scheme == "http" || parsed.Scheme == "https")
+	return v.Check(ok, field, "url", field+" must be an absolute http(s) URL")
+}
+
+// suspiciousInputPattern matches common attack terms in free text sent to the chatbot
+var suspiciousInputPattern = regexp.MustCompile(`(?i)(hack|exploit|attack|inject|<script|javascript:|data:|vbscript:)`)
+
+// ChatInput applies the rules for free text that ends up in an LLM prompt: required, at most max characters,
+// no attack terms and no runs of more than 10 repeated characters
+func (v *Validator) ChatInput(field, value string, max int) bool {
+	if !v.Required(field, value) || !v.MaxLength(field, value, max) {
+		return false
+	}
+	if !v.Check(!suspiciousInputPattern.MatchString(value), field, "suspicious", "invalid input detected") {
+		return false
+	}
+
+	// Check for repeated characters manually (since Go regexp doesn't support backreferences)
+	run := 1
+	for i := 1; i < len(value); i++ {
+		if value[i] != value[i-1] {
+			run = 1
+			continue
+		}
+		run++
+		if run > 10 {
+			return v.Check(false, field, "repeated_characters", "invalid input detected")
+		}
+	}
+	return true
+}
+
+// QueryInt reads an optional integer query parameter within [min, max], returning fallback when it is absent
+func (v *Validator) QueryInt(r *http.Request, name string, fallback, min, max int) int {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return fallback
+	}
+	parsed, err := strconv.Atoi(value)
+	if !v.Check(err == nil && parsed >= min && parsed <= max, name, "range", fmt.Sprintf("%s must be between %d and %d", name, min, max)) {
+		return fallback
+	}
+	return parsed
+}
+
+// Err returns the collected errors, or nil if every rule passed
+func (v *Validator) Err() error {
+	if len(v.errors) == 0 {
+		return nil
+	}
+	return v.errors
+}
+
+// writeInvalidInput answers 400 with the field errors as JSON. Errors that are not ValidationErrors are
+// reported as a single error without a field.
+func writeInvalidInput(w http.ResponseWriter, err error) {
+	var fieldErrors ValidationErrors
+	if !errors.As(err, &fieldErrors) {
+		fieldErrors = ValidationErrors{{Rule: "invalid", Message: err.Error()}}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error":  "Invalid input: " + fieldErrors.Error(),
+		"errors": fieldErrors,
+	})
+}
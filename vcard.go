@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var vcardEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`,`, `\,`,
+	`;`, `\;`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+)
+
+var vcardFilenameSanitizer = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// foldVCardLine splits content lines longer than 75 octets as required by RFC 6350,
+// taking care not to split inside a multi-byte UTF-8 character
+func foldVCardLine(line string) string {
+	if len(line) <= 75 {
+		return line + "\r\n"
+	}
+
+	var b strings.Builder
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && (line[cut]&0xC0) == 0x80 {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space, which counts toward the limit
+		limit = 74
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+	return b.String()
+}
+
+// buildVCard renders a vCard 4.0 for the author; contact may be nil if there is no resume
+func buildVCard(author *Author, contact *Contact) string {
+	var b strings.Builder
+	b.WriteString("BEGIN:VCARD\r\n")
+	b.WriteString("VERSION:4.0\r\n")
+	b.WriteString(foldVCardLine("FN:" + vcardEscaper.Replace(author.Name)))
+
+	// Structured name: family;given;additional;prefix;suffix
+	nameParts := strings.Fields(author.Name)
+	family, given := "", ""
+	if len(nameParts) > 0 {
+		given = nameParts[0]
+	}
+	if len(nameParts) > 1 {
+		family = nameParts[len(nameParts)-1]
+	}
+	b.WriteString(foldVCardLine(fmt.Sprintf("N:%s;%s;;;", vcardEscaper.Replace(family), vcardEscaper.Replace(given))))
+
+	if author.JobTitle != "" {
+		b.WriteString(foldVCardLine("TITLE:" + vcardEscaper.Replace(author.JobTitle)))
+	}
+
+	email := author.Email
+	if email == "" && contact != nil {
+		email = contact.Email
+	}
+	if email != "" {
+		b.WriteString(foldVCardLine("EMAIL;TYPE=work:" + vcardEscaper.Replace(string(email))))
+	}
+	if contact != nil && contact.Email != "" && contact.Email != email {
+		b.WriteString(foldVCardLine("EMAIL:" + vcardEscaper.Replace(string(contact.Email))))
+	}
+	if contact != nil && contact.Phone != "" {
+		b.WriteString(foldVCardLine("TEL;TYPE=cell;VALUE=text:" + vcardEscaper.Replace(string(contact.Phone))))
+	}
+
+	// URL values are not escaped: commas and semicolons are legal in URIs
+	if author.LinkedinURL != "" {
+		b.WriteString(foldVCardLine("URL;TYPE=linkedin:" + author.LinkedinURL))
+	}
+	if author.GithubURL != "" {
+		b.WriteString(foldVCardLine("URL;TYPE=github:" + author.GithubURL))
+	}
+
+	b.WriteString(foldVCardLine("REV:" + time.Now().UTC().Format("20060102T150405Z")))
+	b.WriteString("END:VCARD\r\n")
+	return b.String()
+}
+
+// loadAuthorVCard fetches the author and their resume contact and renders a vCard. Unless admin is set only
+// the contact details the author published are included; masked values are no use in an address book.
+func (h *APIHandler) loadAuthorVCard(ctx context.Context, authorID primitive.ObjectID, admin bool) (*Author, string, error) {
+	author, err := h.service.GetAuthorByID(ctx, authorID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var contact *Contact
+	resume, err := h.service.GetResumeByAuthor(ctx, authorID)
+	if err == nil {
+		contact = &resume.Contact
+	} else if err != mongo.ErrNoDocuments {
+		return nil, "", err
+	}
+
+	// Visitors only get the contact details the author published
+	if !admin {
+		card := *author
+		if !author.publishes("email") {
+			card.Email = ""
+		}
+		if contact != nil {
+			public := *contact
+			if !author.publishes("email") {
+				public.Email = ""
+			}
+			if !author.publishes("phone") {
+				public.Phone = ""
+			}
+			contact = &public
+		}
+		return author, buildVCard(&card, contact), nil
+	}
+
+	return author, buildVCard(author, contact), nil
+}
+
+// vCard endpoint: /api/authors/{id}/vcard
+func (h *APIHandler) handleAuthorVCard(w http.ResponseWriter, r *http.Request, authorID primitive.ObjectID) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	ctx := context.Background()
+	author, vcard, err := h.loadAuthorVCard(ctx, authorID, isAdminRequest(r))
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Author not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("Date: %s | Route: /api/authors/{id}/vcard | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	filename := strings.Trim(vcardFilenameSanitizer.ReplaceAllString(author.Name, "_"), "_")
+	if filename == "" {
+		filename = "contact"
+	}
+
+	log.Printf("Date: %s | Route: /api/authors/{id}/vcard | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+	w.Header().Set("Content-Type", "text/vcard; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.vcf"`, filename))
+	w.Write([]byte(vcard))
+}
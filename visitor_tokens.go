@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// visitorTokenHeader carries the token for clients that cannot use cookies, such as cross-origin widgets
+	visitorTokenHeader = "X-Visitor-Token"
+	visitorTokenCookie = "portfolio_visitor"
+	visitorTokenMaxAge = 365 * 24 * time.Hour
+	// visitorTokensPerHour caps the tokens issued to one IP; the chatbot also limits each IP as a whole, so
+	// discarding tokens does not reset chat limits
+	visitorTokensPerHour = 10
+)
+
+// VisitorTokens issues and checks signed anonymous visitor tokens. A token names a random visitor ID and
+// is signed with VISITOR_TOKEN_SECRET; chat sessions and quotas are keyed on it rather than on the IP, so
+// visitors behind a shared NAT are limited one by one and keep their session when their IP changes.
+type VisitorTokens struct {
+	store  StateStore
+	secret []byte
+}
+
+// NewVisitorTokens loads VISITOR_TOKEN_SECRET. Without it a random secret is used, and tokens only hold
+// until the process restarts.
+func NewVisitorTokens(store StateStore) *VisitorTokens {
+	secret := []byte(strings.TrimSpace(os.Getenv("VISITOR_TOKEN_SECRET")))
+	if len(secret) == 0 {
+		log.Println("VISITOR_TOKEN_SECRET not set, visitor tokens expire on restart")
+		secret = make([]byte, 32)
+		rand.Read(secret)
+	}
+	return &VisitorTokens{store: store, secret: secret}
+}
+
+// sign returns the token for a visitor ID issued at the given time: v1.<id>.<unix time>.<signature>
+func (vt *VisitorTokens) sign(visitorID string, issued time.Time) string {
+	payload := fmt.Sprintf("v1.%s.%d", visitorID, issued.Unix())
+	mac := hmac.New(sha256.New, vt.secret)
+	mac.Write([]byte(payload))
+	return payload + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// verify returns the visitor ID of a token, or false if it is malformed, forged or expired
+func (vt *VisitorTokens) verify(token string) (string, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 4 || parts[0] != "v1" || len(parts[1]) != 32 {
+		return "", false
+	}
+	if _, err := hex.DecodeString(parts[1]); err != nil {
+		return "", false
+	}
+	issued, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil || time.Since(time.Unix(issued, 0)) > visitorTokenMaxAge {
+		return "", false
+	}
+	if !hmac.Equal([]byte(vt.sign(parts[1], time.Unix(issued, 0))), []byte(token)) {
+		return "", false
+	}
+	return parts[1], true
+}
+
+// visitorTokenOf returns the visitor token a request carries, from the header or else the cookie
+func visitorTokenOf(r *http.Request) string {
+	if token := strings.TrimSpace(r.Header.Get(visitorTokenHeader)); token != "" {
+		return token
+	}
+	if cookie, err := r.Cookie(visitorTokenCookie); err == nil {
+		return cookie.Value
+	}
+	return ""
+}
+
+// Identify returns the visitor ID of a request. A request without a valid token is issued one, in a cookie
+// and in the X-Visitor-Token response header, unless its IP already had its share of tokens this hour; the
+// visitor ID is empty then, and the caller falls back to the IP.
+func (vt *VisitorTokens) Identify(w http.ResponseWriter, r *http.Request) string {
+	if visitorID, ok := vt.verify(visitorTokenOf(r)); ok {
+		return visitorID
+	}
+
+	allowed, err := vt.store.Allow(context.Background(), "visitor_tokens:"+trustedClientIP(r), []RateLimit{
+		{Window: time.Hour, Max: visitorTokensPerHour},
+	})
+	if err != nil {
+		log.Printf("Error checking visitor token limit: %v", err)
+		return ""
+	}
+	if !allowed {
+		return ""
+	}
+
+	visitorID := newChatSessionID()
+	token := vt.sign(visitorID, time.Now())
+	http.SetCookie(w, &http.Cookie{
+		Name:     visitorTokenCookie,
+		Value:    token,
+		Path:     "/",
+		MaxAge:   int(visitorTokenMaxAge.Seconds()),
+		HttpOnly: true,
+		Secure:   r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https",
+		SameSite: http.SameSiteLaxMode,
+	})
+	w.Header().Set(visitorTokenHeader, token)
+	return visitorID
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// widgetConfigID is the settings document holding the chat widget configuration
+const widgetConfigID = "widget"
+
+const (
+	maxWidgetGreeting           = 500
+	maxWidgetSuggestedQuestions = 10
+)
+
+var widgetColorPattern = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// WidgetTheme holds the chat widget colors as CSS hex colors; empty colors use the defaults
+type WidgetTheme struct {
+	Primary    string `bson:"primary" json:"primary"` // Launcher button and visitor messages
+	Accent     string `bson:"accent" json:"accent"`   // Links and highlights
+	Background string `bson:"background" json:"background"`
+	Text       string `bson:"text" json:"text"`
+}
+
+// WidgetFeatures switches optional parts of the chat widget on and off
+type WidgetFeatures struct {
+	Voice    bool `bson:"voice" json:"voice"`       // Speech input and read-aloud answers
+	Feedback bool `bson:"feedback" json:"feedback"` // Rating and reporting answers
+}
+
+// WidgetConfig is the chat widget's appearance and behavior, managed server-side so the widget can change
+// without redeploying the sites embedding it
+type WidgetConfig struct {
+	ID                 string         `bson:"_id" json:"-"`
+	Theme              WidgetTheme    `bson:"theme" json:"theme"`
+	Greeting           string         `bson:"greeting" json:"greeting"`
+	SuggestedQuestions []string       `bson:"suggested_questions" json:"suggested_questions"`
+	Features           WidgetFeatures `bson:"features" json:"features"`
+	UpdatedAt          *time.Time     `bson:"updated_at,omitempty" json:"updated_at,omitempty"` // Unset until first saved
+}
+
+// defaultWidgetConfig is served until a configuration is saved, in the colors of the Open Graph images
+func defaultWidgetConfig() *WidgetConfig {
+	return &WidgetConfig{
+		ID: widgetConfigID,
+		Theme: WidgetTheme{
+			Primary:    "#F5A9B8",
+			Accent:     "#5BCEFA",
+			Background: "#1E1E1E",
+			Text:       "#FFFFFF",
+		},
+		Greeting: "Hi! Ask me anything about my projects, experience or skills.",
+		SuggestedQuestions: []string{
+			"What are your most recent projects?",
+			"What technologies do you work with?",
+			"Are you available for new work?",
+		},
+		Features: WidgetFeatures{Feedback: true},
+	}
+}
+
+// GetWidgetConfig returns the saved widget configuration, or the defaults when none was saved
+func (ps *PortfolioService) GetWidgetConfig(ctx context.Context) (*WidgetConfig, error) {
+	var config WidgetConfig
+	err := ps.settings.FindOne(ctx, bson.M{"_id": widgetConfigID}).Decode(&config)
+	if err == mongo.ErrNoDocuments {
+		return defaultWidgetConfig(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	config.applyDefaults()
+	return &config, nil
+}
+
+// applyDefaults fills in the colors left empty, and lists no suggested questions rather than null
+func (c *WidgetConfig) applyDefaults() {
+	defaults := defaultWidgetConfig().Theme
+	if c.Theme.Primary == "" {
+		c.Theme.Primary = defaults.Primary
+	}
+	if c.Theme.Accent == "" {
+		c.Theme.Accent = defaults.Accent
+	}
+	if c.Theme.Background == "" {
+		c.Theme.Background = defaults.Background
+	}
+	if c.Theme.Text == "" {
+		c.Theme.Text = defaults.Text
+	}
+	if c.SuggestedQuestions == nil {
+		c.SuggestedQuestions = []string{}
+	}
+}
+
+// SaveWidgetConfig replaces the widget configuration
+func (ps *PortfolioService) SaveWidgetConfig(ctx context.Context, config *WidgetConfig) error {
+	now := time.Now()
+	config.ID = widgetConfigID
+	config.UpdatedAt = &now
+	_, err := ps.settings.ReplaceOne(ctx, bson.M{"_id": widgetConfigID}, config, options.Replace().SetUpsert(true))
+	return err
+}
+
+// validateWidgetConfig checks a widget configuration submitted through the admin API
+func validateWidgetConfig(config *WidgetConfig) error {
+	var v Validator
+	for _, color := range []struct{ field, value string }{
+		{"theme.primary", config.Theme.Primary},
+		{"theme.accent", config.Theme.Accent},
+		{"theme.background", config.Theme.Background},
+		{"theme.text", config.Theme.Text},
+	} {
+		v.Check(color.value == "" || widgetColorPattern.MatchString(color.value), color.field, "format", color.field+" must be a hex color such as #1E1E1E")
+	}
+	v.MaxLength("greeting", config.Greeting, maxWidgetGreeting)
+	// Suggested questions are sent to the chatbot as they are, so they must pass as chat queries
+	v.MaxItems("suggested_questions", len(config.SuggestedQuestions), maxWidgetSuggestedQuestions)
+	for i, question := range config.SuggestedQuestions {
+		v.ChatInput(fmt.Sprintf("suggested_questions[%d]", i), question, 500)
+	}
+	return v.Err()
+}
+
+// Widget config endpoint: GET returns the chat widget's theme, greeting, suggested questions and enabled
+// features; PUT replaces them (admin only)
+func (h *APIHandler) handleWidgetConfig(w http.ResponseWriter, r *http.Request) {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	gptModel := "DISABLED"
+	if h.llmService != nil {
+		gptModel = h.llmService.model
+	}
+
+	h.enableCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	ctx := context.Background()
+
+	switch r.Method {
+	case "GET":
+		config, err := h.service.GetWidgetConfig(ctx)
+		if err != nil {
+			log.Printf("Date: %s | Route: /api/widget/config | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Date: %s | Route: /api/widget/config | Status: SUCCESS | GPT Model: %s", currentTime, gptModel)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(config)
+
+	case "PUT":
+		if !isAdminRequest(r) {
+			log.Printf("Date: %s | Route: /api/widget/config | Status: UNAUTHORIZED | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		var config WidgetConfig
+		if err := json.NewDecoder(r.Body).Decode(&config); err != nil {
+			log.Printf("Date: %s | Route: /api/widget/config | Status: BAD_REQUEST | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, "Invalid JSON request", http.StatusBadRequest)
+			return
+		}
+		config.Theme.Primary = strings.TrimSpace(config.Theme.Primary)
+		config.Theme.Accent = strings.TrimSpace(config.Theme.Accent)
+		config.Theme.Background = strings.TrimSpace(config.Theme.Background)
+		config.Theme.Text = strings.TrimSpace(config.Theme.Text)
+		config.Greeting = strings.TrimSpace(config.Greeting)
+		config.SuggestedQuestions = trimList(config.SuggestedQuestions)
+		if err := validateWidgetConfig(&config); err != nil {
+			log.Printf("Date: %s | Route: /api/widget/config | Status: INVALID_INPUT | GPT Model: %s", currentTime, gptModel)
+			writeInvalidInput(w, err)
+			return
+		}
+
+		if err := h.service.SaveWidgetConfig(ctx, &config); err != nil {
+			log.Printf("Date: %s | Route: /api/widget/config | Status: ERROR | GPT Model: %s", currentTime, gptModel)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		config.applyDefaults()
+
+		log.Printf("Date: %s | Route: /api/widget/config | Status: UPDATED | GPT Model: %s", currentTime, gptModel)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(config)
+
+	default:
+		log.Printf("Date: %s | Route: /api/widget/config | Status: METHOD_NOT_ALLOWED | GPT Model: %s", currentTime, gptModel)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}